@@ -0,0 +1,137 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ProcessFiles applies a transformation to several XMP files.  Each file is
+// read, passed to transform, and written back using the given options, see
+// [UpdateFile].
+//
+// A failure to process one file does not stop the processing of the
+// remaining files.  If any of the files fail, a [*BatchError] is returned
+// which lists the failed files.
+func ProcessFiles(fileNames []string, opt *FileOptions, transform func(fname string, p *Packet) error) error {
+	return ProcessFilesContext(context.Background(), fileNames, opt, transform)
+}
+
+// ProcessFilesContext is like [ProcessFiles], but processing stops when the
+// context is cancelled or its deadline expires.  Files which have not been
+// processed at this time are included in the returned [*BatchError], with
+// phase [PhaseRead] and the context error.
+func ProcessFilesContext(ctx context.Context, fileNames []string, opt *FileOptions, transform func(fname string, p *Packet) error) error {
+	var res BatchError
+	for _, fname := range fileNames {
+		phase, err := updateFile(ctx, fname, opt, func(p *Packet) error {
+			return transform(fname, p)
+		})
+		if err != nil {
+			res.Errors = append(res.Errors, &FileError{
+				Path:  fname,
+				Phase: phase,
+				Err:   err,
+			})
+		}
+	}
+	if len(res.Errors) > 0 {
+		return &res
+	}
+	return nil
+}
+
+// Phase identifies the stage of processing a file.
+type Phase int
+
+// These are the phases of processing a file.  Failures to lock a file are
+// reported as PhaseRead.
+const (
+	PhaseRead Phase = iota + 1
+	PhaseTransform
+	PhaseWrite
+)
+
+func (ph Phase) String() string {
+	switch ph {
+	case PhaseRead:
+		return "read"
+	case PhaseTransform:
+		return "transform"
+	case PhaseWrite:
+		return "write"
+	default:
+		return fmt.Sprintf("Phase(%d)", int(ph))
+	}
+}
+
+// FileError describes the failure to process one file.
+type FileError struct {
+	Path  string
+	Phase Phase
+	Err   error
+}
+
+func (err *FileError) Error() string {
+	return err.Path + ": " + err.Phase.String() + ": " + err.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (err *FileError) Unwrap() error {
+	return err.Err
+}
+
+// BatchError collects the errors which occurred while processing several
+// files.
+type BatchError struct {
+	Errors []*FileError
+}
+
+func (err *BatchError) Error() string {
+	if len(err.Errors) == 1 {
+		return err.Errors[0].Error()
+	}
+	msgs := make([]string, len(err.Errors))
+	for i, e := range err.Errors {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("%d files failed:\n", len(err.Errors)) + strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors for the individual files.
+func (err *BatchError) Unwrap() []error {
+	res := make([]error, len(err.Errors))
+	for i, e := range err.Errors {
+		res[i] = e
+	}
+	return res
+}
+
+// Paths returns the names of the files which failed in the given phase.
+// If phase is zero, the names of all failed files are returned.  This can
+// be used to retry the failed operations.
+func (err *BatchError) Paths(phase Phase) []string {
+	var res []string
+	for _, e := range err.Errors {
+		if phase == 0 || e.Phase == phase {
+			res = append(res, e.Path)
+		}
+	}
+	return res
+}
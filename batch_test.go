@@ -0,0 +1,111 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcessFiles(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.xmp")
+	bad := filepath.Join(dir, "bad.xmp")
+	missing := filepath.Join(dir, "missing.xmp")
+	for _, fname := range []string{good, bad} {
+		err := WriteFile(fname, NewPacket(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errTest := errors.New("test error")
+	var seen []string
+	err := ProcessFiles([]string{missing, good, bad}, nil, func(fname string, p *Packet) error {
+		seen = append(seen, fname)
+		if fname == bad {
+			return errTest
+		}
+		p.SetValue(basicNamespace, "Label", NewText("done"))
+		return nil
+	})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("got error %v, want BatchError", err)
+	}
+	if d := cmp.Diff([]string{good, bad}, seen); d != "" {
+		t.Errorf("wrong files processed (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{missing, bad}, batchErr.Paths(0)); d != "" {
+		t.Errorf("wrong failed files (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{bad}, batchErr.Paths(PhaseTransform)); d != "" {
+		t.Errorf("wrong transform failures (-want +got):\n%s", d)
+	}
+	if batchErr.Errors[0].Phase != PhaseRead {
+		t.Errorf("wrong phase %s for missing file", batchErr.Errors[0].Phase)
+	}
+	if !errors.Is(err, errTest) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("underlying errors not found in %v", err)
+	}
+
+	p, err := ReadFile(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	label, _ := PacketGetValue[Text](p, basicNamespace, "Label")
+	if label.V != "done" {
+		t.Errorf("good file was not updated")
+	}
+}
+
+func TestProcessFilesCancelled(t *testing.T) {
+	dir := t.TempDir()
+	var fileNames []string
+	for _, name := range []string{"a.xmp", "b.xmp", "c.xmp"} {
+		fname := filepath.Join(dir, name)
+		err := WriteFile(fname, NewPacket(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fileNames = append(fileNames, fname)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := ProcessFilesContext(ctx, fileNames, nil, func(fname string, p *Packet) error {
+		cancel() // stop after the first file
+		return nil
+	})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("got error %v, want BatchError", err)
+	}
+	if d := cmp.Diff(fileNames[1:], batchErr.Paths(PhaseRead)); d != "" {
+		t.Errorf("wrong unprocessed files (-want +got):\n%s", d)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("context error not found in %v", err)
+	}
+}
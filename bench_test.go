@@ -0,0 +1,190 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// loadSmall returns the contents of testdata/small.xmp, a typical packet
+// as written by image editing software.
+func loadSmall(b *testing.B) []byte {
+	body, err := os.ReadFile("testdata/small.xmp")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return body
+}
+
+// makeLarge returns a large packet, with many namespaces, long arrays,
+// localized text, and nested structures.
+func makeLarge() *Packet {
+	p := NewPacket()
+	for i := 0; i < 20; i++ {
+		ns := fmt.Sprintf("http://ns.seehuhn.de/bench/%d/#", i)
+		p.RegisterPrefix(ns, fmt.Sprintf("b%d", i))
+		for j := 0; j < 10; j++ {
+			p.SetValue(ns, fmt.Sprintf("text%d", j), NewText(fmt.Sprintf("value %d/%d", i, j)))
+		}
+
+		var keywords UnorderedArray[Text]
+		for j := 0; j < 50; j++ {
+			keywords.Append(NewText(fmt.Sprintf("keyword %d", j)))
+		}
+		p.SetValue(ns, "keywords", keywords)
+
+		var title Localized
+		title.Default = NewText("title")
+		for _, lang := range []string{"en", "de", "fr", "it", "es"} {
+			title.Set(language.MustParse(lang), "title "+lang)
+		}
+		p.SetValue(ns, "title", title)
+
+		fields := make(map[xml.Name]Raw)
+		for j := 0; j < 10; j++ {
+			fields[xml.Name{Space: ns, Local: fmt.Sprintf("f%d", j)}] = Text{
+				V: fmt.Sprint(j),
+				Q: Q{{Name: xml.Name{Space: ns, Local: "q"}, Value: Text{V: "q"}}},
+			}
+		}
+		p.Properties[xml.Name{Space: ns, Local: "struct"}] = RawStruct{Value: fields}
+	}
+	return p
+}
+
+func BenchmarkDecodeSmall(b *testing.B) {
+	body := loadSmall(b)
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Read(bytes.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeLarge(b *testing.B) {
+	buf := &bytes.Buffer{}
+	err := makeLarge().Write(buf, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	body := buf.Bytes()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Read(bytes.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeReuse(b *testing.B) {
+	body := loadSmall(b)
+	p := NewPacket()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := p.Decode(bytes.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeSmall(b *testing.B) {
+	p, err := Read(bytes.NewReader(loadSmall(b)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := p.Write(io.Discard, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeLarge(b *testing.B) {
+	p := makeLarge()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := p.Write(io.Discard, &PacketOptions{Pretty: true})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkModelGet(b *testing.B) {
+	p, err := Read(bytes.NewReader(loadSmall(b)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dc := &DublinCore{}
+		p.Get(dc)
+		basic := &Basic{}
+		p.Get(basic)
+	}
+}
+
+func BenchmarkModelSet(b *testing.B) {
+	dc := &DublinCore{}
+	dc.Title.Default = NewText("Harbour at dusk")
+	dc.Title.Set(language.German, "Hafen in der Abenddämmerung")
+	dc.Creator.Append(NewProperName("Jane Doe"))
+	for _, kw := range []string{"harbour", "boats", "evening", "water"} {
+		dc.Subject.Append(NewText(kw))
+	}
+	basic := &Basic{
+		CreateDate: NewDate(time.Date(2023, 10, 12, 14, 3, 51, 0, time.UTC)),
+		Rating:     Real{V: 4},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := NewPacket()
+		err := p.Set(dc, basic)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEqual(b *testing.B) {
+	p1 := makeLarge()
+	p2 := makeLarge()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !p1.Equal(p2) {
+			b.Fatal("packets differ")
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// BasicJobTicket represents the XMP Basic Job Ticket namespace.
+//
+// See section 8.7 of ISO 16684-1:2011 for details.
+type BasicJobTicket struct {
+	_ Namespace `xmp:"http://ns.adobe.com/xap/1.0/bj/"`
+	_ Prefix    `xmp:"xmpBJ"`
+
+	// JobRef references the jobs which have used the resource, for example
+	// in a print production workflow.
+	JobRef UnorderedArray[Job]
+}
+
+// Job describes a job for which a resource is used.
+//
+// This is the Job structure from section 8.7.2 of ISO 16684-1:2011.
+type Job struct {
+	// ID is a unique identifier for the job.  This is used to correlate
+	// jobs between different systems.
+	ID Text
+
+	// Name is an informal name of the job.  This is used for display
+	// purposes only.
+	Name Text
+
+	// URL is a file URL referencing an external job management file.
+	URL URI
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (j Job) IsZero() bool {
+	return j.ID.IsZero() && j.Name.IsZero() && j.URL.IsZero() && len(j.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (j Job) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     j.Q,
+	}
+	setField(res, p, stJobNamespace, "id", j.ID)
+	setField(res, p, stJobNamespace, "name", j.Name)
+	setField(res, p, stJobNamespace, "url", j.URL)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Job) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Job{
+		ID:   getField[Text](s, stJobNamespace, "id"),
+		Name: getField[Text](s, stJobNamespace, "name"),
+		URL:  getField[URI](s, stJobNamespace, "url"),
+		Q:    s.Q,
+	}, nil
+}
+
+const (
+	bjNamespace    = "http://ns.adobe.com/xap/1.0/bj/"
+	stJobNamespace = "http://ns.adobe.com/xap/1.0/sType/Job#"
+)
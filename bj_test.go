@@ -0,0 +1,81 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBasicJobTicket(t *testing.T) {
+	in := &BasicJobTicket{}
+	in.JobRef.Append(Job{
+		ID:   NewText("job-0815"),
+		Name: NewText("Spring catalogue"),
+		URL:  NewURI("file:///jobs/0815.jdf"),
+	})
+	in.JobRef.Append(Job{Name: NewText("Reprint")})
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<xmpBJ:JobRef>")) ||
+		!bytes.Contains(buf.Bytes(), []byte(`stJob:url="file:///jobs/0815.jdf"`)) {
+		t.Errorf("wrong encoding:\n%s", buf.Bytes())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &BasicJobTicket{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
+
+func TestJobDecode(t *testing.T) {
+	raw := RawStruct{
+		Value: map[xml.Name]Raw{
+			{Space: stJobNamespace, Local: "id"}:    Text{V: "42"},
+			{Space: stJobNamespace, Local: "other"}: Text{V: "ignored"},
+		},
+	}
+	v, err := Job{}.DecodeAnother(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(Job{ID: NewText("42")}, v); d != "" {
+		t.Errorf("wrong job (-want +got):\n%s", d)
+	}
+
+	_, err = Job{}.DecodeAnother(Text{V: "42"})
+	if err != ErrInvalid {
+		t.Errorf("expected ErrInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BWF holds broadcast audio metadata from a Broadcast Wave Format (BWF)
+// file.  This combines the fields of the bext chunk (EBU Tech 3285) with
+// some commonly used fields from the LIST/INFO and iXML chunks.
+//
+// Use [Packet.SetBWF] and [Packet.GetBWF] to convert between this
+// representation and XMP properties.
+type BWF struct {
+	// Description is a free-text description of the sound sequence
+	// (bext Description).
+	Description string
+
+	// Originator is the name of the originator (bext Originator).
+	Originator string
+
+	// OriginatorReference is a reference allocated by the originating
+	// organisation (bext OriginatorReference).
+	OriginatorReference string
+
+	// OriginationDate is the date of creation, in the format "yyyy-mm-dd"
+	// (bext OriginationDate).
+	OriginationDate string
+
+	// OriginationTime is the time of creation, in the format "hh:mm:ss"
+	// (bext OriginationTime).
+	OriginationTime string
+
+	// TimeReference is the timecode of the first sample, counted in samples
+	// since midnight (bext TimeReference).
+	TimeReference uint64
+
+	// UMID is the SMPTE UMID of the sound sequence, as a hexadecimal string
+	// (bext UMID).
+	UMID string
+
+	// CodingHistory describes the coding processes applied to the audio
+	// data (bext CodingHistory).
+	CodingHistory string
+
+	// Loudness (optional) contains the loudness statistics from a version 2
+	// bext chunk.
+	Loudness *BWFLoudness
+
+	// Artist is the name of the artist (INFO IART).
+	Artist string
+
+	// Album is the name of the product or album (INFO IPRD).
+	Album string
+
+	// Comment is a comment about the recording (INFO ICMT, iXML NOTE).
+	Comment string
+
+	// Scene is the name of the scene (iXML SCENE).
+	Scene string
+
+	// Take is the take number (iXML TAKE).
+	Take int
+
+	// Tape is the name of the tape or roll (iXML TAPE).
+	Tape string
+}
+
+// BWFLoudness holds loudness statistics as defined in EBU R 128.
+type BWFLoudness struct {
+	// Value is the integrated loudness in LUFS.
+	Value float64
+
+	// Range is the loudness range in LU.
+	Range float64
+
+	// MaxTruePeakLevel is the maximum true peak level in dBTP.
+	MaxTruePeakLevel float64
+
+	// MaxMomentaryLoudness is the highest momentary loudness in LUFS.
+	MaxMomentaryLoudness float64
+
+	// MaxShortTermLoudness is the highest short-term loudness in LUFS.
+	MaxShortTermLoudness float64
+}
+
+// SetBWF stores broadcast audio metadata in the packet.
+//
+// The bext fields are stored in the Adobe bext namespace
+// ("http://ns.adobe.com/bwf/bext/1.0/").  This namespace predates version 2
+// of EBU Tech 3285 and has no loudness properties, so the loudness
+// statistics are stored in the private namespace
+// "http://ns.seehuhn.de/xmp/bwf-loudness/1.0/" (prefix "bextLoudness"),
+// using the field names from EBU Tech 3285.  The remaining fields are mapped to the Dynamic Media namespace: Artist to
+// xmpDM:artist, Album to xmpDM:album, Comment to xmpDM:logComment, Scene to
+// xmpDM:scene, Take to xmpDM:takeNumber, and Tape to xmpDM:tapeName.
+//
+// Empty fields cause the corresponding properties to be removed.
+func (p *Packet) SetBWF(b *BWF) {
+	p.RegisterPrefix(bextNamespace, "bext")
+	p.RegisterPrefix(bwfLoudnessNamespace, "bextLoudness")
+	p.RegisterPrefix(dmNamespace, "xmpDM")
+
+	setText := func(ns, name, val string) {
+		if val == "" {
+			p.ClearValue(ns, name)
+		} else {
+			p.SetValue(ns, name, NewText(val))
+		}
+	}
+	setReal := func(ns, name string, val float64, ok bool) {
+		if !ok {
+			p.ClearValue(ns, name)
+		} else {
+			p.SetValue(ns, name, Real{V: val})
+		}
+	}
+
+	setText(bextNamespace, "description", b.Description)
+	setText(bextNamespace, "originator", b.Originator)
+	setText(bextNamespace, "originatorReference", b.OriginatorReference)
+	setText(bextNamespace, "originationDate", b.OriginationDate)
+	setText(bextNamespace, "originationTime", b.OriginationTime)
+	if b.TimeReference != 0 {
+		setText(bextNamespace, "timeReference", strconv.FormatUint(b.TimeReference, 10))
+	} else {
+		p.ClearValue(bextNamespace, "timeReference")
+	}
+	setText(bextNamespace, "umid", b.UMID)
+	setText(bextNamespace, "codingHistory", b.CodingHistory)
+
+	l := b.Loudness
+	hasLoudness := l != nil
+	if l == nil {
+		l = &BWFLoudness{}
+	}
+	setReal(bwfLoudnessNamespace, "loudnessValue", l.Value, hasLoudness)
+	setReal(bwfLoudnessNamespace, "loudnessRange", l.Range, hasLoudness)
+	setReal(bwfLoudnessNamespace, "maxTruePeakLevel", l.MaxTruePeakLevel, hasLoudness)
+	setReal(bwfLoudnessNamespace, "maxMomentaryLoudness", l.MaxMomentaryLoudness, hasLoudness)
+	setReal(bwfLoudnessNamespace, "maxShortTermLoudness", l.MaxShortTermLoudness, hasLoudness)
+
+	setText(dmNamespace, "artist", b.Artist)
+	setText(dmNamespace, "album", b.Album)
+	setText(dmNamespace, "logComment", b.Comment)
+	setText(dmNamespace, "scene", b.Scene)
+	if b.Take != 0 {
+		p.SetValue(dmNamespace, "takeNumber", Integer{V: int64(b.Take)})
+	} else {
+		p.ClearValue(dmNamespace, "takeNumber")
+	}
+	setText(dmNamespace, "tapeName", b.Tape)
+}
+
+// GetBWF extracts broadcast audio metadata from the packet.
+// This is the inverse of [Packet.SetBWF].
+func (p *Packet) GetBWF() *BWF {
+	getText := func(ns, name string) string {
+		v, _ := PacketGetValue[Text](p, ns, name)
+		return v.V
+	}
+	getReal := func(ns, name string) (float64, bool) {
+		v, err := PacketGetValue[Real](p, ns, name)
+		return v.V, err == nil
+	}
+
+	b := &BWF{
+		Description:         getText(bextNamespace, "description"),
+		Originator:          getText(bextNamespace, "originator"),
+		OriginatorReference: getText(bextNamespace, "originatorReference"),
+		OriginationDate:     getText(bextNamespace, "originationDate"),
+		OriginationTime:     getText(bextNamespace, "originationTime"),
+		UMID:                getText(bextNamespace, "umid"),
+		CodingHistory:       getText(bextNamespace, "codingHistory"),
+		Artist:              getText(dmNamespace, "artist"),
+		Album:               getText(dmNamespace, "album"),
+		Comment:             getText(dmNamespace, "logComment"),
+		Scene:               getText(dmNamespace, "scene"),
+		Tape:                getText(dmNamespace, "tapeName"),
+	}
+	ref := strings.TrimSpace(getText(bextNamespace, "timeReference"))
+	b.TimeReference, _ = strconv.ParseUint(ref, 10, 64)
+	if take, err := PacketGetValue[Integer](p, dmNamespace, "takeNumber"); err == nil {
+		b.Take = int(take.V)
+	}
+
+	l := &BWFLoudness{}
+	var found, ok bool
+	l.Value, ok = getReal(bwfLoudnessNamespace, "loudnessValue")
+	found = found || ok
+	l.Range, ok = getReal(bwfLoudnessNamespace, "loudnessRange")
+	found = found || ok
+	l.MaxTruePeakLevel, ok = getReal(bwfLoudnessNamespace, "maxTruePeakLevel")
+	found = found || ok
+	l.MaxMomentaryLoudness, ok = getReal(bwfLoudnessNamespace, "maxMomentaryLoudness")
+	found = found || ok
+	l.MaxShortTermLoudness, ok = getReal(bwfLoudnessNamespace, "maxShortTermLoudness")
+	found = found || ok
+	if found {
+		b.Loudness = l
+	}
+
+	return b
+}
+
+const (
+	bextNamespace        = "http://ns.adobe.com/bwf/bext/1.0/"
+	bwfLoudnessNamespace = "http://ns.seehuhn.de/xmp/bwf-loudness/1.0/"
+)
@@ -0,0 +1,83 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBWF(t *testing.T) {
+	b1 := &BWF{
+		Description:     "Interview, take 2",
+		Originator:      "Field Recorder 3000",
+		OriginationDate: "2024-03-01",
+		OriginationTime: "10:15:00",
+		TimeReference:   1764000000,
+		CodingHistory:   "A=PCM,F=48000,W=24,M=stereo,T=original\r\n",
+		Loudness: &BWFLoudness{
+			Value:            -23,
+			Range:            7.5,
+			MaxTruePeakLevel: -1.25,
+		},
+		Artist:  "Jane Doe",
+		Comment: "wind noise at 01:20",
+		Scene:   "12",
+		Take:    2,
+	}
+
+	p1 := NewPacket()
+	p1.SetBWF(b1)
+
+	buf := &bytes.Buffer{}
+	err := p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Adobe's bext namespace does not define the loudness fields.
+	if _, ok := p2.Properties[xml.Name{Space: bextNamespace, Local: "loudnessValue"}]; ok {
+		t.Error("loudness stored in the bext namespace")
+	}
+	if _, ok := p2.Properties[xml.Name{Space: bwfLoudnessNamespace, Local: "loudnessValue"}]; !ok {
+		t.Error("loudness value not found")
+	}
+
+	b2 := p2.GetBWF()
+	if d := cmp.Diff(b1, b2); d != "" {
+		t.Errorf("BWF metadata differs (-want +got):\n%s", d)
+	}
+
+	dm := &DynamicMedia{}
+	p2.Get(dm)
+	if dm.Artist.V != "Jane Doe" || dm.TakeNumber.V != 2 {
+		t.Errorf("unexpected xmpDM values: %q %d", dm.Artist.V, dm.TakeNumber.V)
+	}
+
+	// Clearing fields removes the properties.
+	p2.SetBWF(&BWF{})
+	if len(p2.Properties) != 0 {
+		t.Errorf("properties not removed: %v", p2.Properties)
+	}
+}
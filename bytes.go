@@ -0,0 +1,78 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"errors"
+)
+
+// The functions in this file operate on byte slices and do not access the
+// file system.  They can be used in environments without file system
+// access, for example in web browsers via WebAssembly.
+
+// ReadBytes decodes an XMP packet from a byte slice.
+func ReadBytes(data []byte) (*Packet, error) {
+	return Read(bytes.NewReader(data))
+}
+
+// Bytes returns the serialized form of the packet.
+func (p *Packet) Bytes(opt *PacketOptions) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := p.Write(buf, opt)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FindPacket locates an XMP packet embedded in the contents of a file, for
+// example a JPEG, TIFF or PDF file.  The packet is found by searching for
+// the packet wrapper described in section 7.3 of ISO 16684-1:2011.  The
+// returned slice shares storage with data.
+//
+// If no packet is found, [ErrNoPacket] is returned.
+func FindPacket(data []byte) ([]byte, error) {
+	start := bytes.Index(data, []byte("<?xpacket begin="))
+	if start < 0 {
+		return nil, ErrNoPacket
+	}
+	end := bytes.Index(data[start:], []byte("<?xpacket end="))
+	if end < 0 {
+		return nil, ErrNoPacket
+	}
+	end += start
+	tail := bytes.Index(data[end:], []byte("?>"))
+	if tail < 0 {
+		return nil, ErrNoPacket
+	}
+	return data[start : end+tail+2], nil
+}
+
+// ExtractPacket locates and decodes an XMP packet embedded in the contents
+// of a file.  See [FindPacket] for details.
+func ExtractPacket(data []byte) (*Packet, error) {
+	body, err := FindPacket(data)
+	if err != nil {
+		return nil, err
+	}
+	return ReadBytes(body)
+}
+
+// ErrNoPacket is returned by [FindPacket] and [ExtractPacket] if no XMP
+// packet is found.
+var ErrNoPacket = errors.New("no XMP packet found")
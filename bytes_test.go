@@ -0,0 +1,64 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFindPacket(t *testing.T) {
+	packet := `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>` +
+		`<x:xmpmeta xmlns:x="adobe:ns:meta/"></x:xmpmeta>` +
+		`<?xpacket end="w"?>`
+	data := []byte("\xff\xd8\xff\xe1junk" + packet + "\xff\xd9")
+	got, err := FindPacket(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != packet {
+		t.Errorf("got %q", got)
+	}
+
+	_, err = FindPacket([]byte("no packet here"))
+	if err != ErrNoPacket {
+		t.Errorf("expected ErrNoPacket, got %v", err)
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	p := NewPacket()
+	p.SetValue("http://purl.org/dc/elements/1.1/", "source", NewText("test"))
+	data, err := p.Bytes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	embedded := append([]byte("prefix"), data...)
+	embedded = append(embedded, "suffix"...)
+	q, err := ExtractPacket(embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data2, err := q.Bytes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, data2) {
+		t.Errorf("round trip mismatch:\n%s\n%s", data, data2)
+	}
+}
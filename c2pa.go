@@ -0,0 +1,106 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"errors"
+	"strings"
+)
+
+// ContentCredentials represents the XMP properties which reference content
+// credentials, i.e. C2PA manifests.
+//
+// The C2PA specification uses the dcterms:provenance property to point to
+// the active manifest.  For manifests embedded in the same file this is a
+// JUMBF URI of the form "self#jumbf=/c2pa/<label>", for remote manifests
+// it is an HTTP(S) URL.  The ingredients of the manifest are linked to the
+// xmpMM:DocumentID and xmpMM:InstanceID of the ingredient documents; these
+// links are stored in the xmpMM:Ingredients array, see
+// [Packet.AddIngredient].
+//
+// See section 11.3 of the C2PA Technical Specification, version 1.3.
+type ContentCredentials struct {
+	_ Namespace `xmp:"http://purl.org/dc/terms/"`
+	_ Prefix    `xmp:"dcterms"`
+
+	// Provenance is the URI of the active C2PA manifest.
+	Provenance Text `xmp:"provenance"`
+}
+
+// SetEmbeddedManifest sets the provenance URI to refer to the C2PA manifest
+// with the given label, stored in the same file.  The label typically has
+// the form "urn:uuid:...".
+func (c *ContentCredentials) SetEmbeddedManifest(label string) {
+	c.Provenance = NewText(c2paSelfPrefix + label)
+}
+
+// EmbeddedManifest returns the label of the referenced C2PA manifest, if
+// the manifest is stored in the same file.  The second return value
+// indicates whether an embedded manifest is referenced.
+func (c *ContentCredentials) EmbeddedManifest() (string, bool) {
+	label, ok := strings.CutPrefix(c.Provenance.V, c2paSelfPrefix)
+	if !ok || label == "" {
+		return "", false
+	}
+	return label, true
+}
+
+// IsRemote returns true if the provenance URI refers to a manifest stored
+// outside the current file.
+func (c *ContentCredentials) IsRemote() bool {
+	return strings.HasPrefix(c.Provenance.V, "http://") ||
+		strings.HasPrefix(c.Provenance.V, "https://")
+}
+
+// AddIngredient adds a reference to an ingredient document to the
+// xmpMM:Ingredients array of the packet.  The reference contains the
+// document ID and instance ID of the ingredient, which must have an
+// xmpMM:InstanceID.  If the array already contains a reference with the
+// same instance ID, the reference is replaced.
+func (p *Packet) AddIngredient(ingredient *Packet) error {
+	instanceID, err := PacketGetValue[GUID](ingredient, mmNamespace, "InstanceID")
+	if err != nil || instanceID.IsZero() {
+		return errMissingInstanceID
+	}
+	documentID, err := PacketGetValue[GUID](ingredient, mmNamespace, "DocumentID")
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	ref := ResourceRef{DocumentID: documentID, InstanceID: instanceID}
+
+	ingredients, err := PacketGetValue[UnorderedArray[ResourceRef]](p, mmNamespace, "Ingredients")
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	replaced := false
+	for i, old := range ingredients.V {
+		if old.InstanceID.V == instanceID.V {
+			ingredients.V[i] = ref
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		ingredients.V = append(ingredients.V, ref)
+	}
+	p.SetValue(mmNamespace, "Ingredients", ingredients)
+	return nil
+}
+
+const c2paSelfPrefix = "self#jumbf=/c2pa/"
+
+const dcTermsNamespace = "http://purl.org/dc/terms/"
@@ -0,0 +1,139 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContentCredentials(t *testing.T) {
+	const label = "urn:uuid:b1c5e3a2-6f1d-4d2a-9d7e-8a3c2f1b0e9d"
+
+	c1 := &ContentCredentials{}
+	c1.SetEmbeddedManifest(label)
+	if c1.IsRemote() {
+		t.Error("embedded manifest reported as remote")
+	}
+
+	p := NewPacket()
+	err := p.Set(c1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<dcterms:provenance>self#jumbf=/c2pa/"+label+"</dcterms:provenance>")) {
+		t.Errorf("provenance not found in output:\n%s", buf.Bytes())
+	}
+
+	p, err = Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2 := &ContentCredentials{}
+	p.Get(c2)
+	got, ok := c2.EmbeddedManifest()
+	if !ok || got != label {
+		t.Errorf("unexpected manifest label %q", got)
+	}
+
+	c3 := &ContentCredentials{Provenance: NewText("https://example.com/manifest.c2pa")}
+	if _, ok := c3.EmbeddedManifest(); ok || !c3.IsRemote() {
+		t.Error("remote manifest not recognised")
+	}
+}
+
+func TestAddIngredient(t *testing.T) {
+	ingredient := NewPacket()
+	err := ingredient.Set(&MediaManagement{
+		DocumentID: NewText("xmp.did:0001"),
+		InstanceID: NewText("xmp.iid:0002"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPacket()
+	err = p.AddIngredient(ingredient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.AddIngredient(ingredient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddIngredient(NewPacket()); err != errMissingInstanceID {
+		t.Errorf("expected errMissingInstanceID, got %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = p.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mm := &MediaManagement{}
+	p.Get(mm)
+	want := []ResourceRef{{
+		DocumentID: GUID{V: "xmp.did:0001"},
+		InstanceID: GUID{V: "xmp.iid:0002"},
+	}}
+	if d := cmp.Diff(want, mm.Ingredients.V); d != "" {
+		t.Errorf("wrong ingredients (-want +got):\n%s", d)
+	}
+}
+
+func TestManifestItem(t *testing.T) {
+	mm1 := &MediaManagement{
+		Manifest: UnorderedArray[ManifestItem]{V: []ManifestItem{{
+			LinkForm:          NewText("EmbedByReference"),
+			PlacedXResolution: Real{V: 300},
+			Reference: ResourceRef{
+				FilePath:   URI{V: "logo.png"},
+				InstanceID: GUID{V: "xmp.iid:0003"},
+			},
+		}}},
+	}
+	p := NewPacket()
+	err := p.Set(mm1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mm2 := &MediaManagement{}
+	p.Get(mm2)
+	if d := cmp.Diff(mm1.Manifest, mm2.Manifest); d != "" {
+		t.Errorf("round trip failed (-want +got):\n%s", d)
+	}
+}
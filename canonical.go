@@ -0,0 +1,284 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"seehuhn.de/go/xmp/jvxml"
+)
+
+// CanonicalPolicy describes the rules used by [Packet.Canonical] to
+// serialize a packet.  The same packet and policy always give the same
+// bytes, so that the output can be hashed or signed.  Different signing
+// profiles can be implemented by choosing different policies.
+type CanonicalPolicy struct {
+	// Exclude (optional) selects properties which are omitted from the
+	// output, for example properties which change every time a file is
+	// saved.
+	Exclude func(xml.Name) bool
+
+	// Indent is the string used to indent nested elements.  If this is
+	// empty, no white space is written between elements.
+	Indent string
+
+	// AttributeOrder (optional) is used to sort the attributes of every
+	// property element.  The function must return a negative number if a
+	// comes before b, a positive number if a comes after b, and zero
+	// otherwise.  If this is nil, the attributes are written in the order
+	// chosen by [Packet.Write].
+	AttributeOrder func(a, b xml.Name) int
+
+	// DefaultPrefixes causes namespace prefixes registered in the packet to
+	// be ignored.  Only the well-known prefixes and generated prefixes are
+	// used, so that the output does not depend on the prefixes found in
+	// the source of the packet.
+	DefaultPrefixes bool
+
+	// KeepComments causes the comments and processing instructions attached
+	// to the packet to be included in the output.  By default, they are
+	// omitted.
+	KeepComments bool
+}
+
+// DefaultCanonicalPolicy is the policy used by [Packet.Canonical] if no
+// policy is given.  Volatile properties (see [IsVolatile]) are excluded,
+// no white space is added between elements, attributes are sorted by
+// namespace and local name, and the default namespace prefixes are used.
+var DefaultCanonicalPolicy = &CanonicalPolicy{
+	Exclude:         IsVolatile,
+	AttributeOrder:  CompareNames,
+	DefaultPrefixes: true,
+}
+
+// CompareNames orders XML names by namespace and then by local name.
+// It can be used as the AttributeOrder of a [CanonicalPolicy].
+func CompareNames(a, b xml.Name) int {
+	if c := strings.Compare(a.Space, b.Space); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Local, b.Local)
+}
+
+// Canonical returns the serialized form of the packet, following the
+// given policy.  If policy is nil, [DefaultCanonicalPolicy] is used.
+//
+// Values are normalized before they are written:  structure fields and
+// qualifiers are written in sorted order, ignoring [RawStruct.Order], the
+// scheme and host of URLs are converted to lower case, and language tags
+// use the letter case recommended by BCP 47.  If the policy sets
+// DefaultPrefixes and does not set KeepComments, as [DefaultCanonicalPolicy]
+// does, packets which are equal according to [Packet.Equal] have the same
+// canonical form.
+func (p *Packet) Canonical(policy *CanonicalPolicy) ([]byte, error) {
+	if policy == nil {
+		policy = DefaultCanonicalPolicy
+	}
+
+	// Differences which are ignored when packets are compared are
+	// normalized here.
+	q := *p
+	q.About = canonicalURL(p.About)
+	q.Properties = make(map[xml.Name]Raw, len(p.Properties))
+	for name, val := range p.Properties {
+		if policy.Exclude == nil || !policy.Exclude(name) {
+			q.Properties[name] = canonicalRaw(val)
+		}
+	}
+	if policy.DefaultPrefixes {
+		q.nsToPrefix = maps.Clone(defaultPrefix)
+	}
+	if !policy.KeepComments {
+		q.LeadingComments = nil
+		q.TrailingComments = nil
+		q.PropertyComments = nil
+		q.ProcInsts = nil
+	}
+
+	buf := &bytes.Buffer{}
+	e, err := q.newEncoder(buf, &PacketOptions{
+		Pretty: policy.Indent != "",
+		Indent: policy.Indent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.writeProperties(e, policy.AttributeOrder)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalRaw returns a copy of val in canonical form.  Values which are
+// equal according to [EqualRaw] have the same canonical form:  The Order
+// fields of all structures are cleared, so that fields are written in
+// sorted order, the scheme and host of URLs are converted to lower case,
+// language tags are converted to the case recommended by BCP 47, and
+// qualifiers are sorted.
+func canonicalRaw(val Raw) Raw {
+	switch val := val.(type) {
+	case Text:
+		return Text{V: val.V, Q: canonicalQ(val.Q)}
+	case URL:
+		return URL{V: canonicalURL(val.V), Q: canonicalQ(val.Q)}
+	case RawStruct:
+		res := RawStruct{
+			Value: make(map[xml.Name]Raw, len(val.Value)),
+			Q:     canonicalQ(val.Q),
+		}
+		for name, v := range val.Value {
+			res.Value[name] = canonicalRaw(v)
+		}
+		return res
+	case RawArray:
+		res := RawArray{
+			Value: make([]Raw, len(val.Value)),
+			Kind:  val.Kind,
+			Q:     canonicalQ(val.Q),
+		}
+		for i, v := range val.Value {
+			res.Value[i] = canonicalRaw(v)
+		}
+		return res
+	}
+	return val
+}
+
+// canonicalQ returns a copy of q, where all qualifier values are in
+// canonical form.  Qualifiers are sorted by name, and qualifiers with the
+// same name are sorted by value.
+func canonicalQ(q Q) Q {
+	if len(q) == 0 {
+		return q
+	}
+	res := make(Q, len(q))
+	for i, qual := range q {
+		v := canonicalRaw(qual.Value)
+		if t, ok := v.(Text); ok && qual.Name == nameXMLLang {
+			v = Text{V: canonicalLangCase(t.V), Q: t.Q}
+		}
+		res[i] = Qualifier{Name: qual.Name, Value: v}
+	}
+	slices.SortStableFunc(res, func(a, b Qualifier) int {
+		if c := CompareNames(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(rawKey(a.Value), rawKey(b.Value))
+	})
+	return res
+}
+
+// canonicalURL returns a copy of u, with scheme and host converted to lower
+// case.
+func canonicalURL(u *url.URL) *url.URL {
+	if u == nil {
+		return nil
+	}
+	res := *u
+	res.Scheme = strings.ToLower(res.Scheme)
+	res.Host = strings.ToLower(res.Host)
+	return &res
+}
+
+// canonicalLangCase converts a language tag to the case recommended in
+// section 2.1.1 of RFC 5646, for example "de-DE" or "zh-Hant-TW".  Only the
+// case of the letters is changed.
+func canonicalLangCase(tag string) string {
+	parts := strings.Split(tag, "-")
+	for i, part := range parts {
+		if i > 0 && len(parts[i-1]) == 1 {
+			// everything after a singleton is written in lower case
+			for j := i; j < len(parts); j++ {
+				parts[j] = strings.ToLower(parts[j])
+			}
+			break
+		}
+		switch {
+		case i > 0 && len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		case i > 0 && len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// rawKey returns a string which identifies a value in canonical form.  Two
+// canonical values have the same key if and only if they are equal.
+func rawKey(val Raw) string {
+	b := &strings.Builder{}
+	writeRawKey(b, val)
+	return b.String()
+}
+
+func writeRawKey(b *strings.Builder, val Raw) {
+	var q Q
+	switch val := val.(type) {
+	case Text:
+		b.WriteString("T" + strconv.Quote(val.V))
+		q = val.Q
+	case URL:
+		b.WriteString("U" + strconv.Quote(normalizedURL(val.V)))
+		q = val.Q
+	case RawStruct:
+		names := maps.Keys(val.Value)
+		sortNames(names)
+		b.WriteString("S{")
+		for _, name := range names {
+			b.WriteString(strconv.Quote(name.Space) + strconv.Quote(name.Local))
+			writeRawKey(b, val.Value[name])
+		}
+		b.WriteString("}")
+		q = val.Q
+	case RawArray:
+		b.WriteString("A" + strconv.Itoa(int(val.Kind)) + "[")
+		for _, v := range val.Value {
+			writeRawKey(b, v)
+		}
+		b.WriteString("]")
+		q = val.Q
+	}
+	b.WriteString("Q[")
+	for _, qual := range q {
+		b.WriteString(strconv.Quote(qual.Name.Space) + strconv.Quote(qual.Name.Local))
+		writeRawKey(b, qual.Value)
+	}
+	b.WriteString("]")
+}
+
+// sortAttrs sorts the attributes of a start element in place.
+func sortAttrs(t jvxml.Token, cmp func(a, b xml.Name) int) {
+	var attrs []xml.Attr
+	switch t := t.(type) {
+	case xml.StartElement:
+		attrs = t.Attr
+	case jvxml.EmptyElement:
+		attrs = t.Attr
+	}
+	slices.SortStableFunc(attrs, func(a, b xml.Attr) int {
+		return cmp(a.Name, b.Name)
+	})
+}
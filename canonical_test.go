@@ -0,0 +1,201 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	makePacket := func(prefix, instanceID string) *Packet {
+		p := NewPacket()
+		p.RegisterPrefix(ns, prefix)
+		p.SetValue(ns, "s", NewText("value"))
+		p.SetValue(mmNamespace, "InstanceID", GUID{V: instanceID})
+		return p
+	}
+	p1 := makePacket("a", "xmp.iid:1")
+	p2 := makePacket("b", "xmp.iid:2")
+
+	c1, err := p1.Canonical(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := p2.Canonical(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(c1, c2) {
+		t.Errorf("canonical forms differ:\n%s\n%s", c1, c2)
+	}
+	if bytes.Contains(c1, []byte("InstanceID")) {
+		t.Errorf("volatile property not excluded:\n%s", c1)
+	}
+	if bytes.Contains(c1, []byte("\n\t")) {
+		t.Errorf("unexpected white space:\n%s", c1)
+	}
+
+	// Without exclusions and with registered prefixes, the output is the
+	// same as written by Packet.Write.
+	c3, err := p1.Canonical(&CanonicalPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(c3, buf.Bytes()) {
+		t.Errorf("empty policy differs from Write:\n%s\n%s", c3, buf.Bytes())
+	}
+}
+
+func TestCanonicalPolicy(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	p := NewPacket()
+	p.Properties[xml.Name{Space: ns, Local: "s"}] = RawStruct{
+		Value: map[xml.Name]Raw{
+			{Space: ns, Local: "a"}: Text{V: "1"},
+			{Space: ns, Local: "b"}: Text{V: "2"},
+		},
+	}
+	p.SetValue(ns, "secret", NewText("x"))
+
+	reverse := func(a, b xml.Name) int { return -CompareNames(a, b) }
+	out, err := p.Canonical(&CanonicalPolicy{
+		Exclude:        func(name xml.Name) bool { return name.Local == "secret" },
+		Indent:         "  ",
+		AttributeOrder: reverse,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(out)
+	if strings.Contains(s, "secret") {
+		t.Errorf("excluded property present:\n%s", s)
+	}
+	if !strings.Contains(s, "\n  ") {
+		t.Errorf("missing indentation:\n%s", s)
+	}
+	if !strings.Contains(s, `test:b="2" test:a="1"`) {
+		t.Errorf("wrong attribute order:\n%s", s)
+	}
+}
+
+// TestCanonicalFieldOrder checks that the order of structure fields in the
+// source does not affect the canonical form.
+func TestCanonicalFieldOrder(t *testing.T) {
+	makePacket := func(fields string) *Packet {
+		in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about=""
+	xmlns:Iptc4xmpCore="http://iptc.org/std/Iptc4xmpCore/1.0/xmlns/">
+<Iptc4xmpCore:CreatorContactInfo rdf:parseType="Resource">` + fields + `</Iptc4xmpCore:CreatorContactInfo>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>`
+		p, err := Read(strings.NewReader(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	const city = `<Iptc4xmpCore:CiAdrCity xml:lang="en">Leeds</Iptc4xmpCore:CiAdrCity>`
+	const email = "<Iptc4xmpCore:CiEmailWork>a@b.c</Iptc4xmpCore:CiEmailWork>"
+	p1 := makePacket(city + email)
+	p2 := makePacket(email + city)
+	if d := Diff(p1, p2); len(d) != 0 {
+		t.Fatalf("packets differ: %v", d)
+	}
+
+	c1, err := p1.Canonical(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := p2.Canonical(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(c1, c2) {
+		t.Errorf("canonical forms differ:\n%s\n%s", c1, c2)
+	}
+}
+
+// TestCanonicalEqual checks that packets which are equal according to
+// Packet.Equal have the same canonical form.
+func TestCanonicalEqual(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	qName := xml.Name{Space: ns, Local: "q"}
+	makePacket := func(about, u, lang string, q1, q2 string) *Packet {
+		p := NewPacket()
+		p.About, _ = url.Parse(about)
+		uu, _ := url.Parse(u)
+		p.Properties[xml.Name{Space: ns, Local: "u"}] = URL{
+			V: uu,
+			Q: Q{{Name: nameXMLLang, Value: Text{V: lang}}},
+		}
+		p.Properties[xml.Name{Space: ns, Local: "t"}] = Text{
+			V: "x",
+			Q: Q{{Name: qName, Value: Text{V: q1}}, {Name: qName, Value: Text{V: q2}}},
+		}
+		return p
+	}
+	p1 := makePacket("HTTP://Example.COM/", "HTTP://Example.COM/a", "de-DE", "a", "b")
+	p2 := makePacket("http://example.com/", "http://example.com/a", "de-de", "b", "a")
+	if !p1.Equal(p2) {
+		t.Fatal("packets are not equal")
+	}
+
+	c1, err := p1.Canonical(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := p2.Canonical(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(c1, c2) {
+		t.Errorf("canonical forms differ:\n%s\n%s", c1, c2)
+	}
+	if !bytes.Contains(c1, []byte(`"de-DE"`)) {
+		t.Errorf("language tag not normalized:\n%s", c1)
+	}
+}
+
+func TestCanonicalLangCase(t *testing.T) {
+	cases := map[string]string{
+		"de-de":          "de-DE",
+		"EN":             "en",
+		"zh-hant-tw":     "zh-Hant-TW",
+		"x-default":      "x-default",
+		"en-GB-x-OED":    "en-GB-x-oed",
+		"SGN-BE-FR":      "sgn-BE-FR",
+		"es-419":         "es-419",
+		"az-LATN-x-LATN": "az-Latn-x-latn",
+	}
+	for in, want := range cases {
+		if got := canonicalLangCase(in); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
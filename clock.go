@@ -0,0 +1,86 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+// now returns the current time, using the clock of the packet if set.
+func (p *Packet) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// newID returns a new unique identifier, using the ID source of the packet if
+// set.
+func (p *Packet) newID() string {
+	if p.NewID != nil {
+		return p.NewID()
+	}
+	return newUUID()
+}
+
+// NewGUID returns a new, globally unique identifier of the form
+// "uuid:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx".
+//
+// The identifier is obtained from p.NewID, if set.
+func (p *Packet) NewGUID() GUID {
+	return GUID{V: "uuid:" + p.newID()}
+}
+
+// ContentGUID returns an identifier derived from the SHA-256 hash of the
+// data read from r.  The result has the form "xmp.did:sha256-" followed by
+// 64 hexadecimal digits.
+//
+// Unlike [Packet.NewGUID], this gives the same identifier for identical
+// content, so that re-ingesting the same file on different machines yields
+// the same xmpMM:DocumentID.
+func ContentGUID(r io.Reader) (GUID, error) {
+	h := sha256.New()
+	_, err := io.Copy(h, r)
+	if err != nil {
+		return GUID{}, err
+	}
+	return GUID{V: "xmp.did:sha256-" + hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// UpdateMetadataDate sets the xmp:MetadataDate property to the current time.
+//
+// The current time is obtained from p.Now, if set.
+func (p *Packet) UpdateMetadataDate() {
+	p.SetValue(basicNamespace, "MetadataDate", NewDate(p.now()))
+}
+
+// newUUID returns a random (version 4) UUID in its canonical string form.
+func newUUID() string {
+	var u [16]byte
+	_, err := rand.Read(u[:])
+	if err != nil {
+		panic(err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
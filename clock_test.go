@@ -0,0 +1,74 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFixedClock(t *testing.T) {
+	fixed := time.Date(2024, 5, 17, 12, 30, 0, 0, time.UTC)
+
+	p := NewPacket()
+	p.Now = func() time.Time { return fixed }
+	p.NewID = func() string { return "1234" }
+
+	p.UpdateMetadataDate()
+	basic := &Basic{}
+	p.Get(basic)
+	if !basic.MetadataDate.V.Equal(fixed) {
+		t.Errorf("unexpected MetadataDate %s", basic.MetadataDate.V)
+	}
+
+	if id := p.NewGUID(); id.V != "uuid:1234" {
+		t.Errorf("unexpected GUID %q", id.V)
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	pat := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a := newUUID()
+	b := newUUID()
+	if !pat.MatchString(a) {
+		t.Errorf("malformed UUID %q", a)
+	}
+	if a == b {
+		t.Errorf("UUIDs are not unique: %q", a)
+	}
+}
+
+func TestContentGUID(t *testing.T) {
+	a, err := ContentGUID(strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "xmp.did:sha256-2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if a.V != want {
+		t.Errorf("got %q, want %q", a.V, want)
+	}
+
+	b, err := ContentGUID(strings.NewReader("hello!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.V == b.V {
+		t.Error("different content gives the same GUID")
+	}
+}
@@ -0,0 +1,73 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Xmpdump prints the contents of XMP sidecar files in a human-readable
+// form.
+//
+// Usage:
+//
+//	xmpdump [-color] [-width n] file.xmp ...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seehuhn.de/go/xmp"
+)
+
+func main() {
+	color := flag.Bool("color", false, "highlight the output using ANSI colors")
+	width := flag.Int("width", 0, "maximum length of text values (-1 for no limit)")
+	flag.Parse()
+
+	opt := &xmp.PrintOptions{
+		Color:       *color,
+		MaxValueLen: *width,
+	}
+	failed := false
+	for i, fname := range flag.Args() {
+		if i > 0 {
+			fmt.Println()
+		}
+		if flag.NArg() > 1 {
+			fmt.Println("# " + fname)
+		}
+		err := dump(fname, opt)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func dump(fname string, opt *xmp.PrintOptions) error {
+	fd, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	p, err := xmp.Read(fd)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fname, err)
+	}
+	return p.Print(os.Stdout, opt)
+}
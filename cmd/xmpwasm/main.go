@@ -0,0 +1,66 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build js && wasm
+
+// Xmpwasm exposes XMP decoding to JavaScript when compiled to WebAssembly.
+// It registers a global function
+//
+//	xmpDump(data: Uint8Array): string
+//
+// which locates the XMP packet in the given file contents and returns a
+// human-readable listing of its properties.  On error, an exception is
+// thrown.
+//
+// Build with:
+//
+//	GOOS=js GOARCH=wasm go build -o xmp.wasm ./cmd/xmpwasm
+package main
+
+import (
+	"strings"
+	"syscall/js"
+
+	"seehuhn.de/go/xmp"
+)
+
+func main() {
+	js.Global().Set("xmpDump", js.FuncOf(dump))
+	select {}
+}
+
+func dump(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		panic(js.Global().Get("Error").New("xmpDump: expected one argument"))
+	}
+	data := make([]byte, args[0].Get("length").Int())
+	js.CopyBytesToGo(data, args[0])
+
+	p, err := xmp.ExtractPacket(data)
+	if err == xmp.ErrNoPacket {
+		p, err = xmp.ReadBytes(data)
+	}
+	if err != nil {
+		panic(js.Global().Get("Error").New(err.Error()))
+	}
+
+	buf := &strings.Builder{}
+	err = p.Print(buf, nil)
+	if err != nil {
+		panic(js.Global().Get("Error").New(err.Error()))
+	}
+	return buf.String()
+}
@@ -0,0 +1,279 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Store is a storage backend for a [Collection].  Packets are identified
+// by string keys, for example file paths or document IDs.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Load returns the packet stored under the given key.
+	// If the key is not present, [ErrNotFound] is returned.
+	Load(key string) (*Packet, error)
+
+	// Save stores a packet under the given key, replacing any previous
+	// packet with the same key.
+	Save(key string, p *Packet) error
+
+	// Delete removes the packet with the given key.
+	// Deleting a non-existent key is not an error.
+	Delete(key string) error
+
+	// Keys returns the keys of all stored packets, in sorted order.
+	Keys() ([]string, error)
+}
+
+// A Collection is a set of XMP packets, stored in a [Store].
+type Collection struct {
+	Store Store
+}
+
+// NewCollection returns a new collection which uses the given storage
+// backend.
+func NewCollection(s Store) *Collection {
+	return &Collection{Store: s}
+}
+
+// Put adds a packet to the collection, using the given key.
+func (c *Collection) Put(key string, p *Packet) error {
+	return c.Store.Save(key, p)
+}
+
+// PutDocument adds a packet to the collection, using the value of the
+// xmpMM:DocumentID property as the key.  The key is returned.
+func (c *Collection) PutDocument(p *Packet) (string, error) {
+	id, err := PacketGetValue[Text](p, mmNamespace, "DocumentID")
+	if err != nil || id.V == "" {
+		return "", errMissingDocumentID
+	}
+	return id.V, c.Store.Save(id.V, p)
+}
+
+// Get returns the packet with the given key.
+func (c *Collection) Get(key string) (*Packet, error) {
+	return c.Store.Load(key)
+}
+
+// Delete removes the packet with the given key from the collection.
+func (c *Collection) Delete(key string) error {
+	return c.Store.Delete(key)
+}
+
+// Keys returns the keys of all packets in the collection, in sorted order.
+func (c *Collection) Keys() ([]string, error) {
+	return c.Store.Keys()
+}
+
+// Find returns the keys of all packets which contain a value at the given
+// path for which match returns true.  If match is nil, all packets where
+// the path selects at least one value are returned.
+func (c *Collection) Find(path Path, match func(Raw) bool) ([]string, error) {
+	return c.FindContext(context.Background(), path, match)
+}
+
+// FindContext is like [Collection.Find], but the search is aborted with an
+// error if the context is cancelled or its deadline expires.
+func (c *Collection) FindContext(ctx context.Context, path Path, match func(Raw) bool) ([]string, error) {
+	keys, err := c.Store.Keys()
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p, err := c.Store.Load(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, val := range p.Select(path) {
+			if match == nil || match(val) {
+				res = append(res, key)
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// MemoryStore is a [Store] which keeps packets in memory.
+// Packets are stored by reference and must not be modified while they are
+// part of the store.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	packets map[string]*Packet
+}
+
+// NewMemoryStore returns a new, empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{packets: make(map[string]*Packet)}
+}
+
+// Load implements the [Store] interface.
+func (s *MemoryStore) Load(key string) (*Packet, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.packets[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return p, nil
+}
+
+// Save implements the [Store] interface.
+func (s *MemoryStore) Save(key string, p *Packet) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packets[key] = p
+	return nil
+}
+
+// Delete implements the [Store] interface.
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.packets, key)
+	return nil
+}
+
+// Keys implements the [Store] interface.
+func (s *MemoryStore) Keys() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.packets))
+	for key := range s.packets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// DirStore is a [Store] which keeps every packet in a separate .xmp file
+// inside a directory.  File names are derived from the keys by
+// percent-encoding all bytes other than lower-case letters, digits, "-"
+// and "_".  This keeps distinct keys apart on case-insensitive file
+// systems, and avoids characters which are not allowed in file names on
+// some systems.
+type DirStore struct {
+	Dir string
+
+	// Atomic enables atomic writes, so that other processes never see
+	// partially written files.  See [FileOptions] for details.
+	Atomic bool
+
+	mu sync.RWMutex
+}
+
+// NewDirStore returns a store which uses the given directory.
+// The directory is created if it does not exist.
+func NewDirStore(dir string) (*DirStore, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &DirStore{Dir: dir}, nil
+}
+
+// Load implements the [Store] interface.
+func (s *DirStore) Load(key string) (*Packet, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, err := ReadFile(s.fileName(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return p, err
+}
+
+// Save implements the [Store] interface.
+func (s *DirStore) Save(key string, p *Packet) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return WriteFile(s.fileName(key), p, &FileOptions{Atomic: s.Atomic})
+}
+
+// Delete implements the [Store] interface.
+func (s *DirStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := os.Remove(s.fileName(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Keys implements the [Store] interface.
+func (s *DirStore) Keys() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), dirStoreExt)
+		if !ok || e.IsDir() {
+			continue
+		}
+		key, err := url.PathUnescape(name)
+		if err != nil || escapeKey(key) != name {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *DirStore) fileName(key string) string {
+	return filepath.Join(s.Dir, escapeKey(key)+dirStoreExt)
+}
+
+// escapeKey returns the file name, without extension, used for a key.
+func escapeKey(key string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		}
+	}
+	return b.String()
+}
+
+const dirStoreExt = ".xmp"
+
+var errMissingDocumentID = errors.New("missing xmpMM:DocumentID")
@@ -0,0 +1,154 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCollection(t *testing.T) {
+	dirStore, err := NewDirStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"dir":    dirStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			c := NewCollection(store)
+
+			for i, kw := range []string{"cat", "dog", "cat"} {
+				p := NewPacket()
+				p.SetValue(mmNamespace, "DocumentID", NewText("xmp.did:"+string(rune('a'+i))))
+				subject := UnorderedArray[Text]{}
+				subject.Append(NewText(kw))
+				p.SetValue("http://purl.org/dc/elements/1.1/", "subject", subject)
+				_, err := c.PutDocument(p)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := c.Put("../other/file.jpg", NewPacket())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			keys, err := c.Keys()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []string{"../other/file.jpg", "xmp.did:a", "xmp.did:b", "xmp.did:c"}
+			if d := cmp.Diff(want, keys); d != "" {
+				t.Errorf("keys differ (-want +got):\n%s", d)
+			}
+
+			path, err := ParsePath("dc:subject")
+			if err != nil {
+				t.Fatal(err)
+			}
+			found, err := c.Find(path, func(val Raw) bool {
+				txt, ok := val.(Text)
+				return ok && txt.V == "cat"
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff([]string{"xmp.did:a", "xmp.did:c"}, found); d != "" {
+				t.Errorf("found keys differ (-want +got):\n%s", d)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = c.FindContext(ctx, path, nil)
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+
+			err = c.Delete("xmp.did:a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = c.Get("xmp.did:a")
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+// TestDirStoreFileName checks that keys which differ only in case, or which
+// contain characters not allowed in file names, are stored separately.
+func TestDirStoreFileName(t *testing.T) {
+	if got, want := escapeKey("xmp.did:Ab"), "xmp%2Edid%3A%41b"; got != want {
+		t.Errorf("escapeKey: got %q, want %q", got, want)
+	}
+
+	dir := t.TempDir()
+	store, err := NewDirStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{"uuid:ABC", "uuid:abc", "x/y\\z"}
+	for i, key := range keys {
+		p := NewPacket()
+		p.SetValue(mmNamespace, "DocumentID", NewText(key))
+		p.SetValue(mmNamespace, "VersionID", NewText(string(rune('1'+i))))
+		err := store.Save(key, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escape := regexp.MustCompile(`%[0-9A-F]{2}`)
+	for _, e := range entries {
+		plain := escape.ReplaceAllString(e.Name(), "")
+		if strings.ContainsAny(plain, ":/\\%ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			t.Errorf("unsafe file name %q", e.Name())
+		}
+	}
+
+	got, err := store.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(keys, got); d != "" {
+		t.Errorf("keys differ (-want +got):\n%s", d)
+	}
+	for _, key := range keys {
+		p, err := store.Load(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(NewText(key), p.Properties[xml.Name{Space: mmNamespace, Local: "DocumentID"}]); d != "" {
+			t.Errorf("%s: wrong packet (-want +got):\n%s", key, d)
+		}
+	}
+}
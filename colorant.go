@@ -0,0 +1,156 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// Colorant describes a colour used in a document, for example a process
+// colour or a spot colour.
+//
+// Which of the colour value fields are used depends on Mode: C, M, Y and
+// K (in percent) for "CMYK", R, G and B (0-255) for "RGB", and L (0-100),
+// A and B (-128 to 127) for "LAB".
+//
+// See section 8.2.2.2 of part 1 of the XMP specification (2012).
+type Colorant struct {
+	// SwatchName is the name of the swatch.
+	SwatchName Text
+
+	// Mode is the colour space of the colour values: "CMYK", "RGB", or
+	// "LAB".
+	Mode Text
+
+	// Type is "PROCESS" for process colours and "SPOT" for spot colours.
+	Type Text
+
+	// C, M, Y and K are the cyan, magenta, yellow and black components
+	// for CMYK colours.
+	C, M, Y, K Real
+
+	// Red, Green and Blue are the components of RGB colours.
+	Red, Green, Blue Integer
+
+	// L, A and B are the components of LAB colours.
+	L    Real
+	A, B Integer
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (c Colorant) IsZero() bool {
+	return c.SwatchName.IsZero() && c.Mode.IsZero() && c.Type.IsZero() &&
+		c.C.IsZero() && c.M.IsZero() && c.Y.IsZero() && c.K.IsZero() &&
+		c.Red.IsZero() && c.Green.IsZero() && c.Blue.IsZero() &&
+		c.L.IsZero() && c.A.IsZero() && c.B.IsZero() && len(c.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (c Colorant) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     c.Q,
+	}
+	setField(res, p, xmpGNamespace, "swatchName", c.SwatchName)
+	setField(res, p, xmpGNamespace, "mode", c.Mode)
+	setField(res, p, xmpGNamespace, "type", c.Type)
+	setField(res, p, xmpGNamespace, "cyan", c.C)
+	setField(res, p, xmpGNamespace, "magenta", c.M)
+	setField(res, p, xmpGNamespace, "yellow", c.Y)
+	setField(res, p, xmpGNamespace, "black", c.K)
+	setField(res, p, xmpGNamespace, "red", c.Red)
+	setField(res, p, xmpGNamespace, "green", c.Green)
+	setField(res, p, xmpGNamespace, "blue", c.Blue)
+	setField(res, p, xmpGNamespace, "L", c.L)
+	setField(res, p, xmpGNamespace, "A", c.A)
+	setField(res, p, xmpGNamespace, "B", c.B)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Colorant) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Colorant{
+		SwatchName: getField[Text](s, xmpGNamespace, "swatchName"),
+		Mode:       getField[Text](s, xmpGNamespace, "mode"),
+		Type:       getField[Text](s, xmpGNamespace, "type"),
+		C:          getField[Real](s, xmpGNamespace, "cyan"),
+		M:          getField[Real](s, xmpGNamespace, "magenta"),
+		Y:          getField[Real](s, xmpGNamespace, "yellow"),
+		K:          getField[Real](s, xmpGNamespace, "black"),
+		Red:        getField[Integer](s, xmpGNamespace, "red"),
+		Green:      getField[Integer](s, xmpGNamespace, "green"),
+		Blue:       getField[Integer](s, xmpGNamespace, "blue"),
+		L:          getField[Real](s, xmpGNamespace, "L"),
+		A:          getField[Integer](s, xmpGNamespace, "A"),
+		B:          getField[Integer](s, xmpGNamespace, "B"),
+		Q:          s.Q,
+	}, nil
+}
+
+// SwatchGroup is a named group of colorants, as used in
+// xmpTPg:SwatchGroups.
+type SwatchGroup struct {
+	// GroupName is the name of the group.
+	GroupName Text
+
+	// GroupType is the type of the group: 0 for a normal group, 1 for a
+	// colour group.
+	GroupType Integer
+
+	// Colorants lists the colours in the group.
+	Colorants OrderedArray[Colorant]
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (g SwatchGroup) IsZero() bool {
+	return g.GroupName.IsZero() && g.GroupType.IsZero() &&
+		g.Colorants.IsZero() && len(g.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (g SwatchGroup) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     g.Q,
+	}
+	setField(res, p, xmpGNamespace, "groupName", g.GroupName)
+	setField(res, p, xmpGNamespace, "groupType", g.GroupType)
+	setField(res, p, xmpGNamespace, "Colorants", g.Colorants)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (SwatchGroup) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return SwatchGroup{
+		GroupName: getField[Text](s, xmpGNamespace, "groupName"),
+		GroupType: getField[Integer](s, xmpGNamespace, "groupType"),
+		Colorants: getField[OrderedArray[Colorant]](s, xmpGNamespace, "Colorants"),
+		Q:         s.Q,
+	}, nil
+}
+
+const xmpGNamespace = "http://ns.adobe.com/xap/1.0/g/"
@@ -0,0 +1,151 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// ColorLabel is one of the color labels used by Adobe Lightroom and Adobe
+// Bridge.  The label is stored in the xmp:Label property.
+type ColorLabel int
+
+// These are the color labels supported by Lightroom and Bridge.
+const (
+	LabelNone ColorLabel = iota
+	LabelRed
+	LabelYellow
+	LabelGreen
+	LabelBlue
+	LabelPurple
+)
+
+func (c ColorLabel) String() string {
+	return c.Name(language.English)
+}
+
+// Name returns the name of the label in the given language.  If no
+// translation is available, the English name is returned.
+func (c ColorLabel) Name(lang language.Tag) string {
+	if c <= LabelNone || c > LabelPurple {
+		return ""
+	}
+	_, i, _ := labelMatcher.Match(lang)
+	return labelNames[labelLanguages[i]][c-1]
+}
+
+// Urgency returns the photoshop:Urgency value corresponding to the label,
+// using the table in [LabelUrgency].  The result is 0 if the label has no
+// corresponding urgency.
+func (c ColorLabel) Urgency() int {
+	return LabelUrgency[c]
+}
+
+// LabelUrgency maps color labels to photoshop:Urgency values.  Urgency
+// values range from 1 (most urgent) to 8 (least urgent), the value 0 means
+// "none".  The table can be modified to match the conventions of a
+// particular workflow.
+var LabelUrgency = map[ColorLabel]int{
+	LabelRed:    1,
+	LabelYellow: 2,
+	LabelGreen:  3,
+	LabelBlue:   4,
+	LabelPurple: 5,
+}
+
+// ColorLabelFromUrgency returns the color label corresponding to a
+// photoshop:Urgency value, using the table in [LabelUrgency].
+func ColorLabelFromUrgency(urgency int) ColorLabel {
+	for c, u := range LabelUrgency {
+		if u == urgency && urgency != 0 {
+			return c
+		}
+	}
+	return LabelNone
+}
+
+// ParseColorLabel converts an xmp:Label value into a color label.
+// Both the color names in all supported languages and the label names from
+// the default Bridge label set ("Select", "Second", "Approved", "Review",
+// "To Do") are recognized.  Case is ignored.
+func ParseColorLabel(s string) (ColorLabel, bool) {
+	s = strings.TrimSpace(s)
+	for _, names := range labelNames {
+		for i, name := range names {
+			if strings.EqualFold(s, name) {
+				return ColorLabel(i + 1), true
+			}
+		}
+	}
+	for i, name := range bridgeLabelNames {
+		if strings.EqualFold(s, name) {
+			return ColorLabel(i + 1), true
+		}
+	}
+	return LabelNone, false
+}
+
+// ColorLabel returns the color label of the resource, as stored in
+// xmp:Label.  The result is [LabelNone] if the label is missing or is not
+// a known color label.
+func (p *Packet) ColorLabel() ColorLabel {
+	label, err := PacketGetValue[Text](p, basicNamespace, "Label")
+	if err != nil {
+		return LabelNone
+	}
+	c, _ := ParseColorLabel(label.V)
+	return c
+}
+
+// SetColorLabel stores a color label in xmp:Label, using the label name in
+// the given language.  Localized versions of Lightroom use translated label
+// names, so lang should match the language of the target application.
+// Setting [LabelNone] removes the label.
+func (p *Packet) SetColorLabel(c ColorLabel, lang language.Tag) {
+	name := c.Name(lang)
+	if name == "" {
+		p.ClearValue(basicNamespace, "Label")
+		return
+	}
+	p.SetValue(basicNamespace, "Label", NewText(name))
+}
+
+var labelLanguages = []language.Tag{
+	language.English,
+	language.German,
+	language.French,
+	language.Spanish,
+	language.Italian,
+	language.Dutch,
+	language.Japanese,
+}
+
+var labelMatcher = language.NewMatcher(labelLanguages)
+
+var labelNames = map[language.Tag][5]string{
+	language.English:  {"Red", "Yellow", "Green", "Blue", "Purple"},
+	language.German:   {"Rot", "Gelb", "Grün", "Blau", "Lila"},
+	language.French:   {"Rouge", "Jaune", "Vert", "Bleu", "Violet"},
+	language.Spanish:  {"Rojo", "Amarillo", "Verde", "Azul", "Púrpura"},
+	language.Italian:  {"Rosso", "Giallo", "Verde", "Blu", "Viola"},
+	language.Dutch:    {"Rood", "Geel", "Groen", "Blauw", "Paars"},
+	language.Japanese: {"レッド", "イエロー", "グリーン", "ブルー", "パープル"},
+}
+
+var bridgeLabelNames = [5]string{"Select", "Second", "Approved", "Review", "To Do"}
@@ -0,0 +1,63 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestColorLabel(t *testing.T) {
+	p := NewPacket()
+	if c := p.ColorLabel(); c != LabelNone {
+		t.Errorf("got %v, want none", c)
+	}
+
+	p.SetColorLabel(LabelGreen, language.MustParse("de-CH"))
+	label, err := PacketGetValue[Text](p, basicNamespace, "Label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label.V != "Grün" {
+		t.Errorf("got label %q, want %q", label.V, "Grün")
+	}
+	if c := p.ColorLabel(); c != LabelGreen {
+		t.Errorf("got %v, want %v", c, LabelGreen)
+	}
+
+	p.SetValue(basicNamespace, "Label", NewText("to do"))
+	if c := p.ColorLabel(); c != LabelPurple {
+		t.Errorf("got %v, want %v", c, LabelPurple)
+	}
+
+	p.SetColorLabel(LabelNone, language.English)
+	if len(p.Properties) != 0 {
+		t.Errorf("label not removed")
+	}
+}
+
+func TestLabelUrgency(t *testing.T) {
+	for c := LabelRed; c <= LabelPurple; c++ {
+		if got := ColorLabelFromUrgency(c.Urgency()); got != c {
+			t.Errorf("%v -> %d -> %v", c, c.Urgency(), got)
+		}
+	}
+	if got := ColorLabelFromUrgency(0); got != LabelNone {
+		t.Errorf("urgency 0 -> %v", got)
+	}
+}
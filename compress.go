@@ -0,0 +1,180 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+
+	"golang.org/x/exp/maps"
+)
+
+// Large values can be stored in compressed form, to keep XMP packets within
+// the size limits of container formats.  A compressed value is a text value
+// with qualifier comp:encoding="gzip+base64" (namespace
+// "http://ns.seehuhn.de/xmp/compressed/1.0/").  The text is the base64
+// encoding of a gzip-compressed XMP packet, which contains the original
+// value under the same property name.
+
+// maxDecompressed is the maximal total size of the decompressed values in a
+// packet.  Values which would exceed this budget are left in compressed
+// form.  Compressed values are only expanded at the top level of a packet;
+// a compressed value inside the contents of another compressed value is
+// an error.
+var maxDecompressed = 64 << 20
+
+// compressed returns a copy of the packet where all values with an XML
+// encoding longer than limit are replaced by their compressed form.  If no
+// values are compressed, p is returned unchanged.
+func (p *Packet) compressed(limit int) *Packet {
+	var props map[xml.Name]Raw
+	for name, val := range p.Properties {
+		z, ok := compressValue(name, val, limit)
+		if !ok {
+			continue
+		}
+		if props == nil {
+			props = make(map[xml.Name]Raw, len(p.Properties))
+			for name, val := range p.Properties {
+				props[name] = val
+			}
+		}
+		props[name] = z
+	}
+	if props == nil {
+		return p
+	}
+
+	res := *p
+	res.Properties = props
+	return &res
+}
+
+// compressValue returns the compressed form of a value, if the XML encoding
+// of the value is longer than limit and if compression reduces the size.
+func compressValue(name xml.Name, val Raw, limit int) (Raw, bool) {
+	if isCompressed(val) {
+		return nil, false
+	}
+
+	tmp := NewPacket()
+	tmp.Properties[name] = val
+	buf := &bytes.Buffer{}
+	err := tmp.write(buf, nil)
+	if err != nil || buf.Len() <= limit {
+		return nil, false
+	}
+
+	zBuf := &bytes.Buffer{}
+	b64 := base64.NewEncoder(base64.StdEncoding, zBuf)
+	gz, _ := gzip.NewWriterLevel(b64, gzip.BestCompression)
+	gz.Write(buf.Bytes())
+	gz.Close()
+	b64.Close()
+	if zBuf.Len() >= buf.Len() {
+		return nil, false
+	}
+
+	return Text{
+		V: zBuf.String(),
+		Q: Q{{Name: nameCompEncoding, Value: Text{V: compEncodingGzip}}},
+	}, true
+}
+
+// decompressAll replaces all compressed values in the packet by their
+// original form.  Values which cannot be decompressed are left unchanged
+// and a diagnostic is recorded.
+func (p *Packet) decompressAll() {
+	budget := maxDecompressed
+	names := maps.Keys(p.Properties)
+	sortNames(names)
+	for _, name := range names {
+		val := p.Properties[name]
+		if !isCompressed(val) {
+			continue
+		}
+		orig, n, err := decompressValue(name, val.(Text).V, budget)
+		if err != nil {
+			p.addDiagnostic(name, "cannot decompress value: "+err.Error())
+			continue
+		}
+		budget -= n
+		p.Properties[name] = orig
+	}
+}
+
+// decompressValue decodes a compressed value.  At most limit bytes of
+// decompressed data are accepted.  The number of bytes used is returned.
+func decompressValue(name xml.Name, data string, limit int) (Raw, int, error) {
+	b64 := base64.NewDecoder(base64.StdEncoding, bytes.NewReader([]byte(data)))
+	gz, err := gzip.NewReader(b64)
+	if err != nil {
+		return nil, 0, err
+	}
+	body, err := io.ReadAll(io.LimitReader(gz, int64(limit)+1))
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(body) > limit {
+		return nil, 0, errCompressedTooLarge
+	}
+
+	tmp := NewPacket()
+	err = tmp.decode(bytes.NewReader(body), &DecodeOptions{nested: true})
+	if err != nil {
+		return nil, 0, err
+	}
+	orig, ok := tmp.Properties[name]
+	if !ok {
+		return nil, 0, ErrNotFound
+	}
+	if isCompressed(orig) {
+		return nil, 0, errCompressedNested
+	}
+	return orig, len(body), nil
+}
+
+// isCompressed checks whether val is a value in compressed form.
+func isCompressed(val Raw) bool {
+	t, ok := val.(Text)
+	if !ok {
+		return false
+	}
+	for _, q := range t.Q {
+		if q.Name == nameCompEncoding {
+			enc, _ := q.Value.(Text)
+			return enc.V == compEncodingGzip
+		}
+	}
+	return false
+}
+
+const (
+	compNamespace    = "http://ns.seehuhn.de/xmp/compressed/1.0/"
+	compEncodingGzip = "gzip+base64"
+)
+
+var nameCompEncoding = xml.Name{Space: compNamespace, Local: "encoding"}
+
+var (
+	errCompressedTooLarge = errors.New("decompressed value too large")
+	errCompressedNested   = errors.New("nested compressed value")
+)
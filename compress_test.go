@@ -0,0 +1,184 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompress(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	big := xml.Name{Space: ns, Local: "big"}
+	small := xml.Name{Space: ns, Local: "small"}
+
+	var items []Raw
+	for i := 0; i < 200; i++ {
+		items = append(items, RawStruct{
+			Value: map[xml.Name]Raw{
+				{Space: ns, Local: "a"}: Text{V: strings.Repeat("x", 20)},
+				{Space: ns, Local: "b"}: Text{V: "y"},
+			},
+			Order: []xml.Name{{Space: ns, Local: "a"}, {Space: ns, Local: "b"}},
+		})
+	}
+
+	p1 := NewPacket()
+	p1.Properties[big] = RawArray{Value: items, Kind: Ordered}
+	p1.Properties[small] = Text{V: "hello"}
+
+	plain := &bytes.Buffer{}
+	err := p1.Write(plain, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, &PacketOptions{CompressAbove: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() >= plain.Len()/2 {
+		t.Errorf("packet not compressed: %d vs. %d bytes", buf.Len(), plain.Len())
+	}
+	if !strings.Contains(buf.String(), "gzip+base64") {
+		t.Errorf("compression marker missing:\n%s", buf.String())
+	}
+	if _, ok := p1.Properties[big].(RawArray); !ok {
+		t.Errorf("original packet was modified")
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(p1.Properties, p2.Properties); d != "" {
+		t.Errorf("round trip failed (-want +got):\n%s", d)
+	}
+}
+
+func TestDecompressInvalid(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	name := xml.Name{Space: ns, Local: "prop"}
+	bad := Text{
+		V: "not base64!",
+		Q: Q{{Name: nameCompEncoding, Value: Text{V: compEncodingGzip}}},
+	}
+
+	p1 := NewPacket()
+	p1.Properties[name] = bad
+	buf := &bytes.Buffer{}
+	err := p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(Raw(bad), p2.Properties[name]); d != "" {
+		t.Errorf("invalid value was modified (-want +got):\n%s", d)
+	}
+	if len(p2.Diagnostics()) != 1 {
+		t.Errorf("expected one diagnostic, got %v", p2.Diagnostics())
+	}
+}
+
+func TestDecompressNested(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	name := xml.Name{Space: ns, Local: "prop"}
+
+	inner, ok := compressValue(name, Text{V: strings.Repeat("x", 1000)}, 0)
+	if !ok {
+		t.Fatal("value not compressed")
+	}
+	// Wrap the compressed value in a second layer of compression.
+	tmp := NewPacket()
+	tmp.Properties[name] = inner
+	body := &bytes.Buffer{}
+	err := tmp.write(body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zBuf := &bytes.Buffer{}
+	b64 := base64.NewEncoder(base64.StdEncoding, zBuf)
+	gz := gzip.NewWriter(b64)
+	gz.Write(body.Bytes())
+	gz.Close()
+	b64.Close()
+	outer := Text{
+		V: zBuf.String(),
+		Q: Q{{Name: nameCompEncoding, Value: Text{V: compEncodingGzip}}},
+	}
+
+	p1 := NewPacket()
+	p1.Properties[name] = outer
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(Raw(outer), p2.Properties[name]); d != "" {
+		t.Errorf("nested value was modified (-want +got):\n%s", d)
+	}
+	if len(p2.Diagnostics()) != 1 {
+		t.Errorf("expected one diagnostic, got %v", p2.Diagnostics())
+	}
+}
+
+func TestDecompressBudget(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	a := xml.Name{Space: ns, Local: "a"}
+	b := xml.Name{Space: ns, Local: "b"}
+
+	p1 := NewPacket()
+	p1.Properties[a] = Text{V: strings.Repeat("x", 1000)}
+	p1.Properties[b] = Text{V: strings.Repeat("y", 1000)}
+	buf := &bytes.Buffer{}
+	err := p1.Write(buf, &PacketOptions{CompressAbove: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The budget is large enough for one value, but not for both.
+	defer func(old int) { maxDecompressed = old }(maxDecompressed)
+	maxDecompressed = 1500
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(p1.Properties[a], p2.Properties[a]); d != "" {
+		t.Errorf("first value not decompressed (-want +got):\n%s", d)
+	}
+	if !isCompressed(p2.Properties[b]) {
+		t.Errorf("second value was decompressed")
+	}
+	if len(p2.Diagnostics()) != 1 {
+		t.Errorf("expected one diagnostic, got %v", p2.Diagnostics())
+	}
+}
@@ -0,0 +1,403 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// CSVTable holds the values of selected properties for a collection of XMP
+// packets, one row per packet.  Columns are given by property paths (see
+// [Path]), so that fields of structures can be included.  Only the textual
+// content of simple values and of arrays of simple values is represented;
+// qualifiers (other than the language of localized text) are not included.
+//
+// In the CSV representation, the first column contains the row keys
+// (typically file names) and the remaining columns correspond to property
+// paths.  Column headings are the string form of the paths, for example
+// "dc:title" or "xmpMM:DerivedFrom/stRef:documentID".
+type CSVTable struct {
+	Columns []Path
+	Rows    []CSVRow
+
+	// Separator (optional) is used to join the elements of unordered and
+	// ordered arrays into a single cell.  If this is empty, "; " is used.
+	Separator string
+}
+
+// CSVRow is a row in a [CSVTable].
+type CSVRow struct {
+	// Key identifies the packet, typically by a file name.
+	Key string
+
+	// Values contains one string per column of the table.
+	Values []string
+}
+
+// NewCSVTable returns a new, empty table with the given columns.
+func NewCSVTable(columns ...Path) *CSVTable {
+	return &CSVTable{Columns: columns}
+}
+
+// AddPacket appends a row with values from the given packet to the table.
+// Properties which are missing or which cannot be represented as text
+// result in empty cells.  If a path without an array index leads through
+// an array, the cell is also left empty.
+func (t *CSVTable) AddPacket(key string, p *Packet) {
+	row := CSVRow{
+		Key:    key,
+		Values: make([]string, len(t.Columns)),
+	}
+	for i, path := range t.Columns {
+		if val, ok := lookupPath(p, path); ok {
+			row.Values[i] = flattenRaw(val, t.separator())
+		}
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// ApplyRow stores the values from the row with the given key in the packet.
+// Empty cells are ignored.  The return value indicates whether a row with
+// the given key was found.
+//
+// If a property is already present as an unordered or ordered array, the
+// cell is split at the separator, and white space around the elements is
+// removed.  For localized text, the default (x-default) value is replaced.
+// Existing URL values stay URLs, and the qualifiers of existing values are
+// kept.  Missing properties and structure fields are stored as simple text
+// values.  Cells are ignored if the path leads through a missing structure
+// or array element, or if it refers to a structure.
+func (t *CSVTable) ApplyRow(key string, p *Packet) bool {
+	for _, row := range t.Rows {
+		if row.Key != key {
+			continue
+		}
+		for i, path := range t.Columns {
+			if i >= len(row.Values) || row.Values[i] == "" || len(path) == 0 {
+				continue
+			}
+			cell := row.Values[i]
+			val, ok := updatePath(p.Properties[path[0].Name], path[0].Index, path[1:], func(old Raw) Raw {
+				return unflattenRaw(old, cell, t.separator())
+			})
+			if !ok {
+				continue
+			}
+			for _, step := range path {
+				p.registerDefaultPrefix(step.Name.Space)
+			}
+			p.record(path[0].Name, val)
+			p.Properties[path[0].Name] = val
+		}
+		return true
+	}
+	return false
+}
+
+// Write writes the table in CSV format, including a header row.
+func (t *CSVTable) Write(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, 0, len(t.Columns)+1)
+	header = append(header, "key")
+	for _, path := range t.Columns {
+		header = append(header, path.String())
+	}
+	err := cw.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		record := make([]string, 0, len(t.Columns)+1)
+		record = append(record, row.Key)
+		record = append(record, row.Values...)
+		err = cw.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads a table in the format written by [CSVTable.Write].
+func ReadCSV(r io.Reader) (*CSVTable, error) {
+	cr := csv.NewReader(r)
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || len(records[0]) == 0 {
+		return nil, errors.New("missing CSV header")
+	}
+
+	t := &CSVTable{}
+	for _, heading := range records[0][1:] {
+		path, err := ParsePath(heading)
+		if err != nil {
+			return nil, err
+		}
+		t.Columns = append(t.Columns, path)
+	}
+	for _, record := range records[1:] {
+		t.Rows = append(t.Rows, CSVRow{
+			Key:    record[0],
+			Values: record[1:],
+		})
+	}
+	return t, nil
+}
+
+// ExportCSV reads the given XMP files and writes the values at the given
+// property paths to w in CSV format, using the file names as row keys.
+func ExportCSV(w io.Writer, columns []Path, fileNames ...string) error {
+	t := NewCSVTable(columns...)
+	for _, fname := range fileNames {
+		p, err := ReadFile(fname)
+		if err != nil {
+			return err
+		}
+		t.AddPacket(fname, p)
+	}
+	return t.Write(w)
+}
+
+// ImportCSV reads a CSV table from r and applies each row to the XMP file
+// named by the row key.  The files are modified in place.  Errors are
+// reported as for [ImportCSVWithOptions].
+func ImportCSV(r io.Reader) error {
+	return ImportCSVWithOptions(r, nil)
+}
+
+// ImportCSVWithOptions is like [ImportCSV], but the files are updated
+// using [ProcessFiles] with the given options.  If some of the files cannot
+// be updated, the remaining files are still processed and a [*BatchError]
+// is returned.
+func ImportCSVWithOptions(r io.Reader, opt *FileOptions) error {
+	t, err := ReadCSV(r)
+	if err != nil {
+		return err
+	}
+	fileNames := make([]string, len(t.Rows))
+	for i, row := range t.Rows {
+		fileNames[i] = row.Key
+	}
+	return ProcessFiles(fileNames, opt, func(fname string, p *Packet) error {
+		t.ApplyRow(fname, p)
+		return nil
+	})
+}
+
+func (t *CSVTable) separator() string {
+	if t.Separator == "" {
+		return "; "
+	}
+	return t.Separator
+}
+
+// flattenRaw converts a value into a single string.
+func flattenRaw(val Raw, sep string) string {
+	switch val := val.(type) {
+	case Text:
+		return val.V
+	case URL:
+		if val.V == nil {
+			return ""
+		}
+		return val.V.String()
+	case RawArray:
+		if val.Kind == Alternative {
+			idx := defaultIndex(val)
+			if idx < 0 {
+				return ""
+			}
+			return flattenRaw(val.Value[idx], sep)
+		}
+		var parts []string
+		for _, elem := range val.Value {
+			switch elem.(type) {
+			case Text, URL:
+				parts = append(parts, flattenRaw(elem, sep))
+			}
+		}
+		return strings.Join(parts, sep)
+	default:
+		return ""
+	}
+}
+
+// lookupPath returns the value identified by path.  Array elements must be
+// selected by index, except in the last step, where the whole array is
+// returned.
+func lookupPath(p *Packet, path Path) (Raw, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	val, ok := p.Properties[path[0].Name]
+	for i := 0; ok; i++ {
+		if idx := path[i].Index; idx > 0 {
+			a, isArray := val.(RawArray)
+			if !isArray || idx > len(a.Value) {
+				return nil, false
+			}
+			val = a.Value[idx-1]
+		}
+		if i == len(path)-1 {
+			return val, true
+		}
+		s, isStruct := val.(RawStruct)
+		if !isStruct {
+			return nil, false
+		}
+		val, ok = s.Value[path[i+1].Name]
+	}
+	return nil, false
+}
+
+// updatePath replaces the value identified by idx and rest inside of val,
+// using the function update.  The updated copy of val is returned.  If the path cannot be followed, the second
+// return value is false.
+func updatePath(val Raw, idx int, rest Path, update func(Raw) Raw) (Raw, bool) {
+	if idx > 0 {
+		a, ok := val.(RawArray)
+		if !ok || idx > len(a.Value) {
+			return nil, false
+		}
+		elem, ok := updatePath(a.Value[idx-1], 0, rest, update)
+		if !ok {
+			return nil, false
+		}
+		res := RawArray{Kind: a.Kind, Q: a.Q, Value: slices.Clone(a.Value)}
+		res.Value[idx-1] = elem
+		return res, true
+	}
+
+	if len(rest) == 0 {
+		if _, isStruct := val.(RawStruct); isStruct {
+			return nil, false
+		}
+		return update(val), true
+	}
+
+	old, ok := val.(RawStruct)
+	if !ok {
+		return nil, false
+	}
+	s := RawStruct{Value: maps.Clone(old.Value), Order: old.Order, Q: old.Q}
+	field, ok := updatePath(s.Value[rest[0].Name], rest[0].Index, rest[1:], update)
+	if !ok {
+		return nil, false
+	}
+	s.Value[rest[0].Name] = field
+	return s, true
+}
+
+// unflattenRaw converts a string into a value, using the previous value of
+// the property (if any) to determine the type and the qualifiers.
+func unflattenRaw(old Raw, s string, sep string) Raw {
+	a, ok := old.(RawArray)
+	if !ok {
+		return unflattenSimple(old, s)
+	}
+
+	res := RawArray{Kind: a.Kind, Q: a.Q}
+	if a.Kind == Alternative {
+		res.Value = append(res.Value, a.Value...)
+		idx := defaultIndex(a)
+		if idx < 0 {
+			res.Value = append(res.Value, Text{V: s})
+		} else {
+			res.Value[idx] = unflattenSimple(a.Value[idx], s)
+		}
+		return res
+	}
+
+	for i, part := range strings.Split(s, sep) {
+		part = strings.TrimSpace(part)
+		var prev Raw
+		if i < len(a.Value) {
+			prev = a.Value[i]
+		} else if len(a.Value) > 0 {
+			prev = a.Value[len(a.Value)-1]
+		}
+		if prev != nil && flattenRaw(prev, sep) != part {
+			// keep the element type, but not the qualifiers
+			prev = withoutQ(prev)
+		}
+		res.Value = append(res.Value, unflattenSimple(prev, part))
+	}
+	return res
+}
+
+// unflattenSimple converts a string into a simple value, keeping the type
+// and the qualifiers of old.  URLs which cannot be parsed are stored as
+// text.
+func unflattenSimple(old Raw, s string) Raw {
+	switch old := old.(type) {
+	case Text:
+		return Text{V: s, Q: old.Q}
+	case URL:
+		if u, err := url.Parse(s); err == nil {
+			return URL{V: u, Q: old.Q}
+		}
+		return Text{V: s, Q: old.Q}
+	default:
+		return Text{V: s}
+	}
+}
+
+// withoutQ returns a copy of a simple value without qualifiers.
+func withoutQ(val Raw) Raw {
+	switch val := val.(type) {
+	case Text:
+		return Text{V: val.V}
+	case URL:
+		return URL{V: val.V}
+	default:
+		return val
+	}
+}
+
+// defaultIndex returns the index of the default element of an alternative
+// array, or -1 if the array is empty.  If there is no element with
+// xml:lang="x-default", the first element is used.
+func defaultIndex(a RawArray) int {
+	for i, elem := range a.Value {
+		txt, ok := elem.(Text)
+		if !ok {
+			continue
+		}
+		for _, q := range txt.Q {
+			if lang, ok := q.Value.(Text); ok && q.Name == nameXMLLang && lang.V == "x-default" {
+				return i
+			}
+		}
+	}
+	if len(a.Value) > 0 {
+		return 0
+	}
+	return -1
+}
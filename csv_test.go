@@ -0,0 +1,221 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	dc := &DublinCore{}
+	dc.Title.Default = NewText("Sunset")
+	dc.Title.Set(language.German, "Sonnenuntergang")
+	dc.Subject.Append(NewText("sun"))
+	dc.Subject.Append(NewText("sea"))
+	p := NewPacket()
+	p.Set(dc)
+	p.SetValue("http://ns.seehuhn.de/test/#", "prop", NewText("a, \"b\""))
+
+	columns := []Path{
+		{{Name: xml.Name{Space: "http://purl.org/dc/elements/1.1/", Local: "title"}}},
+		{{Name: xml.Name{Space: "http://purl.org/dc/elements/1.1/", Local: "subject"}}},
+		{{Name: xml.Name{Space: "http://purl.org/dc/elements/1.1/", Local: "rights"}}},
+		{{Name: xml.Name{Space: "http://ns.seehuhn.de/test/#", Local: "prop"}}},
+	}
+	t1 := NewCSVTable(columns...)
+	t1.AddPacket("img.jpg", p)
+
+	buf := &bytes.Buffer{}
+	err := t1.Write(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, _, _ := strings.Cut(buf.String(), "\n")
+	if header != "key,dc:title,dc:subject,dc:rights,{http://ns.seehuhn.de/test/#}prop" {
+		t.Errorf("unexpected header %q", header)
+	}
+
+	t2, err := ReadCSV(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(t1, t2); d != "" {
+		t.Fatalf("tables differ (-want +got):\n%s", d)
+	}
+
+	t2.Rows[0].Values[0] = "Dawn"
+	t2.Rows[0].Values[1] = "sun; sky; sea"
+	if !t2.ApplyRow("img.jpg", p) {
+		t.Fatal("row not found")
+	}
+	dc2 := &DublinCore{}
+	p.Get(dc2)
+	if dc2.Title.Default.V != "Dawn" {
+		t.Errorf("unexpected title %q", dc2.Title.Default.V)
+	}
+	if dc2.Title.V[language.German].V != "Sonnenuntergang" {
+		t.Errorf("German title was lost")
+	}
+	if len(dc2.Subject.V) != 3 || dc2.Subject.V[1].V != "sky" {
+		t.Errorf("unexpected subject %v", dc2.Subject.V)
+	}
+}
+
+func TestCSVSeparator(t *testing.T) {
+	old := RawArray{Kind: Unordered, Value: []Raw{Text{V: "x"}}}
+	for _, tc := range []struct {
+		sep, cell string
+		want      []string
+	}{
+		{"; ", "sun; sky;  sea ", []string{"sun", "sky", "sea"}},
+		{" ", "sun sky", []string{"sun", "sky"}},
+		{"\t", "sun \t sky", []string{"sun", "sky"}},
+		{" | ", "a|b | c", []string{"a|b", "c"}},
+	} {
+		res := unflattenRaw(old, tc.cell, tc.sep).(RawArray)
+		var got []string
+		for _, v := range res.Value {
+			got = append(got, v.(Text).V)
+		}
+		if d := cmp.Diff(tc.want, got); d != "" {
+			t.Errorf("separator %q: (-want +got):\n%s", tc.sep, d)
+		}
+	}
+}
+
+func TestCSVFiles(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "test.xmp")
+
+	p := NewPacket()
+	p.SetValue(basicNamespace, "Label", NewText("red"))
+	err := WriteFile(fname, p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []Path{{{Name: xml.Name{Space: basicNamespace, Local: "Label"}}}}
+	buf := &bytes.Buffer{}
+	err = ExportCSV(buf, columns, fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csvData := strings.Replace(buf.String(), "red", "green", 1)
+	err = ImportCSV(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(body, []byte("<xmp:Label>green</xmp:Label>")) {
+		t.Errorf("file not updated:\n%s", body)
+	}
+}
+
+// TestCSVKeepType checks that importing a cell keeps the type and the
+// qualifiers of the existing value.
+func TestCSVKeepType(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	u, _ := url.Parse("http://example.com/old")
+	q := Q{{Name: xml.Name{Space: ns, Local: "q"}, Value: Text{V: "x"}}}
+	p := NewPacket()
+	p.Properties[xml.Name{Space: ns, Local: "u"}] = URL{V: u, Q: q}
+	p.Properties[xml.Name{Space: ns, Local: "t"}] = Text{V: "old", Q: q}
+	p.Properties[xml.Name{Space: ns, Local: "a"}] = RawArray{
+		Kind:  Ordered,
+		Value: []Raw{URL{V: u, Q: q}},
+	}
+
+	tab, err := ReadCSV(strings.NewReader("key,{" + ns + "}u,{" + ns + "}t,{" + ns + "}a\n" +
+		"f,http://example.com/new,new,http://example.com/old; http://example.com/2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab.ApplyRow("f", p)
+
+	u2, _ := url.Parse("http://example.com/new")
+	u3, _ := url.Parse("http://example.com/2")
+	want := map[xml.Name]Raw{
+		{Space: ns, Local: "u"}: URL{V: u2, Q: q},
+		{Space: ns, Local: "t"}: Text{V: "new", Q: q},
+		{Space: ns, Local: "a"}: RawArray{
+			Kind:  Ordered,
+			Value: []Raw{URL{V: u, Q: q}, URL{V: u3}},
+		},
+	}
+	urlCmp := cmp.Comparer(func(a, b *url.URL) bool { return a.String() == b.String() })
+	if d := cmp.Diff(want, p.Properties, urlCmp); d != "" {
+		t.Errorf("properties differ (-want +got):\n%s", d)
+	}
+}
+
+// TestCSVPaths checks that columns can refer to structure fields and array
+// elements.
+func TestCSVPaths(t *testing.T) {
+	mm := &MediaManagement{
+		DerivedFrom: ResourceRef{DocumentID: GUID{V: "xmp.did:1"}},
+	}
+	dc := &DublinCore{}
+	dc.Creator.Append(NewProperName("A"))
+	dc.Creator.Append(NewProperName("B"))
+	p := NewPacket()
+	p.Set(mm, dc)
+
+	var columns []Path
+	for _, s := range []string{"xmpMM:DerivedFrom/stRef:documentID", "dc:creator[2]", "dc:creator", "xmpMM:Ingredients/stRef:documentID"} {
+		path, err := ParsePath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, path)
+	}
+	tab := NewCSVTable(columns...)
+	tab.AddPacket("f", p)
+	want := []string{"xmp.did:1", "B", "A; B", ""}
+	if d := cmp.Diff(want, tab.Rows[0].Values); d != "" {
+		t.Fatalf("values differ (-want +got):\n%s", d)
+	}
+
+	tab.Rows[0].Values = []string{"xmp.did:2", "C", "", "xmp.did:3"}
+	tab.ApplyRow("f", p)
+	mm2 := &MediaManagement{}
+	dc2 := &DublinCore{}
+	p.Get(mm2)
+	p.Get(dc2)
+	if mm2.DerivedFrom.DocumentID.V != "xmp.did:2" {
+		t.Errorf("unexpected DerivedFrom %v", mm2.DerivedFrom)
+	}
+	if len(mm2.Ingredients.V) != 0 {
+		t.Errorf("unexpected ingredients %v", mm2.Ingredients.V)
+	}
+	if len(dc2.Creator.V) != 2 || dc2.Creator.V[1].V != "C" {
+		t.Errorf("unexpected creators %v", dc2.Creator.V)
+	}
+}
@@ -28,33 +28,104 @@ import (
 
 // Read reads an XMP packet from a reader.
 func Read(r io.Reader) (*Packet, error) {
-	dec := xml.NewDecoder(r)
-	p := &Packet{
-		Properties: make(map[xml.Name]Raw),
+	p := NewPacket()
+	err := p.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Decode reads an XMP packet from a reader and stores it in p.
+// Any previous contents of p are discarded, see [Packet.Reset].  The
+// fields Now, NewID and Journal are kept.
+//
+// This can be used to re-use a Packet for several decoding operations,
+// e.g. in combination with a [sync.Pool].
+func (p *Packet) Decode(r io.Reader) error {
+	return p.DecodeWithOptions(r, nil)
+}
+
+// DecodeOptions can be used to control the behaviour of
+// [Packet.DecodeWithOptions].
+type DecodeOptions struct {
+	// DropUndeclaredPrefixes causes attributes and elements which use an
+	// undeclared namespace prefix to be ignored.  By default, the
+	// well-known prefixes (e.g. "dc" or "xmp") are mapped to their usual
+	// namespaces, and a diagnostic is recorded.  Names with other
+	// undeclared prefixes are always ignored.
+	DropUndeclaredPrefixes bool
+
+	// Lenient enables the repair of malformed values, for example numbers
+	// written with a decimal comma ("0,5") or with thousands separators.
+	// Only properties described in the schema registry are repaired.  The
+	// repaired values are stored in the packet, and each repair is
+	// recorded in [Packet.Diagnostics].  By default, malformed values are
+	// kept unchanged and are rejected when they are accessed.
+	Lenient bool
+
+	// nested is set when decoding the contents of a compressed value.
+	// Compressed values are not expanded recursively.
+	nested bool
+}
+
+// DecodeWithOptions is like [Packet.Decode], but allows to control the
+// decoding process.  If opt is nil, the default options are used.
+func (p *Packet) DecodeWithOptions(r io.Reader, opt *DecodeOptions) error {
+	if opt == nil {
+		opt = &DecodeOptions{}
 	}
+	err := p.decode(r, opt)
+	if m := getMetrics(); m != nil {
+		if err != nil {
+			m.Add(CounterParseErrors, 1)
+		} else {
+			m.Add(CounterPacketsRead, 1)
+		}
+	}
+	return err
+}
+
+// decode implements [Packet.Decode], without recording metrics.
+func (p *Packet) decode(r io.Reader, opt *DecodeOptions) error {
+	now, newID, journal := p.Now, p.NewID, p.Journal
+	p.Reset()
+	p.Now, p.NewID, p.Journal = now, newID, journal
+	dec := xml.NewDecoder(r)
+	pf := &prefixFixer{p: p, drop: opt.DropUndeclaredPrefixes}
 
 	var level int
 	descriptionLevel := -1
 	propertyLevel := -1
 	var propertyElement []xml.Token
+	var rdfLang, descLang string
+	var pendingComments, innerComments []string
+	seenRDF := false
+	pp := &propertyParser{}
 tokenLoop:
 	for {
 		t, err := dec.Token()
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			return nil, err
+			return err
 		}
+		t = pf.fixToken(t, level > 0)
 
 		switch t := t.(type) {
 		case xml.StartElement:
 			if level > 0 || t.Name == nameRDFRoot {
+				if level == 0 {
+					rdfLang = xmlLang(t.Attr, "")
+					seenRDF = true
+				}
 				level++
 			} else {
 				// Ignore anything outside the rdf:RDF element.
 				continue tokenLoop
 			}
 			if descriptionLevel < 0 && t.Name == nameRDFDescription {
+				descLang = xmlLang(t.Attr, rdfLang)
 				for _, a := range t.Attr {
 					switch a.Name {
 					case nameRDFAbout:
@@ -69,13 +140,13 @@ tokenLoop:
 						if p.About == nil {
 							p.About = aboutURL
 						} else if aboutURL != nil && *aboutURL != *p.About {
-							return nil, fmt.Errorf("inconsistent `about` attributes: %s != %s", p.About, aboutURL)
+							return fmt.Errorf("inconsistent `about` attributes: %s != %s", p.About, aboutURL)
 						}
 					default:
 						// Simple properties can be encoded as attributes of
 						// the rdf:Description element.
 						if isValidPropertyName(a.Name) {
-							p.Properties[a.Name] = Text{V: a.Value}
+							p.Properties[a.Name] = pp.text(a.Value, pp.withLang(nil, descLang))
 						}
 					}
 				}
@@ -84,6 +155,15 @@ tokenLoop:
 				// start recording the XML tokens which make up a property element
 				propertyLevel = level
 				propertyElement = nil
+				if pendingComments != nil {
+					if isValidPropertyName(t.Name) {
+						if p.PropertyComments == nil {
+							p.PropertyComments = make(map[xml.Name][]string)
+						}
+						p.PropertyComments[t.Name] = pendingComments
+					}
+					pendingComments = nil
+				}
 			}
 		case xml.EndElement:
 			if level == propertyLevel {
@@ -91,11 +171,18 @@ tokenLoop:
 				// including the start element, but not the end element.
 				start := propertyElement[0].(xml.StartElement)
 				if isValidPropertyName(start.Name) {
-					val := parsePropertyElement(start, propertyElement[1:], nil)
+					val := pp.parsePropertyElement(start, propertyElement[1:], nil, descLang)
 					if val != nil {
 						p.Properties[start.Name] = val
 					}
+					if innerComments != nil {
+						if p.PropertyComments == nil {
+							p.PropertyComments = make(map[xml.Name][]string)
+						}
+						p.PropertyComments[start.Name] = append(p.PropertyComments[start.Name], innerComments...)
+					}
 				}
+				innerComments = nil
 				propertyLevel = -1
 			}
 			if level == descriptionLevel {
@@ -103,6 +190,27 @@ tokenLoop:
 			}
 			if level > 0 {
 				level--
+				if level == 0 {
+					p.TrailingComments = append(p.TrailingComments, pendingComments...)
+					pendingComments = nil
+				}
+			}
+		case xml.Comment:
+			// Comments inside a property value are attached to the
+			// property, see [Packet.PropertyComments].
+			switch {
+			case propertyLevel >= 0:
+				innerComments = append(innerComments, string(t))
+			case level > 0:
+				pendingComments = append(pendingComments, string(t))
+			case seenRDF:
+				p.TrailingComments = append(p.TrailingComments, string(t))
+			default:
+				p.LeadingComments = append(p.LeadingComments, string(t))
+			}
+		case xml.ProcInst:
+			if t.Target != "xpacket" && t.Target != "xml" {
+				p.ProcInsts = append(p.ProcInsts, t.Copy())
 			}
 		}
 
@@ -110,7 +218,139 @@ tokenLoop:
 			propertyElement = append(propertyElement, xml.CopyToken(t))
 		}
 	}
-	return p, nil
+	p.TrailingComments = append(p.TrailingComments, pendingComments...)
+	if !opt.nested {
+		p.decompressAll()
+	}
+	p.repairArrayKinds()
+	if opt.Lenient {
+		p.repairValues()
+	}
+	return nil
+}
+
+// prefixFixer handles namespace prefixes which are used without being
+// declared.  In this case, [xml.Decoder] leaves the prefix in the Space
+// field of the name.  To distinguish undeclared prefixes from namespace
+// URIs, the fixer keeps track of the namespaces declared in the current
+// scope.
+type prefixFixer struct {
+	p    *Packet
+	drop bool
+	seen map[string]bool
+
+	declared map[string]int // number of declarations in scope, by URI
+	scopes   [][]string     // the URIs declared by each open element
+}
+
+// fixToken replaces undeclared prefixes in the names of start and end
+// elements.  If report is true, a diagnostic is recorded the first time
+// a prefix is seen.
+func (f *prefixFixer) fixToken(t xml.Token, report bool) xml.Token {
+	switch t := t.(type) {
+	case xml.StartElement:
+		f.push(t.Attr)
+		t.Name = f.fix(t.Name, report)
+		for i, a := range t.Attr {
+			t.Attr[i].Name = f.fix(a.Name, report)
+		}
+		return t
+	case xml.EndElement:
+		t.Name = f.fix(t.Name, false)
+		f.pop()
+		return t
+	}
+	return t
+}
+
+// push records the namespace declarations of a start element.
+func (f *prefixFixer) push(attr []xml.Attr) {
+	var uris []string
+	for _, a := range attr {
+		if a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns" {
+			uris = append(uris, a.Value)
+		}
+	}
+	if len(uris) > 0 && f.declared == nil {
+		f.declared = make(map[string]int)
+	}
+	for _, uri := range uris {
+		f.declared[uri]++
+	}
+	f.scopes = append(f.scopes, uris)
+}
+
+// pop removes the namespace declarations of the innermost open element.
+func (f *prefixFixer) pop() {
+	if len(f.scopes) == 0 {
+		return
+	}
+	for _, uri := range f.scopes[len(f.scopes)-1] {
+		f.declared[uri]--
+	}
+	f.scopes = f.scopes[:len(f.scopes)-1]
+}
+
+// fix returns the name with an undeclared prefix replaced by the
+// corresponding well-known namespace.  If the prefix cannot be resolved,
+// or if undeclared prefixes are dropped, the namespace is set to the empty
+// string so that the name is ignored.
+func (f *prefixFixer) fix(name xml.Name, report bool) xml.Name {
+	pfx := name.Space
+	if pfx == "" || pfx == "xmlns" || pfx == xmlNamespace || f.declared[pfx] > 0 {
+		return name
+	}
+
+	ns, known := prefixNamespace(pfx)
+	if f.drop || !known {
+		ns = ""
+	}
+	if report && !f.seen[pfx] {
+		if f.seen == nil {
+			f.seen = make(map[string]bool)
+		}
+		f.seen[pfx] = true
+		if ns != "" {
+			f.p.addDiagnostic(xml.Name{Space: ns, Local: name.Local},
+				fmt.Sprintf("undeclared namespace prefix %q", pfx))
+		} else {
+			f.p.addDiagnostic(xml.Name{Local: pfx + ":" + name.Local},
+				fmt.Sprintf("ignored undeclared namespace prefix %q", pfx))
+		}
+	}
+	return xml.Name{Space: ns, Local: name.Local}
+}
+
+// propertyParser converts property elements into [Raw] values.
+//
+// Short text values without qualifiers are shared between all their
+// occurrences in a packet.  This saves the memory for the string data and
+// for the interface value, which matters for large catalogs where values
+// like keywords and language tags are repeated many times.  Sharing is
+// safe, since a Text with no qualifiers cannot be modified through the
+// Raw interface.
+type propertyParser struct {
+	texts map[string]Raw
+}
+
+// maxSharedText is the maximal length of text values shared by
+// propertyParser.
+const maxSharedText = 64
+
+// text returns the Raw value for a text with the given qualifiers.
+func (pp *propertyParser) text(v string, qq Q) Raw {
+	if len(qq) > 0 || len(v) > maxSharedText {
+		return Text{V: v, Q: qq}
+	}
+	if t, ok := pp.texts[v]; ok {
+		return t
+	}
+	if pp.texts == nil {
+		pp.texts = make(map[string]Raw)
+	}
+	t := Raw(Text{V: v})
+	pp.texts[v] = t
+	return t
 }
 
 // ParsePropertyElement parses a property element and updates the packet. The
@@ -121,16 +361,23 @@ tokenLoop:
 // This implements the rules from appendix C.2.5 (Content of a nodeElement)
 // of ISO 16684-1:2011.
 //
+// The argument `lang` is the value of xml:lang inherited from the enclosing
+// elements, or the empty string if no language is in scope.  Following the
+// RDF rules, the language applies to nested text values, including struct
+// fields and array items, which do not specify a language of their own.
+// Qualifiers do not inherit the language of the value they qualify.
+//
 // Invalid XML is ignored, and the function decodes as much of the property
 // element as possible.  If no valid data is found, the function returns nil.
-func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw {
+func (pp *propertyParser) parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q, lang string) Raw {
 	tp := getProperyElementType(start, tokens)
+	lang = xmlLang(start.Attr, lang)
 	switch tp {
 	case literalPropertyElt:
 		// See appendix C.2.7 of ISO 16684-1:2011.
 		for _, a := range start.Attr {
-			if isValidQualifierName(a.Name) {
-				qq = append(qq, Qualifier{Name: a.Name, Value: Text{V: a.Value}})
+			if isQualifierAttr(a) {
+				qq = append(qq, Qualifier{Name: a.Name, Value: pp.text(a.Value, nil)})
 			}
 		}
 
@@ -140,13 +387,13 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 				text += string(c)
 			}
 		}
-		return Text{V: text, Q: qq}
+		return pp.text(text, pp.withLang(qq, lang))
 
 	case resourcePropertyElt:
 		// See appendix C.2.6 of ISO 16684-1:2011.
 		for _, a := range start.Attr {
-			if a.Name == nameXMLLang {
-				qq = append(qq, Qualifier{Name: a.Name, Value: Text{V: a.Value}})
+			if a.Name == nameXMLLang && a.Value != "" {
+				qq = append(qq, Qualifier{Name: a.Name, Value: pp.text(a.Value, nil)})
 			}
 		}
 
@@ -161,6 +408,7 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 		case child.name == nameRDFDescription:
 			descStart := tokens[child.start].(xml.StartElement)
 			inner := tokens[child.start+1 : child.end]
+			lang := xmlLang(descStart.Attr, lang)
 			fields := getChildren(inner)
 
 			// If there is an rdf:value field or attribute, this encodes a
@@ -181,13 +429,13 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 			}
 			if attrIdx >= 0 || valueIdx >= 0 {
 				for _, a := range descStart.Attr {
-					if isValidQualifierName(a.Name) {
-						qq = append(qq, Qualifier{Name: a.Name, Value: Text{V: a.Value}})
+					if isQualifierAttr(a) {
+						qq = append(qq, Qualifier{Name: a.Name, Value: pp.text(a.Value, nil)})
 					}
 				}
 				for _, f := range fields {
 					if isValidQualifierName(f.name) {
-						val := parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], nil)
+						val := pp.parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], nil, "")
 						if val != nil {
 							qq = append(qq, Qualifier{Name: f.name, Value: val})
 						}
@@ -195,10 +443,10 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 				}
 
 				if attrIdx >= 0 {
-					return Text{V: descStart.Attr[attrIdx].Value, Q: qq}
+					return pp.text(descStart.Attr[attrIdx].Value, pp.withLang(qq, lang))
 				}
 				f := fields[valueIdx]
-				return parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], qq)
+				return pp.parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], qq, lang)
 			}
 
 			// Otherwise, this is a structure.
@@ -208,14 +456,14 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 			}
 			for _, a := range descStart.Attr {
 				if isValidPropertyName(a.Name) {
-					res.Value[a.Name] = Text{V: a.Value}
+					res.add(a.Name, pp.text(a.Value, pp.withLang(nil, lang)))
 				}
 			}
 			for _, f := range fields {
 				if isValidPropertyName(f.name) {
-					val := parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], nil)
+					val := pp.parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], nil, lang)
 					if val != nil {
-						res.Value[f.name] = val
+						res.add(f.name, val)
 					}
 				}
 			}
@@ -232,6 +480,7 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 				tp = Alternative
 			}
 			inner := tokens[child.start+1 : child.end]
+			lang := xmlLang(tokens[child.start].(xml.StartElement).Attr, lang)
 			items := getChildren(inner)
 			res := RawArray{
 				Value: make([]Raw, 0, len(items)),
@@ -239,7 +488,7 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 				Q:     qq,
 			}
 			for _, i := range items {
-				val := parsePropertyElement(inner[i.start].(xml.StartElement), inner[i.start+1:i.end], nil)
+				val := pp.parsePropertyElement(inner[i.start].(xml.StartElement), inner[i.start+1:i.end], nil, lang)
 				if val != nil {
 					res.Value = append(res.Value, val)
 				}
@@ -248,6 +497,7 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 
 		default: // a typed node
 			inner := tokens[child.start+1 : child.end]
+			lang := xmlLang(tokens[child.start].(xml.StartElement).Attr, lang)
 
 			typeURLString := child.name.Space + child.name.Local
 			typeURL, _ := url.Parse(typeURLString)
@@ -269,7 +519,7 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 			if valueIdx >= 0 {
 				for _, f := range fields {
 					if isValidQualifierName(f.name) {
-						val := parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], nil)
+						val := pp.parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], nil, "")
 						if val != nil {
 							qq = append(qq, Qualifier{Name: f.name, Value: val})
 						}
@@ -277,7 +527,7 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 				}
 
 				f := fields[valueIdx]
-				return parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], qq)
+				return pp.parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], qq, lang)
 			}
 
 			// Otherwise, this is a structure.
@@ -287,9 +537,9 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 			}
 			for _, f := range fields {
 				if isValidPropertyName(f.name) {
-					val := parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], nil)
+					val := pp.parsePropertyElement(inner[f.start].(xml.StartElement), inner[f.start+1:f.end], nil, lang)
 					if val != nil {
-						res.Value[f.name] = val
+						res.add(f.name, val)
 					}
 				}
 			}
@@ -301,8 +551,8 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 		// See appendix C.2.9 (The parseTypeResourcePropertyElt) of ISO 16684-1:2011.
 
 		for _, a := range start.Attr {
-			if a.Name == nameXMLLang {
-				qq = append(qq, Qualifier{Name: a.Name, Value: Text{V: a.Value}})
+			if a.Name == nameXMLLang && a.Value != "" {
+				qq = append(qq, Qualifier{Name: a.Name, Value: pp.text(a.Value, nil)})
 			}
 		}
 
@@ -320,14 +570,14 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 		if valueIdx >= 0 {
 			for _, f := range fields {
 				if isValidQualifierName(f.name) {
-					val := parsePropertyElement(tokens[f.start].(xml.StartElement), tokens[f.start+1:f.end], nil)
+					val := pp.parsePropertyElement(tokens[f.start].(xml.StartElement), tokens[f.start+1:f.end], nil, "")
 					if val != nil {
 						qq = append(qq, Qualifier{Name: f.name, Value: val})
 					}
 				}
 			}
 			f := fields[valueIdx]
-			return parsePropertyElement(tokens[f.start].(xml.StartElement), tokens[f.start+1:f.end], qq)
+			return pp.parsePropertyElement(tokens[f.start].(xml.StartElement), tokens[f.start+1:f.end], qq, lang)
 		}
 
 		// Otherwise this is a structure.
@@ -337,9 +587,9 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 		}
 		for _, f := range fields {
 			if isValidPropertyName(f.name) {
-				val := parsePropertyElement(tokens[f.start].(xml.StartElement), tokens[f.start+1:f.end], nil)
+				val := pp.parsePropertyElement(tokens[f.start].(xml.StartElement), tokens[f.start+1:f.end], nil, lang)
 				if val != nil {
-					res.Value[f.name] = val
+					res.add(f.name, val)
 				}
 			}
 		}
@@ -379,11 +629,11 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 			for _, a := range start.Attr {
 				if a.Name == nameRDFValue {
 					value = a.Value
-				} else if isValidQualifierName(a.Name) {
-					qq = append(qq, Qualifier{Name: a.Name, Value: Text{V: a.Value}})
+				} else if isQualifierAttr(a) {
+					qq = append(qq, Qualifier{Name: a.Name, Value: pp.text(a.Value, nil)})
 				}
 			}
-			return Text{V: value, Q: qq}
+			return pp.text(value, pp.withLang(qq, lang))
 		case isURIProperty:
 			// If there is an rdf:resource attribute, then this is a simple
 			// property with a URI value.  All other attributes are qualifiers.
@@ -391,8 +641,8 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 			for _, a := range start.Attr {
 				if a.Name == nameRDFResource {
 					uriString = a.Value
-				} else if isValidQualifierName(a.Name) {
-					qq = append(qq, Qualifier{Name: a.Name, Value: Text{V: a.Value}})
+				} else if isQualifierAttr(a) {
+					qq = append(qq, Qualifier{Name: a.Name, Value: pp.text(a.Value, nil)})
 				}
 			}
 			uri, err := url.Parse(uriString)
@@ -403,15 +653,7 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 		case isEmptyValue:
 			// If there are no attributes other than xml:lang, rdf:ID, or
 			// rdf:nodeID, then this is a simple property with an empty value.
-			for _, a := range start.Attr {
-				if a.Name == nameXMLLang {
-					res := Text{
-						Q: Q{{Name: nameXMLLang, Value: Text{V: a.Value}}},
-					}
-					return res
-				}
-			}
-			return Text{}
+			return pp.text("", pp.withLang(nil, lang))
 		default:
 			// Otherwise, this is a struct, and the attributes other than
 			// xml:lang, rdf:ID, or rdf:nodeID are the fields.
@@ -421,9 +663,11 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 			}
 			for _, a := range start.Attr {
 				if a.Name == nameXMLLang {
-					res.Q = append(res.Q, Qualifier{Name: a.Name, Value: Text{V: a.Value}})
+					if a.Value != "" {
+						res.Q = append(res.Q, Qualifier{Name: a.Name, Value: pp.text(a.Value, nil)})
+					}
 				} else if isValidPropertyName(a.Name) {
-					res.Value[a.Name] = Text{V: a.Value}
+					res.add(a.Name, pp.text(a.Value, pp.withLang(nil, lang)))
 				}
 			}
 			return res
@@ -435,6 +679,41 @@ func parsePropertyElement(start xml.StartElement, tokens []xml.Token, qq Q) Raw
 	}
 }
 
+// isQualifierAttr checks whether an attribute of a property element
+// represents a qualifier.  An empty xml:lang attribute only cancels an
+// inherited language and is not a qualifier.
+func isQualifierAttr(a xml.Attr) bool {
+	if a.Name == nameXMLLang {
+		return a.Value != ""
+	}
+	return isValidQualifierName(a.Name)
+}
+
+// xmlLang returns the value of the xml:lang attribute, if present, and the
+// inherited language otherwise.
+func xmlLang(attr []xml.Attr, inherited string) string {
+	for _, a := range attr {
+		if a.Name == nameXMLLang {
+			return a.Value
+		}
+	}
+	return inherited
+}
+
+// withLang adds an xml:lang qualifier for the given language, unless lang is
+// empty or the qualifiers already specify a language.
+func (pp *propertyParser) withLang(qq Q, lang string) Q {
+	if lang == "" {
+		return qq
+	}
+	for _, q := range qq {
+		if q.Name == nameXMLLang {
+			return qq
+		}
+	}
+	return append(qq, Qualifier{Name: nameXMLLang, Value: pp.text(lang, nil)})
+}
+
 // getProperyElementType determines the RDF type of a property element.
 //
 // This implements the rules from appendix C.2.5 (Content of a nodeElement)
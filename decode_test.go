@@ -21,10 +21,16 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+	"unsafe"
 
 	"github.com/google/go-cmp/cmp"
+
+	"seehuhn.de/go/xmp/samples"
 )
 
 type decodeTestCase struct {
@@ -598,6 +604,82 @@ var decodeTestCases = []decodeTestCase{
 			},
 		},
 	},
+	{
+		desc: "xml:lang inherited from rdf:Description",
+		in: `<rdf:Description rdf:about="" xml:lang="de" test:a="1">
+				<test:b>2</test:b>
+				<test:c xml:lang="fr">3</test:c>
+			</rdf:Description>`,
+		out: &Packet{
+			Properties: map[xml.Name]Raw{
+				elemTestA: Text{V: "1", Q: Q{{nameXMLLang, Text{V: "de"}}}},
+				elemTestB: Text{V: "2", Q: Q{{nameXMLLang, Text{V: "de"}}}},
+				elemTestC: Text{V: "3", Q: Q{{nameXMLLang, Text{V: "fr"}}}},
+			},
+		},
+	},
+	{
+		desc: "xml:lang inherited by struct fields",
+		in: `<rdf:Description rdf:about="">
+				<test:prop xml:lang="de" rdf:parseType="Resource">
+					<test:a>1</test:a>
+					<test:b xml:lang="">2</test:b>
+				</test:prop>
+			</rdf:Description>`,
+		out: &Packet{
+			Properties: map[xml.Name]Raw{
+				elemTest: RawStruct{
+					Value: map[xml.Name]Raw{
+						elemTestA: Text{V: "1", Q: Q{{nameXMLLang, Text{V: "de"}}}},
+						elemTestB: Text{V: "2"},
+					},
+					Q: Q{{nameXMLLang, Text{V: "de"}}},
+				},
+			},
+		},
+	},
+	{
+		desc: "xml:lang inherited by array items",
+		in: `<rdf:Description rdf:about="">
+				<test:prop>
+					<rdf:Seq xml:lang="de">
+						<rdf:li>1</rdf:li>
+						<rdf:li xml:lang="fr">2</rdf:li>
+					</rdf:Seq>
+				</test:prop>
+			</rdf:Description>`,
+		out: &Packet{
+			Properties: map[xml.Name]Raw{
+				elemTest: RawArray{
+					Value: []Raw{
+						Text{V: "1", Q: Q{{nameXMLLang, Text{V: "de"}}}},
+						Text{V: "2", Q: Q{{nameXMLLang, Text{V: "fr"}}}},
+					},
+					Kind: Ordered,
+				},
+			},
+		},
+	},
+	{
+		desc: "xml:lang not inherited by qualifiers",
+		in: `<rdf:Description rdf:about="">
+				<test:prop xml:lang="de" rdf:parseType="Resource">
+					<rdf:value>1</rdf:value>
+					<test:q>q</test:q>
+				</test:prop>
+			</rdf:Description>`,
+		out: &Packet{
+			Properties: map[xml.Name]Raw{
+				elemTest: Text{
+					V: "1",
+					Q: Q{
+						{nameXMLLang, Text{V: "de"}},
+						{elemTestQ, Text{V: "q"}},
+					},
+				},
+			},
+		},
+	},
 }
 
 func TestDecode(t *testing.T) {
@@ -686,6 +768,17 @@ func FuzzRoundTrip(f *testing.F) {
 		}
 		f.Add(buf.Bytes())
 	}
+	for _, body := range samples.All() {
+		f.Add(body)
+	}
+	files, _ := filepath.Glob("testdata/*.xmp")
+	for _, name := range files {
+		body, err := os.ReadFile(name)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(body)
+	}
 
 	urlCmp := cmp.Comparer(func(u1, u2 *url.URL) bool {
 		if u1 == nil && u2 == nil {
@@ -729,3 +822,203 @@ func FuzzRoundTrip(f *testing.F) {
 		}
 	})
 }
+
+func TestDecodeReuse(t *testing.T) {
+	p := NewPacket()
+	p.RegisterPrefix("http://example.com/", "ex")
+	p.SetValue("http://example.com/", "a", NewText("1"))
+	p.About = testURL
+
+	in := head + `<rdf:Description rdf:about="" xmlns:ex="http://example.com/b/" ex:b="2"/>` + foot
+	err := p.Decode(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Packet{
+		Properties: map[xml.Name]Raw{
+			{Space: "http://example.com/b/", Local: "b"}: Text{V: "2"},
+		},
+		nsToPrefix: map[string]string{},
+	}
+	if d := cmp.Diff(want, p, cmp.AllowUnexported(Packet{})); d != "" {
+		t.Errorf("unexpected packet (-want +got):\n%s", d)
+	}
+}
+
+// TestDecodeKeepsHooks checks that decoding into a packet, once or
+// several times, keeps the clock, the ID source and the journal.
+func TestDecodeKeepsHooks(t *testing.T) {
+	const ns = "http://example.com/"
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := NewPacket()
+	p.Now = func() time.Time { return clock }
+	p.NewID = func() string { return "test-id" }
+	journal := &Journal{}
+	p.Journal = journal
+
+	in := head + `<rdf:Description rdf:about="" xmlns:ex="http://example.com/" ex:a="1"/>` + foot
+	for i := 0; i < 2; i++ {
+		err := p.Decode(strings.NewReader(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if p.Now == nil || !p.Now().Equal(clock) {
+		t.Error("clock not kept")
+	}
+	if p.NewID == nil || p.NewID() != "test-id" {
+		t.Error("ID source not kept")
+	}
+	if p.Journal != journal {
+		t.Fatal("journal not kept")
+	}
+	p.SetValue(ns, "a", NewText("2"))
+	want := []JournalEntry{
+		{Change{Name: xml.Name{Space: ns, Local: "a"}, Old: Text{V: "1"}, New: Text{V: "2"}}, clock},
+	}
+	if d := cmp.Diff(want, p.Journal.Entries); d != "" {
+		t.Errorf("wrong journal (-want +got):\n%s", d)
+	}
+}
+
+// TestStructFieldOrder checks that the order of struct fields is preserved
+// when a packet is read and written again.
+func TestStructFieldOrder(t *testing.T) {
+	in := head + `<rdf:Description rdf:about="">
+	<test:s rdf:parseType="Resource">
+		<test:c>3</test:c>
+		<test:a>1</test:a>
+		<test:b>2</test:b>
+	</test:s>
+</rdf:Description>` + foot
+	p, err := Read(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := func(local string) xml.Name {
+		return xml.Name{Space: "http://ns.seehuhn.de/test/#", Local: local}
+	}
+	s := p.Properties[name("s")].(RawStruct)
+	want := []xml.Name{name("c"), name("a"), name("b")}
+	if d := cmp.Diff(want, s.Order); d != "" {
+		t.Errorf("wrong field order (-want +got):\n%s", d)
+	}
+
+	buf := &bytes.Buffer{}
+	err = p.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	posC := strings.Index(out, "test:c")
+	posA := strings.Index(out, "test:a")
+	posB := strings.Index(out, "test:b")
+	if posC < 0 || !(posC < posA && posA < posB) {
+		t.Errorf("field order not preserved:\n%s", out)
+	}
+
+	// Fields not listed in Order are written last, in sorted order.
+	s.Order = []xml.Name{name("b"), name("missing")}
+	want = []xml.Name{name("b"), name("a"), name("c")}
+	if d := cmp.Diff(want, s.FieldNames()); d != "" {
+		t.Errorf("wrong field names (-want +got):\n%s", d)
+	}
+}
+
+// TestDecodeSharedText checks that repeated short text values share their
+// memory.
+func TestDecodeSharedText(t *testing.T) {
+	in := head + `<rdf:Description rdf:about="">
+	<test:a><rdf:Bag><rdf:li>keyword</rdf:li><rdf:li>keyword</rdf:li></rdf:Bag></test:a>
+	<test:b xml:lang="de">keyword</test:b>
+</rdf:Description>` + foot
+	p, err := Read(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := p.Properties[xml.Name{Space: "http://ns.seehuhn.de/test/#", Local: "a"}].(RawArray)
+	x1 := a.Value[0].(Text).V
+	x2 := a.Value[1].(Text).V
+	if x1 != "keyword" || unsafe.StringData(x1) != unsafe.StringData(x2) {
+		t.Errorf("text values are not shared")
+	}
+
+	// Values with qualifiers are not shared.
+	b := p.Properties[xml.Name{Space: "http://ns.seehuhn.de/test/#", Local: "b"}].(Text)
+	if b.V != "keyword" || len(b.Q) != 1 || unsafe.StringData(b.V) == unsafe.StringData(x1) {
+		t.Errorf("unexpected value %v", b)
+	}
+}
+
+func TestUndeclaredPrefixes(t *testing.T) {
+	in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmp:CreatorTool="test" foo:bar="baz">
+<dc:format>image/jpeg</dc:format>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>`
+
+	nameCreatorTool := xml.Name{Space: basicNamespace, Local: "CreatorTool"}
+	nameFormat := xml.Name{Space: "http://purl.org/dc/elements/1.1/", Local: "format"}
+
+	p := NewPacket()
+	err := p.Decode(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[xml.Name]Raw{
+		nameCreatorTool: Text{V: "test"},
+		nameFormat:      Text{V: "image/jpeg"},
+	}
+	if d := cmp.Diff(expected, p.Properties); d != "" {
+		t.Errorf("recovered properties differ (-want +got):\n%s", d)
+	}
+	if n := len(p.Diagnostics()); n != 3 {
+		t.Errorf("got %d diagnostics, want 3: %v", n, p.Diagnostics())
+	}
+
+	err = p.DecodeWithOptions(strings.NewReader(in), &DecodeOptions{DropUndeclaredPrefixes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Properties) != 0 {
+		t.Errorf("unexpected properties: %v", p.Properties)
+	}
+}
+
+// TestDeclaredPrefixWithoutColon checks that namespaces with URIs which do
+// not contain a colon are not mistaken for undeclared prefixes.
+func TestDeclaredPrefixWithoutColon(t *testing.T) {
+	in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:foo="urn-like-without-colon" foo:a="1">
+<foo:b>2</foo:b>
+</rdf:Description>
+<rdf:Description rdf:about="" urn-like-without-colon:c="3"/>
+</rdf:RDF>
+</x:xmpmeta>`
+
+	p := NewPacket()
+	err := p.Decode(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[xml.Name]Raw{
+		{Space: "urn-like-without-colon", Local: "a"}: Text{V: "1"},
+		{Space: "urn-like-without-colon", Local: "b"}: Text{V: "2"},
+	}
+	if d := cmp.Diff(expected, p.Properties); d != "" {
+		t.Errorf("properties differ (-want +got):\n%s", d)
+	}
+
+	// Outside the scope of the declaration, the string is an undeclared
+	// prefix.
+	if n := len(p.Diagnostics()); n != 1 {
+		t.Errorf("got %d diagnostics, want 1: %v", n, p.Diagnostics())
+	}
+}
@@ -0,0 +1,146 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"reflect"
+)
+
+// A Diagnostic describes a problem with the XMP data which did not prevent
+// the data from being used, for example because a malformed value could be
+// repaired.
+type Diagnostic struct {
+	// Property is the name of the affected property.
+	Property xml.Name
+
+	// Message describes the problem.
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return formatName(d.Property) + ": " + d.Message
+}
+
+// Diagnostics returns the problems encountered while reading the packet.
+// The list is cleared by [Packet.Reset].
+func (p *Packet) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+// addDiagnostic records a problem with the given property.
+func (p *Packet) addDiagnostic(name xml.Name, msg string) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{Property: name, Message: msg})
+	if m := getMetrics(); m != nil {
+		m.Add(CounterRepairs, 1)
+	}
+}
+
+// A repairer is a [Value] which can decode some malformed representations
+// which are rejected by its DecodeAnother method, for example numbers
+// written with a decimal comma.
+type repairer interface {
+	// repair converts a malformed low-level XMP representation into a
+	// Value.  If the input can be repaired, the repaired value is returned
+	// together with a description of the problem.  Otherwise, ok is false.
+	repair(Raw) (val Value, msg string, ok bool)
+}
+
+// repairValue tries to decode val as a value of the same type as zero,
+// repairing malformed input if possible.  If val is valid, msg is empty.
+func repairValue(zero Value, val Raw) (res Value, msg string, ok bool) {
+	res, err := zero.DecodeAnother(val)
+	if err == nil {
+		return res, "", true
+	}
+	r, isRepairer := zero.(repairer)
+	if !isRepairer {
+		return nil, "", false
+	}
+	return r.repair(val)
+}
+
+// repairAs is like repairValue, but returns a value of type E.
+func repairAs[E Value](val Raw) (res E, msg string, ok bool) {
+	dec, ok := DecoderFor(reflect.TypeFor[E]())
+	if !ok {
+		return res, "", false
+	}
+	v, msg, ok := repairValue(dec, val)
+	if !ok {
+		return res, "", false
+	}
+	res, ok = v.(E)
+	return res, msg, ok
+}
+
+// repairValues repairs malformed values of properties which are described
+// in the schema registry.  All repairs are recorded as diagnostics.  This
+// is used in lenient mode, see [DecodeOptions].
+func (p *Packet) repairValues() {
+	names := make([]xml.Name, 0, len(p.Properties))
+	for name := range p.Properties {
+		names = append(names, name)
+	}
+	sortNames(names)
+	for _, name := range names {
+		val := p.Properties[name]
+		info, ok := lookupProperty(name)
+		if !ok {
+			continue
+		}
+		dec, ok := DecoderFor(info.Type)
+		if !ok {
+			continue
+		}
+		res, msg, ok := repairValue(dec, val)
+		if !ok || msg == "" {
+			continue
+		}
+		p.addDiagnostic(name, msg)
+		p.Properties[name] = res.EncodeXMP(p)
+	}
+}
+
+// repairElements decodes the elements of an array as values of type E,
+// repairing malformed elements where possible.  A single value is treated
+// as a one-element array.  The qualifiers of the array are returned
+// together with the elements.
+func repairElements[E Value](val Raw) ([]E, Q, string, bool) {
+	a, isArray := val.(RawArray)
+	if !isArray {
+		v, msg, ok := repairAs[E](val)
+		if !ok {
+			return nil, nil, "", false
+		}
+		return []E{v}, nil, msg, true
+	}
+
+	res := make([]E, len(a.Value))
+	var msg string
+	for i, elem := range a.Value {
+		v, m, ok := repairAs[E](elem)
+		if !ok {
+			return nil, nil, "", false
+		}
+		if m != "" {
+			msg = m
+		}
+		res[i] = v
+	}
+	return res, a.Q, msg, true
+}
@@ -0,0 +1,58 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "strings"
+
+// DigiKam represents the digiKam namespace.
+// Only the hierarchical tags are supported.
+type DigiKam struct {
+	_ Namespace `xmp:"http://www.digikam.org/ns/1.0/"`
+	_ Prefix    `xmp:"digiKam"`
+
+	// TagsList lists the tags of the resource.  Each entry is the path of
+	// a tag in the tag hierarchy, with the components separated by "/",
+	// for example "Places/Europe/Scotland".
+	TagsList OrderedArray[Text]
+}
+
+// Tags returns the tags of the resource, split into their path
+// components.
+func (d *DigiKam) Tags() [][]string {
+	res := make([][]string, 0, len(d.TagsList.V))
+	for _, tag := range d.TagsList.V {
+		res = append(res, strings.Split(tag.V, "/"))
+	}
+	return res
+}
+
+// AddTag adds a tag, given as the path of components from the root of the
+// tag hierarchy.  If the tag is already present, the model is not changed.
+func (d *DigiKam) AddTag(path ...string) {
+	if len(path) == 0 {
+		return
+	}
+	tag := strings.Join(path, "/")
+	for _, t := range d.TagsList.V {
+		if t.V == tag {
+			return
+		}
+	}
+	d.TagsList.Append(NewText(tag))
+}
+
+const digiKamNamespace = "http://www.digikam.org/ns/1.0/"
@@ -0,0 +1,64 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDigiKam(t *testing.T) {
+	in := &DigiKam{}
+	in.AddTag("Places", "Europe", "Scotland")
+	in.AddTag("People", "Ada")
+	in.AddTag("Places", "Europe", "Scotland")
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `<digiKam:TagsList>`) {
+		t.Errorf("wrong encoding:\n%s", buf.String())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &DigiKam{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+
+	expected := [][]string{
+		{"Places", "Europe", "Scotland"},
+		{"People", "Ada"},
+	}
+	if d := cmp.Diff(expected, out.Tags()); d != "" {
+		t.Errorf("tags differ (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,122 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+// DJI represents the namespace used by DJI drones to record the position
+// and orientation of the aircraft and the camera gimbal.
+//
+// Angles are given in degrees, altitudes in metres and speeds in metres per
+// second.  There is no published specification; the property names follow
+// the files written by current DJI firmware.
+type DJI struct {
+	_ Namespace `xmp:"http://www.dji.com/drone-dji/1.0/"`
+	_ Prefix    `xmp:"drone-dji"`
+
+	// AbsoluteAltitude is the altitude of the aircraft above sea level.
+	AbsoluteAltitude Real
+
+	// RelativeAltitude is the altitude of the aircraft above the take-off
+	// point.
+	RelativeAltitude Real
+
+	// GpsLatitude is the latitude of the aircraft, in degrees.
+	GpsLatitude Real
+
+	// GpsLongitude is the longitude of the aircraft, in degrees.
+	GpsLongitude Real
+
+	// GpsLongtitude is the misspelt form of GpsLongitude, written by older
+	// firmware versions.  Use [DJI.Longitude] to read either form.
+	GpsLongtitude Real
+
+	// GimbalRollDegree is the roll angle of the camera gimbal.
+	GimbalRollDegree Real
+
+	// GimbalYawDegree is the yaw angle (heading) of the camera gimbal.
+	GimbalYawDegree Real
+
+	// GimbalPitchDegree is the pitch angle of the camera gimbal.  A value
+	// of -90 means that the camera points straight down.
+	GimbalPitchDegree Real
+
+	// FlightRollDegree is the roll angle of the aircraft.
+	FlightRollDegree Real
+
+	// FlightYawDegree is the yaw angle (heading) of the aircraft.
+	FlightYawDegree Real
+
+	// FlightPitchDegree is the pitch angle of the aircraft.
+	FlightPitchDegree Real
+
+	// FlightXSpeed is the speed of the aircraft in north direction.
+	FlightXSpeed Real
+
+	// FlightYSpeed is the speed of the aircraft in east direction.
+	FlightYSpeed Real
+
+	// FlightZSpeed is the vertical speed of the aircraft, positive
+	// downwards.
+	FlightZSpeed Real
+
+	// CamReverse indicates whether the camera is mounted upside down.
+	CamReverse Integer
+
+	// GimbalReverse indicates whether the gimbal is mounted upside down.
+	GimbalReverse Integer
+
+	// RtkFlag describes the quality of the RTK position fix.  The value 50
+	// indicates a fixed solution.
+	RtkFlag Integer
+
+	// RtkStdLon, RtkStdLat and RtkStdHgt are the standard deviations of
+	// the RTK position, in metres.
+	RtkStdLon, RtkStdLat, RtkStdHgt Real
+
+	// CalibratedFocalLength is the calibrated focal length of the camera,
+	// in pixels.
+	CalibratedFocalLength Real
+
+	// CalibratedOpticalCenterX and CalibratedOpticalCenterY give the
+	// calibrated position of the optical centre, in pixels.
+	CalibratedOpticalCenterX, CalibratedOpticalCenterY Real
+
+	// DewarpData holds the lens distortion parameters, as a comma
+	// separated list.
+	DewarpData Text
+
+	// DewarpFlag indicates whether lens distortion has been corrected in
+	// the image.
+	DewarpFlag Integer
+
+	// SelfData is a user-defined string, which can be set in the DJI
+	// apps.
+	SelfData Text
+
+	// Version is the version of the DJI metadata format.
+	Version Text
+}
+
+// Longitude returns the longitude of the aircraft.  Both the correct and
+// the misspelt property names are tried.
+func (d *DJI) Longitude() Real {
+	if !d.GpsLongitude.IsZero() {
+		return d.GpsLongitude
+	}
+	return d.GpsLongtitude
+}
+
+const djiNamespace = "http://www.dji.com/drone-dji/1.0/"
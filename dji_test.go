@@ -0,0 +1,85 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDJI(t *testing.T) {
+	in := &DJI{
+		AbsoluteAltitude:  Real{V: 152.25},
+		RelativeAltitude:  Real{V: 60},
+		GpsLatitude:       Real{V: 55.9533},
+		GpsLongitude:      Real{V: -3.1883},
+		GimbalPitchDegree: Real{V: -90},
+		GimbalYawDegree:   Real{V: 12.5},
+		FlightYawDegree:   Real{V: 13},
+		SelfData:          NewText("survey 7"),
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<drone-dji:GimbalPitchDegree>-90</drone-dji:GimbalPitchDegree>") {
+		t.Errorf("wrong encoding:\n%s", buf.String())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &DJI{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
+
+func TestDJIFirmware(t *testing.T) {
+	// Older firmware writes explicit plus signs and misspells the
+	// longitude property.
+	body := `<rdf:Description rdf:about=""
+	xmlns:drone-dji="http://www.dji.com/drone-dji/1.0/"
+	drone-dji:AbsoluteAltitude="+152.25"
+	drone-dji:GpsLatitude="55.9533"
+	drone-dji:GpsLongtitude="-3.1883"
+	drone-dji:FlightPitchDegree="+1.20"/>`
+	p, err := Read(strings.NewReader(head + body + foot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &DJI{}
+	p.Get(d)
+	if d.AbsoluteAltitude.V != 152.25 || d.FlightPitchDegree.V != 1.2 {
+		t.Errorf("wrong values: %v, %v", d.AbsoluteAltitude, d.FlightPitchDegree)
+	}
+	if lon := d.Longitude(); lon.V != -3.1883 {
+		t.Errorf("wrong longitude: %v", lon)
+	}
+}
@@ -0,0 +1,353 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// DynamicMedia represents the XMP Dynamic Media namespace.
+//
+// See section 1.2.6 of part 2 of the XMP specification (2016).
+type DynamicMedia struct {
+	_ Namespace `xmp:"http://ns.adobe.com/xmp/1.0/DynamicMedia/"`
+	_ Prefix    `xmp:"xmpDM"`
+
+	// Album is the name of the album.
+	Album Text `xmp:"album"`
+
+	// AltTimecode is a timecode set by the user.  If present, it is used
+	// instead of StartTimecode.
+	AltTimecode Timecode `xmp:"altTimecode"`
+
+	// Artist is the name of the artist or artists.
+	Artist Text `xmp:"artist"`
+
+	// AudioChannelType is the audio channel type.  One of "Mono", "Stereo",
+	// "5.1", "7.1", "16 Channel", or "Other".
+	AudioChannelType Text `xmp:"audioChannelType"`
+
+	// AudioCompressor is the audio compression used, e.g. "MP3".
+	AudioCompressor Text `xmp:"audioCompressor"`
+
+	// AudioSampleRate is the audio sample rate in Hz.
+	AudioSampleRate Integer `xmp:"audioSampleRate"`
+
+	// AudioSampleType is the audio sample type.  One of "8Int", "16Int",
+	// "24Int", "32Int", "32Float", "Compressed", "Packed", or "Other".
+	AudioSampleType Text `xmp:"audioSampleType"`
+
+	// BeatSpliceParams holds the parameters for beat splice time
+	// stretching, if StretchMode is "Beat Splice".
+	BeatSpliceParams BeatSpliceStretch `xmp:"beatSpliceParams"`
+
+	// Comment is a user's comment.
+	Comment Text `xmp:"comment"`
+
+	// Composer is the name of the composer.
+	Composer Text `xmp:"composer"`
+
+	// Duration is the duration of the media file.
+	Duration Time `xmp:"duration"`
+
+	// Engineer is the name of the engineer.
+	Engineer Text `xmp:"engineer"`
+
+	// Genre is the name of the genre.
+	Genre Text `xmp:"genre"`
+
+	// IntroTime is the duration of the lead time for queuing music.
+	IntroTime Time `xmp:"introTime"`
+
+	// LogComment contains the user's log comments.
+	LogComment Text `xmp:"logComment"`
+
+	// OutCue is the time at which to fade out.
+	OutCue Time `xmp:"outCue"`
+
+	// RelativeTimestamp is the start time of the media inside the audio
+	// project.
+	RelativeTimestamp Time `xmp:"relativeTimestamp"`
+
+	// ProjectRef references the project of which this file is a part.
+	ProjectRef ProjectLink `xmp:"projectRef"`
+
+	// ReleaseDate is the date the title was released.
+	ReleaseDate Date `xmp:"releaseDate"`
+
+	// ResampleParams holds the parameters for resample time stretching,
+	// if StretchMode is "Resample".
+	ResampleParams ResampleStretch `xmp:"resampleParams"`
+
+	// Scene is the name of the scene.
+	Scene Text `xmp:"scene"`
+
+	// ShotName is the name of the shot or take.
+	ShotName Text `xmp:"shotName"`
+
+	// StartTimecode is the timecode of the first frame of the video, as
+	// found in the file.
+	StartTimecode Timecode `xmp:"startTimecode"`
+
+	// StretchMode is the audio time-stretching mode: "Fixed length",
+	// "Time-Scale", "Resample", "Beat Splice", or "Hybrid".
+	StretchMode Text `xmp:"stretchMode"`
+
+	// TakeNumber is a numeric value indicating the absolute number of a
+	// take.
+	TakeNumber Integer `xmp:"takeNumber"`
+
+	// TapeName is the name of the tape from which the clip was captured.
+	TapeName Text `xmp:"tapeName"`
+
+	// TimeScaleParams holds the parameters for time-scale stretching, if
+	// StretchMode is "Time-Scale".
+	TimeScaleParams TimeScaleStretch `xmp:"timeScaleParams"`
+
+	// TrackNumber is a numeric value indicating the order of the audio file
+	// within its original recording.
+	TrackNumber Integer `xmp:"trackNumber"`
+
+	// Tracks is an unordered list of tracks.  A track is a named set of
+	// markers, which can specify a frame rate for all markers in the set.
+	Tracks UnorderedArray[Track] `xmp:"Tracks"`
+
+	// VideoFrameSize is the frame size of the video.
+	VideoFrameSize Dimensions `xmp:"videoFrameSize"`
+}
+
+// Track represents a named set of markers.
+//
+// See section 1.2.6.17 of part 2 of the XMP specification (2016).
+type Track struct {
+	// FrameRate is the frame rate used by the markers of the track, for
+	// example "f25" or "f30000s1001".
+	FrameRate FrameRate
+
+	// Markers is the list of markers in the track.
+	Markers OrderedArray[Marker]
+
+	// TrackName is a descriptive name for the track.
+	TrackName Text
+
+	// TrackType is the type of the markers in the track, for example
+	// "Comment", "Chapter", "FlashCuePoint", "WebLink", "Cue", or "Beat".
+	TrackType Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (t Track) IsZero() bool {
+	return t.FrameRate.IsZero() && t.Markers.IsZero() &&
+		t.TrackName.IsZero() && t.TrackType.IsZero() && len(t.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (t Track) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     t.Q,
+	}
+	setField(res, p, dmNamespace, "frameRate", t.FrameRate)
+	setField(res, p, dmNamespace, "markers", t.Markers)
+	setField(res, p, dmNamespace, "trackName", t.TrackName)
+	setField(res, p, dmNamespace, "trackType", t.TrackType)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Track) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Track{
+		FrameRate: getField[FrameRate](s, dmNamespace, "frameRate"),
+		Markers:   getField[OrderedArray[Marker]](s, dmNamespace, "markers"),
+		TrackName: getField[Text](s, dmNamespace, "trackName"),
+		TrackType: getField[Text](s, dmNamespace, "trackType"),
+		Q:         s.Q,
+	}, nil
+}
+
+// Marker represents a marker within a [Track], for example a chapter
+// start or a cue point.
+//
+// See section 1.2.6.10 of part 2 of the XMP specification (2016).
+type Marker struct {
+	// Comment is a descriptive comment.
+	Comment Text
+
+	// CuePointParams lists additional parameters of a cue point.
+	CuePointParams OrderedArray[CuePointParam]
+
+	// CuePointType is the type of a cue point, for example "Event" or
+	// "Navigation".
+	CuePointType Text
+
+	// Duration is the length of the marked section, in units of the frame
+	// rate of the enclosing track.
+	Duration FrameCount
+
+	// Location is the URL of a web link marker.
+	Location URI
+
+	// Name is the name of the marker.
+	Name Text
+
+	// StartTime is the start of the marker, in units of the frame rate
+	// of the enclosing track.
+	StartTime FrameCount
+
+	// Target is the frame target of a web link marker, for example
+	// "_blank".
+	Target Text
+
+	// Type is the type of the marker, for example "Chapter" or "Cue".
+	Type Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (m Marker) IsZero() bool {
+	return m.Comment.IsZero() && m.CuePointParams.IsZero() &&
+		m.CuePointType.IsZero() && m.Duration.IsZero() &&
+		m.Location.IsZero() && m.Name.IsZero() && m.StartTime.IsZero() &&
+		m.Target.IsZero() && m.Type.IsZero() && len(m.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (m Marker) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     m.Q,
+	}
+	setField(res, p, dmNamespace, "comment", m.Comment)
+	setField(res, p, dmNamespace, "cuePointParams", m.CuePointParams)
+	setField(res, p, dmNamespace, "cuePointType", m.CuePointType)
+	setField(res, p, dmNamespace, "duration", m.Duration)
+	setField(res, p, dmNamespace, "location", m.Location)
+	setField(res, p, dmNamespace, "name", m.Name)
+	setField(res, p, dmNamespace, "startTime", m.StartTime)
+	setField(res, p, dmNamespace, "target", m.Target)
+	setField(res, p, dmNamespace, "type", m.Type)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Marker) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Marker{
+		Comment:        getField[Text](s, dmNamespace, "comment"),
+		CuePointParams: getField[OrderedArray[CuePointParam]](s, dmNamespace, "cuePointParams"),
+		CuePointType:   getField[Text](s, dmNamespace, "cuePointType"),
+		Duration:       getField[FrameCount](s, dmNamespace, "duration"),
+		Location:       getField[URI](s, dmNamespace, "location"),
+		Name:           getField[Text](s, dmNamespace, "name"),
+		StartTime:      getField[FrameCount](s, dmNamespace, "startTime"),
+		Target:         getField[Text](s, dmNamespace, "target"),
+		Type:           getField[Text](s, dmNamespace, "type"),
+		Q:              s.Q,
+	}, nil
+}
+
+// CuePointParam is a key/value pair attached to a cue point [Marker].
+//
+// See section 1.2.6.3 of part 2 of the XMP specification (2016).
+type CuePointParam struct {
+	Key   Text
+	Value Text
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (c CuePointParam) IsZero() bool {
+	return c.Key.IsZero() && c.Value.IsZero() && len(c.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (c CuePointParam) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     c.Q,
+	}
+	setField(res, p, dmNamespace, "key", c.Key)
+	setField(res, p, dmNamespace, "value", c.Value)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (CuePointParam) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return CuePointParam{
+		Key:   getField[Text](s, dmNamespace, "key"),
+		Value: getField[Text](s, dmNamespace, "value"),
+		Q:     s.Q,
+	}, nil
+}
+
+// ProjectLink is a reference to a project file, as used in
+// xmpDM:projectRef.
+//
+// See section 1.2.6.12 of part 2 of the XMP specification (2016).
+type ProjectLink struct {
+	// Path is the full path to the project file.
+	Path URI
+
+	// Type is the type of the file: "movie", "still", "audio", or
+	// "custom".
+	Type Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (l ProjectLink) IsZero() bool {
+	return l.Path.IsZero() && l.Type.IsZero() && len(l.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (l ProjectLink) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     l.Q,
+	}
+	setField(res, p, dmNamespace, "path", l.Path)
+	setField(res, p, dmNamespace, "type", l.Type)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (ProjectLink) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return ProjectLink{
+		Path: getField[URI](s, dmNamespace, "path"),
+		Type: getField[Text](s, dmNamespace, "type"),
+		Q:    s.Q,
+	}, nil
+}
+
+const dmNamespace = "http://ns.adobe.com/xmp/1.0/DynamicMedia/"
@@ -0,0 +1,171 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTrackRoundTrip(t *testing.T) {
+	in := &DynamicMedia{
+		Tracks: UnorderedArray[Track]{V: []Track{
+			{
+				FrameRate: NewFrameRate(25, 1),
+				TrackName: NewText("Chapters"),
+				TrackType: NewText("Chapter"),
+				Markers: OrderedArray[Marker]{V: []Marker{
+					{StartTime: FrameCount{Count: 0}, Name: NewText("Intro")},
+					{StartTime: FrameCount{Count: 250}, Duration: FrameCount{Count: 50}, Name: NewText("Part 1"), Comment: NewText("first part")},
+				}},
+			},
+		}},
+		ProjectRef: ProjectLink{
+			Path: NewURI("file:///projects/interview.prproj"),
+			Type: NewText("movie"),
+		},
+		VideoFrameSize: Dimensions{
+			W:    Real{V: 1920},
+			H:    Real{V: 1080},
+			Unit: NewText("pixel"),
+		},
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &DynamicMedia{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
+
+func TestTrackSample(t *testing.T) {
+	body, err := os.ReadFile("testdata/premiere-video.xmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Read(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := &DynamicMedia{}
+	p.Get(dm)
+
+	want := []Track{
+		{
+			FrameRate: NewFrameRate(25, 1),
+			TrackName: NewText("Comment"),
+			TrackType: NewText("Comment"),
+			Markers: OrderedArray[Marker]{V: []Marker{
+				{
+					StartTime: FrameCount{Count: 125},
+					Name:      NewText("Interview starts"),
+				},
+			}},
+		},
+	}
+	if d := cmp.Diff(want, dm.Tracks.V); d != "" {
+		t.Errorf("tracks differ (-want +got):\n%s", d)
+	}
+}
+
+func TestTimecodeSample(t *testing.T) {
+	body, err := os.ReadFile("testdata/premiere-video.xmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Read(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := &DynamicMedia{}
+	p.Get(dm)
+
+	want := Timecode{
+		TimeFormat: NewText("25Timecode"),
+		TimeValue:  NewText("00:00:00:00"),
+	}
+	if d := cmp.Diff(want, dm.StartTimecode); d != "" {
+		t.Errorf("start timecode differs (-want +got):\n%s", d)
+	}
+}
+
+func TestCuePoints(t *testing.T) {
+	in := &DynamicMedia{
+		Tracks: UnorderedArray[Track]{V: []Track{
+			{
+				FrameRate: NewFrameRate(30000, 1001),
+				TrackType: NewText("FlashCuePoint"),
+				Markers: OrderedArray[Marker]{V: []Marker{
+					{
+						StartTime:    FrameCount{Count: 900},
+						Name:         NewText("quiz"),
+						Type:         NewText("FlashCuePoint"),
+						CuePointType: NewText("Event"),
+						CuePointParams: OrderedArray[CuePointParam]{V: []CuePointParam{
+							{Key: NewText("question"), Value: NewText("3")},
+							{Key: NewText("mode"), Value: NewText("single")},
+						}},
+					},
+					{
+						StartTime: FrameCount{Count: 1800, Rate: NewFrameRate(30000, 1001)},
+						Type:      NewText("WebLink"),
+						Location:  NewURI("https://example.com/chapter2"),
+						Target:    NewText("_blank"),
+					},
+				}},
+			},
+		}},
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &DynamicMedia{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
@@ -22,6 +22,10 @@
 // packet.  XMP packets can be read from file using the [Read] function and
 // written to file using the [Packet.Write] method.
 //
+// For JPEG files, [OpenImageMetadata] reads the XMP, EXIF and IPTC-IIM
+// metadata blocks, reconciles them into a single packet, and writes all
+// blocks back in sync when [ImageMetadata.Save] is called.
+//
 // # Properties
 //
 // An XMP packet stores a set of properties.  Each property is identified by a
@@ -35,20 +39,70 @@
 //   - [Text] represents a generic text string.
 //   - [AgentName] represents the name of some document creator software.
 //   - [AlternativeArray] is an ordered array of values.
+//   - [Area] describes an area of an image.
+//   - [ArtworkDetails] describes an artwork or object.
+//   - [Base64Data] represents binary data.
+//   - [BeatSpliceStretch] holds audio beat splice parameters.
+//   - [CFAPattern] describes the colour filter array of an image sensor.
+//   - [CollectionInfo] identifies a collection.
+//   - [Colorant] describes a colour used in a document.
+//   - [ContactInfo] holds contact information.
+//   - [CopyrightOwner] identifies a copyright owner.
+//   - [CuePointParam] is a key/value pair attached to a cue point.
 //   - [Date] represents a date and time.
+//   - [DateRange] represents a period of time.
+//   - [Decimal] represents a number which keeps its textual form.
+//   - [DeviceSettings] describes the picture-taking conditions of a camera.
+//   - [Dimensions] represents the size of an image or page.
+//   - [Flash] describes the state of a camera flash.
+//   - [Font] describes a font used in a document.
+//   - [FrameCount] represents a position in a media file, in frames.
+//   - [FrameRate] represents the frame rate of a media file.
+//   - [GPSCoordinate] represents a latitude or longitude.
 //   - [GUID] represents a globally unique identifier.
+//   - [Identifier] is an identifier together with its scheme.
+//   - [Integer] represents a signed integer.
+//   - [Job] describes a job for which a resource is used.
+//   - [KeywordInfo] holds a keyword hierarchy.
+//   - [KeywordStruct] is a node in a keyword hierarchy.
+//   - [Layer] describes a text layer of a Photoshop document.
+//   - [Licensor] describes a party which licenses an image.
 //   - [Locale] represents a language code.
 //   - [Localized] represents a localized text value
+//   - [LocationDetails] describes a location.
+//   - [Marker] represents a marker in a media track.
+//   - [MPRegion] is a region of an image showing a person.
+//   - [MPRegionInfo] describes the tagged regions of an image.
 //   - [MimeType] represents the media type of a file.
+//   - [OECF] describes an opto-electronic conversion function.
 //   - [OptionalBool] represents a value which can be true, false or unset.
 //   - [OrderedArray] is an ordered array of values.
+//   - [OrganisationDetails] describes a person or organisation and their roles.
+//   - [PantryItem] holds the metadata of an ingredient of a document.
+//   - [PersonDetails] describes a person.
+//   - [ProjectLink] is a reference to a project file.
 //   - [ProperName] represents a proper name.
+//   - [Rational] represents a fraction of two integers.
 //   - [Real] represents a floating-point number.
+//   - [Region] describes a region of an image.
+//   - [RegionInfo] lists the regions of an image.
+//   - [RegistryEntry] identifies a resource in a registry.
 //   - [RenditionClass] states the form or intended usage of a resource
 //     (e.g. "draft" or "low-res").
+//   - [ResampleStretch] holds audio resample parameters.
+//   - [ResourceEvent] describes an action which changed a document.
 //   - [ResourceRef] represents a reference to an external resource.
+//   - [Struct] is a structure whose fields are not known in advance.
+//   - [SwatchGroup] is a named group of colorants.
+//   - [Thumbnail] represents a thumbnail image.
+//   - [Time] represents a time within a media file.
+//   - [TimeScaleStretch] holds audio time-scale stretching parameters.
+//   - [Timecode] represents an SMPTE timecode.
+//   - [Track] represents a named set of markers in a media file.
+//   - [URI] is a URI stored as text.
 //   - [URL] is a URL or URI.
 //   - [UnorderedArray] is an unordered array of values.
+//   - [Version] describes one version of a document.
 //
 // Additional types can be defined by implementing the [Value] interface.
 //
@@ -67,9 +121,36 @@
 //   - [MediaManagement] represents the XMP Media Management namespace.
 //   - [RightsManagement] represents the XMP RightsManagement Management namespace.
 //   - [Basic] represents the XMP basic namespace.
+//   - [BasicJobTicket] represents the XMP Basic Job Ticket namespace.
+//   - [PagedText] represents the XMP Paged-Text namespace.
+//   - [Note] represents the XMP Note namespace.
+//   - [DigiKam] represents the digiKam namespace.
+//   - [DJI] represents the DJI drone namespace.
+//   - [DynamicMedia] represents the XMP Dynamic Media namespace.
+//   - [EXIF] represents the EXIF namespace.
+//   - [GDepth] represents the Google depth map namespace.
+//   - [GImage] represents the Google image namespace.
+//   - [GPano] represents the Google Photo Sphere namespace.
+//   - [IPTCCore] represents the IPTC Core namespace.
+//   - [IPTCExt] represents the IPTC Extension namespace.
+//   - [MicrosoftPhoto] represents the Microsoft Photo namespace.
+//   - [MicrosoftPhotoRegions] represents the Microsoft Photo 1.2 region namespace.
+//   - [MWGRegions] represents the MWG regions namespace.
+//   - [MWGCollections] represents the MWG collections namespace.
+//   - [MWGKeywords] represents the MWG hierarchical keywords namespace.
+//   - [PDF] represents the Adobe PDF namespace.
+//   - [PDFA] represents the PDF/A identification namespace.
+//   - [PDFX] represents the PDF/X identification namespace.
+//   - [Photoshop] represents the Adobe Photoshop namespace.
+//   - [PRISM] represents the PRISM basic metadata namespace.
+//   - [PLUS] represents the Picture Licensing Universal System namespace.
+//   - [ContentCredentials] references C2PA content credentials.
 //
 // Additional models can be defined by defining a struct with fields of type
 // [Value] and using the Go struct tags to specify the XMP property name where
 // this is different from the field name.  See [DublinCore], [Namespace] and
 // [Prefix] for examples.
+//
+// The function [Label] returns human-readable names of well-known
+// properties, for display in user interfaces.
 package xmp
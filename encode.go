@@ -17,9 +17,12 @@
 package xmp
 
 import (
+	"bytes"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"sort"
+	"strings"
 
 	"golang.org/x/exp/maps"
 	"seehuhn.de/go/xmp/jvxml"
@@ -27,17 +30,131 @@ import (
 
 // PacketOptions can be used to control the output format of the [Packet.Write]
 // method.
+//
+// The functions [ProfileCompact], [ProfileAdobeToolkit5] and
+// [ProfileCanonical] provide ready-made options for common use cases.
 type PacketOptions struct {
 	Pretty bool
+
+	// Indent (optional) is the string used to indent nested elements if
+	// Pretty is set.  The default is a single tab character.
+	Indent string
+
+	// CompressAbove (optional) enables compression of large values.  Values
+	// whose XML encoding is longer than this many bytes are stored in
+	// gzip-compressed, base64-encoded form, if this reduces their size.
+	// Such values are decompressed transparently by [Packet.Decode].
+	// Zero means no compression.
+	CompressAbove int
+
+	// XMPMeta causes the rdf:RDF element to be wrapped in an x:xmpmeta
+	// element, as written by the Adobe XMP Toolkit.
+	XMPMeta bool
+
+	// Toolkit (optional) is the value of the x:xmptk attribute of the
+	// x:xmpmeta element.  This is only used if XMPMeta is set.
+	Toolkit string
+
+	// Padding (optional) is the number of bytes of white space written at
+	// the end of the packet, immediately before the xpacket trailer and
+	// after any trailing comments.  This allows to update the packet in place,
+	// as described in section 7.3.2 of ISO 16684-1:2011.
+	Padding int
+
+	// SortAttributes causes the attributes of every element to be sorted
+	// by namespace and local name, see [CompareNames].
+	SortAttributes bool
+
+	// DefaultPrefixes causes namespace prefixes registered in the packet to
+	// be ignored, see [CanonicalPolicy].
+	DefaultPrefixes bool
+}
+
+// The following functions return predefined output profiles, for use with
+// [Packet.Write].  Each call returns a new value, which the caller may
+// modify.
+
+// ProfileCompact returns options which give the shortest output.
+func ProfileCompact() *PacketOptions {
+	return &PacketOptions{}
+}
+
+// ProfileAdobeToolkit5 returns options which mimic the layout used by
+// version 5.x of the Adobe XMP Toolkit: the packet is wrapped in an
+// x:xmpmeta element, elements are indented by three spaces, and padding is
+// added for in-place updates.
+func ProfileAdobeToolkit5() *PacketOptions {
+	return &PacketOptions{
+		Pretty:  true,
+		Indent:  "   ",
+		XMPMeta: true,
+		Toolkit: "seehuhn.de/go/xmp",
+		Padding: 2048,
+	}
+}
+
+// ProfileCanonical returns options which give output depending only on the
+// contents of the packet: attributes are sorted and the well-known
+// namespace prefixes are used.  Use [Packet.Canonical] for more control.
+func ProfileCanonical() *PacketOptions {
+	return &PacketOptions{
+		Pretty:          true,
+		SortAttributes:  true,
+		DefaultPrefixes: true,
+	}
+}
+
+// indent returns the string used to indent nested elements, or the empty
+// string if no indentation is used.
+func (opt *PacketOptions) indent() string {
+	if opt == nil || !opt.Pretty {
+		return ""
+	}
+	if opt.Indent != "" {
+		return opt.Indent
+	}
+	return "\t"
 }
 
 // Write writes the XMP packet to the given writer.
 func (p *Packet) Write(w io.Writer, opt *PacketOptions) error {
+	if m := getMetrics(); m != nil {
+		cw := &countingWriter{w: w}
+		defer func() { m.Add(CounterBytesWritten, cw.n) }()
+		w = cw
+	}
+	return p.write(w, opt)
+}
+
+// write implements [Packet.Write], without recording metrics.
+func (p *Packet) write(w io.Writer, opt *PacketOptions) error {
+	if opt == nil {
+		opt = &PacketOptions{}
+	}
+	if opt.CompressAbove > 0 {
+		p = p.compressed(opt.CompressAbove)
+	}
+	if opt.DefaultPrefixes {
+		q := *p
+		q.nsToPrefix = maps.Clone(defaultPrefix)
+		p = &q
+	}
+
 	e, err := p.newEncoder(w, opt)
 	if err != nil {
 		return err
 	}
+	var attrCmp func(a, b xml.Name) int
+	if opt.SortAttributes {
+		attrCmp = CompareNames
+	}
+	return p.writeProperties(e, attrCmp)
+}
 
+// writeProperties writes all properties of the packet to the encoder, and
+// closes the encoder.  If attrCmp is not nil, the attributes of every
+// element are sorted using this function.
+func (p *Packet) writeProperties(e *encoder, attrCmp func(a, b xml.Name) int) error {
 	names := maps.Keys(p.Properties)
 	sort.Slice(names, func(i, j int) bool {
 		if names[i].Space != names[j].Space {
@@ -47,24 +164,27 @@ func (p *Packet) Write(w io.Writer, opt *PacketOptions) error {
 	})
 
 	for _, name := range names {
+		err := e.writeComments(p.PropertyComments[name], "")
+		if err != nil {
+			return err
+		}
+
 		value := p.Properties[name]
 		tokens := value.appendXML(nil, name)
 		for _, t := range tokens {
+			if attrCmp != nil {
+				sortAttrs(t, attrCmp)
+			}
 			t = e.fixToken(t)
 
-			err = e.EncodeToken(t)
+			err := e.EncodeToken(t)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	err = e.Close()
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return e.Close()
 }
 
 func (e *encoder) fixToken(t jvxml.Token) jvxml.Token {
@@ -97,63 +217,31 @@ type encoder struct {
 	*jvxml.Encoder
 	nsToPrefix map[string]string
 	prefixToNS map[string]string
+	trailing   []string
+	xmpMeta    bool
+	padding    int
 }
 
-// newEncoder returns a new encoder that writes to w.
+// newEncoder returns a new encoder that writes to w, using the output
+// format described by opt.
 func (p *Packet) newEncoder(w io.Writer, opt *PacketOptions) (*encoder, error) {
-	// Gather a list of all namespaces used in the packet.
-	nsUsed := make(map[string]struct{})
+	nsUsed := p.getNamespaces()
 	nsUsed[xmlNamespace] = struct{}{}
 	nsUsed[rdfNamespace] = struct{}{}
-	for key, value := range p.Properties {
-		nsUsed[key.Space] = struct{}{}
-		value.getNamespaces(nsUsed)
-	}
-	nsList := maps.Keys(nsUsed)
-	sort.Strings(nsList)
-
-	// Fix the namespace prefixes.
-	nsToPrefix := make(map[string]string)
-	prefixToNS := make(map[string]string)
-	// register default namespaces first, ...
-	nsToPrefix[xmlNamespace] = "xml"
-	prefixToNS["xml"] = xmlNamespace
-	nsToPrefix[rdfNamespace] = "rdf"
-	prefixToNS["rdf"] = rdfNamespace
-	// ... then the ones registered in the packet, ...
-	for _, ns := range nsList {
-		if _, alreadyDone := nsToPrefix[ns]; alreadyDone {
-			continue
-		}
-		pfx, isRegistered := p.nsToPrefix[ns]
-		if !isRegistered {
-			continue
-		}
-		if _, isClash := nsToPrefix[pfx]; isClash {
-			continue
-		}
-		nsToPrefix[ns] = pfx
-		prefixToNS[pfx] = ns
-	}
-	// ... and then the rest:
-	for _, ns := range nsList {
-		if _, alreadyDone := nsToPrefix[ns]; alreadyDone {
-			continue
-		}
-		pfx := getPrefix(prefixToNS, ns)
-		nsToPrefix[ns] = pfx
-		prefixToNS[pfx] = ns
-	}
+	nsToPrefix, prefixToNS := p.getPrefixes(nsUsed)
 
 	enc := jvxml.NewEncoder(w)
-	if opt != nil && opt.Pretty {
-		enc.Indent("", "\t")
+	if indent := opt.indent(); indent != "" {
+		enc.Indent("", indent)
 	}
 	e := &encoder{
 		w:          w,
 		Encoder:    enc,
 		nsToPrefix: nsToPrefix,
 		prefixToNS: prefixToNS,
+		trailing:   p.TrailingComments,
+		xmpMeta:    opt.XMPMeta,
+		padding:    opt.Padding,
 	}
 
 	err := e.EncodeToken(xml.ProcInst{
@@ -169,6 +257,39 @@ func (p *Packet) newEncoder(w io.Writer, opt *PacketOptions) (*encoder, error) {
 		return nil, err
 	}
 
+	for _, pi := range p.ProcInsts {
+		if !isValidProcInst(pi) {
+			return nil, fmt.Errorf("invalid XML processing instruction %q", pi.Target)
+		}
+		err = e.EncodeToken(pi)
+		if err != nil {
+			return nil, err
+		}
+		err = e.EncodeToken(xml.CharData("\n"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = e.writeComments(p.LeadingComments, "\n")
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.XMPMeta {
+		attrs := []xml.Attr{{Name: xml.Name{Local: "xmlns:x"}, Value: xmpMetaNamespace}}
+		if opt.Toolkit != "" {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "x:xmptk"}, Value: opt.Toolkit})
+		}
+		err = e.EncodeToken(xml.StartElement{
+			Name: xml.Name{Local: "x:xmpmeta"},
+			Attr: attrs,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var attrs []xml.Attr
 	namespaces := maps.Keys(e.nsToPrefix)
 	sort.Strings(namespaces)
@@ -204,6 +325,57 @@ func (p *Packet) newEncoder(w io.Writer, opt *PacketOptions) (*encoder, error) {
 	return e, nil
 }
 
+// getNamespaces returns the set of all namespaces used in the packet.
+func (p *Packet) getNamespaces() map[string]struct{} {
+	nsUsed := make(map[string]struct{})
+	for key, value := range p.Properties {
+		nsUsed[key.Space] = struct{}{}
+		value.getNamespaces(nsUsed)
+	}
+	return nsUsed
+}
+
+// getPrefixes chooses a prefix for each of the given namespaces.
+// The prefixes for the XML and RDF namespaces are always included.
+func (p *Packet) getPrefixes(nsUsed map[string]struct{}) (nsToPrefix, prefixToNS map[string]string) {
+	nsList := maps.Keys(nsUsed)
+	sort.Strings(nsList)
+
+	nsToPrefix = make(map[string]string)
+	prefixToNS = make(map[string]string)
+	// register default namespaces first, ...
+	nsToPrefix[xmlNamespace] = "xml"
+	prefixToNS["xml"] = xmlNamespace
+	nsToPrefix[rdfNamespace] = "rdf"
+	prefixToNS["rdf"] = rdfNamespace
+	// ... then the ones registered in the packet, ...
+	for _, ns := range nsList {
+		if _, alreadyDone := nsToPrefix[ns]; alreadyDone {
+			continue
+		}
+		pfx, isRegistered := p.nsToPrefix[ns]
+		if !isRegistered {
+			continue
+		}
+		if _, isClash := prefixToNS[pfx]; isClash {
+			continue
+		}
+		nsToPrefix[ns] = pfx
+		prefixToNS[pfx] = ns
+	}
+	// ... and then the rest:
+	for _, ns := range nsList {
+		if _, alreadyDone := nsToPrefix[ns]; alreadyDone {
+			continue
+		}
+		pfx := getPrefix(prefixToNS, ns)
+		nsToPrefix[ns] = pfx
+		prefixToNS[pfx] = ns
+	}
+
+	return nsToPrefix, prefixToNS
+}
+
 // Close closes the encoder.  This must be called after all data has been
 // written to the encoder.
 func (e *encoder) Close() error {
@@ -221,11 +393,32 @@ func (e *encoder) Close() error {
 		return err
 	}
 
+	if e.xmpMeta {
+		err = e.EncodeToken(xml.EndElement{Name: xml.Name{Local: "x:xmpmeta"}})
+		if err != nil {
+			return err
+		}
+	}
+
 	err = e.EncodeToken(xml.CharData("\n"))
 	if err != nil {
 		return err
 	}
 
+	err = e.writeComments(e.trailing, "\n")
+	if err != nil {
+		return err
+	}
+
+	// The padding must come immediately before the xpacket trailer, so
+	// that the packet can be updated in place.
+	if e.padding > 0 {
+		err = e.EncodeToken(xml.CharData(padding(e.padding)))
+		if err != nil {
+			return err
+		}
+	}
+
 	err = e.EncodeToken(xml.ProcInst{
 		Target: "xpacket",
 		Inst:   []byte("end=\"w\""),
@@ -241,6 +434,54 @@ func (e *encoder) Close() error {
 	return nil
 }
 
+// padding returns at least n bytes of white space, split into lines of
+// 100 bytes.
+func padding(n int) []byte {
+	line := append(bytes.Repeat([]byte{' '}, 99), '\n')
+	res := make([]byte, 0, n+len(line))
+	for len(res) < n {
+		res = append(res, line...)
+	}
+	return res
+}
+
+// writeComments writes the given XML comments to the encoder.  If sep is
+// not empty, it is written after each comment.
+func (e *encoder) writeComments(comments []string, sep string) error {
+	for _, c := range comments {
+		if !isValidComment(c) {
+			return fmt.Errorf("invalid XML comment %q", c)
+		}
+		err := e.EncodeToken(xml.Comment(c))
+		if err != nil {
+			return err
+		}
+		if sep != "" {
+			err = e.EncodeToken(xml.CharData(sep))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isValidComment reports whether c can be used as the text of an XML
+// comment.  The XML specification does not allow "--" inside comments,
+// and a comment must not end with "-".
+func isValidComment(c string) bool {
+	return !strings.Contains(c, "--") && !strings.HasSuffix(c, "-")
+}
+
+// isValidProcInst reports whether pi can be written inside an XMP packet.
+// The targets "xml" and "xpacket" are reserved, and the instruction must
+// not contain "?>".
+func isValidProcInst(pi xml.ProcInst) bool {
+	target := strings.ToLower(pi.Target)
+	return pi.Target != "" && target != "xml" && target != "xpacket" &&
+		!strings.ContainsAny(pi.Target, " \t\r\n") && !bytes.Contains(pi.Inst, []byte("?>"))
+}
+
 func (e *encoder) fixName(name xml.Name) xml.Name {
 	pfx, ok := e.nsToPrefix[name.Space]
 	if !ok {
@@ -19,12 +19,14 @@ package xmp
 import (
 	"bytes"
 	"encoding/xml"
+	"io"
 	"net/url"
 	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
 )
 
 var (
@@ -272,6 +274,49 @@ var encodeTestCases = []encodeTestCase{
 			"</test:prop>",
 		},
 	},
+	{
+		desc: "xml:lang on structure, not on fields",
+		in: &Packet{
+			Properties: map[xml.Name]Raw{
+				elemTest: RawStruct{
+					Value: map[xml.Name]Raw{
+						elemTestA: Text{V: "1"},
+						elemTestB: Text{V: "2", Q: Q{{nameXMLLang, Text{V: "de"}}}},
+					},
+					Q: Q{{nameXMLLang, Text{V: "fr"}}},
+				},
+			},
+		},
+		pattern: []string{
+			"<test:prop xml:lang=\"fr\" rdf:parseType=\"Resource\">",
+			"<test:a xml:lang=\"\">1</test:a>",
+			"<test:b xml:lang=\"de\">2</test:b>",
+			"</test:prop>",
+		},
+	},
+	{
+		desc: "xml:lang on array, not on items",
+		in: &Packet{
+			Properties: map[xml.Name]Raw{
+				elemTest: RawArray{
+					Value: []Raw{
+						Text{V: "a"},
+						URL{V: testURL},
+					},
+					Kind: Unordered,
+					Q:    Q{{nameXMLLang, Text{V: "fr"}}},
+				},
+			},
+		},
+		pattern: []string{
+			"<test:prop xml:lang=\"fr\">",
+			"<rdf:Bag>",
+			"<rdf:li xml:lang=\"\">a</rdf:li>",
+			"<rdf:li xml:lang=\"\" rdf:resource=\"http://example.com\"/>",
+			"</rdf:Bag>",
+			"</test:prop>",
+		},
+	},
 }
 
 func TestRoundTrip(t *testing.T) {
@@ -310,3 +355,203 @@ func TestRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+// TestDeterministic checks that writing the same packet several times
+// produces byte-identical output.
+func TestDeterministic(t *testing.T) {
+	makePacket := func() *Packet {
+		p := NewPacket()
+		p.RegisterPrefix("http://ns.seehuhn.de/test/a/#", "test")
+		p.RegisterPrefix("http://ns.seehuhn.de/test/b/#", "test")
+		p.RegisterPrefix("http://ns.seehuhn.de/test/c/#", "test")
+
+		title := Localized{Default: NewText("Hello")}
+		for _, lang := range []string{"en", "de", "fr", "it", "es", "nl", "pl", "sv"} {
+			title.Set(language.MustParse(lang), "Hello "+lang)
+		}
+		p.SetValue("http://ns.seehuhn.de/test/a/#", "title", title)
+		p.SetValue("http://ns.seehuhn.de/test/b/#", "s", Text{V: "b"})
+		p.SetValue("http://ns.seehuhn.de/test/c/#", "s", Text{V: "c"})
+		p.Properties[xml.Name{Space: "http://ns.seehuhn.de/test/d/#", Local: "s"}] = RawStruct{
+			Value: map[xml.Name]Raw{
+				elemTestA: Text{V: "1"},
+				elemTestB: Text{V: "2", Q: Q{{elemTestQ, Text{V: "q"}}}},
+				elemTestC: Text{V: "3"},
+			},
+		}
+		return p
+	}
+
+	var first []byte
+	for i := 0; i < 20; i++ {
+		buf := &bytes.Buffer{}
+		err := makePacket().Write(buf, &PacketOptions{Pretty: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			first = buf.Bytes()
+		} else if !bytes.Equal(first, buf.Bytes()) {
+			t.Fatalf("output differs between runs:\n%s\n%s", first, buf.Bytes())
+		}
+	}
+
+	// each namespace must have been assigned a distinct prefix
+	p, err := Read(bytes.NewReader(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Properties) != 4 {
+		t.Errorf("expected 4 properties, got %d", len(p.Properties))
+	}
+}
+
+func TestComments(t *testing.T) {
+	in := `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<?adobe-xap-filters esc="CR"?>
+<!-- generated by test -->
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:t="http://ns.seehuhn.de/test/#">
+<!-- note on a -->
+<t:a>1<!-- inside a --></t:a>
+<t:b>2</t:b>
+<!-- end of description -->
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>
+<!-- trailer -->
+<?xpacket end="w"?>`
+
+	p := &Packet{}
+	err := p.Decode(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(p *Packet) {
+		t.Helper()
+		if d := cmp.Diff([]string{" generated by test "}, p.LeadingComments); d != "" {
+			t.Error(d)
+		}
+		if d := cmp.Diff([]string{" end of description ", " trailer "}, p.TrailingComments); d != "" {
+			t.Error(d)
+		}
+		expected := map[xml.Name][]string{elemTestA: {" note on a ", " inside a "}}
+		if d := cmp.Diff(expected, p.PropertyComments); d != "" {
+			t.Error(d)
+		}
+		if d := cmp.Diff(Text{V: "1"}, p.Properties[elemTestA]); d != "" {
+			t.Error(d)
+		}
+		pi := []xml.ProcInst{{Target: "adobe-xap-filters", Inst: []byte(`esc="CR"`)}}
+		if d := cmp.Diff(pi, p.ProcInsts); d != "" {
+			t.Error(d)
+		}
+	}
+	check(p)
+
+	for _, pretty := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		err = p.Write(buf, &PacketOptions{Pretty: pretty, Padding: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The padding comes immediately before the xpacket trailer.
+		if !regexp.MustCompile(`<!-- trailer -->\n *\n<\?xpacket end="w"\?>$`).Match(buf.Bytes()) {
+			t.Errorf("wrong trailer:\n%s", buf.Bytes())
+		}
+		q := &Packet{}
+		err = q.Decode(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(q)
+	}
+
+	// comments are omitted from the canonical form by default
+	data, err := p.Canonical(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("<!--")) {
+		t.Errorf("unexpected comment in %q", data)
+	}
+	data, err = p.Canonical(&CanonicalPolicy{KeepComments: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("<!-- note on a -->")) {
+		t.Errorf("missing comment in %q", data)
+	}
+}
+
+func TestInvalidProcInst(t *testing.T) {
+	for _, pi := range []xml.ProcInst{
+		{Target: "xpacket", Inst: []byte(`end="w"`)},
+		{Target: "XML"},
+		{Target: "a b"},
+		{Target: "a", Inst: []byte("?>")},
+	} {
+		p := NewPacket()
+		p.ProcInsts = []xml.ProcInst{pi}
+		err := p.Write(io.Discard, nil)
+		if err == nil {
+			t.Errorf("processing instruction %v: expected error", pi)
+		}
+	}
+}
+
+func TestInvalidComment(t *testing.T) {
+	for _, c := range []string{"a--b", "a-"} {
+		p := NewPacket()
+		p.LeadingComments = []string{c}
+		err := p.Write(io.Discard, nil)
+		if err == nil {
+			t.Errorf("comment %q: expected error", c)
+		}
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	p := NewPacket()
+	p.RegisterPrefix(elemTestA.Space, "foo")
+	p.SetValue(elemTestA.Space, elemTestA.Local, NewText("value"))
+	p.SetValue(basicNamespace, "Label", NewText("draft"))
+
+	for _, opt := range []*PacketOptions{ProfileCompact(), ProfileAdobeToolkit5(), ProfileCanonical()} {
+		buf := &bytes.Buffer{}
+		err := p.Write(buf, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := buf.String()
+
+		q, err := Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p.Equal(q) {
+			t.Errorf("round trip failed:\n%s", out)
+		}
+
+		hasMeta := strings.Contains(out, `<x:xmpmeta xmlns:x="adobe:ns:meta/"`)
+		if hasMeta != opt.XMPMeta {
+			t.Errorf("x:xmpmeta wrapper: got %t, want %t", hasMeta, opt.XMPMeta)
+		}
+		if n := strings.Count(out, " "); n < opt.Padding {
+			t.Errorf("got %d spaces, want at least %d", n, opt.Padding)
+		}
+		hasFoo := strings.Contains(out, "foo:prop") || strings.Contains(out, "foo:a")
+		if hasFoo == opt.DefaultPrefixes {
+			t.Errorf("registered prefix used: %t\n%s", hasFoo, out)
+		}
+	}
+}
+
+func TestProfileIsFresh(t *testing.T) {
+	opt := ProfileAdobeToolkit5()
+	opt.Padding = 0
+	if ProfileAdobeToolkit5().Padding != 2048 {
+		t.Error("modifying a profile changed the predefined options")
+	}
+}
@@ -0,0 +1,227 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Equal reports whether two packets contain the same properties and
+// describe the same resource.  Values are compared using [EqualRaw].
+func (p *Packet) Equal(other *Packet) bool {
+	return p.EqualExcept(other, nil)
+}
+
+// EqualExcept is like [Packet.Equal], but properties for which ignore
+// returns true are not compared.  Use [IsVolatile] to ignore properties
+// which change every time a file is saved.
+func (p *Packet) EqualExcept(other *Packet, ignore func(xml.Name) bool) bool {
+	if !equalURL(p.About, other.About) {
+		return false
+	}
+	for name, a := range p.Properties {
+		if ignore != nil && ignore(name) {
+			continue
+		}
+		b, ok := other.Properties[name]
+		if !ok || !EqualRaw(a, b) {
+			return false
+		}
+	}
+	for name := range other.Properties {
+		if ignore != nil && ignore(name) {
+			continue
+		}
+		if _, ok := p.Properties[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// A Change describes a property which differs between two packets.
+type Change struct {
+	Name xml.Name
+
+	// Old is the value in the first packet, or nil if the property was
+	// added.
+	Old Raw
+
+	// New is the value in the second packet, or nil if the property was
+	// removed.
+	New Raw
+}
+
+// Diff returns the properties which differ between two packets.  Values are
+// compared using [EqualRaw].  The result is sorted by namespace and
+// property name.  Either packet can be nil, to indicate an empty packet.
+func Diff(old, new *Packet) []Change {
+	return DiffExcept(old, new, nil)
+}
+
+// DiffExcept is like [Diff], but properties for which ignore returns true
+// are not compared.
+func DiffExcept(old, new *Packet, ignore func(xml.Name) bool) []Change {
+	var oldProps, newProps map[xml.Name]Raw
+	if old != nil {
+		oldProps = old.Properties
+	}
+	if new != nil {
+		newProps = new.Properties
+	}
+
+	var res []Change
+	for name, a := range oldProps {
+		if ignore != nil && ignore(name) {
+			continue
+		}
+		b, ok := newProps[name]
+		if !ok {
+			res = append(res, Change{Name: name, Old: a})
+		} else if !EqualRaw(a, b) {
+			res = append(res, Change{Name: name, Old: a, New: b})
+		}
+	}
+	for name, b := range newProps {
+		if ignore != nil && ignore(name) {
+			continue
+		}
+		if _, ok := oldProps[name]; !ok {
+			res = append(res, Change{Name: name, New: b})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i].Name, res[j].Name
+		if a.Space != b.Space {
+			return a.Space < b.Space
+		}
+		return a.Local < b.Local
+	})
+	return res
+}
+
+// EqualValue reports whether two values have the same XMP representation.
+// The values are compared using [EqualRaw], after conversion to their
+// low-level representation.
+func EqualValue(a, b Value) bool {
+	p := NewPacket()
+	return EqualRaw(a.EncodeXMP(p), b.EncodeXMP(p))
+}
+
+// EqualRaw reports whether two low-level XMP values are equal.
+//
+// The order of qualifiers is not significant, and language tags are compared
+// case-insensitively.  URLs are compared after normalising the case of
+// the scheme and host.
+//
+// The order of array elements is significant for all kinds of arrays,
+// including unordered arrays (rdf:Bag).  XMP does not assign a meaning to
+// the order of Bag items, but [Packet.Canonical] writes array elements in
+// the order given.  Comparing Bags as multisets would let packets with
+// different canonical forms compare equal.
+func EqualRaw(a, b Raw) bool {
+	switch a := a.(type) {
+	case Text:
+		b, ok := b.(Text)
+		return ok && a.V == b.V && equalQ(a.Q, b.Q)
+	case URL:
+		b, ok := b.(URL)
+		return ok && equalURL(a.V, b.V) && equalQ(a.Q, b.Q)
+	case RawStruct:
+		b, ok := b.(RawStruct)
+		if !ok || len(a.Value) != len(b.Value) {
+			return false
+		}
+		for name, va := range a.Value {
+			vb, ok := b.Value[name]
+			if !ok || !EqualRaw(va, vb) {
+				return false
+			}
+		}
+		return equalQ(a.Q, b.Q)
+	case RawArray:
+		b, ok := b.(RawArray)
+		if !ok || a.Kind != b.Kind || len(a.Value) != len(b.Value) {
+			return false
+		}
+		for i := range a.Value {
+			if !EqualRaw(a.Value[i], b.Value[i]) {
+				return false
+			}
+		}
+		return equalQ(a.Q, b.Q)
+	default:
+		return a == nil && b == nil
+	}
+}
+
+// equalQ reports whether two lists of qualifiers contain the same qualifiers,
+// ignoring the order.
+func equalQ(a, b Q) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+qualifierLoop:
+	for _, qa := range a {
+		for j, qb := range b {
+			if used[j] || qa.Name != qb.Name {
+				continue
+			}
+			if equalQualifierValue(qa, qb) {
+				used[j] = true
+				continue qualifierLoop
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func equalQualifierValue(a, b Qualifier) bool {
+	if a.Name == nameXMLLang {
+		ta, okA := a.Value.(Text)
+		tb, okB := b.Value.(Text)
+		if okA && okB {
+			return strings.EqualFold(ta.V, tb.V) && equalQ(ta.Q, tb.Q)
+		}
+	}
+	return EqualRaw(a.Value, b.Value)
+}
+
+// equalURL compares two URLs, ignoring the case of the scheme and host.
+func equalURL(a, b *url.URL) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return normalizedURL(a) == normalizedURL(b)
+}
+
+// normalizedURL returns the string form of u, with scheme and host
+// converted to lower case.
+func normalizedURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	n := *u
+	n.Scheme = strings.ToLower(n.Scheme)
+	n.Host = strings.ToLower(n.Host)
+	return n.String()
+}
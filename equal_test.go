@@ -0,0 +1,138 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
+)
+
+func TestEqualRaw(t *testing.T) {
+	u1, _ := url.Parse("HTTP://Example.COM/a")
+	u2, _ := url.Parse("http://example.com/a")
+	u3, _ := url.Parse("http://example.com/A")
+
+	type testCase struct {
+		a, b  Raw
+		equal bool
+	}
+	cases := []testCase{
+		{Text{V: "a"}, Text{V: "a"}, true},
+		{Text{V: "a"}, Text{V: "b"}, false},
+		{Text{V: "a"}, URL{V: u2}, false},
+		{
+			Text{V: "a", Q: Q{{elemTestQ, Text{V: "1"}}, {elemTestA, Text{V: "2"}}}},
+			Text{V: "a", Q: Q{{elemTestA, Text{V: "2"}}, {elemTestQ, Text{V: "1"}}}},
+			true,
+		},
+		{
+			Text{V: "a", Q: Q{{elemTestQ, Text{V: "1"}}}},
+			Text{V: "a", Q: Q{{elemTestQ, Text{V: "2"}}}},
+			false,
+		},
+		{
+			Text{V: "a", Q: Q{{elemTestQ, Text{V: "1"}}, {elemTestQ, Text{V: "1"}}}},
+			Text{V: "a", Q: Q{{elemTestQ, Text{V: "1"}}, {elemTestQ, Text{V: "2"}}}},
+			false,
+		},
+		{
+			Text{V: "a", Q: Q{{nameXMLLang, Text{V: "de-DE"}}}},
+			Text{V: "a", Q: Q{{nameXMLLang, Text{V: "de-de"}}}},
+			true,
+		},
+		{URL{V: u1}, URL{V: u2}, true},
+		{URL{V: u2}, URL{V: u3}, false},
+		{
+			RawStruct{Value: map[xml.Name]Raw{elemTestA: Text{V: "1"}}},
+			RawStruct{Value: map[xml.Name]Raw{elemTestA: Text{V: "1"}}},
+			true,
+		},
+		{
+			RawStruct{Value: map[xml.Name]Raw{elemTestA: Text{V: "1"}}},
+			RawStruct{Value: map[xml.Name]Raw{elemTestB: Text{V: "1"}}},
+			false,
+		},
+		{
+			RawArray{Value: []Raw{Text{V: "1"}, Text{V: "2"}}, Kind: Ordered},
+			RawArray{Value: []Raw{Text{V: "1"}, Text{V: "2"}}, Kind: Ordered},
+			true,
+		},
+		{
+			RawArray{Value: []Raw{Text{V: "1"}, Text{V: "2"}}, Kind: Ordered},
+			RawArray{Value: []Raw{Text{V: "1"}, Text{V: "2"}}, Kind: Unordered},
+			false,
+		},
+		{
+			RawArray{Value: []Raw{Text{V: "1"}, Text{V: "2"}}, Kind: Ordered},
+			RawArray{Value: []Raw{Text{V: "2"}, Text{V: "1"}}, Kind: Ordered},
+			false,
+		},
+	}
+	for i, tc := range cases {
+		if got := EqualRaw(tc.a, tc.b); got != tc.equal {
+			t.Errorf("%d: EqualRaw = %t, want %t", i, got, tc.equal)
+		}
+		if got := EqualRaw(tc.b, tc.a); got != tc.equal {
+			t.Errorf("%d: EqualRaw (swapped) = %t, want %t", i, got, tc.equal)
+		}
+	}
+}
+
+func TestEqualValue(t *testing.T) {
+	var a, b Localized
+	a.Set(language.English, "Hello")
+	a.Set(language.German, "Hallo")
+	b.Set(language.German, "Hallo")
+	b.Set(language.English, "Hello")
+	if !EqualValue(a, b) {
+		t.Error("localized values differ")
+	}
+
+	b.Set(language.German, "Guten Tag")
+	if EqualValue(a, b) {
+		t.Error("different localized values compare equal")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := NewPacket()
+	a.Properties[elemTestA] = Text{V: "1"}
+	a.Properties[elemTestB] = Text{V: "2"}
+	b := NewPacket()
+	b.Properties[elemTestB] = Text{V: "3"}
+	b.Properties[elemTestC] = Text{V: "4"}
+
+	want := []Change{
+		{Name: elemTestA, Old: Text{V: "1"}},
+		{Name: elemTestB, Old: Text{V: "2"}, New: Text{V: "3"}},
+		{Name: elemTestC, New: Text{V: "4"}},
+	}
+	if d := cmp.Diff(want, Diff(a, b)); d != "" {
+		t.Errorf("changes differ (-want +got):\n%s", d)
+	}
+
+	if changes := Diff(a, a); len(changes) != 0 {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+	if changes := Diff(nil, b); len(changes) != 2 {
+		t.Errorf("expected 2 changes, got %v", changes)
+	}
+}
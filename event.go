@@ -0,0 +1,88 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// ResourceEvent describes a high-level action which changed a document.
+//
+// See section 8.2.2.6 of ISO 16684-1:2011.
+type ResourceEvent struct {
+	// Action is the action which occurred, for example "created", "edited",
+	// "saved", or "converted".
+	Action Text
+
+	// Changed lists the parts of the document which were changed, as a
+	// semicolon-separated list, for example "/metadata".  If the field is
+	// empty, all parts may have changed.
+	Changed Text
+
+	// InstanceID is the instance ID of the modified document.
+	InstanceID GUID
+
+	// Parameters gives additional information about the action.
+	Parameters Text
+
+	// SoftwareAgent is the software which performed the action.
+	SoftwareAgent AgentName
+
+	// When is the time at which the action occurred.
+	When Date
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (e ResourceEvent) IsZero() bool {
+	return e.Action.IsZero() && e.Changed.IsZero() && e.InstanceID.IsZero() &&
+		e.Parameters.IsZero() && e.SoftwareAgent.IsZero() && e.When.IsZero() &&
+		len(e.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (e ResourceEvent) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     e.Q,
+	}
+	setField(res, p, stEvtNamespace, "action", e.Action)
+	setField(res, p, stEvtNamespace, "changed", e.Changed)
+	setField(res, p, stEvtNamespace, "instanceID", e.InstanceID)
+	setField(res, p, stEvtNamespace, "parameters", e.Parameters)
+	setField(res, p, stEvtNamespace, "softwareAgent", e.SoftwareAgent)
+	setField(res, p, stEvtNamespace, "when", e.When)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (ResourceEvent) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return ResourceEvent{
+		Action:        getField[Text](s, stEvtNamespace, "action"),
+		Changed:       getField[Text](s, stEvtNamespace, "changed"),
+		InstanceID:    getField[GUID](s, stEvtNamespace, "instanceID"),
+		Parameters:    getField[Text](s, stEvtNamespace, "parameters"),
+		SoftwareAgent: getField[AgentName](s, stEvtNamespace, "softwareAgent"),
+		When:          getField[Date](s, stEvtNamespace, "when"),
+		Q:             s.Q,
+	}, nil
+}
+
+const stEvtNamespace = "http://ns.adobe.com/xap/1.0/sType/ResourceEvent#"
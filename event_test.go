@@ -0,0 +1,76 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHistorySample(t *testing.T) {
+	body, err := os.ReadFile("testdata/lightroom-raw.xmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Read(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mm := &MediaManagement{}
+	p.Get(mm)
+
+	if len(mm.History.V) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(mm.History.V))
+	}
+	if mm.History.V[0].Action.V != "derived" {
+		t.Errorf("wrong action %q", mm.History.V[0].Action.V)
+	}
+	ev := mm.History.V[1]
+	if ev.Action.V != "saved" || ev.Changed.V != "/metadata" {
+		t.Errorf("wrong event %+v", ev)
+	}
+	if ev.InstanceID.V != "xmp.iid:3e2a7c1b-50f4-4a7b-9e6d-1f2b3c4d5e6f" {
+		t.Errorf("wrong instance ID %q", ev.InstanceID.V)
+	}
+	want := time.Date(2024, 5, 19, 8, 12, 45, 0, time.UTC)
+	if !ev.When.V.Equal(want) {
+		t.Errorf("wrong time %v", ev.When.V)
+	}
+}
+
+func TestAddHistoryTyped(t *testing.T) {
+	p := NewPacket()
+	p.Now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	p.Journal = &Journal{}
+	p.SetValue(basicNamespace, "Label", NewText("Red"))
+	p.AddHistory("example-tool 1.0")
+
+	mm := &MediaManagement{}
+	p.Get(mm)
+	if len(mm.History.V) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(mm.History.V))
+	}
+	ev := mm.History.V[0]
+	if ev.Action.V != "edited" || ev.Parameters.V != "changed xmp:Label" {
+		t.Errorf("wrong event %+v", ev)
+	}
+	if ev.SoftwareAgent.String() != "example-tool 1.0" {
+		t.Errorf("wrong software agent %q", ev.SoftwareAgent)
+	}
+}
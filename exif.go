@@ -0,0 +1,310 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+// EXIF represents the EXIF namespace for properties from the EXIF 2.2
+// specification.
+//
+// See section 1.2.7 of part 2 of the XMP specification (2016).
+type EXIF struct {
+	_ Namespace `xmp:"http://ns.adobe.com/exif/1.0/"`
+	_ Prefix    `xmp:"exif"`
+
+	// ExifVersion is the EXIF version number, e.g. "0230".
+	ExifVersion Text
+
+	// FlashpixVersion is the Flashpix format version supported by the file.
+	FlashpixVersion Text
+
+	// ColorSpace is the color space: 1=sRGB, 65535=uncalibrated.
+	ColorSpace Integer
+
+	// ComponentsConfiguration describes the channels of the compressed data:
+	// 0=does not exist, 1=Y, 2=Cb, 3=Cr, 4=R, 5=G, 6=B.
+	ComponentsConfiguration OrderedArray[Integer]
+
+	// CompressedBitsPerPixel is the compression mode used, as a rational
+	// number.
+	CompressedBitsPerPixel Rational
+
+	// PixelXDimension is the valid image width, in pixels.
+	PixelXDimension Integer
+
+	// PixelYDimension is the valid image height, in pixels.
+	PixelYDimension Integer
+
+	// UserComment contains comments from the user.
+	UserComment Localized
+
+	// RelatedSoundFile is the name of a related audio file.
+	RelatedSoundFile Text
+
+	// DateTimeOriginal is the date and time when the original image data was
+	// generated.
+	DateTimeOriginal Date
+
+	// DateTimeDigitized is the date and time when the image was stored as
+	// digital data.
+	DateTimeDigitized Date
+
+	// ExposureTime is the exposure time in seconds, as a rational number
+	// like "1/200".
+	ExposureTime Rational
+
+	// FNumber is the F number, as a rational number.
+	FNumber Rational
+
+	// ExposureProgram is the class of program used for exposure:
+	// 0=not defined, 1=manual, 2=normal program, 3=aperture priority,
+	// 4=shutter priority, 5=creative program, 6=action program,
+	// 7=portrait mode, 8=landscape mode.
+	ExposureProgram Integer
+
+	// SpectralSensitivity describes the spectral sensitivity of each channel.
+	SpectralSensitivity Text
+
+	// ISOSpeedRatings lists the ISO speed and ISO latitude of the camera or
+	// input device.
+	ISOSpeedRatings OrderedArray[Integer]
+
+	// OECF is the opto-electronic conversion function of the camera.
+	OECF OECF
+
+	// ShutterSpeedValue is the shutter speed in APEX units, as a rational
+	// number.
+	ShutterSpeedValue Rational
+
+	// ApertureValue is the lens aperture in APEX units, as a rational number.
+	ApertureValue Rational
+
+	// BrightnessValue is the brightness in APEX units, as a rational number.
+	BrightnessValue Rational
+
+	// ExposureBiasValue is the exposure bias in APEX units, as a rational
+	// number.
+	ExposureBiasValue Rational
+
+	// MaxApertureValue is the smallest F number of the lens, in APEX units,
+	// as a rational number.
+	MaxApertureValue Rational
+
+	// SubjectDistance is the distance to the subject in meters, as a rational
+	// number.
+	SubjectDistance Rational
+
+	// MeteringMode is the metering mode: 0=unknown, 1=average,
+	// 2=center-weighted average, 3=spot, 4=multi-spot, 5=pattern, 6=partial,
+	// 255=other.
+	MeteringMode Integer
+
+	// LightSource is the kind of light source, using the codes from the EXIF
+	// specification.
+	LightSource Integer
+
+	// Flash describes the state of the flash when the image was taken.
+	Flash Flash
+
+	// FocalLength is the focal length of the lens in millimeters, as a
+	// rational number.
+	FocalLength Rational
+
+	// SubjectArea gives the location and area of the main subject.
+	SubjectArea OrderedArray[Integer]
+
+	// FlashEnergy is the strobe energy in BCPS, as a rational number.
+	FlashEnergy Rational
+
+	// SpatialFrequencyResponse is the spatial frequency table of the
+	// camera.
+	SpatialFrequencyResponse OECF
+
+	// FocalPlaneXResolution is the number of pixels per FocalPlaneResolutionUnit
+	// in the image width direction, as a rational number.
+	FocalPlaneXResolution Rational
+
+	// FocalPlaneYResolution is the number of pixels per FocalPlaneResolutionUnit
+	// in the image height direction, as a rational number.
+	FocalPlaneYResolution Rational
+
+	// FocalPlaneResolutionUnit is the unit for FocalPlaneXResolution and
+	// FocalPlaneYResolution: 2=inches, 3=centimeters.
+	FocalPlaneResolutionUnit Integer
+
+	// SubjectLocation gives the location of the main subject.
+	SubjectLocation OrderedArray[Integer]
+
+	// ExposureIndex is the selected exposure index, as a rational number.
+	ExposureIndex Rational
+
+	// SensingMethod is the image sensor type, using the codes from the EXIF
+	// specification.
+	SensingMethod Integer
+
+	// FileSource indicates the image source: 3=digital still camera.
+	FileSource Integer
+
+	// SceneType indicates the type of scene: 1=directly photographed image.
+	SceneType Integer
+
+	// CFAPattern is the geometric pattern of the colour filter array of
+	// the image sensor.
+	CFAPattern CFAPattern
+
+	// CustomRendered indicates the use of special processing:
+	// 0=normal process, 1=custom process.
+	CustomRendered Integer
+
+	// ExposureMode is the exposure mode: 0=auto, 1=manual, 2=auto bracket.
+	ExposureMode Integer
+
+	// WhiteBalance is the white balance mode: 0=auto, 1=manual.
+	WhiteBalance Integer
+
+	// DigitalZoomRatio is the digital zoom ratio, as a rational number.
+	DigitalZoomRatio Rational
+
+	// FocalLengthIn35mmFilm is the equivalent focal length for a 35mm camera,
+	// in millimeters.
+	FocalLengthIn35mmFilm Integer
+
+	// SceneCaptureType is the type of scene: 0=standard, 1=landscape,
+	// 2=portrait, 3=night scene.
+	SceneCaptureType Integer
+
+	// GainControl is the degree of overall gain adjustment: 0=none,
+	// 1=low gain up, 2=high gain up, 3=low gain down, 4=high gain down.
+	GainControl Integer
+
+	// Contrast is the direction of contrast processing: 0=normal, 1=soft,
+	// 2=hard.
+	Contrast Integer
+
+	// Saturation is the direction of saturation processing: 0=normal,
+	// 1=low saturation, 2=high saturation.
+	Saturation Integer
+
+	// Sharpness is the direction of sharpness processing: 0=normal, 1=soft,
+	// 2=hard.
+	Sharpness Integer
+
+	// DeviceSettingDescription describes the picture-taking conditions
+	// of a particular camera model.
+	DeviceSettingDescription DeviceSettings
+
+	// SubjectDistanceRange is the distance to the subject: 0=unknown,
+	// 1=macro, 2=close view, 3=distant view.
+	SubjectDistanceRange Integer
+
+	// ImageUniqueID is an identifier assigned uniquely to each image, as a
+	// 32 character hexadecimal string.
+	ImageUniqueID Text
+
+	// GPSVersionID is the version of the GPS information, e.g. "2.2.0.0".
+	GPSVersionID Text
+
+	// GPSLatitude is the latitude, in the form "DDD,MM,SSk" or "DDD,MM.mmk",
+	// where k is N or S.
+	GPSLatitude GPSCoordinate
+
+	// GPSLongitude is the longitude, in the form "DDD,MM,SSk" or
+	// "DDD,MM.mmk", where k is E or W.
+	GPSLongitude GPSCoordinate
+
+	// GPSAltitudeRef is the altitude reference: 0=above sea level,
+	// 1=below sea level.
+	GPSAltitudeRef Integer
+
+	// GPSAltitude is the altitude in meters, as a rational number.
+	GPSAltitude Rational
+
+	// GPSTimeStamp is the date and time of the GPS fix, in UTC.
+	GPSTimeStamp Date
+
+	// GPSSatellites describes the satellites used for measurement.
+	GPSSatellites Text
+
+	// GPSStatus is the status of the receiver: "A"=measurement in progress,
+	// "V"=measurement interoperability.
+	GPSStatus Text
+
+	// GPSMeasureMode is "2" for two-dimensional and "3" for
+	// three-dimensional measurement.
+	GPSMeasureMode Text
+
+	// GPSDOP is the degree of precision for the GPS data, as a rational number.
+	GPSDOP Rational
+
+	// GPSSpeedRef is the unit for GPSSpeed: "K"=kilometers per hour,
+	// "M"=miles per hour, "N"=knots.
+	GPSSpeedRef Text
+
+	// GPSSpeed is the speed of the GPS receiver, as a rational number.
+	GPSSpeed Rational
+
+	// GPSTrackRef is the reference for GPSTrack: "T"=true direction,
+	// "M"=magnetic direction.
+	GPSTrackRef Text
+
+	// GPSTrack is the direction of movement in degrees, as a rational number.
+	GPSTrack Rational
+
+	// GPSImgDirectionRef is the reference for GPSImgDirection: "T"=true
+	// direction, "M"=magnetic direction.
+	GPSImgDirectionRef Text
+
+	// GPSImgDirection is the direction of the image in degrees, as a
+	// rational number.
+	GPSImgDirection Rational
+
+	// GPSMapDatum is the geodetic survey data used by the receiver.
+	GPSMapDatum Text
+
+	// GPSDestLatitude is the latitude of the destination point.
+	GPSDestLatitude GPSCoordinate
+
+	// GPSDestLongitude is the longitude of the destination point.
+	GPSDestLongitude GPSCoordinate
+
+	// GPSDestBearingRef is the reference for GPSDestBearing: "T"=true
+	// direction, "M"=magnetic direction.
+	GPSDestBearingRef Text
+
+	// GPSDestBearing is the bearing to the destination point in degrees, as
+	// a rational number.
+	GPSDestBearing Rational
+
+	// GPSDestDistanceRef is the unit for GPSDestDistance: "K"=kilometers,
+	// "M"=miles, "N"=nautical miles.
+	GPSDestDistanceRef Text
+
+	// GPSDestDistance is the distance to the destination point, as a
+	// rational number.
+	GPSDestDistance Rational
+
+	// GPSProcessingMethod is the name of the method used for location
+	// finding.
+	GPSProcessingMethod Text
+
+	// GPSAreaInformation is the name of the GPS area.
+	GPSAreaInformation Text
+
+	// GPSDifferential indicates whether differential correction was applied:
+	// 0=without correction, 1=with correction.
+	GPSDifferential Integer
+}
+
+const exifNamespace = "http://ns.adobe.com/exif/1.0/"
@@ -0,0 +1,193 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEXIF(t *testing.T) {
+	in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:exif="http://ns.adobe.com/exif/1.0/"
+  exif:ExposureTime="1/200" exif:FNumber="28/10" exif:FocalLength="50/1"
+  exif:DateTimeOriginal="2024-03-01T10:15:00+01:00"
+  exif:GPSLatitude="52,30.123N">
+<exif:ISOSpeedRatings><rdf:Seq><rdf:li>400</rdf:li></rdf:Seq></exif:ISOSpeedRatings>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>`
+	p, err := Read(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exif := &EXIF{}
+	p.Get(exif)
+	if exif.ExposureTime.String() != "1/200" || exif.FNumber.Float64() != 2.8 {
+		t.Errorf("wrong exposure: %s f/%s", exif.ExposureTime, exif.FNumber)
+	}
+	if len(exif.ISOSpeedRatings.V) != 1 || exif.ISOSpeedRatings.V[0].V != 400 {
+		t.Errorf("wrong ISO: %v", exif.ISOSpeedRatings)
+	}
+	want := time.Date(2024, 3, 1, 9, 15, 0, 0, time.UTC)
+	if !exif.DateTimeOriginal.V.Equal(want) {
+		t.Errorf("wrong date: %v", exif.DateTimeOriginal.V)
+	}
+	if exif.GPSLatitude.String() != "52,30.123N" {
+		t.Errorf("wrong latitude: %s", exif.GPSLatitude)
+	}
+
+	q := NewPacket()
+	err = q.Set(exif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Equal(p) {
+		t.Errorf("packets differ: %+v != %+v", q, p)
+	}
+}
+
+func TestEXIFTables(t *testing.T) {
+	in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:exif="http://ns.adobe.com/exif/1.0/">
+<exif:OECF rdf:parseType="Resource">
+  <exif:Columns>2</exif:Columns>
+  <exif:Rows>1</exif:Rows>
+  <exif:Names><rdf:Seq><rdf:li>Level</rdf:li><rdf:li>Output</rdf:li></rdf:Seq></exif:Names>
+  <exif:Values><rdf:Seq><rdf:li>1/2</rdf:li><rdf:li>3/4</rdf:li></rdf:Seq></exif:Values>
+</exif:OECF>
+<exif:CFAPattern>
+<rdf:Description exif:Columns="2" exif:Rows="2">
+  <exif:Values><rdf:Seq><rdf:li>0</rdf:li><rdf:li>1</rdf:li><rdf:li>1</rdf:li><rdf:li>2</rdf:li></rdf:Seq></exif:Values>
+</rdf:Description>
+</exif:CFAPattern>
+<exif:DeviceSettingDescription rdf:parseType="Resource">
+  <exif:Columns>2</exif:Columns>
+  <exif:Rows>1</exif:Rows>
+  <exif:Settings><rdf:Seq><rdf:li>Mode</rdf:li><rdf:li>Portrait</rdf:li></rdf:Seq></exif:Settings>
+</exif:DeviceSettingDescription>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>`
+	p, err := Read(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exif := &EXIF{}
+	p.Get(exif)
+	if exif.OECF.Columns.V != 2 || exif.OECF.Rows.V != 1 {
+		t.Errorf("wrong OECF size: %dx%d", exif.OECF.Columns.V, exif.OECF.Rows.V)
+	}
+	if len(exif.OECF.Names.V) != 2 || exif.OECF.Names.V[1].V != "Output" {
+		t.Errorf("wrong OECF names: %v", exif.OECF.Names)
+	}
+	if len(exif.OECF.Values.V) != 2 || exif.OECF.Values.V[1].String() != "3/4" {
+		t.Errorf("wrong OECF values: %v", exif.OECF.Values)
+	}
+	var pattern []int64
+	for _, v := range exif.CFAPattern.Values.V {
+		pattern = append(pattern, v.V)
+	}
+	if exif.CFAPattern.Columns.V != 2 || exif.CFAPattern.Rows.V != 2 ||
+		len(pattern) != 4 || pattern[0] != 0 || pattern[3] != 2 {
+		t.Errorf("wrong CFA pattern: %v", pattern)
+	}
+	ds := exif.DeviceSettingDescription
+	if ds.Columns.V != 2 || ds.Rows.V != 1 || len(ds.Settings.V) != 2 ||
+		ds.Settings.V[1].V != "Portrait" {
+		t.Errorf("wrong device settings: %+v", ds)
+	}
+
+	q := NewPacket()
+	err = q.Set(exif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Equal(p) {
+		t.Errorf("packets differ: %+v != %+v", q, p)
+	}
+}
+
+func TestFlash(t *testing.T) {
+	in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:exif="http://ns.adobe.com/exif/1.0/">
+<exif:Flash exif:Fired="True" exif:Return="3" exif:Mode="1"
+  exif:Function="False" exif:RedEyeMode="True"/>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>`
+	p, err := Read(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exif := &EXIF{}
+	p.Get(exif)
+	f := exif.Flash
+	if !f.Fired.IsTrue() || f.Return.V != 3 || f.Mode.V != 1 ||
+		!f.Function.IsFalse() || !f.RedEyeMode.IsTrue() {
+		t.Errorf("wrong flash: %+v", f)
+	}
+	if err := f.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	q := NewPacket()
+	err = q.Set(exif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Equal(p) {
+		t.Errorf("packets differ: %+v != %+v", q, p)
+	}
+}
+
+func TestFlashInvalid(t *testing.T) {
+	raw := RawStruct{
+		Value: map[xml.Name]Raw{
+			{Space: exifNamespace, Local: "Fired"}:  Text{V: "True"},
+			{Space: exifNamespace, Local: "Return"}: Text{V: "1"},
+			{Space: exifNamespace, Local: "Mode"}:   Text{V: "2"},
+		},
+	}
+	if _, err := (Flash{}).DecodeAnother(raw); err == nil {
+		t.Error("invalid flash value accepted")
+	}
+	v, msg, ok := repairValue(Flash{}, raw)
+	if !ok || msg == "" {
+		t.Fatalf("expected repaired value, got ok=%t, msg=%q", ok, msg)
+	}
+	f := v.(Flash)
+	if !f.Return.IsZero() || f.Mode.V != 2 || !f.Fired.IsTrue() {
+		t.Errorf("wrong flash: %+v", f)
+	}
+
+	for _, f := range []Flash{
+		{Return: Integer{V: 1}},
+		{Mode: Integer{V: 4}},
+	} {
+		if f.Validate() == nil {
+			t.Errorf("%+v: expected error", f)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// OECF describes an opto-electronic conversion function or a spatial
+// frequency response, as used in exif:OECF and
+// exif:SpatialFrequencyResponse.
+//
+// The table has Columns columns and Rows rows.  Names gives the column
+// names, and Values lists the table entries in row-major order.
+//
+// See section 1.2.7.2 of part 2 of the XMP specification (2016).
+type OECF struct {
+	Columns Integer
+	Rows    Integer
+	Names   OrderedArray[Text]
+	Values  OrderedArray[Rational]
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (o OECF) IsZero() bool {
+	return o.Columns.IsZero() && o.Rows.IsZero() && o.Names.IsZero() &&
+		o.Values.IsZero() && len(o.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (o OECF) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     o.Q,
+	}
+	setField(res, p, exifNamespace, "Columns", o.Columns)
+	setField(res, p, exifNamespace, "Rows", o.Rows)
+	setField(res, p, exifNamespace, "Names", o.Names)
+	setField(res, p, exifNamespace, "Values", o.Values)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (OECF) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return OECF{
+		Columns: getField[Integer](s, exifNamespace, "Columns"),
+		Rows:    getField[Integer](s, exifNamespace, "Rows"),
+		Names:   getField[OrderedArray[Text]](s, exifNamespace, "Names"),
+		Values:  getField[OrderedArray[Rational]](s, exifNamespace, "Values"),
+		Q:       s.Q,
+	}, nil
+}
+
+// CFAPattern describes the colour filter array of an image sensor, as
+// used in exif:CFAPattern.
+//
+// Values lists the colour of each filter element in row-major order:
+// 0=red, 1=green, 2=blue, 3=cyan, 4=magenta, 5=yellow, 6=white.
+//
+// See section 1.2.7.1 of part 2 of the XMP specification (2016).
+type CFAPattern struct {
+	Columns Integer
+	Rows    Integer
+	Values  OrderedArray[Integer]
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (c CFAPattern) IsZero() bool {
+	return c.Columns.IsZero() && c.Rows.IsZero() && c.Values.IsZero() &&
+		len(c.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (c CFAPattern) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     c.Q,
+	}
+	setField(res, p, exifNamespace, "Columns", c.Columns)
+	setField(res, p, exifNamespace, "Rows", c.Rows)
+	setField(res, p, exifNamespace, "Values", c.Values)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (CFAPattern) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return CFAPattern{
+		Columns: getField[Integer](s, exifNamespace, "Columns"),
+		Rows:    getField[Integer](s, exifNamespace, "Rows"),
+		Values:  getField[OrderedArray[Integer]](s, exifNamespace, "Values"),
+		Q:       s.Q,
+	}, nil
+}
+
+// DeviceSettings describes the picture-taking conditions of a particular
+// camera model, as used in exif:DeviceSettingDescription.
+//
+// The table has Columns columns and Rows rows, and Settings lists the
+// table entries in row-major order.
+//
+// See section 1.2.7.4 of part 2 of the XMP specification (2016).
+type DeviceSettings struct {
+	Columns  Integer
+	Rows     Integer
+	Settings OrderedArray[Text]
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (d DeviceSettings) IsZero() bool {
+	return d.Columns.IsZero() && d.Rows.IsZero() && d.Settings.IsZero() &&
+		len(d.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (d DeviceSettings) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     d.Q,
+	}
+	setField(res, p, exifNamespace, "Columns", d.Columns)
+	setField(res, p, exifNamespace, "Rows", d.Rows)
+	setField(res, p, exifNamespace, "Settings", d.Settings)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (DeviceSettings) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return DeviceSettings{
+		Columns:  getField[Integer](s, exifNamespace, "Columns"),
+		Rows:     getField[Integer](s, exifNamespace, "Rows"),
+		Settings: getField[OrderedArray[Text]](s, exifNamespace, "Settings"),
+		Q:        s.Q,
+	}, nil
+}
+
+// Flash describes the state of the flash when an image was taken, as used
+// in exif:Flash.
+//
+// See section 1.2.7.3 of part 2 of the XMP specification (2016).
+type Flash struct {
+	// Fired is true if the flash fired.
+	Fired OptionalBool
+
+	// Return is the status of the strobe return light: 0=no strobe return
+	// detection, 2=strobe return light not detected, 3=strobe return
+	// light detected.
+	Return Integer
+
+	// Mode is the flash mode: 0=unknown, 1=compulsory flash firing,
+	// 2=compulsory flash suppression, 3=auto mode.
+	Mode Integer
+
+	// Function is true if the camera has no flash function.
+	Function OptionalBool
+
+	// RedEyeMode is true if red-eye reduction is supported.
+	RedEyeMode OptionalBool
+
+	Q
+}
+
+// Validate checks that the Return and Mode fields have one of the values
+// allowed by the EXIF specification.
+func (f Flash) Validate() error {
+	switch f.Return.V {
+	case 0, 2, 3:
+		// pass
+	default:
+		return fmt.Errorf("invalid flash return value %d", f.Return.V)
+	}
+	if f.Mode.V < 0 || f.Mode.V > 3 {
+		return fmt.Errorf("invalid flash mode %d", f.Mode.V)
+	}
+	return nil
+}
+
+// IsZero implements the [Value] interface.
+func (f Flash) IsZero() bool {
+	return f.Fired.IsZero() && f.Return.IsZero() && f.Mode.IsZero() &&
+		f.Function.IsZero() && f.RedEyeMode.IsZero() && len(f.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (f Flash) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     f.Q,
+	}
+	setField(res, p, exifNamespace, "Fired", f.Fired)
+	setField(res, p, exifNamespace, "Return", f.Return)
+	setField(res, p, exifNamespace, "Mode", f.Mode)
+	setField(res, p, exifNamespace, "Function", f.Function)
+	setField(res, p, exifNamespace, "RedEyeMode", f.RedEyeMode)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+// Values which do not pass [Flash.Validate] are rejected.
+func (Flash) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	res := decodeFlash(s)
+	if res.Validate() != nil {
+		return nil, ErrInvalid
+	}
+	return res, nil
+}
+
+// repair implements the repairer interface.
+// Values of Return and Mode which are not allowed by the EXIF
+// specification are removed.
+func (Flash) repair(val Raw) (Value, string, bool) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, "", false
+	}
+	res := decodeFlash(s)
+	err := res.Validate()
+	if err == nil {
+		return res, "", true
+	}
+	if res.Return.V == 1 || res.Return.V < 0 || res.Return.V > 3 {
+		res.Return = Integer{}
+	}
+	if res.Mode.V < 0 || res.Mode.V > 3 {
+		res.Mode = Integer{}
+	}
+	return res, err.Error(), true
+}
+
+// decodeFlash decodes the fields of an exif:Flash structure, without
+// validating the values.
+func decodeFlash(s RawStruct) Flash {
+	return Flash{
+		Fired:      getField[OptionalBool](s, exifNamespace, "Fired"),
+		Return:     getField[Integer](s, exifNamespace, "Return"),
+		Mode:       getField[Integer](s, exifNamespace, "Mode"),
+		Function:   getField[OptionalBool](s, exifNamespace, "Function"),
+		RedEyeMode: getField[OptionalBool](s, exifNamespace, "RedEyeMode"),
+		Q:          s.Q,
+	}
+}
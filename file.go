@@ -0,0 +1,320 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileOptions controls how XMP files are written by [WriteFile] and
+// [UpdateFile].
+type FileOptions struct {
+	// Atomic enables atomic writes.  The new contents are written to a lock
+	// file next to the target, flushed to stable storage, and then renamed
+	// over the target.  Readers see either the old or the new contents,
+	// never a partially written file.  While the lock file exists, other
+	// atomic writers of the same file wait.
+	//
+	// If a process is killed while holding the lock, the lock file (the
+	// file name with ".lock" appended) is left behind and must be removed
+	// manually.
+	Atomic bool
+
+	// LockTimeout is the maximal time to wait for the lock held by another
+	// writer.  If this is zero, [DefaultLockTimeout] is used.
+	LockTimeout time.Duration
+
+	// DryRun prevents files from being modified.  The changes which would
+	// have been made are still reported to OnChange.
+	DryRun bool
+
+	// OnChange (optional) is called for every file which is modified, or
+	// which would be modified in dry-run mode.  Files where the new contents
+	// coincide with the old contents are not reported.
+	OnChange func(*FileChange)
+}
+
+// FileChange describes the modification of an XMP file.
+type FileChange struct {
+	FileName string
+
+	// Offset and Length give the byte range of the file which is replaced.
+	// NewLength is the length of the replacement data.  For sidecar files,
+	// the whole file is replaced.
+	Offset, Length, NewLength int64
+
+	// Changes lists the properties which are modified.
+	Changes []Change
+}
+
+// DefaultLockTimeout is the lock timeout used if FileOptions.LockTimeout is
+// zero.
+const DefaultLockTimeout = 10 * time.Second
+
+// ErrLocked is returned if the lock on an XMP file could not be obtained
+// within the lock timeout.
+var ErrLocked = errors.New("XMP file is locked")
+
+// WriteFile writes the packet to the named file, in the form of an XMP
+// sidecar file.
+func WriteFile(fname string, p *Packet, opt *FileOptions) error {
+	if opt == nil {
+		opt = &FileOptions{}
+	}
+
+	var l *fileLock
+	if opt.Atomic && !opt.DryRun {
+		var err error
+		l, err = lockFile(context.Background(), fname, opt.lockTimeout())
+		if err != nil {
+			return err
+		}
+		defer l.release()
+	}
+
+	var old []byte
+	if opt.OnChange != nil {
+		var err error
+		old, err = os.ReadFile(fname)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return opt.store(fname, l, old, p)
+}
+
+// UpdateFile reads the named XMP file, calls update to modify the packet,
+// and writes the result back to the file.  The file must exist.  If update
+// returns an error, the file is left unchanged.
+//
+// In atomic mode, the lock is held from before the file is read until the
+// new contents are in place, so that concurrent updates are not lost.
+func UpdateFile(fname string, opt *FileOptions, update func(*Packet) error) error {
+	_, err := updateFile(context.Background(), fname, opt, update)
+	return err
+}
+
+// updateFile implements [UpdateFile].  In case of failure, the phase in
+// which the error occurred is returned together with the error.
+func updateFile(ctx context.Context, fname string, opt *FileOptions, update func(*Packet) error) (Phase, error) {
+	if opt == nil {
+		opt = &FileOptions{}
+	}
+	if err := ctx.Err(); err != nil {
+		return PhaseRead, err
+	}
+
+	var l *fileLock
+	if opt.Atomic && !opt.DryRun {
+		var err error
+		l, err = lockFile(ctx, fname, opt.lockTimeout())
+		if err != nil {
+			return PhaseRead, err
+		}
+		defer l.release()
+	}
+
+	old, err := os.ReadFile(fname)
+	if err != nil {
+		return PhaseRead, err
+	}
+	p, err := Read(bytes.NewReader(old))
+	if err != nil {
+		return PhaseRead, err
+	}
+	err = update(p)
+	if err != nil {
+		return PhaseTransform, err
+	}
+	err = opt.store(fname, l, old, p)
+	if err != nil {
+		return PhaseWrite, err
+	}
+	return 0, nil
+}
+
+// store writes the packet to the file, reporting the change if needed.
+// The argument old is the previous contents of the file, or nil if these
+// are not known or if the file does not exist.
+func (opt *FileOptions) store(fname string, l *fileLock, old []byte, p *Packet) error {
+	buf := &bytes.Buffer{}
+	err := p.write(buf, &PacketOptions{Pretty: true})
+	if err != nil {
+		return err
+	}
+	data := buf.Bytes()
+
+	if opt.OnChange != nil && !bytes.Equal(old, data) {
+		var oldPacket *Packet
+		if old != nil {
+			// If the old file cannot be parsed, all properties are
+			// reported as new.
+			oldPacket = NewPacket()
+			if oldPacket.decode(bytes.NewReader(old), &DecodeOptions{}) != nil {
+				oldPacket = nil
+			}
+		}
+		opt.OnChange(&FileChange{
+			FileName:  fname,
+			Length:    int64(len(old)),
+			NewLength: int64(len(data)),
+			Changes:   Diff(oldPacket, p),
+		})
+	}
+	if opt.DryRun {
+		return nil
+	}
+
+	if l == nil {
+		err = os.WriteFile(fname, data, 0o666)
+	} else {
+		err = l.commit(data)
+	}
+	if m := getMetrics(); m != nil && err == nil {
+		m.Add(CounterBytesWritten, len(data))
+	}
+	return err
+}
+
+func (opt *FileOptions) lockTimeout() time.Duration {
+	if opt.LockTimeout > 0 {
+		return opt.LockTimeout
+	}
+	return DefaultLockTimeout
+}
+
+// ReadFile reads an XMP sidecar file.
+func ReadFile(fname string) (*Packet, error) {
+	return ReadFileContext(context.Background(), fname)
+}
+
+// ReadFileContext is like [ReadFile], but reading is aborted with an error
+// if the context is cancelled or its deadline expires.
+func ReadFileContext(ctx context.Context, fname string) (*Packet, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fd, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	return Read(&ctxReader{ctx: ctx, r: fd})
+}
+
+// ctxReader is an [io.Reader] which fails once the context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(buf []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(buf)
+}
+
+// fileLock is an advisory lock on an XMP file, implemented as a lock file
+// which is created exclusively.  The lock file also receives the new
+// contents of the file.
+type fileLock struct {
+	fname    string
+	lockName string
+	fd       *os.File
+}
+
+// lockFile obtains the lock for the given file.  While the file is locked
+// by another writer, lockFile waits until the timeout expires or the context
+// is done.
+func lockFile(ctx context.Context, fname string, timeout time.Duration) (*fileLock, error) {
+	lockName := fname + ".lock"
+	deadline := time.Now().Add(timeout)
+	delay := time.Millisecond
+	for {
+		fd, err := os.OpenFile(lockName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+		if err == nil {
+			return &fileLock{fname: fname, lockName: lockName, fd: fd}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s: %w", lockName, ErrLocked)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if delay < 100*time.Millisecond {
+			delay *= 2
+		}
+	}
+}
+
+// commit writes data to the lock file and renames the lock file over the
+// target.  This releases the lock.
+func (l *fileLock) commit(data []byte) error {
+	if fi, err := os.Stat(l.fname); err == nil {
+		// Keep the permissions of the existing file.  This is not
+		// supported on all systems, so errors are ignored.
+		_ = l.fd.Chmod(fi.Mode().Perm())
+	}
+
+	_, err := l.fd.Write(data)
+	if err == nil {
+		err = l.fd.Sync()
+	}
+	if err2 := l.fd.Close(); err == nil {
+		err = err2
+	}
+	l.fd = nil
+	if err == nil {
+		err = os.Rename(l.lockName, l.fname)
+	}
+	if err != nil {
+		os.Remove(l.lockName)
+		return err
+	}
+
+	// Make the rename durable.  Directories cannot be synced on all
+	// systems, so errors are ignored.
+	if dir, err := os.Open(filepath.Dir(l.fname)); err == nil {
+		_ = dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// release removes the lock file, unless commit has already been called.
+func (l *fileLock) release() {
+	if l.fd == nil {
+		return
+	}
+	l.fd.Close()
+	os.Remove(l.lockName)
+	l.fd = nil
+}
@@ -0,0 +1,225 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpdateFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "test.xmp")
+	err := WriteFile(fname, NewPacket(), &FileOptions{Atomic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent read-modify-write cycles must not lose updates.
+	const n = 20
+	opt := &FileOptions{Atomic: true}
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- UpdateFile(fname, opt, func(p *Packet) error {
+				count, _ := PacketGetValue[Real](p, basicNamespace, "Rating")
+				p.SetValue(basicNamespace, "Rating", Real{V: count.V + 1})
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := PacketGetValue[Real](p, basicNamespace, "Rating")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.V != n {
+		t.Errorf("got count %g, want %d", count.V, n)
+	}
+
+	if _, err := os.Stat(fname + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestUpdateFileLocked(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "test.xmp")
+	err := WriteFile(fname, NewPacket(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(fname+".lock", nil, 0o666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opt := &FileOptions{Atomic: true, LockTimeout: 10 * time.Millisecond}
+	err = UpdateFile(fname, opt, func(p *Packet) error { return nil })
+	if !errors.Is(err, ErrLocked) {
+		t.Errorf("got error %v, want ErrLocked", err)
+	}
+}
+
+func TestUpdateFileError(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "test.xmp")
+	p := NewPacket()
+	p.SetValue(basicNamespace, "Label", NewText("red"))
+	err := WriteFile(fname, p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errTest := errors.New("test error")
+	err = UpdateFile(fname, &FileOptions{Atomic: true}, func(p *Packet) error {
+		p.SetValue(basicNamespace, "Label", NewText("green"))
+		return errTest
+	})
+	if err != errTest {
+		t.Errorf("got error %v, want %v", err, errTest)
+	}
+
+	p, err = ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	label, _ := PacketGetValue[Text](p, basicNamespace, "Label")
+	if label.V != "red" {
+		t.Errorf("file was modified: label %q", label.V)
+	}
+	if _, err := os.Stat(fname + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "test.xmp")
+
+	var changes []*FileChange
+	opt := &FileOptions{
+		DryRun:   true,
+		OnChange: func(c *FileChange) { changes = append(changes, c) },
+	}
+
+	p := NewPacket()
+	p.SetValue(basicNamespace, "Label", NewText("red"))
+	err := WriteFile(fname, p, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(fname); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("file was created in dry-run mode")
+	}
+
+	err = WriteFile(fname, p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = UpdateFile(fname, opt, func(p *Packet) error {
+		p.SetValue(basicNamespace, "Label", NewText("green"))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = UpdateFile(fname, opt, func(p *Packet) error {
+		return nil // no change, not reported
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Errorf("file was modified in dry-run mode")
+	}
+
+	label := xml.Name{Space: basicNamespace, Local: "Label"}
+	want := []*FileChange{
+		{
+			FileName:  fname,
+			NewLength: int64(len(before)),
+			Changes:   []Change{{Name: label, New: Text{V: "red"}}},
+		},
+		{
+			FileName: fname,
+			Length:   int64(len(before)),
+			Changes:  []Change{{Name: label, Old: Text{V: "red"}, New: Text{V: "green"}}},
+		},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(changes), len(want))
+	}
+	changes[1].NewLength = 0 // depends on the encoding
+	if d := cmp.Diff(want, changes); d != "" {
+		t.Errorf("wrong changes (-want +got):\n%s", d)
+	}
+}
+
+func TestReadFileContext(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "test.xmp")
+	err := WriteFile(fname, NewPacket(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ReadFileContext(context.Background(), fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ReadFileContext(ctx, fname)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,270 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The types in this file implement the [fmt.Formatter] interface, so that
+// the verbs %+v and %#v give a compact, readable representation of XMP
+// data.  The representation lists properties and structure fields in
+// sorted order, uses the conventional prefixes for well-known namespaces,
+// and writes array kinds and language tags in a short form:
+//
+//	{dc:creator: Seq["Jane Doe"], dc:title: Alt["Hello"@x-default]}
+//
+// With %#v, the type name is included.  All other verbs use the default
+// formatting.  Values of other types, for example the structure types used
+// by the XMP models, can be formatted in the same way using [Compact].
+
+// Format implements the [fmt.Formatter] interface.
+func (p *Packet) Format(f fmt.State, verb rune) {
+	if verb != 'v' || !(f.Flag('+') || f.Flag('#')) || p == nil {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), (*plainPacket)(p))
+		return
+	}
+
+	nsToPrefix := p.displayPrefixes()
+	nameFn := func(name xml.Name) string {
+		if pfx, ok := nsToPrefix[name.Space]; ok {
+			return pfx + ":" + name.Local
+		}
+		return formatName(name)
+	}
+
+	b := &strings.Builder{}
+	if f.Flag('#') {
+		b.WriteString("xmp.Packet")
+	}
+	b.WriteString("{")
+	sep := ""
+	if p.About != nil {
+		b.WriteString("about: <" + p.About.String() + ">")
+		sep = ", "
+	}
+	names := make([]xml.Name, 0, len(p.Properties))
+	for name := range p.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return nameFn(names[i]) < nameFn(names[j])
+	})
+	for _, name := range names {
+		b.WriteString(sep + nameFn(name) + ": ")
+		writeCompact(b, p.Properties[name], nameFn)
+		sep = ", "
+	}
+	b.WriteString("}")
+	io.WriteString(f, b.String())
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (t Text) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, t, plainText(t))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (u URL) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, u, plainURL(u))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (s RawStruct) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, s, plainRawStruct(s))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (a RawArray) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, a, plainRawArray(a))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (l Localized) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, l, plainLocalized(l))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (d Date) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, d, plainDate(d))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (p ProperName) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, p, plainProperName(p))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (a AgentName) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, a, plainAgentName(a))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (r Real) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, r, plainReal(r))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (i Integer) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, i, plainInteger(i))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (a OrderedArray[E]) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, a, plainOrderedArray[E](a))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (a UnorderedArray[E]) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, a, plainUnorderedArray[E](a))
+}
+
+// Format implements the [fmt.Formatter] interface.
+func (a AlternativeArray[E]) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, a, plainAlternativeArray[E](a))
+}
+
+// Compact returns a [fmt.Formatter] which formats v using the compact
+// representation for the verbs %+v and %#v.  This works for all values,
+// including those whose type has no Format method.
+func Compact(v Value) fmt.Formatter {
+	return compactValue{v}
+}
+
+type compactValue struct {
+	v Value
+}
+
+func (c compactValue) Format(f fmt.State, verb rune) {
+	formatValue(f, verb, c.v, c.v)
+}
+
+// These types have the same structure as the corresponding XMP types, but
+// no Format method.  They are used to get the default formatting.
+type (
+	plainPacket     Packet
+	plainText       Text
+	plainURL        URL
+	plainRawStruct  RawStruct
+	plainRawArray   RawArray
+	plainLocalized  Localized
+	plainDate       Date
+	plainProperName ProperName
+	plainAgentName  AgentName
+	plainReal       Real
+	plainInteger    Integer
+
+	plainOrderedArray[E Value]     OrderedArray[E]
+	plainUnorderedArray[E Value]   UnorderedArray[E]
+	plainAlternativeArray[E Value] AlternativeArray[E]
+)
+
+// formatValue implements the Format method for XMP values.  The argument v
+// must be either a [Raw] or a [Value].  The argument plain must be the same
+// value, converted to a type without a Format method.
+func formatValue(f fmt.State, verb rune, v any, plain any) {
+	if verb == 'v' && (f.Flag('+') || f.Flag('#')) {
+		b := &strings.Builder{}
+		if f.Flag('#') {
+			fmt.Fprintf(b, "%T(", v)
+		}
+		var raw Raw
+		switch v := v.(type) {
+		case Raw:
+			raw = v
+		case Value:
+			raw = v.EncodeXMP(NewPacket())
+		}
+		writeCompact(b, raw, formatName)
+		if f.Flag('#') {
+			b.WriteString(")")
+		}
+		io.WriteString(f, b.String())
+		return
+	}
+
+	if s, ok := v.(fmt.Stringer); ok && (verb == 'v' || verb == 's' || verb == 'q') {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), s.String())
+		return
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), plain)
+}
+
+// writeCompact writes the compact representation of a low-level XMP value.
+func writeCompact(b *strings.Builder, val Raw, nameFn func(xml.Name) string) {
+	var q Q
+	switch val := val.(type) {
+	case Text:
+		b.WriteString(strconv.Quote(val.V))
+		q = val.Q
+	case URL:
+		if val.V == nil {
+			b.WriteString("<>")
+		} else {
+			b.WriteString("<" + val.V.String() + ">")
+		}
+		q = val.Q
+	case RawStruct:
+		b.WriteString("Struct{")
+		for i, name := range val.FieldNames() {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(nameFn(name) + ": ")
+			writeCompact(b, val.Value[name], nameFn)
+		}
+		b.WriteString("}")
+		q = val.Q
+	case RawArray:
+		b.WriteString(val.Kind.String() + "[")
+		for i, elem := range val.Value {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeCompact(b, elem, nameFn)
+		}
+		b.WriteString("]")
+		q = val.Q
+	default:
+		b.WriteString("<nil>")
+	}
+
+	var other Q
+	for _, qi := range q {
+		if t, ok := qi.Value.(Text); ok && qi.Name == nameXMLLang && len(t.Q) == 0 {
+			b.WriteString("@" + t.V)
+		} else {
+			other = append(other, qi)
+		}
+	}
+	if len(other) > 0 {
+		b.WriteString("{")
+		for i, qi := range other {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(nameFn(qi.Name) + ": ")
+			writeCompact(b, qi.Value, nameFn)
+		}
+		b.WriteString("}")
+	}
+}
@@ -0,0 +1,83 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestFormat(t *testing.T) {
+	title := Localized{Default: NewText("Hello")}
+	title.Set(language.German, "Hallo")
+
+	p := NewPacket()
+	p.About = testURL
+	p.SetValue("http://purl.org/dc/elements/1.1/", "title", title)
+	p.Properties[elemTest] = RawStruct{
+		Value: map[xml.Name]Raw{
+			elemTestB: URL{V: testURL},
+			elemTestA: Text{V: "1", Q: Q{{Name: elemTestQ, Value: Text{V: "q"}}}},
+		},
+	}
+
+	cases := []struct {
+		format string
+		arg    any
+		want   string
+	}{
+		{"%+v", p, `{about: <http://example.com>, dc:title: Alt["Hello"@x-default, "Hallo"@de], ` +
+			`test:prop: Struct{test:a: "1"{test:q: "q"}, test:b: <http://example.com>}}`},
+		{"%#v", Text{V: "x"}, `xmp.Text("x")`},
+		{"%v", Text{V: "x"}, `x`},
+		{"%5s|", Text{V: "x"}, `    x|`},
+		{"%q", Text{V: "x"}, `"x"`},
+		{"%+v", RawArray{Kind: Unordered, Value: []Raw{Text{V: "a"}, Text{V: "b"}}}, `Bag["a", "b"]`},
+		{"%+v", title, `Alt["Hello"@x-default, "Hallo"@de]`},
+		{"%#v", NewDate(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), `xmp.Date("2024-05-01T00:00:00Z")`},
+		{"%+v", ProperName{V: "Jane Doe"}, `"Jane Doe"`},
+		{"%v", ProperName{V: "Jane Doe"}, `Jane Doe`},
+		{"%+v", AgentName{V: "Tool 1.0"}, `"Tool 1.0"`},
+		{"%#v", Real{V: 1.5}, `xmp.Real("1.5")`},
+		{"%+v", Integer{V: 42}, `"42"`},
+		{"%+v", OrderedArray[ProperName]{V: []ProperName{{V: "a"}, {V: "b"}}}, `Seq["a", "b"]`},
+		{"%+v", UnorderedArray[Text]{V: []Text{{V: "a"}}}, `Bag["a"]`},
+		{"%+v", AlternativeArray[Text]{V: []Text{{V: "a"}}}, `Alt["a"]`},
+		{"%+v", Compact(Dimensions{W: Real{V: 2}, H: Real{V: 3}, Unit: NewText("inch")}),
+			`Struct{stDim:h: "3", stDim:unit: "inch", stDim:w: "2"}`},
+		{"%#v", Compact(GUID{V: "uuid:1"}), `xmp.GUID("uuid:1")`},
+		{"%v", Compact(Text{V: "x"}), `x`},
+	}
+	for _, c := range cases {
+		got := fmt.Sprintf(c.format, c.arg)
+		if got != c.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", c.format, got, c.want)
+		}
+	}
+
+	// Output must be stable.
+	first := fmt.Sprintf("%+v", p)
+	for i := 0; i < 10; i++ {
+		if got := fmt.Sprintf("%+v", p); got != first {
+			t.Fatalf("output not stable: %s != %s", got, first)
+		}
+	}
+}
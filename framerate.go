@@ -0,0 +1,185 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FrameRate represents the frame rate of a media file, for example in
+// xmpDM:Tracks.  The number of frames per second is Num/Den.
+//
+// In XMP, frame rates are written as "f25" or, if the denominator is not
+// one, as "f30000s1001".
+//
+// See section 1.2.6.7 of part 2 of the XMP specification (2016).
+type FrameRate struct {
+	Num, Den int64
+	Q
+}
+
+// NewFrameRate creates a new frame rate of num/den frames per second.
+func NewFrameRate(num, den int64, qualifiers ...Qualifier) FrameRate {
+	return FrameRate{Num: num, Den: den, Q: qualifiers}
+}
+
+// FPS returns the number of frames per second.
+func (r FrameRate) FPS() float64 {
+	return float64(r.Num) / float64(r.denominator())
+}
+
+func (r FrameRate) denominator() int64 {
+	if r.Den == 0 {
+		return 1
+	}
+	return r.Den
+}
+
+func (r FrameRate) String() string {
+	s := "f" + strconv.FormatInt(r.Num, 10)
+	if den := r.denominator(); den != 1 {
+		s += "s" + strconv.FormatInt(den, 10)
+	}
+	return s
+}
+
+// IsZero implements the [Value] interface.
+func (r FrameRate) IsZero() bool {
+	return r.Num == 0 && r.Den == 0 && len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r FrameRate) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: r.String(),
+		Q: r.Q,
+	}
+}
+
+// DecodeAnother implements the [Value] interface.
+func (FrameRate) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	num, den, ok := parseFrameRate(v.V)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return FrameRate{Num: num, Den: den, Q: v.Q}, nil
+}
+
+// parseFrameRate parses a frame rate of the form "f###" or "f###s###".
+func parseFrameRate(s string) (num, den int64, ok bool) {
+	s, found := strings.CutPrefix(s, "f")
+	if !found {
+		return 0, 0, false
+	}
+	numString, denString, hasDen := strings.Cut(s, "s")
+	num, err := strconv.ParseInt(numString, 10, 64)
+	if err != nil || num <= 0 {
+		return 0, 0, false
+	}
+	den = 1
+	if hasDen {
+		den, err = strconv.ParseInt(denString, 10, 64)
+		if err != nil || den <= 0 {
+			return 0, 0, false
+		}
+	}
+	return num, den, true
+}
+
+// FrameCount represents a position or length in a media file, measured in
+// frames, as used for example in xmpDM:startTime.
+//
+// In XMP, frame counts are written as "###", or with an explicit frame
+// rate as "###f###" or "###f###s###".
+//
+// See section 1.2.6.6 of part 2 of the XMP specification (2016).
+type FrameCount struct {
+	// Count is the number of frames.
+	Count int64
+
+	// Rate is the frame rate.  If Rate is zero, the frame rate is given by
+	// the context, for example by the enclosing track.  Qualifiers of Rate
+	// are ignored.
+	Rate FrameRate
+
+	Q
+}
+
+// Duration converts the frame count to a [time.Duration].  If the frame
+// count has no frame rate, the given default rate is used.  The result is
+// rounded to the nearest nanosecond.
+func (c FrameCount) Duration(defaultRate FrameRate) (time.Duration, error) {
+	rate := c.Rate
+	if rate.Num == 0 {
+		rate = defaultRate
+	}
+	if rate.Num <= 0 {
+		return 0, ErrInvalid
+	}
+	sec := float64(c.Count) * float64(rate.denominator()) / float64(rate.Num)
+	return time.Duration(sec*1e9 + 0.5), nil
+}
+
+func (c FrameCount) String() string {
+	s := strconv.FormatInt(c.Count, 10)
+	if c.Rate.Num != 0 {
+		s += c.Rate.String()
+	}
+	return s
+}
+
+// IsZero implements the [Value] interface.
+func (c FrameCount) IsZero() bool {
+	return c.Count == 0 && c.Rate.Num == 0 && c.Rate.Den == 0 && len(c.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (c FrameCount) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: c.String(),
+		Q: c.Q,
+	}
+}
+
+// DecodeAnother implements the [Value] interface.
+func (FrameCount) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+
+	countString, rateString, hasRate := strings.Cut(v.V, "f")
+	count, err := strconv.ParseInt(countString, 10, 64)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	res := FrameCount{Count: count, Q: v.Q}
+	if hasRate {
+		num, den, ok := parseFrameRate("f" + rateString)
+		if !ok {
+			return nil, ErrInvalid
+		}
+		res.Rate = FrameRate{Num: num, Den: den}
+	}
+	return res, nil
+}
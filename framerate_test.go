@@ -0,0 +1,107 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFrameRate(t *testing.T) {
+	testCases := []struct {
+		in       string
+		num, den int64
+		ok       bool
+	}{
+		{"f25", 25, 1, true},
+		{"f30000s1001", 30000, 1001, true},
+		{"f254", 254, 1, true},
+		{"25", 0, 0, false},
+		{"f", 0, 0, false},
+		{"f0", 0, 0, false},
+		{"f25s", 0, 0, false},
+		{"f25s0", 0, 0, false},
+	}
+	for _, tc := range testCases {
+		v, err := FrameRate{}.DecodeAnother(Text{V: tc.in})
+		if (err == nil) != tc.ok {
+			t.Errorf("%q: got error %v", tc.in, err)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		r := v.(FrameRate)
+		if r.Num != tc.num || r.Den != tc.den {
+			t.Errorf("%q: got %d/%d, want %d/%d", tc.in, r.Num, r.Den, tc.num, tc.den)
+		}
+		if s := r.String(); s != tc.in {
+			t.Errorf("%q: formatted as %q", tc.in, s)
+		}
+	}
+
+	if fps := NewFrameRate(30000, 1001).FPS(); fps < 29.97 || fps > 29.98 {
+		t.Errorf("wrong FPS %g", fps)
+	}
+}
+
+func TestFrameCount(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want FrameCount
+		ok   bool
+	}{
+		{"125", FrameCount{Count: 125}, true},
+		{"250f25", FrameCount{Count: 250, Rate: NewFrameRate(25, 1)}, true},
+		{"3003f30000s1001", FrameCount{Count: 3003, Rate: NewFrameRate(30000, 1001)}, true},
+		{"", FrameCount{}, false},
+		{"12f", FrameCount{}, false},
+		{"x", FrameCount{}, false},
+	}
+	for _, tc := range testCases {
+		v, err := FrameCount{}.DecodeAnother(Text{V: tc.in})
+		if (err == nil) != tc.ok {
+			t.Errorf("%q: got error %v", tc.in, err)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		c := v.(FrameCount)
+		if c.Count != tc.want.Count || c.Rate.Num != tc.want.Rate.Num || c.Rate.Den != tc.want.Rate.Den {
+			t.Errorf("%q: got %s, want %s", tc.in, c, tc.want)
+		}
+		if s := c.String(); s != tc.in {
+			t.Errorf("%q: formatted as %q", tc.in, s)
+		}
+	}
+}
+
+func TestFrameCountDuration(t *testing.T) {
+	d, err := FrameCount{Count: 125}.Duration(NewFrameRate(25, 1))
+	if err != nil || d != 5*time.Second {
+		t.Errorf("got %v, %v", d, err)
+	}
+	d, err = FrameCount{Count: 3000, Rate: NewFrameRate(30000, 1001)}.Duration(NewFrameRate(25, 1))
+	if err != nil || d != 100100*time.Millisecond {
+		t.Errorf("got %v, %v", d, err)
+	}
+	_, err = FrameCount{Count: 1}.Duration(FrameRate{})
+	if err == nil {
+		t.Error("expected error for missing frame rate")
+	}
+}
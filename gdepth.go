@@ -0,0 +1,98 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+// GDepth represents Google's depth map namespace.
+//
+// A depth map gives the distance from the camera for every pixel of the
+// image.  It is stored as an image (usually PNG or JPEG) inside the XMP
+// data.  Because of its size, the depth map is normally found in the
+// extended XMP data of a JPEG file.
+//
+// See https://developers.google.com/depthmap-metadata/reference .
+type GDepth struct {
+	_ Namespace `xmp:"http://ns.google.com/photos/1.0/depthmap/"`
+	_ Prefix    `xmp:"GDepth"`
+
+	// Format is the conversion from pixel values to depth, either
+	// "RangeInverse" or "RangeLinear".
+	Format Text
+
+	// Near is the near distance of the depth range.
+	Near Real
+
+	// Far is the far distance of the depth range.
+	Far Real
+
+	// Mime is the media type of the depth map image.
+	Mime MimeType
+
+	// Data holds the depth map image.
+	Data Base64Data
+
+	// Units are the units of Near and Far, for example "m" or "mm".
+	Units Text
+
+	// MeasureType is the type of depth measurement, either "OpticalAxis"
+	// (the default) or "OpticRay".
+	MeasureType Text
+
+	// ConfidenceMime is the media type of the confidence map image.
+	ConfidenceMime MimeType
+
+	// Confidence holds the confidence map image, which gives the
+	// confidence of the depth value for every pixel.
+	Confidence Base64Data
+
+	// Manufacturer is the manufacturer of the device which created the
+	// depth map.
+	Manufacturer Text
+
+	// Model is the model of the device which created the depth map.
+	Model Text
+
+	// Software is the software which created the depth map.
+	Software Text
+
+	// ImageWidth is the width of the original image, in pixels.
+	ImageWidth Integer
+
+	// ImageHeight is the height of the original image, in pixels.
+	ImageHeight Integer
+}
+
+// GImage represents Google's image namespace.
+//
+// This is used by camera apps which apply effects like background blur,
+// to store the original, unmodified image inside the XMP data.
+//
+// See https://developers.google.com/depthmap-metadata/reference .
+type GImage struct {
+	_ Namespace `xmp:"http://ns.google.com/photos/1.0/image/"`
+	_ Prefix    `xmp:"GImage"`
+
+	// Mime is the media type of the original image.
+	Mime MimeType
+
+	// Data holds the original image.
+	Data Base64Data
+}
+
+const (
+	gdepthNamespace = "http://ns.google.com/photos/1.0/depthmap/"
+	gimageNamespace = "http://ns.google.com/photos/1.0/image/"
+)
@@ -0,0 +1,100 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestGDepth(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	in := &GDepth{
+		Format:      NewText("RangeInverse"),
+		Near:        Real{V: 0.25},
+		Far:         Real{V: 12.5},
+		Mime:        MimeType{V: "image/png"},
+		Data:        Base64Data{V: png},
+		Units:       NewText("m"),
+		MeasureType: NewText("OpticalAxis"),
+		ImageWidth:  Integer{V: 4032},
+		ImageHeight: Integer{V: 3024},
+	}
+	orig := &GImage{
+		Mime: MimeType{V: "image/jpeg"},
+		Data: Base64Data{V: []byte{0xFF, 0xD8, 0xFF, 0xD9}},
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in, orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("/9j/2Q==")) {
+		t.Errorf("wrong encoding:\n%s", buf.Bytes())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &GDepth{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out, cmpopts.EquateEmpty()); d != "" {
+		t.Errorf("GDepth differs (-want +got):\n%s", d)
+	}
+	outOrig := &GImage{}
+	p2.Get(outOrig)
+	if d := cmp.Diff(orig, outOrig, cmpopts.EquateEmpty()); d != "" {
+		t.Errorf("GImage differs (-want +got):\n%s", d)
+	}
+}
+
+func TestBase64DataDecode(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []byte
+		ok   bool
+	}{
+		{"", []byte{}, true},
+		{"aGVsbG8=", []byte("hello"), true},
+		{"aGVs\n  bG8=\n", []byte("hello"), true},
+		{"aGVsbG8", []byte("hello"), true},
+		{"not base64!", nil, false},
+	}
+	for _, c := range cases {
+		v, err := Base64Data{}.DecodeAnother(Text{V: c.in})
+		if (err == nil) != c.ok {
+			t.Errorf("%q: unexpected error %v", c.in, err)
+			continue
+		}
+		if !c.ok {
+			continue
+		}
+		if d := cmp.Diff(c.want, v.(Base64Data).V); d != "" {
+			t.Errorf("%q: (-want +got):\n%s", c.in, d)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GPano represents Google's Photo Sphere namespace.
+//
+// The properties describe panoramic images, for example 360° photos in
+// equirectangular projection.  If only part of the full panorama is
+// covered by the image, the CroppedArea properties give the position of
+// the image within the full panorama.
+//
+// See https://developers.google.com/streetview/spherical-metadata .
+type GPano struct {
+	_ Namespace `xmp:"http://ns.google.com/photos/1.0/panorama/"`
+	_ Prefix    `xmp:"GPano"`
+
+	// UsePanoramaViewer indicates whether the image should be shown in a
+	// panorama viewer rather than as a normal flat image.
+	UsePanoramaViewer OptionalBool
+
+	// CaptureSoftware is the software used to take the photos.
+	CaptureSoftware Text
+
+	// StitchingSoftware is the software used to stitch the panorama.
+	StitchingSoftware Text
+
+	// ProjectionType is the projection type used in the image file.
+	// Currently, only "equirectangular" is supported by viewers.
+	ProjectionType Text
+
+	// PoseHeadingDegrees is the compass heading of the image center, in
+	// degrees, measured clockwise from north.
+	PoseHeadingDegrees Real
+
+	// PosePitchDegrees is the pitch of the image, in degrees.  Positive
+	// values look up.
+	PosePitchDegrees Real
+
+	// PoseRollDegrees is the roll of the image, in degrees.
+	PoseRollDegrees Real
+
+	// InitialViewHeadingDegrees is the heading of the initial view, in
+	// degrees.
+	InitialViewHeadingDegrees Real
+
+	// InitialViewPitchDegrees is the pitch of the initial view, in
+	// degrees.
+	InitialViewPitchDegrees Real
+
+	// InitialViewRollDegrees is the roll of the initial view, in degrees.
+	InitialViewRollDegrees Real
+
+	// InitialHorizontalFOVDegrees is the horizontal field of view of the
+	// initial view, in degrees.
+	InitialHorizontalFOVDegrees Real
+
+	// FirstPhotoDate is the date and time of the first photo of the
+	// panorama.
+	FirstPhotoDate Date
+
+	// LastPhotoDate is the date and time of the last photo of the
+	// panorama.
+	LastPhotoDate Date
+
+	// SourcePhotosCount is the number of photos used to create the
+	// panorama.
+	SourcePhotosCount Integer
+
+	// ExposureLockUsed indicates whether the exposure was locked while
+	// taking the photos.
+	ExposureLockUsed OptionalBool
+
+	// CroppedAreaImageWidthPixels is the width of the image, in pixels.
+	CroppedAreaImageWidthPixels Integer
+
+	// CroppedAreaImageHeightPixels is the height of the image, in pixels.
+	CroppedAreaImageHeightPixels Integer
+
+	// FullPanoWidthPixels is the width of the full panorama, in pixels.
+	FullPanoWidthPixels Integer
+
+	// FullPanoHeightPixels is the height of the full panorama, in pixels.
+	FullPanoHeightPixels Integer
+
+	// CroppedAreaLeftPixels is the horizontal position of the image
+	// within the full panorama, in pixels.
+	CroppedAreaLeftPixels Integer
+
+	// CroppedAreaTopPixels is the vertical position of the image within
+	// the full panorama, in pixels.
+	CroppedAreaTopPixels Integer
+
+	// InitialCameraDolly moves the virtual camera along the line of
+	// sight, away from the center of the photo sphere.  The range is -1
+	// to 1, where 0 is the center.
+	InitialCameraDolly Real
+}
+
+// Validate checks that the properties required by Google's viewers are
+// present and consistent: a projection type must be given, the cropped
+// area must lie within the full panorama, and the full panorama of an
+// equirectangular projection must be twice as wide as it is high.
+func (g *GPano) Validate() error {
+	if g.ProjectionType.IsZero() {
+		return errors.New("missing GPano projection type")
+	}
+
+	fullW, fullH := g.FullPanoWidthPixels.V, g.FullPanoHeightPixels.V
+	cropW, cropH := g.CroppedAreaImageWidthPixels.V, g.CroppedAreaImageHeightPixels.V
+	if fullW <= 0 || fullH <= 0 {
+		return errors.New("missing GPano full panorama size")
+	}
+	if cropW <= 0 || cropH <= 0 {
+		return errors.New("missing GPano cropped area size")
+	}
+	left, top := g.CroppedAreaLeftPixels.V, g.CroppedAreaTopPixels.V
+	if left < 0 || top < 0 || left+cropW > fullW || top+cropH > fullH {
+		return fmt.Errorf("GPano cropped area %dx%d+%d+%d exceeds full panorama %dx%d",
+			cropW, cropH, left, top, fullW, fullH)
+	}
+
+	if g.ProjectionType.V == "equirectangular" && fullW != 2*fullH {
+		return fmt.Errorf("invalid equirectangular panorama size %dx%d", fullW, fullH)
+	}
+	return nil
+}
+
+const gpanoNamespace = "http://ns.google.com/photos/1.0/panorama/"
@@ -0,0 +1,97 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGPano(t *testing.T) {
+	in := &GPano{
+		UsePanoramaViewer:            OptionalBool{V: 2},
+		ProjectionType:               NewText("equirectangular"),
+		PoseHeadingDegrees:           Real{V: 350.5},
+		CroppedAreaImageWidthPixels:  Integer{V: 8000},
+		CroppedAreaImageHeightPixels: Integer{V: 2000},
+		FullPanoWidthPixels:          Integer{V: 8000},
+		FullPanoHeightPixels:         Integer{V: 4000},
+		CroppedAreaTopPixels:         Integer{V: 1000},
+	}
+	err := in.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := NewPacket()
+	err = p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<GPano:ProjectionType>equirectangular</GPano:ProjectionType>")) {
+		t.Errorf("wrong encoding:\n%s", buf.Bytes())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &GPano{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
+
+func TestGPanoValidate(t *testing.T) {
+	valid := GPano{
+		ProjectionType:               NewText("equirectangular"),
+		CroppedAreaImageWidthPixels:  Integer{V: 4000},
+		CroppedAreaImageHeightPixels: Integer{V: 2000},
+		FullPanoWidthPixels:          Integer{V: 4000},
+		FullPanoHeightPixels:         Integer{V: 2000},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	noType := valid
+	noType.ProjectionType = Text{}
+	if err := noType.Validate(); err == nil {
+		t.Error("missing projection type not detected")
+	}
+
+	outside := valid
+	outside.CroppedAreaLeftPixels = Integer{V: 1}
+	if err := outside.Validate(); err == nil {
+		t.Error("cropped area outside of panorama not detected")
+	}
+
+	badAspect := valid
+	badAspect.FullPanoHeightPixels = Integer{V: 3000}
+	badAspect.CroppedAreaImageHeightPixels = Integer{V: 3000}
+	if err := badAspect.Validate(); err == nil {
+		t.Error("invalid aspect ratio not detected")
+	}
+}
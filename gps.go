@@ -0,0 +1,179 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GPSCoordinate represents a latitude or longitude.
+//
+// In XMP, coordinates are represented as text of the form "DDD,MM,SSk" or
+// "DDD,MM.mmk", where DDD are the degrees, MM the minutes, SS the seconds,
+// and k is one of N, S, E, or W.
+type GPSCoordinate struct {
+	// V is the coordinate in decimal degrees.  Positive values indicate
+	// north or east, negative values indicate south or west.
+	V float64
+
+	// Longitude is true for east/west coordinates and false for north/south
+	// coordinates.
+	Longitude bool
+
+	Q
+}
+
+// NewLatitude creates a new latitude value from decimal degrees.
+// Positive values indicate north.
+func NewLatitude(deg float64, qualifiers ...Qualifier) GPSCoordinate {
+	return GPSCoordinate{V: deg, Q: Q(qualifiers)}
+}
+
+// NewLongitude creates a new longitude value from decimal degrees.
+// Positive values indicate east.
+func NewLongitude(deg float64, qualifiers ...Qualifier) GPSCoordinate {
+	return GPSCoordinate{V: deg, Longitude: true, Q: Q(qualifiers)}
+}
+
+func (c GPSCoordinate) String() string {
+	return c.format()
+}
+
+// IsZero implements the [Value] interface.
+func (c GPSCoordinate) IsZero() bool {
+	return c.V == 0 && !c.Longitude && len(c.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+// The value is written in the form "DDD,MM.mmk", with minutes rounded to
+// six decimal places.
+func (c GPSCoordinate) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: c.format(),
+		Q: c.Q,
+	}
+}
+
+func (c GPSCoordinate) format() string {
+	var ref byte
+	switch {
+	case c.Longitude && c.V < 0:
+		ref = 'W'
+	case c.Longitude:
+		ref = 'E'
+	case c.V < 0:
+		ref = 'S'
+	default:
+		ref = 'N'
+	}
+
+	// Round to whole micro-minutes first, so that the minutes never
+	// round up to 60.
+	total := int64(math.Round(math.Abs(c.V) * 60e6))
+	deg := total / 60e6
+	minutes := float64(total%60e6) / 1e6
+
+	m := strconv.FormatFloat(minutes, 'f', -1, 64)
+	if !strings.Contains(m, ".") {
+		m += ".0"
+	}
+	return strconv.FormatInt(deg, 10) + "," + m + string(ref)
+}
+
+// DecodeAnother implements the [Value] interface.
+func (GPSCoordinate) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	res, repaired, err := parseGPSCoordinate(v)
+	if err != nil || repaired {
+		return nil, ErrInvalid
+	}
+	return res, nil
+}
+
+// repair implements the repairer interface.
+// Surrounding white space and lower-case direction letters are repaired.
+func (GPSCoordinate) repair(val Raw) (Value, string, bool) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, "", false
+	}
+	res, _, err := parseGPSCoordinate(v)
+	if err != nil {
+		return nil, "", false
+	}
+	return res, "malformed coordinate " + strconv.Quote(v.V), true
+}
+
+// parseGPSCoordinate parses a coordinate in either of the two formats
+// allowed by the EXIF specification.  If the input is malformed but can
+// be repaired, repaired is set to true.
+func parseGPSCoordinate(v Text) (res GPSCoordinate, repaired bool, err error) {
+	s := strings.TrimSpace(v.V)
+	if s != v.V {
+		repaired = true
+	}
+	if len(s) < 2 {
+		return res, false, ErrInvalid
+	}
+
+	res.Q = v.Q
+	var sign float64
+	var maxDeg float64
+	ref := s[len(s)-1]
+	if ref >= 'a' && ref <= 'z' {
+		ref -= 'a' - 'A'
+		repaired = true
+	}
+	switch ref {
+	case 'N':
+		sign, maxDeg = 1, 90
+	case 'S':
+		sign, maxDeg = -1, 90
+	case 'E':
+		sign, maxDeg = 1, 180
+		res.Longitude = true
+	case 'W':
+		sign, maxDeg = -1, 180
+		res.Longitude = true
+	default:
+		return res, false, ErrInvalid
+	}
+
+	parts := strings.Split(s[:len(s)-1], ",")
+	if len(parts) != 2 && len(parts) != 3 {
+		return res, false, ErrInvalid
+	}
+	var x [3]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(part, 64)
+		if err != nil || !(f >= 0) || math.IsInf(f, 0) || i > 0 && f >= 60 {
+			return res, false, ErrInvalid
+		}
+		x[i] = f
+	}
+	deg := x[0] + x[1]/60 + x[2]/3600
+	if deg > maxDeg {
+		return res, false, ErrInvalid
+	}
+	res.V = sign * deg
+	return res, repaired, nil
+}
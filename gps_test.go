@@ -0,0 +1,95 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGPSCoordinateDecode(t *testing.T) {
+	testCases := []struct {
+		in        string
+		deg       float64
+		longitude bool
+		ok        bool
+		repaired  bool
+	}{
+		{"52,30.123N", 52 + 30.123/60, false, true, false},
+		{"52,30,36S", -(52 + 30.0/60 + 36.0/3600), false, true, false},
+		{"1,32.9W", -(1 + 32.9/60), true, true, false},
+		{"179,59.5E", 179 + 59.5/60, true, true, false},
+		{"0,0.0N", 0, false, true, false},
+		{" 52,30.0n", 52.5, false, true, true},
+		{"91,0.0N", 0, false, false, false},
+		{"52,60.0N", 0, false, false, false},
+		{"52,30.0X", 0, false, false, false},
+		{"52.5N", 0, false, false, false},
+		{"-52,30.0N", 0, false, false, false},
+		{"N", 0, false, false, false},
+	}
+	for _, tc := range testCases {
+		_, err := GPSCoordinate{}.DecodeAnother(Text{V: tc.in})
+		if valid := err == nil; valid != (tc.ok && !tc.repaired) {
+			t.Errorf("%q: DecodeAnother gave error %v", tc.in, err)
+		}
+		v, msg, ok := repairValue(GPSCoordinate{}, Text{V: tc.in})
+		if ok != tc.ok {
+			t.Errorf("%q: got ok=%t, want %t", tc.in, ok, tc.ok)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		if repaired := msg != ""; repaired != tc.repaired {
+			t.Errorf("%q: repaired=%t, want %t", tc.in, repaired, tc.repaired)
+		}
+		c := v.(GPSCoordinate)
+		if math.Abs(c.V-tc.deg) > 1e-12 || c.Longitude != tc.longitude {
+			t.Errorf("%q: got %g (longitude=%t), want %g (longitude=%t)",
+				tc.in, c.V, c.Longitude, tc.deg, tc.longitude)
+		}
+	}
+}
+
+func TestGPSCoordinateEncode(t *testing.T) {
+	testCases := []struct {
+		in   GPSCoordinate
+		want string
+	}{
+		{NewLatitude(52.5), "52,30.0N"},
+		{NewLatitude(-33.8688), "33,52.128S"},
+		{NewLongitude(151.2093), "151,12.558E"},
+		{NewLongitude(-0.1276), "0,7.656W"},
+		{NewLatitude(10 - 1e-9), "10,0.0N"},
+	}
+	for _, tc := range testCases {
+		got := tc.in.EncodeXMP(nil).(Text).V
+		if got != tc.want {
+			t.Errorf("%g: got %q, want %q", tc.in.V, got, tc.want)
+		}
+
+		back, err := GPSCoordinate{}.DecodeAnother(Text{V: got})
+		if err != nil {
+			t.Errorf("%q: %v", got, err)
+			continue
+		}
+		if c := back.(GPSCoordinate); math.Abs(c.V-tc.in.V) > 1e-7 || c.Longitude != tc.in.Longitude {
+			t.Errorf("%q: round trip gave %g", got, c.V)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"unicode/utf16"
+)
+
+// ICCProfileInfo contains identifying information about an ICC profile.
+type ICCProfileInfo struct {
+	// Description is the profile description from the 'desc' tag.
+	Description string
+
+	// ProfileID is the MD5 checksum of the profile, computed as described
+	// in section 7.2.18 of ICC.1:2010.  For version 4 profiles this
+	// coincides with the profile ID stored in the profile header.
+	ProfileID [16]byte
+}
+
+// ParseICCProfile extracts the description and the profile ID from the
+// binary data of an ICC profile.
+func ParseICCProfile(data []byte) (*ICCProfileInfo, error) {
+	if len(data) < 132 || string(data[36:40]) != "acsp" {
+		return nil, errMalformedICC
+	}
+
+	info := &ICCProfileInfo{
+		ProfileID: iccProfileID(data),
+	}
+
+	numTags := int(binary.BigEndian.Uint32(data[128:132]))
+	if numTags > (len(data)-132)/12 {
+		return nil, errMalformedICC
+	}
+	for i := 0; i < numTags; i++ {
+		entry := data[132+12*i : 144+12*i]
+		if string(entry[0:4]) != "desc" {
+			continue
+		}
+		offs := int64(binary.BigEndian.Uint32(entry[4:8]))
+		size := int64(binary.BigEndian.Uint32(entry[8:12]))
+		if offs+size > int64(len(data)) {
+			return nil, errMalformedICC
+		}
+		desc, err := parseICCText(data[offs : offs+size])
+		if err != nil {
+			return nil, err
+		}
+		info.Description = desc
+		break
+	}
+
+	return info, nil
+}
+
+// SetICCProfile sets photoshop:ICCProfile to the description of the given
+// ICC profile.  The profile ID is attached to the value as an
+// iccRef:profileID qualifier (namespace "http://ns.seehuhn.de/xmp/icc/1.0/"),
+// so that [Packet.CheckICCProfile] can later verify that an embedded profile
+// matches the one referenced in the metadata.
+func (p *Packet) SetICCProfile(data []byte) error {
+	info, err := ParseICCProfile(data)
+	if err != nil {
+		return err
+	}
+
+	p.RegisterPrefix(iccRefNamespace, "iccRef")
+	p.SetValue(photoshopNamespace, "ICCProfile", Text{
+		V: info.Description,
+		Q: Q{{Name: nameICCProfileID, Value: Text{V: hex.EncodeToString(info.ProfileID[:])}}},
+	})
+	return nil
+}
+
+// CheckICCProfile checks whether the given ICC profile matches the profile
+// referenced by photoshop:ICCProfile.  If the packet records a profile ID
+// (see [Packet.SetICCProfile]), the IDs are compared.  Otherwise, the
+// profile descriptions are compared.
+//
+// If the packet does not reference an ICC profile, [ErrNotFound] is
+// returned.
+func (p *Packet) CheckICCProfile(data []byte) (bool, error) {
+	ref, err := PacketGetValue[Text](p, photoshopNamespace, "ICCProfile")
+	if err != nil {
+		return false, err
+	}
+	info, err := ParseICCProfile(data)
+	if err != nil {
+		return false, err
+	}
+
+	for _, q := range ref.Q {
+		if q.Name != nameICCProfileID {
+			continue
+		}
+		if id, ok := q.Value.(Text); ok {
+			return strings.EqualFold(id.V, hex.EncodeToString(info.ProfileID[:])), nil
+		}
+	}
+	return ref.V == info.Description, nil
+}
+
+// iccProfileID computes the MD5 checksum of an ICC profile, with the
+// profile flags, rendering intent and profile ID header fields set to zero.
+func iccProfileID(data []byte) [16]byte {
+	h := md5.New()
+	var zero [16]byte
+	h.Write(data[:44])
+	h.Write(zero[:4]) // profile flags
+	h.Write(data[48:64])
+	h.Write(zero[:4]) // rendering intent
+	h.Write(data[68:84])
+	h.Write(zero[:16]) // profile ID
+	h.Write(data[100:])
+
+	var res [16]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
+// parseICCText decodes a 'desc' (version 2) or 'mluc' (version 4) tag.
+func parseICCText(tag []byte) (string, error) {
+	if len(tag) < 12 {
+		return "", errMalformedICC
+	}
+	switch string(tag[:4]) {
+	case "desc":
+		n := int(binary.BigEndian.Uint32(tag[8:12]))
+		if n > len(tag)-12 {
+			return "", errMalformedICC
+		}
+		return strings.TrimRight(string(tag[12:12+n]), "\x00"), nil
+	case "mluc":
+		if len(tag) < 16 {
+			return "", errMalformedICC
+		}
+		numRecords := int(binary.BigEndian.Uint32(tag[8:12]))
+		recordSize := int(binary.BigEndian.Uint32(tag[12:16]))
+		if numRecords < 1 || recordSize < 12 || len(tag) < 16+recordSize {
+			return "", errMalformedICC
+		}
+		// We use the first record, which is typically the English version.
+		rec := tag[16 : 16+recordSize]
+		n := int64(binary.BigEndian.Uint32(rec[4:8]))
+		offs := int64(binary.BigEndian.Uint32(rec[8:12]))
+		if offs+n > int64(len(tag)) || n%2 != 0 {
+			return "", errMalformedICC
+		}
+		raw := tag[offs : offs+n]
+		u := make([]uint16, len(raw)/2)
+		for i := range u {
+			u[i] = binary.BigEndian.Uint16(raw[2*i:])
+		}
+		return strings.TrimRight(string(utf16.Decode(u)), "\x00"), nil
+	default:
+		return "", errMalformedICC
+	}
+}
+
+var errMalformedICC = errors.New("malformed ICC profile")
+
+const (
+	photoshopNamespace = "http://ns.adobe.com/photoshop/1.0/"
+	iccRefNamespace    = "http://ns.seehuhn.de/xmp/icc/1.0/"
+)
+
+var nameICCProfileID = xml.Name{Space: iccRefNamespace, Local: "profileID"}
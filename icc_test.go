@@ -0,0 +1,116 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+// makeICCProfile returns a minimal ICC profile with a single description
+// tag.  If v4 is set, the description is stored in a 'mluc' tag, otherwise
+// a 'desc' tag is used.
+func makeICCProfile(desc string, v4 bool) []byte {
+	var tag []byte
+	if v4 {
+		u := utf16.Encode([]rune(desc))
+		tag = make([]byte, 28+2*len(u))
+		copy(tag, "mluc")
+		binary.BigEndian.PutUint32(tag[8:], 1)
+		binary.BigEndian.PutUint32(tag[12:], 12)
+		copy(tag[16:], "enUS")
+		binary.BigEndian.PutUint32(tag[20:], uint32(2*len(u)))
+		binary.BigEndian.PutUint32(tag[24:], 28)
+		for i, c := range u {
+			binary.BigEndian.PutUint16(tag[28+2*i:], c)
+		}
+	} else {
+		tag = make([]byte, 12+len(desc)+1)
+		copy(tag, "desc")
+		binary.BigEndian.PutUint32(tag[8:], uint32(len(desc)+1))
+		copy(tag[12:], desc)
+	}
+
+	data := make([]byte, 144+len(tag))
+	binary.BigEndian.PutUint32(data[0:], uint32(len(data)))
+	copy(data[36:], "acsp")
+	binary.BigEndian.PutUint32(data[128:], 1)
+	copy(data[132:], "desc")
+	binary.BigEndian.PutUint32(data[136:], 144)
+	binary.BigEndian.PutUint32(data[140:], uint32(len(tag)))
+	copy(data[144:], tag)
+	return data
+}
+
+func TestParseICCProfile(t *testing.T) {
+	for _, v4 := range []bool{false, true} {
+		data := makeICCProfile("sRGB IEC61966-2.1", v4)
+		info, err := ParseICCProfile(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Description != "sRGB IEC61966-2.1" {
+			t.Errorf("v4=%t: unexpected description %q", v4, info.Description)
+		}
+
+		// The profile ID must not depend on the rendering intent.
+		data[67] = 1
+		info2, err := ParseICCProfile(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info2.ProfileID != info.ProfileID {
+			t.Errorf("v4=%t: profile ID depends on rendering intent", v4)
+		}
+	}
+
+	_, err := ParseICCProfile([]byte("not a profile"))
+	if err == nil {
+		t.Error("invalid profile accepted")
+	}
+}
+
+func TestCheckICCProfile(t *testing.T) {
+	srgb := makeICCProfile("sRGB IEC61966-2.1", false)
+	other := makeICCProfile("sRGB IEC61966-2.1 (modified)", false)
+
+	p := NewPacket()
+	_, err := p.CheckICCProfile(srgb)
+	if err != ErrNotFound {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	err = p.SetICCProfile(srgb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := &Photoshop{}
+	p.Get(ps)
+	if ps.ICCProfile.V != "sRGB IEC61966-2.1" {
+		t.Errorf("unexpected ICCProfile %q", ps.ICCProfile.V)
+	}
+
+	ok, err := p.CheckICCProfile(srgb)
+	if err != nil || !ok {
+		t.Errorf("matching profile not recognised: %t %v", ok, err)
+	}
+	ok, err = p.CheckICCProfile(other)
+	if err != nil || ok {
+		t.Errorf("different profile not detected: %t %v", ok, err)
+	}
+}
@@ -0,0 +1,106 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// Identifier is an identifier together with the formal identification
+// scheme used for the identifier, for example an ISBN.  This is used for
+// the items of the xmp:Identifier array.
+//
+// The scheme is stored in the xmpidq:Scheme qualifier.
+type Identifier struct {
+	V string
+
+	// Scheme (optional) is the name of the identification scheme.
+	Scheme string
+
+	Q
+}
+
+// NewIdentifier creates a new Identifier value.
+func NewIdentifier(v, scheme string, qualifiers ...Qualifier) Identifier {
+	return Identifier{V: v, Scheme: scheme, Q: qualifiers}
+}
+
+// IsZero implements the [Value] interface.
+func (id Identifier) IsZero() bool {
+	return id.V == "" && id.Scheme == "" && len(id.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (id Identifier) EncodeXMP(p *Packet) Raw {
+	q := id.Q
+	if id.Scheme != "" {
+		p.registerDefaultPrefix(xmpidqNamespace)
+		q = q.WithScheme(id.Scheme)
+	}
+	return Text{V: id.V, Q: q}
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Identifier) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	scheme, q := v.Q.StripScheme()
+	return Identifier{V: v.V, Scheme: scheme, Q: q}, nil
+}
+
+// Scheme returns a qualifier which specifies the identification scheme of
+// a value.
+func Scheme(scheme string) Qualifier {
+	return Qualifier{
+		Name:  nameIDQScheme,
+		Value: Text{V: scheme},
+	}
+}
+
+// StripScheme returns the xmpidq:Scheme qualifier of a [Q] and a new [Q]
+// with the scheme qualifier removed.  If no scheme qualifier is present,
+// the empty string is returned.
+func (q Q) StripScheme() (string, Q) {
+	var scheme string
+	var stripped Q
+	for _, q := range q {
+		if q.Name == nameIDQScheme {
+			if v, ok := q.Value.(Text); ok && scheme == "" {
+				scheme = v.V
+			}
+		} else {
+			stripped = append(stripped, q)
+		}
+	}
+	return scheme, stripped
+}
+
+// WithScheme returns a new [Q] with the given xmpidq:Scheme qualifier.
+// Any pre-existing scheme qualifier is removed.
+func (q Q) WithScheme(scheme string) Q {
+	res := make(Q, 0, len(q)+1)
+	for _, q := range q {
+		if q.Name != nameIDQScheme {
+			res = append(res, q)
+		}
+	}
+	return append(res, Scheme(scheme))
+}
+
+const xmpidqNamespace = "http://ns.adobe.com/xmp/Identifier/qual/1.0/"
+
+var nameIDQScheme = xml.Name{Space: xmpidqNamespace, Local: "Scheme"}
@@ -0,0 +1,63 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIdentifier(t *testing.T) {
+	in := &Basic{}
+	in.Identifier.Append(NewIdentifier("978-3-16-148410-0", "ISBN"))
+	in.Identifier.Append(NewIdentifier("local-42", ""))
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `xmpidq:Scheme`) {
+		t.Errorf("scheme qualifier missing:\n%s", buf.String())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &Basic{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
+
+func TestWithScheme(t *testing.T) {
+	q := Q{Scheme("ISBN")}.WithScheme("DOI")
+	scheme, rest := q.StripScheme()
+	if scheme != "DOI" || len(rest) != 0 {
+		t.Errorf("got %q, %v", scheme, rest)
+	}
+}
@@ -0,0 +1,342 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+)
+
+// iimDataset is a dataset of an IPTC-IIM record.
+type iimDataset struct {
+	record  byte
+	dataset byte
+	data    []byte
+}
+
+// iimTag identifies an IIM dataset.
+type iimTag struct {
+	record  byte
+	dataset byte
+}
+
+// IIM datasets used for metadata reconciliation.
+var (
+	iimCodedCharset    = iimTag{1, 90}
+	iimObjectName      = iimTag{2, 5}
+	iimKeywords        = iimTag{2, 25}
+	iimDateCreated     = iimTag{2, 55}
+	iimTimeCreated     = iimTag{2, 60}
+	iimDigitalDate     = iimTag{2, 62}
+	iimDigitalTime     = iimTag{2, 63}
+	iimByline          = iimTag{2, 80}
+	iimCity            = iimTag{2, 90}
+	iimSublocation     = iimTag{2, 92}
+	iimProvinceState   = iimTag{2, 95}
+	iimCountryName     = iimTag{2, 101}
+	iimCopyrightNotice = iimTag{2, 116}
+	iimCaptionAbstract = iimTag{2, 120}
+)
+
+// iimMaxBytes gives the maximum length in bytes of text datasets, as
+// specified in the IPTC-IIM standard, version 4.2.
+var iimMaxBytes = map[iimTag]int{
+	iimObjectName:      64,
+	iimKeywords:        64,
+	iimDateCreated:     8,
+	iimTimeCreated:     11,
+	iimDigitalDate:     8,
+	iimDigitalTime:     11,
+	iimByline:          32,
+	iimCity:            32,
+	iimSublocation:     32,
+	iimProvinceState:   32,
+	iimCountryName:     64,
+	iimCopyrightNotice: 128,
+	iimCaptionAbstract: 2000,
+}
+
+// iimUTF8Charset is the value of dataset 1:90 which selects UTF-8 encoding.
+const iimUTF8Charset = "\x1b%G"
+
+// parseIIM splits IPTC-IIM data into datasets.
+func parseIIM(data []byte) ([]iimDataset, error) {
+	var res []iimDataset
+	for len(data) > 0 {
+		if data[0] == 0 { // padding
+			data = data[1:]
+			continue
+		}
+		if len(data) < 5 || data[0] != 0x1C {
+			return nil, errMalformedIIM
+		}
+		d := iimDataset{record: data[1], dataset: data[2]}
+		n := int(binary.BigEndian.Uint16(data[3:]))
+		data = data[5:]
+		if n&0x8000 != 0 { // extended dataset
+			k := n & 0x7FFF
+			if k > 4 || len(data) < k {
+				return nil, errMalformedIIM
+			}
+			n = 0
+			for _, c := range data[:k] {
+				n = n<<8 | int(c)
+			}
+			data = data[k:]
+		}
+		if n < 0 || n > len(data) {
+			return nil, errMalformedIIM
+		}
+		d.data = data[:n]
+		data = data[n:]
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+// encodeIIM is the inverse of parseIIM.
+func encodeIIM(datasets []iimDataset) []byte {
+	buf := &bytes.Buffer{}
+	for _, d := range datasets {
+		buf.Write([]byte{0x1C, d.record, d.dataset})
+		if len(d.data) < 0x8000 {
+			buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(d.data))))
+		} else {
+			buf.Write([]byte{0x80, 4})
+			buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(d.data))))
+		}
+		buf.Write(d.data)
+	}
+	return buf.Bytes()
+}
+
+// iimValues collects the dataset values of IIM data.  Text datasets are
+// decoded to UTF-8, using the character set from dataset 1:90; if this is
+// not set to UTF-8, text is interpreted as Latin-1.  Binary datasets are
+// returned unchanged.
+func iimValues(datasets []iimDataset) map[iimTag][]string {
+	isUTF8 := iimIsUTF8(datasets)
+	res := make(map[iimTag][]string)
+	for _, d := range datasets {
+		tag := iimTag{d.record, d.dataset}
+		res[tag] = append(res[tag], iimText(d, isUTF8))
+	}
+	return res
+}
+
+// setIIMValues returns a copy of the datasets, where the given datasets are
+// replaced by new values.  New values are truncated to the maximum length
+// of the dataset.  The result always uses UTF-8 encoding.
+//
+// Binary datasets, and text which is already valid UTF-8, are copied
+// byte-for-byte.  Since the character set is switched to UTF-8, text
+// datasets in other character sets are converted.
+func setIIMValues(datasets []iimDataset, values map[iimTag][]string) []iimDataset {
+	isUTF8 := iimIsUTF8(datasets)
+
+	// Record 1 (the envelope) is kept unchanged, apart from the character
+	// set, and must precede all other records.
+	var res []iimDataset
+	for _, d := range datasets {
+		if d.record == 1 && (iimTag{d.record, d.dataset}) != iimCodedCharset {
+			res = append(res, d)
+		}
+	}
+	res = append(res, iimDataset{
+		record:  iimCodedCharset.record,
+		dataset: iimCodedCharset.dataset,
+		data:    []byte(iimUTF8Charset),
+	})
+	for _, d := range datasets {
+		tag := iimTag{d.record, d.dataset}
+		if d.record == 1 {
+			continue
+		}
+		if _, replaced := values[tag]; replaced {
+			continue
+		}
+		if iimIsText(tag) && !isUTF8 && !isASCII(d.data) {
+			d.data = []byte(latin1ToUTF8(d.data))
+		}
+		res = append(res, d)
+	}
+	for _, tag := range iimTagOrder {
+		tr := &Truncation{MaxBytes: iimMaxBytes[tag]}
+		for _, v := range values[tag] {
+			v, _ = tr.String(v)
+			res = append(res, iimDataset{tag.record, tag.dataset, []byte(v)})
+		}
+	}
+	return res
+}
+
+// iimIsUTF8 reports whether dataset 1:90 selects UTF-8 encoding.
+func iimIsUTF8(datasets []iimDataset) bool {
+	isUTF8 := false
+	for _, d := range datasets {
+		if (iimTag{d.record, d.dataset}) == iimCodedCharset {
+			isUTF8 = string(d.data) == iimUTF8Charset
+		}
+	}
+	return isUTF8
+}
+
+// iimIsText reports whether the given dataset contains text in the coded
+// character set.  Only the application record (record 2) is considered,
+// and there the record version and the object preview datasets are binary.
+func iimIsText(tag iimTag) bool {
+	if tag.record != 2 {
+		return false
+	}
+	switch tag.dataset {
+	case 0, 200, 201, 202:
+		return false
+	}
+	return true
+}
+
+// iimText returns the value of a dataset as a string.
+func iimText(d iimDataset, isUTF8 bool) string {
+	if isUTF8 || isASCII(d.data) || !iimIsText(iimTag{d.record, d.dataset}) {
+		return string(d.data)
+	}
+	return latin1ToUTF8(d.data)
+}
+
+// iimTagOrder lists the datasets written by setIIMValues, in the order
+// they are written.
+var iimTagOrder = []iimTag{
+	iimObjectName,
+	iimKeywords,
+	iimDateCreated,
+	iimTimeCreated,
+	iimDigitalDate,
+	iimDigitalTime,
+	iimByline,
+	iimCity,
+	iimSublocation,
+	iimProvinceState,
+	iimCountryName,
+	iimCopyrightNotice,
+	iimCaptionAbstract,
+}
+
+func isASCII(data []byte) bool {
+	for _, c := range data {
+		if c >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func latin1ToUTF8(data []byte) string {
+	buf := make([]rune, len(data))
+	for i, c := range data {
+		buf[i] = rune(c)
+	}
+	return string(buf)
+}
+
+// irbResource is a Photoshop image resource, as stored in the APP13
+// segment of a JPEG file.
+type irbResource struct {
+	id   uint16
+	name []byte // Pascal string, without padding
+	data []byte
+}
+
+// Photoshop image resources used for metadata reconciliation.
+const (
+	irbIPTC       = 0x0404
+	irbIPTCDigest = 0x0425
+)
+
+// parseIRB splits Photoshop image resource data into resources.
+func parseIRB(data []byte) ([]irbResource, error) {
+	var res []irbResource
+	for len(data) > 0 {
+		if len(data) < 7 || string(data[:4]) != "8BIM" {
+			return nil, errMalformedIIM
+		}
+		r := irbResource{id: binary.BigEndian.Uint16(data[4:])}
+		nameLen := int(data[6])
+		pos := 7 + nameLen
+		if pos%2 != 0 {
+			pos++
+		}
+		if pos+4 > len(data) {
+			return nil, errMalformedIIM
+		}
+		r.name = data[7 : 7+nameLen]
+		n := int(binary.BigEndian.Uint32(data[pos:]))
+		pos += 4
+		if n < 0 || pos+n > len(data) {
+			return nil, errMalformedIIM
+		}
+		r.data = data[pos : pos+n]
+		pos += n
+		if pos%2 != 0 && pos < len(data) {
+			pos++
+		}
+		data = data[pos:]
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// encodeIRB is the inverse of parseIRB.
+func encodeIRB(resources []irbResource) []byte {
+	buf := &bytes.Buffer{}
+	for _, r := range resources {
+		buf.WriteString("8BIM")
+		buf.Write(binary.BigEndian.AppendUint16(nil, r.id))
+		buf.WriteByte(byte(len(r.name)))
+		buf.Write(r.name)
+		if len(r.name)%2 == 0 {
+			buf.WriteByte(0)
+		}
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(r.data))))
+		buf.Write(r.data)
+		if len(r.data)%2 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	return buf.Bytes()
+}
+
+// setResource replaces or appends the resource with the given ID.
+func setResource(resources []irbResource, id uint16, data []byte) []irbResource {
+	for i := range resources {
+		if resources[i].id == id {
+			resources[i].data = data
+			return resources
+		}
+	}
+	return append(resources, irbResource{id: id, data: data})
+}
+
+// iimDigest computes the value of the IPTC digest resource for the given
+// IIM data.
+func iimDigest(iim []byte) []byte {
+	sum := md5.Sum(iim)
+	return sum[:]
+}
+
+var errMalformedIIM = errors.New("malformed IPTC-IIM data")
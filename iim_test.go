@@ -0,0 +1,164 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestIIMValues(t *testing.T) {
+	long := string(bytes.Repeat([]byte("x"), 0x9000))
+	datasets := []iimDataset{
+		{1, 0, []byte{0, 4}},
+		{2, 25, []byte("caf\xe9")},
+		{2, 25, []byte("plain")},
+		{2, 120, []byte(long)},
+		{2, 202, []byte{0xff, 0xd8, 0x80}},
+		{8, 10, []byte{0xe9, 0x00}},
+	}
+	raw := encodeIIM(datasets)
+	parsed, err := parseIIM(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(datasets, parsed, cmp.AllowUnexported(iimDataset{})); d != "" {
+		t.Errorf("round trip (-want +got):\n%s", d)
+	}
+
+	got := iimValues(parsed)
+	want := map[iimTag][]string{
+		{1, 0}:             {"\x00\x04"},
+		iimKeywords:        {"café", "plain"},
+		iimCaptionAbstract: {long},
+		{2, 202}:           {"\xff\xd8\x80"},
+		{8, 10}:            {"\xe9\x00"},
+	}
+	if d := cmp.Diff(want, got, cmp.AllowUnexported(iimTag{})); d != "" {
+		t.Errorf("values (-want +got):\n%s", d)
+	}
+
+	newSets := setIIMValues(parsed, map[iimTag][]string{
+		iimCaptionAbstract: {"größer"},
+	})
+	for _, d := range newSets {
+		tag := iimTag{d.record, d.dataset}
+		if tag == (iimTag{2, 202}) && !bytes.Equal(d.data, []byte{0xff, 0xd8, 0x80}) ||
+			tag == (iimTag{8, 10}) && !bytes.Equal(d.data, []byte{0xe9, 0x00}) {
+			t.Errorf("binary dataset %d:%d changed to %x", d.record, d.dataset, d.data)
+		}
+	}
+	updated := iimValues(newSets)
+	want = map[iimTag][]string{
+		{1, 0}:             {"\x00\x04"},
+		iimCodedCharset:    {iimUTF8Charset},
+		iimKeywords:        {"café", "plain"},
+		iimCaptionAbstract: {"größer"},
+		{2, 202}:           {"\xff\xd8\x80"},
+		{8, 10}:            {"\xe9\x00"},
+	}
+	if d := cmp.Diff(want, updated, cmp.AllowUnexported(iimTag{})); d != "" {
+		t.Errorf("updated values (-want +got):\n%s", d)
+	}
+}
+
+func TestIRBRoundTrip(t *testing.T) {
+	resources := []irbResource{
+		{id: 0x03ED, name: []byte{}, data: []byte{1, 2, 3}},
+		{id: 0x0404, name: []byte("a"), data: []byte{4, 5}},
+		{id: 0x0425, name: []byte("ab"), data: []byte{}},
+	}
+	parsed, err := parseIRB(encodeIRB(resources))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(resources, parsed, cmp.AllowUnexported(irbResource{})); d != "" {
+		t.Errorf("round trip (-want +got):\n%s", d)
+	}
+}
+
+// TestIIMLimits checks that new values are truncated to the maximum length
+// of the dataset, without splitting UTF-8 sequences.
+func TestIIMLimits(t *testing.T) {
+	byline := strings.Repeat("ä", 20) // 40 bytes
+	caption := strings.Repeat("x", 2500)
+	datasets := setIIMValues(nil, map[iimTag][]string{
+		iimByline:          {byline, "Bob"},
+		iimCity:            {"Llanfairpwllgwyngyllgogerychwyrndrobwllllantysiliogogogoch"},
+		iimKeywords:        {strings.Repeat("k", 65)},
+		iimCaptionAbstract: {caption},
+	})
+	got := iimValues(datasets)
+	want := map[iimTag][]string{
+		iimCodedCharset:    {iimUTF8Charset},
+		iimKeywords:        {strings.Repeat("k", 64)},
+		iimByline:          {strings.Repeat("ä", 16), "Bob"},
+		iimCity:            {"Llanfairpwllgwyngyllgogerychwyrn"},
+		iimCaptionAbstract: {caption[:2000]},
+	}
+	if d := cmp.Diff(want, got, cmp.AllowUnexported(iimTag{})); d != "" {
+		t.Errorf("values (-want +got):\n%s", d)
+	}
+}
+
+func FuzzParseIIM(f *testing.F) {
+	f.Add(encodeIIM([]iimDataset{
+		{record: 1, dataset: 90, data: []byte(iimUTF8Charset)},
+		{record: 2, dataset: 25, data: []byte("sea")},
+	}))
+	f.Add([]byte{0x1C, 2, 120, 0x80, 2, 0, 3, 'a', 'b', 'c', 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		datasets, err := parseIIM(data)
+		if err != nil {
+			return
+		}
+		datasets2, err := parseIIM(encodeIIM(datasets))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(datasets, datasets2, cmp.AllowUnexported(iimDataset{}), cmpopts.EquateEmpty()); d != "" {
+			t.Errorf("round trip (-want +got):\n%s", d)
+		}
+		iimValues(datasets)
+	})
+}
+
+func FuzzParseIRB(f *testing.F) {
+	f.Add(encodeIRB([]irbResource{
+		{id: irbIPTC, name: []byte{}, data: []byte{1, 2, 3}},
+		{id: irbIPTCDigest, name: []byte("ab"), data: []byte{}},
+	}))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		resources, err := parseIRB(data)
+		if err != nil {
+			return
+		}
+		resources2, err := parseIRB(encodeIRB(resources))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(resources, resources2, cmp.AllowUnexported(irbResource{}), cmpopts.EquateEmpty()); d != "" {
+			t.Errorf("round trip (-want +got):\n%s", d)
+		}
+	})
+}
@@ -0,0 +1,562 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// LocationDetails describes a location, for example the location where an
+// image was created or the location shown in an image.
+//
+// This is the Location structure from the IPTC Photo Metadata Standard,
+// used by the Iptc4xmpExt:LocationCreated and Iptc4xmpExt:LocationShown
+// properties.
+type LocationDetails struct {
+	// LocationID is a list of globally unique identifiers (URIs) of the
+	// location.
+	LocationID UnorderedArray[Text]
+
+	// LocationName is the full name of the location.
+	LocationName Localized
+
+	// Sublocation is the name of a sublocation, e.g. a building or a
+	// landmark.
+	Sublocation Text
+
+	// City is the name of the city.
+	City Text
+
+	// ProvinceState is the name of a sub-region of the country, e.g. a
+	// province or a state.
+	ProvinceState Text
+
+	// CountryName is the full name of the country.
+	CountryName Text
+
+	// CountryCode is the ISO 3166 code of the country.
+	CountryCode Text
+
+	// WorldRegion is the name of a world region, e.g. a continent.
+	WorldRegion Text
+
+	// GPSLatitude is the latitude of the location.
+	GPSLatitude GPSCoordinate
+
+	// GPSLongitude is the longitude of the location.
+	GPSLongitude GPSCoordinate
+
+	// GPSAltitude is the altitude of the location in meters.
+	GPSAltitude Text
+
+	// GPSAltitudeRef indicates whether GPSAltitude is above (0) or below
+	// (1) sea level.
+	GPSAltitudeRef Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (l LocationDetails) IsZero() bool {
+	return l.LocationID.IsZero() && l.LocationName.IsZero() &&
+		l.Sublocation.IsZero() && l.City.IsZero() &&
+		l.ProvinceState.IsZero() && l.CountryName.IsZero() &&
+		l.CountryCode.IsZero() && l.WorldRegion.IsZero() &&
+		l.GPSLatitude.IsZero() && l.GPSLongitude.IsZero() &&
+		l.GPSAltitude.IsZero() && l.GPSAltitudeRef.IsZero() &&
+		len(l.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (l LocationDetails) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     l.Q,
+	}
+	setField(res, p, iptcExtNamespace, "LocationId", l.LocationID)
+	setField(res, p, iptcExtNamespace, "LocationName", l.LocationName)
+	setField(res, p, iptcExtNamespace, "Sublocation", l.Sublocation)
+	setField(res, p, iptcExtNamespace, "City", l.City)
+	setField(res, p, iptcExtNamespace, "ProvinceState", l.ProvinceState)
+	setField(res, p, iptcExtNamespace, "CountryName", l.CountryName)
+	setField(res, p, iptcExtNamespace, "CountryCode", l.CountryCode)
+	setField(res, p, iptcExtNamespace, "WorldRegion", l.WorldRegion)
+	setField(res, p, exifNamespace, "GPSLatitude", l.GPSLatitude)
+	setField(res, p, exifNamespace, "GPSLongitude", l.GPSLongitude)
+	setField(res, p, exifNamespace, "GPSAltitude", l.GPSAltitude)
+	setField(res, p, exifNamespace, "GPSAltitudeRef", l.GPSAltitudeRef)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (LocationDetails) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return LocationDetails{
+		LocationID:     getField[UnorderedArray[Text]](s, iptcExtNamespace, "LocationId"),
+		LocationName:   getField[Localized](s, iptcExtNamespace, "LocationName"),
+		Sublocation:    getField[Text](s, iptcExtNamespace, "Sublocation"),
+		City:           getField[Text](s, iptcExtNamespace, "City"),
+		ProvinceState:  getField[Text](s, iptcExtNamespace, "ProvinceState"),
+		CountryName:    getField[Text](s, iptcExtNamespace, "CountryName"),
+		CountryCode:    getField[Text](s, iptcExtNamespace, "CountryCode"),
+		WorldRegion:    getField[Text](s, iptcExtNamespace, "WorldRegion"),
+		GPSLatitude:    getField[GPSCoordinate](s, exifNamespace, "GPSLatitude"),
+		GPSLongitude:   getField[GPSCoordinate](s, exifNamespace, "GPSLongitude"),
+		GPSAltitude:    getField[Text](s, exifNamespace, "GPSAltitude"),
+		GPSAltitudeRef: getField[Text](s, exifNamespace, "GPSAltitudeRef"),
+		Q:              s.Q,
+	}, nil
+}
+
+// PersonDetails describes a person, for example a person shown in an image.
+//
+// This is the Person structure from the IPTC Photo Metadata Standard, used
+// by the Iptc4xmpExt:PersonInImageWDetails property.
+type PersonDetails struct {
+	// Identifiers is a list of globally unique identifiers (URIs) of the
+	// person, e.g. from a knowledge base.
+	Identifiers UnorderedArray[Text]
+
+	// Name is the name of the person.
+	Name Localized
+
+	// Description is a free-text description of the person.
+	Description Localized
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (d PersonDetails) IsZero() bool {
+	return d.Identifiers.IsZero() && d.Name.IsZero() &&
+		d.Description.IsZero() && len(d.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (d PersonDetails) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     d.Q,
+	}
+	setField(res, p, iptcExtNamespace, "PersonId", d.Identifiers)
+	setField(res, p, iptcExtNamespace, "PersonName", d.Name)
+	setField(res, p, iptcExtNamespace, "PersonDescription", d.Description)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (PersonDetails) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return PersonDetails{
+		Identifiers: getField[UnorderedArray[Text]](s, iptcExtNamespace, "PersonId"),
+		Name:        getField[Localized](s, iptcExtNamespace, "PersonName"),
+		Description: getField[Localized](s, iptcExtNamespace, "PersonDescription"),
+		Q:           s.Q,
+	}, nil
+}
+
+// OrganisationDetails describes a person or organisation together with the
+// roles they had in relation to a resource.
+//
+// This is the "Entity with Role" structure from the IPTC Photo Metadata
+// Standard, used for example by the Iptc4xmpExt:Contributor property.
+// It is also used to identify the parties in the PLUS [Licensor] and
+// [CopyrightOwner] structures.
+type OrganisationDetails struct {
+	// Identifiers is a list of globally unique identifiers (URIs) of the
+	// entity.
+	Identifiers UnorderedArray[Text]
+
+	// Name is the name of the entity.
+	Name Localized
+
+	// Role is a list of roles of the entity, given as URIs from a
+	// controlled vocabulary.
+	Role UnorderedArray[Text]
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (d OrganisationDetails) IsZero() bool {
+	return d.Identifiers.IsZero() && d.Name.IsZero() &&
+		d.Role.IsZero() && len(d.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (d OrganisationDetails) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     d.Q,
+	}
+	setField(res, p, iptcExtNamespace, "Identifier", d.Identifiers)
+	setField(res, p, iptcExtNamespace, "Name", d.Name)
+	setField(res, p, iptcExtNamespace, "Role", d.Role)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (OrganisationDetails) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return OrganisationDetails{
+		Identifiers: getField[UnorderedArray[Text]](s, iptcExtNamespace, "Identifier"),
+		Name:        getField[Localized](s, iptcExtNamespace, "Name"),
+		Role:        getField[UnorderedArray[Text]](s, iptcExtNamespace, "Role"),
+		Q:           s.Q,
+	}, nil
+}
+
+// IPTCCore represents the IPTC Core namespace.
+//
+// See the IPTC Photo Metadata Standard, https://iptc.org/std/photometadata/ .
+// Some IPTC Core properties are stored in the Dublin Core, Photoshop and
+// XMP Rights Management namespaces, and are not part of this model.
+type IPTCCore struct {
+	_ Namespace `xmp:"http://iptc.org/std/Iptc4xmpCore/1.0/xmlns/"`
+	_ Prefix    `xmp:"Iptc4xmpCore"`
+
+	// AltTextAccessibility is a brief textual description of the image,
+	// for use by assistive technologies.
+	AltTextAccessibility Localized
+
+	// CountryCode is the ISO 3166 code of the country shown in the image.
+	// This property is deprecated in favour of Iptc4xmpExt:LocationShown.
+	CountryCode Text
+
+	// CreatorContactInfo gives the contact information of the creator
+	// listed in dc:creator.
+	CreatorContactInfo ContactInfo
+
+	// ExtDescrAccessibility is a detailed textual description of the image,
+	// for use by assistive technologies.
+	ExtDescrAccessibility Localized
+
+	// IntellectualGenre describes the nature, intellectual or journalistic
+	// characteristic of the image.
+	IntellectualGenre Text
+
+	// Location is the name of the location shown in the image.
+	// This property is deprecated in favour of Iptc4xmpExt:LocationShown.
+	Location Text
+
+	// Scene lists IPTC Scene NewsCodes describing the scene.
+	// See [SceneCodes].
+	Scene UnorderedArray[Text]
+
+	// SubjectCode lists IPTC Subject NewsCodes describing the subject.
+	// See [IsSubjectCode].
+	SubjectCode UnorderedArray[Text]
+}
+
+// ContactInfo holds contact information for a person or organisation.
+//
+// This is the CreatorContactInfo structure from the IPTC Photo Metadata
+// Standard.
+type ContactInfo struct {
+	// Address is the street address.  This can contain several lines.
+	Address Text
+
+	// City is the name of the city.
+	City Text
+
+	// Region is the name of the state or province.
+	Region Text
+
+	// PostalCode is the postal code.
+	PostalCode Text
+
+	// Country is the name of the country.
+	Country Text
+
+	// Email lists one or more email addresses, separated by commas.
+	Email Text
+
+	// Phone lists one or more phone numbers, separated by commas.
+	Phone Text
+
+	// URL lists one or more web addresses, separated by commas.
+	URL Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (c ContactInfo) IsZero() bool {
+	return c.Address.IsZero() && c.City.IsZero() && c.Region.IsZero() &&
+		c.PostalCode.IsZero() && c.Country.IsZero() && c.Email.IsZero() &&
+		c.Phone.IsZero() && c.URL.IsZero() && len(c.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (c ContactInfo) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     c.Q,
+	}
+	setField(res, p, iptcCoreNamespace, "CiAdrExtadr", c.Address)
+	setField(res, p, iptcCoreNamespace, "CiAdrCity", c.City)
+	setField(res, p, iptcCoreNamespace, "CiAdrRegion", c.Region)
+	setField(res, p, iptcCoreNamespace, "CiAdrPcode", c.PostalCode)
+	setField(res, p, iptcCoreNamespace, "CiAdrCtry", c.Country)
+	setField(res, p, iptcCoreNamespace, "CiEmailWork", c.Email)
+	setField(res, p, iptcCoreNamespace, "CiTelWork", c.Phone)
+	setField(res, p, iptcCoreNamespace, "CiUrlWork", c.URL)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (ContactInfo) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return ContactInfo{
+		Address:    getField[Text](s, iptcCoreNamespace, "CiAdrExtadr"),
+		City:       getField[Text](s, iptcCoreNamespace, "CiAdrCity"),
+		Region:     getField[Text](s, iptcCoreNamespace, "CiAdrRegion"),
+		PostalCode: getField[Text](s, iptcCoreNamespace, "CiAdrPcode"),
+		Country:    getField[Text](s, iptcCoreNamespace, "CiAdrCtry"),
+		Email:      getField[Text](s, iptcCoreNamespace, "CiEmailWork"),
+		Phone:      getField[Text](s, iptcCoreNamespace, "CiTelWork"),
+		URL:        getField[Text](s, iptcCoreNamespace, "CiUrlWork"),
+		Q:          s.Q,
+	}, nil
+}
+
+// IPTCExt represents the IPTC Extension namespace.
+//
+// See the IPTC Photo Metadata Standard, https://iptc.org/std/photometadata/ .
+type IPTCExt struct {
+	_ Namespace `xmp:"http://iptc.org/std/Iptc4xmpExt/2008-02-29/"`
+	_ Prefix    `xmp:"Iptc4xmpExt"`
+
+	// AddlModelInfo gives information about the ethnicity and other facets
+	// of the models in a model-released image.
+	AddlModelInfo Text
+
+	// ArtworkOrObject describes artworks or objects shown in the image.
+	ArtworkOrObject UnorderedArray[ArtworkDetails]
+
+	// Contributor lists parties which contributed to the image, other than
+	// the creator, together with their roles.
+	Contributor UnorderedArray[OrganisationDetails]
+
+	// DigImageGUID is a globally unique identifier for the image, issued
+	// by the creator of the digital image.
+	DigImageGUID Text
+
+	// DigitalSourceType is a URI from the IPTC Digital Source Type
+	// NewsCodes, describing the source from which the image was created.
+	DigitalSourceType Text
+
+	// Event names the event shown in the image.
+	Event Localized
+
+	// LocationCreated gives the location where the image was created.
+	LocationCreated UnorderedArray[LocationDetails]
+
+	// LocationShown lists the locations shown in the image.
+	LocationShown UnorderedArray[LocationDetails]
+
+	// OrganisationInImageCode lists codes of the organisations featured in
+	// the image.
+	OrganisationInImageCode UnorderedArray[Text]
+
+	// OrganisationInImageName lists the names of the organisations
+	// featured in the image.
+	OrganisationInImageName UnorderedArray[Text]
+
+	// PersonInImage lists the names of the persons shown in the image.
+	PersonInImage UnorderedArray[Text]
+
+	// PersonInImageWDetails gives details about the persons shown in the
+	// image.
+	PersonInImageWDetails UnorderedArray[PersonDetails]
+
+	// RegistryID lists identifiers of the image in registries.
+	RegistryID UnorderedArray[RegistryEntry] `xmp:"RegistryId"`
+}
+
+// ArtworkDetails describes an artwork or object shown in an image.
+//
+// This is the "Artwork or Object" structure from the IPTC Photo Metadata
+// Standard, used by the Iptc4xmpExt:ArtworkOrObject property.
+type ArtworkDetails struct {
+	// Title is the title of the artwork or object.
+	Title Localized
+
+	// ContentDescription describes the content of the artwork or object.
+	ContentDescription Localized
+
+	// ContributionDescription describes the contribution of the persons
+	// listed in Creator.
+	ContributionDescription Localized
+
+	// Creator lists the creators of the artwork or object.
+	Creator OrderedArray[ProperName]
+
+	// CreatorID lists globally unique identifiers of the creators, in the
+	// same order as Creator.
+	CreatorID OrderedArray[Text]
+
+	// DateCreated is the date when the artwork or object was created.
+	DateCreated Date
+
+	// CircaDateCreated is an approximate creation date, e.g. "19th century".
+	CircaDateCreated Text
+
+	// PhysicalDescription describes the physical characteristics of the
+	// artwork or object.
+	PhysicalDescription Localized
+
+	// Source is the name of the organisation or body holding the artwork
+	// or object.
+	Source Text
+
+	// SourceInventoryNumber is the inventory number issued by Source.
+	SourceInventoryNumber Text
+
+	// SourceInventoryURL is a reference to the online inventory entry.
+	SourceInventoryURL Text
+
+	// StylePeriod lists style, historical or artistic periods, movements,
+	// groups or schools.
+	StylePeriod UnorderedArray[Text]
+
+	// CopyrightNotice is the copyright notice for the artwork or object.
+	CopyrightNotice Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (a ArtworkDetails) IsZero() bool {
+	return a.Title.IsZero() && a.ContentDescription.IsZero() &&
+		a.ContributionDescription.IsZero() && a.Creator.IsZero() &&
+		a.CreatorID.IsZero() && a.DateCreated.IsZero() &&
+		a.CircaDateCreated.IsZero() && a.PhysicalDescription.IsZero() &&
+		a.Source.IsZero() && a.SourceInventoryNumber.IsZero() &&
+		a.SourceInventoryURL.IsZero() && a.StylePeriod.IsZero() &&
+		a.CopyrightNotice.IsZero() && len(a.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (a ArtworkDetails) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     a.Q,
+	}
+	setField(res, p, iptcExtNamespace, "AOTitle", a.Title)
+	setField(res, p, iptcExtNamespace, "AOContentDescription", a.ContentDescription)
+	setField(res, p, iptcExtNamespace, "AOContributionDescription", a.ContributionDescription)
+	setField(res, p, iptcExtNamespace, "AOCreator", a.Creator)
+	setField(res, p, iptcExtNamespace, "AOCreatorId", a.CreatorID)
+	setField(res, p, iptcExtNamespace, "AODateCreated", a.DateCreated)
+	setField(res, p, iptcExtNamespace, "AOCircaDateCreated", a.CircaDateCreated)
+	setField(res, p, iptcExtNamespace, "AOPhysicalDescription", a.PhysicalDescription)
+	setField(res, p, iptcExtNamespace, "AOSource", a.Source)
+	setField(res, p, iptcExtNamespace, "AOSourceInvNo", a.SourceInventoryNumber)
+	setField(res, p, iptcExtNamespace, "AOSourceInvURL", a.SourceInventoryURL)
+	setField(res, p, iptcExtNamespace, "AOStylePeriod", a.StylePeriod)
+	setField(res, p, iptcExtNamespace, "AOCopyrightNotice", a.CopyrightNotice)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (ArtworkDetails) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return ArtworkDetails{
+		Title:                   getField[Localized](s, iptcExtNamespace, "AOTitle"),
+		ContentDescription:      getField[Localized](s, iptcExtNamespace, "AOContentDescription"),
+		ContributionDescription: getField[Localized](s, iptcExtNamespace, "AOContributionDescription"),
+		Creator:                 getField[OrderedArray[ProperName]](s, iptcExtNamespace, "AOCreator"),
+		CreatorID:               getField[OrderedArray[Text]](s, iptcExtNamespace, "AOCreatorId"),
+		DateCreated:             getField[Date](s, iptcExtNamespace, "AODateCreated"),
+		CircaDateCreated:        getField[Text](s, iptcExtNamespace, "AOCircaDateCreated"),
+		PhysicalDescription:     getField[Localized](s, iptcExtNamespace, "AOPhysicalDescription"),
+		Source:                  getField[Text](s, iptcExtNamespace, "AOSource"),
+		SourceInventoryNumber:   getField[Text](s, iptcExtNamespace, "AOSourceInvNo"),
+		SourceInventoryURL:      getField[Text](s, iptcExtNamespace, "AOSourceInvURL"),
+		StylePeriod:             getField[UnorderedArray[Text]](s, iptcExtNamespace, "AOStylePeriod"),
+		CopyrightNotice:         getField[Text](s, iptcExtNamespace, "AOCopyrightNotice"),
+		Q:                       s.Q,
+	}, nil
+}
+
+// RegistryEntry identifies an image in a registry.
+//
+// This is the "Registry Entry" structure from the IPTC Photo Metadata
+// Standard, used by the Iptc4xmpExt:RegistryId property.
+type RegistryEntry struct {
+	// ItemID is the unique identifier of the image within the registry.
+	ItemID Text
+
+	// OrganisationID identifies the registry.
+	OrganisationID Text
+
+	// Role is a URI describing the role of the registry entry.
+	Role Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (r RegistryEntry) IsZero() bool {
+	return r.ItemID.IsZero() && r.OrganisationID.IsZero() &&
+		r.Role.IsZero() && len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r RegistryEntry) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     r.Q,
+	}
+	setField(res, p, iptcExtNamespace, "RegItemId", r.ItemID)
+	setField(res, p, iptcExtNamespace, "RegOrgId", r.OrganisationID)
+	setField(res, p, iptcExtNamespace, "RegEntryRole", r.Role)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (RegistryEntry) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return RegistryEntry{
+		ItemID:         getField[Text](s, iptcExtNamespace, "RegItemId"),
+		OrganisationID: getField[Text](s, iptcExtNamespace, "RegOrgId"),
+		Role:           getField[Text](s, iptcExtNamespace, "RegEntryRole"),
+		Q:              s.Q,
+	}, nil
+}
+
+const (
+	iptcCoreNamespace = "http://iptc.org/std/Iptc4xmpCore/1.0/xmlns/"
+	iptcExtNamespace  = "http://iptc.org/std/Iptc4xmpExt/2008-02-29/"
+)
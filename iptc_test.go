@@ -0,0 +1,181 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/text/language"
+)
+
+func TestLocationDetails(t *testing.T) {
+	loc := LocationDetails{
+		City:         NewText("Leeds"),
+		CountryName:  NewText("United Kingdom"),
+		CountryCode:  NewText("GBR"),
+		GPSLatitude:  NewLatitude(53 + 47.9/60),
+		GPSLongitude: NewLongitude(-(1 + 32.9/60)),
+	}
+	loc.LocationName.Set(language.English, "Leeds Dock")
+	loc.LocationID.Append(NewText("http://sws.geonames.org/2644688/"))
+
+	p1 := NewPacket()
+	p1.SetValue(iptcExtNamespace, "LocationShown", UnorderedArray[LocationDetails]{
+		V: []LocationDetails{loc},
+	})
+
+	buf := &bytes.Buffer{}
+	err := p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := PacketGetValue[UnorderedArray[LocationDetails]](p2, iptcExtNamespace, "LocationShown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.V) != 1 {
+		t.Fatalf("expected 1 location, got %d", len(got.V))
+	}
+	if d := cmp.Diff(loc, got.V[0]); d != "" {
+		t.Errorf("locations differ (-want +got):\n%s", d)
+	}
+}
+
+func TestEntityDetails(t *testing.T) {
+	person := PersonDetails{}
+	person.Identifiers.Append(NewText("https://www.wikidata.org/wiki/Q1035"))
+	person.Name.Set(language.English, "Charles Darwin")
+	person.Description.Set(language.English, "naturalist")
+
+	org := OrganisationDetails{}
+	org.Name.Default = NewText("Example Agency")
+	org.Role.Append(NewText("http://cv.iptc.org/newscodes/contentprodpartyrole/editor"))
+
+	values := []Value{person, org}
+	p := NewPacket()
+	for _, v := range values {
+		enc := v.EncodeXMP(p)
+		dec, err := v.DecodeAnother(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(v, dec, cmpopts.EquateEmpty()); d != "" {
+			t.Errorf("round trip failed (-want +got):\n%s", d)
+		}
+	}
+}
+
+func TestIPTCCore(t *testing.T) {
+	core := &IPTCCore{
+		CountryCode: NewText("GBR"),
+		CreatorContactInfo: ContactInfo{
+			City:  NewText("Leeds"),
+			Email: NewText("jane@example.com"),
+			URL:   NewText("https://example.com/"),
+		},
+	}
+	core.AltTextAccessibility.Set(language.English, "Boats in a harbour")
+	core.Scene.Append(NewText("011200"))
+
+	p1 := NewPacket()
+	err := p1.Set(core)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := &IPTCCore{}
+	p2.Get(got)
+	if d := cmp.Diff(core, got, cmpopts.EquateEmpty()); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+	if diag := p2.CheckNewsCodes(); len(diag) != 0 {
+		t.Errorf("unexpected diagnostics %v", diag)
+	}
+}
+
+func TestIPTCExt(t *testing.T) {
+	loc := LocationDetails{
+		City:        NewText("Paris"),
+		CountryCode: NewText("FRA"),
+	}
+	loc.LocationName.Set(language.French, "Musée du Louvre")
+
+	artwork := ArtworkDetails{
+		DateCreated:           Date{V: time.Date(1503, 1, 1, 0, 0, 0, 0, time.UTC), NumOmitted: 2},
+		Source:                NewText("Musée du Louvre"),
+		SourceInventoryNumber: NewText("INV 779"),
+	}
+	artwork.Title.Set(language.English, "Mona Lisa")
+	artwork.Creator.Append(ProperName{V: "Leonardo da Vinci"})
+	artwork.StylePeriod.Append(NewText("High Renaissance"))
+
+	person := PersonDetails{}
+	person.Name.Set(language.English, "Lisa Gherardini")
+
+	ext := &IPTCExt{
+		DigitalSourceType: NewText("http://cv.iptc.org/newscodes/digitalsourcetype/digitalCapture"),
+	}
+	ext.Event.Set(language.English, "Museum visit")
+	ext.LocationCreated.Append(loc)
+	ext.LocationShown.Append(loc)
+	ext.ArtworkOrObject.Append(artwork)
+	ext.PersonInImage.Append(NewText("Lisa Gherardini"))
+	ext.PersonInImageWDetails.Append(person)
+	ext.RegistryID.Append(RegistryEntry{
+		ItemID:         NewText("12345"),
+		OrganisationID: NewText("https://registry.example.com/"),
+	})
+
+	p1 := NewPacket()
+	err := p1.Set(ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := &IPTCExt{}
+	p2.Get(got)
+	if d := cmp.Diff(ext, got, cmpopts.EquateEmpty()); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
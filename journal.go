@@ -0,0 +1,138 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Journal records the changes made to a packet through [Packet.SetValue]
+// and [Packet.ClearValue].  To enable recording, set the Journal field of
+// the packet to a new, empty Journal.
+//
+// Changes made by assigning to Packet.Properties directly are not
+// recorded.
+type Journal struct {
+	Entries []JournalEntry
+}
+
+// JournalEntry describes one recorded change.
+type JournalEntry struct {
+	Change
+
+	// Time is the time of the change, as given by the clock of the packet.
+	Time time.Time
+}
+
+// record adds a change to the journal, if the packet has one.
+// Changes which leave the property unchanged are ignored.
+func (p *Packet) record(name xml.Name, new Raw) {
+	if p.Journal == nil {
+		return
+	}
+	old := p.Properties[name]
+	if old == nil && new == nil || old != nil && new != nil && EqualRaw(old, new) {
+		return
+	}
+	p.Journal.Entries = append(p.Journal.Entries, JournalEntry{
+		Change: Change{Name: name, Old: old, New: new},
+		Time:   p.now(),
+	})
+}
+
+// AddHistory appends an event to the xmpMM:History property of the packet,
+// which summarises the changes recorded in the packet's journal.  The event
+// has action "edited", and its parameters list the names of the changed
+// properties.  Afterwards, the journal is cleared.
+//
+// If the journal is nil or empty, the packet is not modified.
+func (p *Packet) AddHistory(softwareAgent string) {
+	j := p.Journal
+	if j == nil || len(j.Entries) == 0 {
+		return
+	}
+
+	seen := make(map[xml.Name]bool)
+	var names []string
+	for _, e := range j.Entries {
+		if !seen[e.Name] {
+			seen[e.Name] = true
+			names = append(names, formatName(e.Name))
+		}
+	}
+	sort.Strings(names)
+
+	ev := ResourceEvent{
+		Action:     NewText("edited"),
+		Changed:    NewText("/metadata"),
+		Parameters: NewText("changed " + strings.Join(names, ", ")),
+		When:       NewDate(j.Entries[len(j.Entries)-1].Time),
+	}
+	if softwareAgent != "" {
+		ev.SoftwareAgent = NewAgentName(softwareAgent)
+	}
+
+	historyName := xml.Name{Space: mmNamespace, Local: "History"}
+	history, ok := p.Properties[historyName].(RawArray)
+	if !ok {
+		history = RawArray{Kind: Ordered}
+	}
+	history.Value = append(history.Value[:len(history.Value):len(history.Value)], ev.EncodeXMP(p))
+	p.Properties[historyName] = history
+
+	j.Entries = nil
+}
+
+// WriteLog writes the journal to w as an audit log, in JSON Lines format.
+// Each line describes one change, with the property name in the form used
+// by [ParsePath] and the values in the compact notation of the %+v format.
+// Values which are absent are written as null.
+func (j *Journal) WriteLog(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, e := range j.Entries {
+		rec := logRecord{
+			Time:     e.Time.Format(time.RFC3339Nano),
+			Property: formatName(e.Name),
+		}
+		if e.Old != nil {
+			s := fmt.Sprintf("%+v", e.Old)
+			rec.Old = &s
+		}
+		if e.New != nil {
+			s := fmt.Sprintf("%+v", e.New)
+			rec.New = &s
+		}
+		err := enc.Encode(rec)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type logRecord struct {
+	Time     string  `json:"time"`
+	Property string  `json:"property"`
+	Old      *string `json:"old"`
+	New      *string `json:"new"`
+}
@@ -0,0 +1,126 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
+)
+
+func TestJournal(t *testing.T) {
+	const dc = "http://purl.org/dc/elements/1.1/"
+
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := NewPacket()
+	p.Now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	p.Journal = &Journal{}
+
+	p.SetValue(dc, "format", NewText("image/png"))
+	p.SetValue(dc, "format", NewText("image/png")) // no change
+	p.SetValue(dc, "format", NewText("image/jpeg"))
+	p.ClearValue(dc, "title") // not present
+	p.ClearValue(dc, "format")
+
+	name := xml.Name{Space: dc, Local: "format"}
+	want := []JournalEntry{
+		{Change{Name: name, New: Text{V: "image/png"}}, time.Date(2024, 5, 1, 12, 0, 1, 0, time.UTC)},
+		{Change{Name: name, Old: Text{V: "image/png"}, New: Text{V: "image/jpeg"}}, time.Date(2024, 5, 1, 12, 0, 2, 0, time.UTC)},
+		{Change{Name: name, Old: Text{V: "image/jpeg"}}, time.Date(2024, 5, 1, 12, 0, 3, 0, time.UTC)},
+	}
+	if d := cmp.Diff(want, p.Journal.Entries); d != "" {
+		t.Errorf("wrong journal (-want +got):\n%s", d)
+	}
+}
+
+func TestJournalHistory(t *testing.T) {
+	p := NewPacket()
+	p.Now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	p.Journal = &Journal{}
+
+	dc := &DublinCore{}
+	dc.Title.Set(language.English, "Harbour")
+	err := p.Set(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetValue(basicNamespace, "Rating", Real{V: 4})
+
+	p.AddHistory("example-tool 1.0")
+	if len(p.Journal.Entries) != 0 {
+		t.Errorf("journal not cleared")
+	}
+	p.AddHistory("example-tool 1.0") // no-op, since the journal is empty
+
+	history, ok := p.Properties[xml.Name{Space: mmNamespace, Local: "History"}].(RawArray)
+	if !ok || len(history.Value) != 1 {
+		t.Fatalf("wrong history %v", history)
+	}
+	ev := history.Value[0].(RawStruct)
+	params := ev.Value[xml.Name{Space: stEvtNamespace, Local: "parameters"}]
+	if d := cmp.Diff(Raw(Text{V: "changed dc:title, xmp:Rating"}), params); d != "" {
+		t.Errorf("wrong parameters (-want +got):\n%s", d)
+	}
+	when := ev.Value[xml.Name{Space: stEvtNamespace, Local: "when"}]
+	if d := cmp.Diff(Raw(Text{V: "2024-05-01T12:00:00Z"}), when); d != "" {
+		t.Errorf("wrong time stamp (-want +got):\n%s", d)
+	}
+}
+
+func TestJournalLog(t *testing.T) {
+	const dc = "http://purl.org/dc/elements/1.1/"
+
+	p := NewPacket()
+	p.Now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	p.Journal = &Journal{}
+	p.SetValue(dc, "format", NewText("image/png"))
+	p.ClearValue(dc, "format")
+
+	buf := &bytes.Buffer{}
+	err := p.Journal.WriteLog(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var rec map[string]any
+	err = json.Unmarshal([]byte(lines[1]), &rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"time":     "2024-05-01T12:00:00Z",
+		"property": "dc:format",
+		"old":      `"image/png"`,
+		"new":      nil,
+	}
+	if d := cmp.Diff(want, rec); d != "" {
+		t.Errorf("wrong log record (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,134 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// jpegSegment is a marker segment of a JPEG file, before the start of the
+// compressed image data.
+type jpegSegment struct {
+	marker byte
+
+	// data is the payload of the segment, without the marker and the
+	// length field.
+	data []byte
+}
+
+// JPEG markers used in this package.
+const (
+	markerSOI   = 0xD8
+	markerEOI   = 0xD9
+	markerSOS   = 0xDA
+	markerAPP0  = 0xE0
+	markerAPP1  = 0xE1
+	markerAPP13 = 0xED
+)
+
+// Identifiers at the start of the APP segments which hold metadata.
+var (
+	jpegEXIFHeader = []byte("Exif\x00\x00")
+	jpegXMPHeader  = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	jpegIRBHeader  = []byte("Photoshop 3.0\x00")
+
+	jpegExtXMPHeader = []byte("http://ns.adobe.com/xmp/extension/\x00")
+)
+
+// maxSegmentData is the maximal payload size of a JPEG marker segment.
+const maxSegmentData = 0xFFFF - 2
+
+// splitJPEG splits a JPEG file into the marker segments which precede the
+// image data, and the remaining data starting with the SOS marker.
+func splitJPEG(data []byte) ([]jpegSegment, []byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != markerSOI {
+		return nil, nil, errNotJPEG
+	}
+
+	var segs []jpegSegment
+	pos := 2
+	for {
+		if pos >= len(data) || data[pos] != 0xFF {
+			return nil, nil, errMalformedJPEG
+		}
+		for pos < len(data) && data[pos] == 0xFF { // skip fill bytes
+			pos++
+		}
+		if pos >= len(data) {
+			return nil, nil, errMalformedJPEG
+		}
+		marker := data[pos]
+		pos++
+
+		switch {
+		case marker == markerSOS || marker == markerEOI:
+			return segs, data[pos-2:], nil
+		case isStandaloneMarker(marker):
+			segs = append(segs, jpegSegment{marker: marker})
+			continue
+		}
+
+		if pos+2 > len(data) {
+			return nil, nil, errMalformedJPEG
+		}
+		n := int(binary.BigEndian.Uint16(data[pos:]))
+		if n < 2 || pos+n > len(data) {
+			return nil, nil, errMalformedJPEG
+		}
+		segs = append(segs, jpegSegment{marker: marker, data: data[pos+2 : pos+n]})
+		pos += n
+	}
+}
+
+// joinJPEG is the inverse of splitJPEG.
+func joinJPEG(segs []jpegSegment, rest []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.Write([]byte{0xFF, markerSOI})
+	for _, seg := range segs {
+		buf.Write([]byte{0xFF, seg.marker})
+		if isStandaloneMarker(seg.marker) {
+			continue
+		}
+		if len(seg.data) > maxSegmentData {
+			return nil, errSegmentTooLarge
+		}
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(seg.data)+2)))
+		buf.Write(seg.data)
+	}
+	buf.Write(rest)
+	return buf.Bytes(), nil
+}
+
+// isStandaloneMarker reports whether a JPEG marker has no length field and
+// no payload.
+func isStandaloneMarker(marker byte) bool {
+	return marker == 0x01 || marker >= 0xD0 && marker <= 0xD7
+}
+
+// isAPP checks whether seg is an APPn segment whose payload starts with
+// the given header.
+func (seg jpegSegment) isAPP(marker byte, header []byte) bool {
+	return seg.marker == marker && bytes.HasPrefix(seg.data, header)
+}
+
+var (
+	errNotJPEG         = errors.New("not a JPEG file")
+	errMalformedJPEG   = errors.New("malformed JPEG file")
+	errSegmentTooLarge = errors.New("metadata too large for a JPEG segment")
+)
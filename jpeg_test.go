@@ -0,0 +1,105 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestJPEGRoundTrip(t *testing.T) {
+	data := []byte{
+		0xFF, markerSOI,
+		0xFF, markerAPP0, 0x00, 0x04, 'a', 'b',
+		0xFF, 0xFF, 0xD0, // fill byte and standalone marker
+		0xFF, markerAPP1, 0x00, 0x02,
+		0xFF, markerSOS, 0x00, 0x02, 0x11, 0x22,
+		0xFF, markerEOI,
+	}
+	segs, rest, err := splitJPEG(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segs) != 3 {
+		t.Fatalf("got %d segments, want 3", len(segs))
+	}
+	if !bytes.Equal(rest, data[15:]) {
+		t.Errorf("wrong image data: %x", rest)
+	}
+
+	out, err := joinJPEG(segs, rest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(data[:9:9], data[10:]...) // fill bytes are not kept
+	if !bytes.Equal(out, want) {
+		t.Errorf("got %x, want %x", out, want)
+	}
+}
+
+func TestJPEGMalformed(t *testing.T) {
+	for _, data := range [][]byte{
+		[]byte("GIF89a"),
+		{0xFF, markerSOI, 0xFF, markerAPP0, 0x00, 0x10, 0x00},
+		{0xFF, markerSOI, 0x00, 0x00},
+	} {
+		_, _, err := splitJPEG(data)
+		if err == nil {
+			t.Errorf("%x: missing error", data)
+		}
+	}
+
+	_, err := joinJPEG([]jpegSegment{{marker: markerAPP1, data: make([]byte, maxSegmentData+1)}}, nil)
+	if err != errSegmentTooLarge {
+		t.Errorf("got %v, want %v", err, errSegmentTooLarge)
+	}
+}
+
+func FuzzSplitJPEG(f *testing.F) {
+	f.Add([]byte{
+		0xFF, markerSOI,
+		0xFF, markerAPP0, 0x00, 0x04, 'a', 'b',
+		0xFF, 0xD0,
+		0xFF, markerSOS, 0x00, 0x02,
+		0xFF, markerEOI,
+	})
+	f.Add([]byte{0xFF, markerSOI, 0xFF, markerEOI})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		segs, rest, err := splitJPEG(data)
+		if err != nil {
+			return
+		}
+		out, err := joinJPEG(segs, rest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		segs2, rest2, err := splitJPEG(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(segs, segs2, cmp.AllowUnexported(jpegSegment{}), cmpopts.EquateEmpty()); d != "" {
+			t.Errorf("round trip (-want +got):\n%s", d)
+		}
+		if !bytes.Equal(rest, rest2) {
+			t.Errorf("image data changed: %x != %x", rest, rest2)
+		}
+	})
+}
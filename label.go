@@ -0,0 +1,154 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"sort"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// Label returns a human-readable name for the given property, suitable for
+// display in user interfaces.  The label is given in the language which
+// best matches the given preferences, in order of decreasing preference.
+// If no preferences are given, or if no label matches, the English label is
+// used.  If no label is known for the property, the name of the property in
+// the form "prefix:local" is returned.
+//
+// Labels for additional properties can be registered using
+// [RegisterLabel].
+func Label(name xml.Name, prefs ...language.Tag) string {
+	labelMutex.RLock()
+	defer labelMutex.RUnlock()
+
+	byLang := labels[name]
+	if len(byLang) > 0 && len(prefs) > 0 {
+		langs := make([]language.Tag, 0, len(byLang))
+		for lang := range byLang {
+			langs = append(langs, lang)
+		}
+		sort.Slice(langs, func(i, j int) bool {
+			return langs[i].String() < langs[j].String()
+		})
+		_, idx, conf := language.NewMatcher(langs).Match(prefs...)
+		if conf != language.No {
+			return byLang[langs[idx]]
+		}
+	}
+	if label, ok := byLang[language.English]; ok {
+		return label
+	}
+	return formatName(name)
+}
+
+// RegisterLabel sets the label for the given property in the given
+// language.  This replaces any existing label for the property and
+// language.
+func RegisterLabel(name xml.Name, lang language.Tag, label string) {
+	labelMutex.Lock()
+	defer labelMutex.Unlock()
+
+	byLang := labels[name]
+	if byLang == nil {
+		byLang = make(map[language.Tag]string)
+		labels[name] = byLang
+	}
+	byLang[lang] = label
+}
+
+var (
+	labelMutex sync.RWMutex
+	labels     = make(map[xml.Name]map[language.Tag]string)
+)
+
+func init() {
+	for _, l := range builtinLabels {
+		name := xml.Name{Space: l.ns, Local: l.local}
+		RegisterLabel(name, language.English, l.en)
+		RegisterLabel(name, language.German, l.de)
+	}
+}
+
+// builtinLabels lists the English and German labels for well-known
+// properties.
+var builtinLabels = []struct {
+	ns, local string
+	en, de    string
+}{
+	{dcNamespace, "contributor", "Contributor", "Mitwirkende"},
+	{dcNamespace, "coverage", "Coverage", "Geltungsbereich"},
+	{dcNamespace, "creator", "Creator", "Ersteller"},
+	{dcNamespace, "date", "Date", "Datum"},
+	{dcNamespace, "description", "Description", "Beschreibung"},
+	{dcNamespace, "format", "Format", "Format"},
+	{dcNamespace, "identifier", "Identifier", "Kennung"},
+	{dcNamespace, "language", "Language", "Sprache"},
+	{dcNamespace, "publisher", "Publisher", "Herausgeber"},
+	{dcNamespace, "relation", "Relation", "Beziehung"},
+	{dcNamespace, "rights", "Rights", "Rechte"},
+	{dcNamespace, "source", "Source", "Quelle"},
+	{dcNamespace, "subject", "Keywords", "Stichwörter"},
+	{dcNamespace, "title", "Title", "Titel"},
+	{dcNamespace, "type", "Type", "Typ"},
+
+	{basicNamespace, "CreateDate", "Date Created", "Erstellungsdatum"},
+	{basicNamespace, "CreatorTool", "Creator Tool", "Erstellungsprogramm"},
+	{basicNamespace, "Identifier", "Identifier", "Kennung"},
+	{basicNamespace, "Label", "Label", "Etikett"},
+	{basicNamespace, "MetadataDate", "Metadata Date", "Metadatendatum"},
+	{basicNamespace, "ModifyDate", "Date Modified", "Änderungsdatum"},
+	{basicNamespace, "Rating", "Rating", "Bewertung"},
+
+	{rightsNamespace, "Certificate", "Certificate", "Zertifikat"},
+	{rightsNamespace, "Marked", "Copyright Status", "Urheberrechtsstatus"},
+	{rightsNamespace, "Owner", "Rights Owner", "Rechteinhaber"},
+	{rightsNamespace, "UsageTerms", "Usage Terms", "Nutzungsbedingungen"},
+	{rightsNamespace, "WebStatement", "Copyright Info URL", "URL für Urheberrechtsinformationen"},
+
+	{mmNamespace, "DerivedFrom", "Derived From", "Abgeleitet von"},
+	{mmNamespace, "DocumentID", "Document ID", "Dokument-ID"},
+	{mmNamespace, "Ingredients", "Ingredients", "Bestandteile"},
+	{mmNamespace, "InstanceID", "Instance ID", "Instanz-ID"},
+	{mmNamespace, "Manifest", "Manifest", "Manifest"},
+	{mmNamespace, "OriginalDocumentID", "Original Document ID", "Ursprüngliche Dokument-ID"},
+
+	{pdfNamespace, "Keywords", "Keywords", "Stichwörter"},
+	{pdfNamespace, "PDFVersion", "PDF Version", "PDF-Version"},
+	{pdfNamespace, "Producer", "PDF Producer", "PDF-Erzeuger"},
+	{pdfNamespace, "Trapped", "Trapped", "Überfüllung"},
+
+	{photoshopNamespace, "AuthorsPosition", "Creator's Job Title", "Position des Erstellers"},
+	{photoshopNamespace, "CaptionWriter", "Description Writer", "Verfasser der Beschreibung"},
+	{photoshopNamespace, "City", "City", "Stadt"},
+	{photoshopNamespace, "Country", "Country", "Land"},
+	{photoshopNamespace, "Credit", "Credit Line", "Quellenangabe"},
+	{photoshopNamespace, "DateCreated", "Date Created", "Erstellungsdatum"},
+	{photoshopNamespace, "Headline", "Headline", "Überschrift"},
+	{photoshopNamespace, "Instructions", "Instructions", "Anweisungen"},
+	{photoshopNamespace, "Source", "Source", "Quelle"},
+	{photoshopNamespace, "State", "State/Province", "Bundesland/Kanton"},
+	{photoshopNamespace, "TransmissionReference", "Job Identifier", "Auftragskennung"},
+
+	{iptcCoreNamespace, "CountryCode", "Country Code", "Ländercode"},
+	{iptcCoreNamespace, "CreatorContactInfo", "Creator's Contact Info", "Kontaktdaten des Erstellers"},
+	{iptcCoreNamespace, "IntellectualGenre", "Intellectual Genre", "Genre"},
+	{iptcCoreNamespace, "Location", "Sublocation", "Ortsdetail"},
+	{iptcCoreNamespace, "Scene", "Scene Code", "Szenencode"},
+	{iptcCoreNamespace, "SubjectCode", "Subject Code", "Themencode"},
+}
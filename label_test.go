@@ -0,0 +1,56 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLabel(t *testing.T) {
+	creator := xml.Name{Space: dcNamespace, Local: "creator"}
+	unknown := xml.Name{Space: "http://ns.seehuhn.de/test/#", Local: "prop"}
+
+	testCases := []struct {
+		name     xml.Name
+		prefs    []language.Tag
+		expected string
+	}{
+		{creator, nil, "Creator"},
+		{creator, []language.Tag{language.German}, "Ersteller"},
+		{creator, []language.Tag{language.MustParse("de-AT")}, "Ersteller"},
+		{creator, []language.Tag{language.Japanese}, "Creator"},
+		{xml.Name{Space: dcNamespace, Local: "nonexistent"}, nil, "dc:nonexistent"},
+		{unknown, []language.Tag{language.German}, "{http://ns.seehuhn.de/test/#}prop"},
+	}
+	for _, tc := range testCases {
+		got := Label(tc.name, tc.prefs...)
+		if got != tc.expected {
+			t.Errorf("Label(%v, %v) = %q, want %q", tc.name, tc.prefs, got, tc.expected)
+		}
+	}
+
+	RegisterLabel(unknown, language.French, "Propriété")
+	if got := Label(unknown, language.French); got != "Propriété" {
+		t.Errorf("registered label: got %q", got)
+	}
+	if got := Label(unknown); got != "{http://ns.seehuhn.de/test/#}prop" {
+		t.Errorf("no English label: got %q", got)
+	}
+}
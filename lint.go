@@ -0,0 +1,248 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+// Severity indicates how serious a [Finding] is.
+type Severity int
+
+// These are the possible severities of a [Finding], in order of
+// increasing seriousness.
+const (
+	// SeverityInfo marks findings which do not affect the use of the
+	// data, for example language tags which are not in canonical form.
+	SeverityInfo Severity = iota
+
+	// SeverityWarning marks findings which may cause other software to
+	// misinterpret the data.
+	SeverityWarning
+
+	// SeverityError marks violations of the XMP specification.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// A Finding describes a problem found by [Lint].
+type Finding struct {
+	Severity Severity
+
+	// Code is a short, machine-readable identifier for the kind of
+	// problem.  The following codes are used:
+	//
+	//   - "diagnostic": a problem recorded while reading the packet,
+	//     see [Packet.Diagnostics].
+	//   - "qualifier": a misplaced or invalid qualifier,
+	//     see [Packet.CheckQualifiers].
+	//   - "invalid-value": the value does not have the type given in the
+	//     schema of the property.
+	//   - "malformed-value": the value is malformed, but can be repaired.
+	//   - "unknown-property": the property is not part of the schema of a
+	//     known namespace.
+	//   - "unknown-namespace": no schema is registered for the namespace.
+	//   - "alias": the property is an alias for another property.
+	//   - "language-tag": a language tag is not in canonical form.
+	//   - "missing-x-default": a language alternative has no default value.
+	Code string
+
+	// Property is the name of the affected property.
+	Property xml.Name
+
+	// Message describes the problem.
+	Message string
+}
+
+func (f Finding) String() string {
+	return f.Severity.String() + ": " + formatName(f.Property) + ": " + f.Message + " [" + f.Code + "]"
+}
+
+// Lint checks the packet for problems.  This combines the checks for
+// qualifiers, the diagnostics recorded while reading the packet, a check
+// of the property values against the registered schemas (see
+// [LookupSchema]), a check for alias properties, and an audit of the
+// language tags used.
+//
+// The findings are sorted by property name.
+func Lint(p *Packet) []Finding {
+	var res []Finding
+	add := func(sev Severity, code string, name xml.Name, msg string) {
+		res = append(res, Finding{
+			Severity: sev,
+			Code:     code,
+			Property: name,
+			Message:  msg,
+		})
+	}
+
+	for _, d := range p.Diagnostics() {
+		add(SeverityWarning, "diagnostic", d.Property, d.Message)
+	}
+	for _, d := range p.CheckQualifiers() {
+		add(SeverityError, "qualifier", d.Property, d.Message)
+	}
+
+	names := make([]xml.Name, 0, len(p.Properties))
+	for name := range p.Properties {
+		names = append(names, name)
+	}
+	sortNames(names)
+
+	unknownNS := make(map[string]bool)
+	for _, name := range names {
+		val := p.Properties[name]
+
+		base, isAlias := aliases[name]
+		info, known := lookupProperty(name)
+		switch {
+		case isAlias:
+			add(SeverityWarning, "alias", name,
+				"alias for "+formatName(base)+", use the base property instead")
+		case known:
+			dec, ok := DecoderFor(info.Type)
+			if !ok {
+				break
+			}
+			_, msg, ok := repairValue(dec, val)
+			if !ok {
+				add(SeverityError, "invalid-value", name, "not a valid "+info.ValueType+" value")
+			} else if msg != "" {
+				add(SeverityWarning, "malformed-value", name, msg)
+			}
+			if info.ValueType == "Lang Alt" && !hasDefaultLanguage(val) {
+				add(SeverityInfo, "missing-x-default", name, "no x-default entry")
+			}
+		case LookupSchema(name.Space) != nil:
+			add(SeverityWarning, "unknown-property", name, "not part of the namespace schema")
+		case !unknownNS[name.Space]:
+			unknownNS[name.Space] = true
+			add(SeverityInfo, "unknown-namespace", name, "no schema registered for "+name.Space)
+		}
+
+		lintLanguages(val, func(tag string) {
+			add(SeverityInfo, "language-tag", name, fmt.Sprintf("language tag %q is not in canonical form", tag))
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return CompareNames(res[i].Property, res[j].Property) < 0
+	})
+	return res
+}
+
+// lintLanguages reports all xml:lang qualifiers inside val which are valid,
+// but not in canonical form.  Invalid tags are reported by
+// [Packet.CheckQualifiers].
+func lintLanguages(val Raw, report func(tag string)) {
+	var qq Q
+	switch val := val.(type) {
+	case Text:
+		qq = val.Q
+	case URL:
+		qq = val.Q
+	case RawStruct:
+		qq = val.Q
+		for _, name := range val.FieldNames() {
+			lintLanguages(val.Value[name], report)
+		}
+	case RawArray:
+		qq = val.Q
+		for _, item := range val.Value {
+			lintLanguages(item, report)
+		}
+	}
+
+	tag := qq.lang()
+	if tag == "" {
+		return
+	}
+	l, err := language.Parse(tag)
+	if err == nil && l.String() != tag {
+		report(tag)
+	}
+}
+
+// hasDefaultLanguage reports whether a language alternative contains an
+// x-default entry.
+func hasDefaultLanguage(val Raw) bool {
+	a, ok := val.(RawArray)
+	if !ok {
+		return true
+	}
+	for _, item := range a.Value {
+		if t, ok := item.(Text); ok && t.Q.lang() == "x-default" {
+			return true
+		}
+	}
+	return false
+}
+
+// aliases maps the alias properties defined by the XMP specification to
+// the corresponding base properties.
+var aliases = map[xml.Name]xml.Name{
+	{Space: basicNamespace, Local: "Author"}:      {Space: dcNamespace, Local: "creator"},
+	{Space: basicNamespace, Local: "Authors"}:     {Space: dcNamespace, Local: "creator"},
+	{Space: basicNamespace, Local: "Description"}: {Space: dcNamespace, Local: "description"},
+	{Space: basicNamespace, Local: "Format"}:      {Space: dcNamespace, Local: "format"},
+	{Space: basicNamespace, Local: "Keywords"}:    {Space: dcNamespace, Local: "subject"},
+	{Space: basicNamespace, Local: "Locale"}:      {Space: dcNamespace, Local: "language"},
+	{Space: basicNamespace, Local: "Title"}:       {Space: dcNamespace, Local: "title"},
+
+	{Space: rightsNamespace, Local: "Copyright"}: {Space: dcNamespace, Local: "rights"},
+
+	{Space: pdfNamespace, Local: "Author"}:       {Space: dcNamespace, Local: "creator"},
+	{Space: pdfNamespace, Local: "BaseURL"}:      {Space: basicNamespace, Local: "BaseURL"},
+	{Space: pdfNamespace, Local: "CreationDate"}: {Space: basicNamespace, Local: "CreateDate"},
+	{Space: pdfNamespace, Local: "Creator"}:      {Space: basicNamespace, Local: "CreatorTool"},
+	{Space: pdfNamespace, Local: "ModDate"}:      {Space: basicNamespace, Local: "ModifyDate"},
+	{Space: pdfNamespace, Local: "Subject"}:      {Space: dcNamespace, Local: "description"},
+	{Space: pdfNamespace, Local: "Title"}:        {Space: dcNamespace, Local: "title"},
+
+	{Space: photoshopNamespace, Local: "Author"}:       {Space: dcNamespace, Local: "creator"},
+	{Space: photoshopNamespace, Local: "Caption"}:      {Space: dcNamespace, Local: "description"},
+	{Space: photoshopNamespace, Local: "Copyright"}:    {Space: dcNamespace, Local: "rights"},
+	{Space: photoshopNamespace, Local: "Keywords"}:     {Space: dcNamespace, Local: "subject"},
+	{Space: photoshopNamespace, Local: "Marked"}:       {Space: rightsNamespace, Local: "Marked"},
+	{Space: photoshopNamespace, Local: "Title"}:        {Space: dcNamespace, Local: "title"},
+	{Space: photoshopNamespace, Local: "WebStatement"}: {Space: rightsNamespace, Local: "WebStatement"},
+
+	{Space: tiffNamespace, Local: "Artist"}:           {Space: dcNamespace, Local: "creator"},
+	{Space: tiffNamespace, Local: "Copyright"}:        {Space: dcNamespace, Local: "rights"},
+	{Space: tiffNamespace, Local: "DateTime"}:         {Space: basicNamespace, Local: "ModifyDate"},
+	{Space: tiffNamespace, Local: "ImageDescription"}: {Space: dcNamespace, Local: "description"},
+	{Space: tiffNamespace, Local: "Software"}:         {Space: basicNamespace, Local: "CreatorTool"},
+
+	{Space: exifNamespace, Local: "DateTimeDigitized"}: {Space: basicNamespace, Local: "CreateDate"},
+}
+
+const tiffNamespace = "http://ns.adobe.com/tiff/1.0/"
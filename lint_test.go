@@ -0,0 +1,73 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLint(t *testing.T) {
+	body := `<rdf:Description rdf:about=""
+	xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+	xmlns:pdf="http://ns.adobe.com/pdf/1.3/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmp:Rating="3,5"
+	xmp:CreateDate="not a date"
+	xmp:NoSuchProperty="x"
+	pdf:Title="Hello"
+	test:prop="y">
+<dc:title><rdf:Alt><rdf:li xml:lang="en-us">Hello</rdf:li></rdf:Alt></dc:title>
+</rdf:Description>`
+	p, err := Read(strings.NewReader(head + body + foot))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		Severity Severity
+		Code     string
+		Property string
+	}
+	var got []result
+	for _, f := range Lint(p) {
+		got = append(got, result{f.Severity, f.Code, formatName(f.Property)})
+	}
+	expected := []result{
+		{SeverityWarning, "alias", "pdf:Title"},
+		{SeverityError, "invalid-value", "xmp:CreateDate"},
+		{SeverityWarning, "unknown-property", "xmp:NoSuchProperty"},
+		{SeverityWarning, "malformed-value", "xmp:Rating"},
+		{SeverityInfo, "unknown-namespace", "{http://ns.seehuhn.de/test/#}prop"},
+		{SeverityInfo, "missing-x-default", "dc:title"},
+		{SeverityInfo, "language-tag", "dc:title"},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("findings differ (-want +got):\n%s", d)
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	p := NewPacket()
+	p.SetValue(basicNamespace, "CreatorTool", NewAgentName("test"))
+	p.SetValue(dcNamespace, "format", NewText("application/pdf"))
+	if findings := Lint(p); len(findings) != 0 {
+		t.Errorf("unexpected findings: %v", findings)
+	}
+}
@@ -0,0 +1,83 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// ManifestItem describes a resource which is used by a composed document,
+// as found in the xmpMM:Manifest array.
+//
+// See section 1.2.4.1 of part 2 of the XMP specification (2016).
+type ManifestItem struct {
+	// LinkForm describes how the resource is included.  Possible values
+	// are "EmbedByReference", "ReferenceStream", "EmbeddedResource" and
+	// "ManagedResource".
+	LinkForm Text
+
+	// PlacedXResolution and PlacedYResolution give the resolution of the
+	// placed resource, in PlacedResolutionUnit.
+	PlacedXResolution Real
+	PlacedYResolution Real
+
+	// PlacedResolutionUnit is the unit of the resolution values, for
+	// example "inch" or "cm".
+	PlacedResolutionUnit Text
+
+	// Reference identifies the resource.
+	Reference ResourceRef
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (m ManifestItem) IsZero() bool {
+	return m.LinkForm.IsZero() && m.PlacedXResolution.IsZero() &&
+		m.PlacedYResolution.IsZero() && m.PlacedResolutionUnit.IsZero() &&
+		m.Reference.IsZero() && len(m.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (m ManifestItem) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     m.Q,
+	}
+	setField(res, p, stMfsNamespace, "linkForm", m.LinkForm)
+	setField(res, p, stMfsNamespace, "placedXResolution", m.PlacedXResolution)
+	setField(res, p, stMfsNamespace, "placedYResolution", m.PlacedYResolution)
+	setField(res, p, stMfsNamespace, "placedResolutionUnit", m.PlacedResolutionUnit)
+	setField(res, p, stMfsNamespace, "reference", m.Reference)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (ManifestItem) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return ManifestItem{
+		LinkForm:             getField[Text](s, stMfsNamespace, "linkForm"),
+		PlacedXResolution:    getField[Real](s, stMfsNamespace, "placedXResolution"),
+		PlacedYResolution:    getField[Real](s, stMfsNamespace, "placedYResolution"),
+		PlacedResolutionUnit: getField[Text](s, stMfsNamespace, "placedResolutionUnit"),
+		Reference:            getField[ResourceRef](s, stMfsNamespace, "reference"),
+		Q:                    s.Q,
+	}, nil
+}
+
+const stMfsNamespace = "http://ns.adobe.com/xap/1.0/sType/ManifestItem#"
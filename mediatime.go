@@ -0,0 +1,105 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// Time represents a point in time or a duration within a media file, as
+// used for example in xmpDM:duration.  The time in seconds is Value
+// multiplied by Scale.
+//
+// See section 1.2.6.23 of part 2 of the XMP specification (2016).
+type Time struct {
+	// Scale is the duration of one unit of Value, in seconds, for example
+	// 1/25 for a frame of 25 fps video.
+	Scale Rational
+
+	// Value is the time in units of Scale.
+	Value Integer
+
+	Q
+}
+
+// NewTime returns a Time value which represents the duration d.
+// If d is a whole number of milliseconds, a scale of 1/1000 is used,
+// otherwise the scale is 1/1000000000.
+func NewTime(d time.Duration) Time {
+	if d%time.Millisecond == 0 {
+		return Time{
+			Scale: NewRational(1, 1000),
+			Value: Integer{V: int64(d / time.Millisecond)},
+		}
+	}
+	return Time{
+		Scale: NewRational(1, 1e9),
+		Value: Integer{V: int64(d)},
+	}
+}
+
+var errTimeRange = errors.New("time value out of range")
+
+// Duration converts the time to a [time.Duration].  The result is rounded
+// towards zero to whole nanoseconds.  An error is returned if the scale
+// has a zero denominator or if the result is out of range.
+func (t Time) Duration() (time.Duration, error) {
+	scale := t.Scale.Rat()
+	if scale == nil {
+		return 0, ErrInvalid
+	}
+	x := new(big.Rat).SetInt64(t.Value.V)
+	x.Mul(x, scale)
+	x.Mul(x, big.NewRat(int64(time.Second), 1))
+	ns := new(big.Int).Quo(x.Num(), x.Denom())
+	if !ns.IsInt64() {
+		return 0, errTimeRange
+	}
+	return time.Duration(ns.Int64()), nil
+}
+
+// IsZero implements the [Value] interface.
+func (t Time) IsZero() bool {
+	return t.Scale.IsZero() && t.Value.IsZero() && len(t.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (t Time) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     t.Q,
+	}
+	setField(res, p, dmNamespace, "scale", t.Scale)
+	setField(res, p, dmNamespace, "value", t.Value)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Time) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Time{
+		Scale: getField[Rational](s, dmNamespace, "scale"),
+		Value: getField[Integer](s, dmNamespace, "value"),
+		Q:     s.Q,
+	}, nil
+}
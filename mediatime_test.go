@@ -0,0 +1,75 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTimeDuration(t *testing.T) {
+	testCases := []struct {
+		in   Time
+		want time.Duration
+	}{
+		{Time{Scale: NewRational(1, 25), Value: Integer{V: 3275}}, 131 * time.Second},
+		{Time{Scale: NewRational(1001, 30000), Value: Integer{V: 30}}, 1001 * time.Millisecond},
+		{Time{Scale: NewRational(1, 48000), Value: Integer{V: 1}}, 20833 * time.Nanosecond},
+		{NewTime(1500 * time.Millisecond), 1500 * time.Millisecond},
+		{NewTime(7 * time.Nanosecond), 7 * time.Nanosecond},
+	}
+	for _, tc := range testCases {
+		got, err := tc.in.Duration()
+		if err != nil {
+			t.Errorf("%v: %v", tc.in, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%v: got %v, want %v", tc.in, got, tc.want)
+		}
+	}
+
+	if _, err := (Time{Scale: NewRational(1, 0), Value: Integer{V: 1}}).Duration(); err == nil {
+		t.Error("expected error for zero denominator")
+	}
+	if _, err := (Time{Scale: NewRational(1000, 1), Value: Integer{V: 1 << 50}}).Duration(); err == nil {
+		t.Error("expected error for overflow")
+	}
+}
+
+func TestTimeSample(t *testing.T) {
+	body, err := os.ReadFile("testdata/premiere-video.xmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Read(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := &DynamicMedia{}
+	p.Get(dm)
+
+	d, err := dm.Duration.Duration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 131*time.Second {
+		t.Errorf("wrong duration %v", d)
+	}
+}
@@ -0,0 +1,104 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Metrics receives counts of events from this package, so that services
+// can export them to a monitoring system like Prometheus.  Implementations
+// must be safe for concurrent use.
+type Metrics interface {
+	// Add increases the given counter by n.
+	Add(c Counter, n int)
+}
+
+// Counter identifies one of the counters reported to [Metrics].
+type Counter int
+
+// These are the counters reported to [Metrics].
+const (
+	// CounterPacketsRead counts the packets decoded successfully by
+	// [Packet.Decode] and [Read].
+	CounterPacketsRead Counter = iota + 1
+
+	// CounterParseErrors counts the packets which could not be decoded.
+	CounterParseErrors
+
+	// CounterBytesWritten counts the bytes written by [Packet.Write] and by
+	// the functions which write XMP files.
+	CounterBytesWritten
+
+	// CounterRepairs counts the malformed values which were repaired,
+	// see [Packet.Diagnostics].
+	CounterRepairs
+)
+
+// String returns a name for the counter, which is suitable as a metric
+// name.
+func (c Counter) String() string {
+	switch c {
+	case CounterPacketsRead:
+		return "packets_read"
+	case CounterParseErrors:
+		return "parse_errors"
+	case CounterBytesWritten:
+		return "bytes_written"
+	case CounterRepairs:
+		return "repairs"
+	default:
+		return fmt.Sprintf("Counter(%d)", int(c))
+	}
+}
+
+// SetMetrics installs m as the receiver for the counters of this package.
+// Use nil to stop reporting.
+func SetMetrics(m Metrics) {
+	metrics.Store(&metricsHolder{m})
+}
+
+// metricsHolder allows to store a nil interface value in an atomic.Pointer.
+type metricsHolder struct {
+	m Metrics
+}
+
+var metrics atomic.Pointer[metricsHolder]
+
+// getMetrics returns the current metrics receiver, or nil if none is
+// installed.
+func getMetrics() Metrics {
+	h := metrics.Load()
+	if h == nil {
+		return nil
+	}
+	return h.m
+}
+
+// countingWriter counts the number of bytes written.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
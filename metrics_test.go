@@ -0,0 +1,82 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testMetrics struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (m *testMetrics) Add(c Counter, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[c.String()] += n
+}
+
+func TestMetrics(t *testing.T) {
+	m := &testMetrics{counts: make(map[string]int)}
+	SetMetrics(m)
+	defer SetMetrics(nil)
+
+	// dc:creator must be an ordered array; this is repaired when reading.
+	p := NewPacket()
+	creator := UnorderedArray[ProperName]{}
+	creator.Append(NewProperName("Jochen Voss"))
+	p.SetValue(dcNamespace, "creator", creator)
+
+	buf := &bytes.Buffer{}
+	err := p.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := buf.Len()
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 { // reading values does not count as a repair
+		_, err = PacketGetValue[OrderedArray[ProperName]](p2, dcNamespace, "creator")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = Read(strings.NewReader("<rdf:RDF"))
+	if err == nil {
+		t.Fatal("invalid packet was accepted")
+	}
+
+	want := map[string]int{
+		"packets_read":  1,
+		"parse_errors":  1,
+		"bytes_written": n,
+		"repairs":       1,
+	}
+	if d := cmp.Diff(want, m.counts); d != "" {
+		t.Errorf("wrong counts (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,205 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MicrosoftPhoto represents the Microsoft Photo namespace, used by Windows
+// Explorer and Windows Photo Gallery.
+//
+// See also [Packet.Rating] and [Packet.SetRating] for keeping
+// MicrosoftPhoto:Rating in sync with xmp:Rating.
+type MicrosoftPhoto struct {
+	_ Namespace `xmp:"http://ns.microsoft.com/photo/1.0/"`
+	_ Prefix    `xmp:"MicrosoftPhoto"`
+
+	// Rating is the rating of the resource, as a percentage between 0 and
+	// 99.  See [StarsToPercent] and [PercentToStars].
+	Rating Integer
+
+	// LastKeywordXMP is the list of keywords last written by Windows to
+	// dc:subject.  Windows uses this to detect keywords changed by other
+	// applications.
+	LastKeywordXMP UnorderedArray[Text]
+
+	// LastKeywordIPTC is the list of keywords last written by Windows to
+	// the IPTC-IIM keywords.
+	LastKeywordIPTC UnorderedArray[Text]
+
+	// DateAcquired is the date when the image was imported.
+	DateAcquired Date
+
+	// CameraSerialNumber is the serial number of the camera.
+	CameraSerialNumber Text
+
+	// LensManufacturer is the manufacturer of the lens.
+	LensManufacturer Text
+
+	// LensModel is the model name of the lens.
+	LensModel Text
+
+	// FlashManufacturer is the manufacturer of the flash.
+	FlashManufacturer Text
+
+	// FlashModel is the model name of the flash.
+	FlashModel Text
+}
+
+// MicrosoftPhotoRegions represents the Microsoft Photo 1.2 namespace,
+// which Windows Photo Gallery uses to tag people in images.
+type MicrosoftPhotoRegions struct {
+	_ Namespace `xmp:"http://ns.microsoft.com/photo/1.2/"`
+	_ Prefix    `xmp:"MP"`
+
+	// RegionInfo lists the tagged regions of the image.
+	RegionInfo MPRegionInfo
+}
+
+// MPRegionInfo describes the tagged regions of an image.
+//
+// This is the RegionInfo structure from the Microsoft Photo 1.2 schema,
+// used by the MP:RegionInfo property.
+type MPRegionInfo struct {
+	// Regions is the list of regions.
+	Regions UnorderedArray[MPRegion]
+
+	// DateRegionsValid is the date when the regions were last known to
+	// match the image.
+	DateRegionsValid Date
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (r MPRegionInfo) IsZero() bool {
+	return r.Regions.IsZero() && r.DateRegionsValid.IsZero() && len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r MPRegionInfo) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     r.Q,
+	}
+	setField(res, p, mpriNamespace, "Regions", r.Regions)
+	setField(res, p, mpriNamespace, "DateRegionsValid", r.DateRegionsValid)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (MPRegionInfo) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return MPRegionInfo{
+		Regions:          getField[UnorderedArray[MPRegion]](s, mpriNamespace, "Regions"),
+		DateRegionsValid: getField[Date](s, mpriNamespace, "DateRegionsValid"),
+		Q:                s.Q,
+	}, nil
+}
+
+// MPRegion is a region of an image showing a person.
+//
+// This is the Region structure from the Microsoft Photo 1.2 schema.
+type MPRegion struct {
+	// Rectangle gives the position of the region, in the form "x, y, w, h".
+	// All values are relative to the image size.  Use [MPRegion.Bounds] to
+	// parse the rectangle.
+	Rectangle Text
+
+	// PersonDisplayName is the name of the person shown in the region.
+	PersonDisplayName Text
+
+	// PersonEmailDigest is the SHA-1 hash of the person's e-mail address.
+	PersonEmailDigest Text
+
+	// PersonLiveIdCID is the Windows Live ID of the person.
+	PersonLiveIdCID Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (r MPRegion) IsZero() bool {
+	return r.Rectangle.IsZero() && r.PersonDisplayName.IsZero() &&
+		r.PersonEmailDigest.IsZero() && r.PersonLiveIdCID.IsZero() &&
+		len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r MPRegion) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     r.Q,
+	}
+	setField(res, p, mpregNamespace, "Rectangle", r.Rectangle)
+	setField(res, p, mpregNamespace, "PersonDisplayName", r.PersonDisplayName)
+	setField(res, p, mpregNamespace, "PersonEmailDigest", r.PersonEmailDigest)
+	setField(res, p, mpregNamespace, "PersonLiveIdCID", r.PersonLiveIdCID)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (MPRegion) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return MPRegion{
+		Rectangle:         getField[Text](s, mpregNamespace, "Rectangle"),
+		PersonDisplayName: getField[Text](s, mpregNamespace, "PersonDisplayName"),
+		PersonEmailDigest: getField[Text](s, mpregNamespace, "PersonEmailDigest"),
+		PersonLiveIdCID:   getField[Text](s, mpregNamespace, "PersonLiveIdCID"),
+		Q:                 s.Q,
+	}, nil
+}
+
+// Bounds parses the Rectangle field of the region.  The values are
+// relative to the image size, with (x, y) giving the top-left corner of
+// the region.
+func (r MPRegion) Bounds() (x, y, w, h float64, err error) {
+	parts := strings.Split(r.Rectangle.V, ",")
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, fmt.Errorf("invalid region rectangle %q", r.Rectangle.V)
+	}
+	var vals [4]float64
+	for i, part := range parts {
+		vals[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("invalid region rectangle %q", r.Rectangle.V)
+		}
+	}
+	return vals[0], vals[1], vals[2], vals[3], nil
+}
+
+// SetBounds sets the Rectangle field of the region.
+func (r *MPRegion) SetBounds(x, y, w, h float64) {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	r.Rectangle.V = f(x) + ", " + f(y) + ", " + f(w) + ", " + f(h)
+}
+
+const (
+	mpNamespace    = "http://ns.microsoft.com/photo/1.2/"
+	mpriNamespace  = "http://ns.microsoft.com/photo/1.2/t/RegionInfo#"
+	mpregNamespace = "http://ns.microsoft.com/photo/1.2/t/Region#"
+)
@@ -0,0 +1,120 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMicrosoftPhoto(t *testing.T) {
+	ms := &MicrosoftPhoto{
+		Rating:         Integer{V: 75},
+		LastKeywordXMP: UnorderedArray[Text]{V: []Text{NewText("beach"), NewText("family")}},
+		DateAcquired:   NewDate(time.Date(2024, 7, 1, 10, 30, 0, 0, time.UTC)),
+		LensModel:      NewText("EF 50mm f/1.8"),
+	}
+	region := MPRegion{PersonDisplayName: NewText("Jane Doe")}
+	region.SetBounds(0.25, 0.1, 0.2, 0.3)
+	mp := &MicrosoftPhotoRegions{
+		RegionInfo: MPRegionInfo{
+			Regions: UnorderedArray[MPRegion]{V: []MPRegion{region}},
+		},
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(ms, mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`MPReg:Rectangle="0.25, 0.1, 0.2, 0.3"`)) {
+		t.Errorf("wrong encoding:\n%s", buf.Bytes())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms2 := &MicrosoftPhoto{}
+	p2.Get(ms2)
+	if d := cmp.Diff(ms, ms2); d != "" {
+		t.Errorf("MicrosoftPhoto differs (-want +got):\n%s", d)
+	}
+	mp2 := &MicrosoftPhotoRegions{}
+	p2.Get(mp2)
+	if d := cmp.Diff(mp, mp2); d != "" {
+		t.Errorf("MicrosoftPhotoRegions differs (-want +got):\n%s", d)
+	}
+}
+
+// TestMicrosoftPhotoRegionsDecode checks that people tags written by
+// Windows Photo Gallery can be read.
+func TestMicrosoftPhotoRegionsDecode(t *testing.T) {
+	in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:MP="http://ns.microsoft.com/photo/1.2/">
+<MP:RegionInfo>
+<rdf:Description xmlns:MPRI="http://ns.microsoft.com/photo/1.2/t/RegionInfo#">
+<MPRI:Regions>
+<rdf:Bag>
+<rdf:li>
+<rdf:Description xmlns:MPReg="http://ns.microsoft.com/photo/1.2/t/Region#"
+ MPReg:Rectangle="0.401316, 0.175439, 0.131579, 0.263158"
+ MPReg:PersonDisplayName="John Smith"/>
+</rdf:li>
+</rdf:Bag>
+</MPRI:Regions>
+</rdf:Description>
+</MP:RegionInfo>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>`
+	p, err := Read(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mp := &MicrosoftPhotoRegions{}
+	p.Get(mp)
+	if len(mp.RegionInfo.Regions.V) != 1 {
+		t.Fatalf("got %d regions, want 1", len(mp.RegionInfo.Regions.V))
+	}
+	r := mp.RegionInfo.Regions.V[0]
+	if r.PersonDisplayName.V != "John Smith" {
+		t.Errorf("wrong name %q", r.PersonDisplayName.V)
+	}
+	x, y, w, h, err := r.Bounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x != 0.401316 || y != 0.175439 || w != 0.131579 || h != 0.263158 {
+		t.Errorf("wrong bounds %g, %g, %g, %g", x, y, w, h)
+	}
+
+	r.Rectangle = NewText("1, 2, 3")
+	if _, _, _, _, err := r.Bounds(); err == nil {
+		t.Error("invalid rectangle not detected")
+	}
+}
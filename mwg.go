@@ -0,0 +1,823 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ImageMetadata holds the metadata of a JPEG file.  The XMP, EXIF and
+// IPTC-IIM blocks of the file are reconciled into a single XMP packet,
+// following the guidelines of the Metadata Working Group (MWG).
+type ImageMetadata struct {
+	// Packet is the reconciled XMP packet.  Changes made to the packet are
+	// written to the file by [ImageMetadata.Save].
+	Packet *Packet
+
+	// Conflicts lists the properties where the EXIF or IPTC-IIM data
+	// disagreed with the XMP data when the file was read.
+	Conflicts []Conflict
+
+	// Warnings lists problems with the legacy metadata blocks which were
+	// found when the file was read.  Blocks which could not be parsed are
+	// ignored.
+	Warnings []error
+
+	fname   string
+	data    []byte
+	origXMP *Packet
+}
+
+// MetadataBlock identifies one of the metadata blocks of an image file.
+type MetadataBlock int
+
+// These are the metadata blocks of a JPEG file.
+const (
+	BlockXMP MetadataBlock = iota
+	BlockEXIF
+	BlockIIM
+)
+
+func (b MetadataBlock) String() string {
+	switch b {
+	case BlockXMP:
+		return "XMP"
+	case BlockEXIF:
+		return "EXIF"
+	case BlockIIM:
+		return "IPTC-IIM"
+	default:
+		return fmt.Sprintf("MetadataBlock(%d)", int(b))
+	}
+}
+
+// Conflict describes a property where a legacy metadata block disagrees
+// with the XMP data.
+type Conflict struct {
+	// Property is the XMP property.
+	Property xml.Name
+
+	// Block is the legacy metadata block which holds a different value.
+	Block MetadataBlock
+
+	// XMP and Other are the values found in the XMP data and in the legacy
+	// block.  Dates are given in the form "2006-01-02T15:04:05", without
+	// time zone.
+	XMP, Other []string
+
+	// UsedOther is true if the value from the legacy block was stored in
+	// the reconciled packet.
+	UsedOther bool
+}
+
+func (c Conflict) String() string {
+	res := fmt.Sprintf("%s: XMP %q, %s %q", formatName(c.Property), c.XMP, c.Block, c.Other)
+	if c.UsedOther {
+		res += fmt.Sprintf(" (using %s)", c.Block)
+	}
+	return res
+}
+
+// mwgKind describes how a reconciled property is represented in XMP.
+type mwgKind int
+
+const (
+	mwgText mwgKind = iota
+	mwgLangAlt
+	mwgSeq
+	mwgBag
+	mwgDate
+)
+
+// mwgField describes how an XMP property corresponds to EXIF tags and
+// IPTC-IIM datasets.
+type mwgField struct {
+	name xml.Name
+	kind mwgKind
+
+	exif tiffTag // zero if there is no corresponding EXIF tag
+
+	iim     iimTag // zero if there is no corresponding IIM dataset
+	iimTime iimTag // the IIM time dataset, for dates
+}
+
+// mwgFields lists the properties which are reconciled between the
+// metadata blocks of a JPEG file.
+var mwgFields = []mwgField{
+	{
+		name: xml.Name{Space: dcNamespace, Local: "description"},
+		kind: mwgLangAlt,
+		exif: tagImageDescription,
+		iim:  iimCaptionAbstract,
+	},
+	{
+		name: xml.Name{Space: dcNamespace, Local: "rights"},
+		kind: mwgLangAlt,
+		exif: tagCopyright,
+		iim:  iimCopyrightNotice,
+	},
+	{
+		name: xml.Name{Space: dcNamespace, Local: "creator"},
+		kind: mwgSeq,
+		exif: tagArtist,
+		iim:  iimByline,
+	},
+	{
+		name: xml.Name{Space: dcNamespace, Local: "title"},
+		kind: mwgLangAlt,
+		iim:  iimObjectName,
+	},
+	{
+		name: xml.Name{Space: dcNamespace, Local: "subject"},
+		kind: mwgBag,
+		iim:  iimKeywords,
+	},
+	{
+		name:    xml.Name{Space: photoshopNamespace, Local: "DateCreated"},
+		kind:    mwgDate,
+		exif:    tagDateTimeOriginal,
+		iim:     iimDateCreated,
+		iimTime: iimTimeCreated,
+	},
+	{
+		name:    xml.Name{Space: basicNamespace, Local: "CreateDate"},
+		kind:    mwgDate,
+		exif:    tagDateTimeDigitized,
+		iim:     iimDigitalDate,
+		iimTime: iimDigitalTime,
+	},
+	{
+		name: xml.Name{Space: basicNamespace, Local: "ModifyDate"},
+		kind: mwgDate,
+		exif: tagDateTime,
+	},
+	{
+		name: xml.Name{Space: photoshopNamespace, Local: "City"},
+		kind: mwgText,
+		iim:  iimCity,
+	},
+	{
+		name: xml.Name{Space: photoshopNamespace, Local: "State"},
+		kind: mwgText,
+		iim:  iimProvinceState,
+	},
+	{
+		name: xml.Name{Space: photoshopNamespace, Local: "Country"},
+		kind: mwgText,
+		iim:  iimCountryName,
+	},
+	{
+		name: xml.Name{Space: iptcCoreNamespace, Local: "Location"},
+		kind: mwgText,
+		iim:  iimSublocation,
+	},
+}
+
+const dcNamespace = "http://purl.org/dc/elements/1.1/"
+
+// OpenImageMetadata reads the metadata of a JPEG file.
+//
+// The XMP, EXIF and IPTC-IIM blocks are reconciled as recommended by the
+// Metadata Working Group: If the IPTC digest stored in the file matches
+// the IIM data, the IIM block is assumed to be in sync with the XMP data
+// and the XMP values are used.  Otherwise, IIM values which differ from
+// the XMP values are used.  EXIF values which differ from the XMP values
+// are used, unless they are not valid UTF-8.  Values from legacy blocks
+// are always used if the XMP data has no value for a property.  All
+// differences are reported in the Conflicts field of the result.
+func OpenImageMetadata(fname string) (*ImageMetadata, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parseImageMetadata(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+	m.fname = fname
+	return m, nil
+}
+
+// parseImageMetadata implements [OpenImageMetadata].
+func parseImageMetadata(data []byte) (*ImageMetadata, error) {
+	segs, _, err := splitJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ImageMetadata{data: data}
+	var exif map[tiffTag]string
+	var iim map[iimTag][]string
+	iimInSync := false
+	for _, seg := range segs {
+		switch {
+		case seg.isAPP(markerAPP1, jpegXMPHeader) && m.origXMP == nil:
+			xmpData := seg.data[len(jpegXMPHeader):]
+			p, err := ReadBytes(xmpData)
+			if err != nil {
+				return nil, err
+			}
+			m.origXMP = p
+			m.Packet, _ = ReadBytes(xmpData) // a separate copy
+		case seg.isAPP(markerAPP1, jpegEXIFHeader) && exif == nil:
+			var err error
+			exif, err = readEXIF(seg.data[len(jpegEXIFHeader):])
+			if err != nil {
+				m.Warnings = append(m.Warnings, fmt.Errorf("EXIF data ignored: %w", err))
+				exif = map[tiffTag]string{}
+			}
+		case seg.isAPP(markerAPP13, jpegIRBHeader) && iim == nil:
+			resources, err := parseIRB(seg.data[len(jpegIRBHeader):])
+			if err != nil {
+				return nil, err
+			}
+			var raw, digest []byte
+			for _, r := range resources {
+				switch r.id {
+				case irbIPTC:
+					raw = r.data
+				case irbIPTCDigest:
+					digest = r.data
+				}
+			}
+			if raw == nil {
+				continue
+			}
+			datasets, err := parseIIM(raw)
+			if err != nil {
+				return nil, err
+			}
+			iim = iimValues(datasets)
+			iimInSync = bytes.Equal(digest, iimDigest(raw))
+		}
+	}
+
+	if m.Packet == nil {
+		m.Packet = NewPacket()
+	}
+	for _, f := range mwgFields {
+		m.reconcile(f, exif, iim, iimInSync)
+	}
+	return m, nil
+}
+
+// readEXIF reads the ASCII values from the EXIF data of a JPEG file.
+func readEXIF(data []byte) (map[tiffTag]string, error) {
+	t, err := parseTIFF(data)
+	if err != nil {
+		return nil, err
+	}
+	return t.readASCII()
+}
+
+// reconcile determines the value of a single property from the XMP data
+// and the legacy metadata blocks.
+func (m *ImageMetadata) reconcile(f mwgField, exif map[tiffTag]string, iim map[iimTag][]string, iimInSync bool) {
+	xmpVal := f.getXMP(m.Packet)
+
+	var exifVal []string
+	exifValid := false
+	if s, ok := exif[f.exif]; ok && f.exif != (tiffTag{}) && s != "" {
+		exifVal = f.fromEXIF(s)
+		exifValid = utf8.ValidString(s)
+	}
+	var iimVal []string
+	if f.iim != (iimTag{}) {
+		iimVal = f.fromIIM(iim)
+	}
+
+	res := xmpVal
+	source := BlockXMP
+	if iimVal != nil && (xmpVal == nil || !iimInSync && !f.equal(xmpVal, iimVal)) {
+		res = iimVal
+		source = BlockIIM
+	}
+	if exifVal != nil && exifValid && (xmpVal == nil || !f.equal(xmpVal, exifVal)) {
+		res = exifVal
+		source = BlockEXIF
+	}
+
+	if xmpVal != nil {
+		if exifVal != nil && !f.equal(xmpVal, exifVal) {
+			m.Conflicts = append(m.Conflicts, Conflict{
+				Property:  f.name,
+				Block:     BlockEXIF,
+				XMP:       xmpVal,
+				Other:     exifVal,
+				UsedOther: source == BlockEXIF,
+			})
+		}
+		if iimVal != nil && !f.equal(xmpVal, iimVal) {
+			m.Conflicts = append(m.Conflicts, Conflict{
+				Property:  f.name,
+				Block:     BlockIIM,
+				XMP:       xmpVal,
+				Other:     iimVal,
+				UsedOther: source == BlockIIM,
+			})
+		}
+	}
+
+	if source != BlockXMP {
+		var loc *time.Location
+		if source == BlockIIM {
+			loc = iimZone(f, iim)
+		}
+		f.setXMP(m.Packet, res, loc)
+	}
+}
+
+// Save writes the metadata back to the file.  The XMP block is always
+// written.  Existing EXIF and IPTC-IIM blocks are updated to match the
+// XMP data, and the IPTC digest is updated accordingly.  No new EXIF or
+// IPTC-IIM blocks are created.
+//
+// If the file was modified since it was read, [ErrFileChanged] is
+// returned and the file is left unchanged.  In atomic mode, this check is
+// done while holding the lock, so that concurrent updates are never lost.
+//
+// Extended XMP data, used for packets which do not fit into a single
+// JPEG segment, is kept unchanged, and the new main packet refers to it.
+//
+// If opt is nil, default options are used.  In dry-run mode, the file is
+// not modified but changes are still reported to opt.OnChange.
+func (m *ImageMetadata) Save(opt *FileOptions) error {
+	if opt == nil {
+		opt = &FileOptions{}
+	}
+	if m.fname == "" {
+		return errNoFileName
+	}
+
+	var l *fileLock
+	if opt.Atomic && !opt.DryRun {
+		var err error
+		l, err = lockFile(context.Background(), m.fname, opt.lockTimeout())
+		if err != nil {
+			return err
+		}
+		defer l.release()
+	}
+	if !opt.DryRun {
+		cur, err := os.ReadFile(m.fname)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(cur, m.data) {
+			return fmt.Errorf("%s: %w", m.fname, ErrFileChanged)
+		}
+	}
+
+	data, packet, err := m.encode()
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, m.data) {
+		return nil
+	}
+
+	if opt.OnChange != nil {
+		opt.OnChange(&FileChange{
+			FileName:  m.fname,
+			Length:    int64(len(m.data)),
+			NewLength: int64(len(data)),
+			Changes:   Diff(m.origXMP, m.Packet),
+		})
+	}
+	if opt.DryRun {
+		return nil
+	}
+
+	if l == nil {
+		err = os.WriteFile(m.fname, data, 0o666)
+	} else {
+		err = l.commit(data)
+	}
+	if err != nil {
+		return err
+	}
+	m.data = data
+	m.origXMP, err = ReadBytes(packet)
+	return err
+}
+
+// encode returns the contents of the JPEG file with all metadata blocks
+// updated from the XMP packet, together with the serialized XMP packet.
+func (m *ImageMetadata) encode() ([]byte, []byte, error) {
+	segs, rest, err := splitJPEG(m.data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if guid := m.extendedXMP(segs); guid != "" {
+		m.Packet.SetValue(noteNamespace, "HasExtendedXMP", GUID{V: guid})
+	}
+	packet, err := m.Packet.Bytes(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	xmpData := append(append([]byte(nil), jpegXMPHeader...), packet...)
+	if len(xmpData) > maxSegmentData {
+		return nil, nil, errSegmentTooLarge
+	}
+
+	exifValues := make(map[tiffTag]string)
+	iimValues := make(map[iimTag][]string)
+	for _, f := range mwgFields {
+		val := f.getXMP(m.Packet)
+		if f.exif != (tiffTag{}) {
+			exifValues[f.exif] = f.toEXIF(val)
+		}
+		if f.iim != (iimTag{}) {
+			f.toIIM(iimValues, val, m.Packet)
+		}
+	}
+
+	// If the packet is unchanged, the XMP segment is kept as it is, so
+	// that differences in the serialization (e.g. namespace prefixes)
+	// do not cause the file to be rewritten.
+	unchanged := m.origXMP != nil && m.Packet.Equal(m.origXMP)
+
+	hasXMP := false
+	for i, seg := range segs {
+		switch {
+		case seg.isAPP(markerAPP1, jpegXMPHeader):
+			if hasXMP {
+				segs[i].marker = 0 // remove duplicate XMP segments
+				continue
+			}
+			if unchanged {
+				packet = seg.data[len(jpegXMPHeader):]
+			} else {
+				segs[i].data = xmpData
+			}
+			hasXMP = true
+		case seg.isAPP(markerAPP1, jpegEXIFHeader):
+			t, err := parseTIFF(seg.data[len(jpegEXIFHeader):])
+			if err == nil {
+				t, err = t.setASCII(exifValues)
+			}
+			if err != nil {
+				continue // malformed EXIF data is left unchanged
+			}
+			segs[i].data = append(append([]byte(nil), jpegEXIFHeader...), t.data...)
+		case seg.isAPP(markerAPP13, jpegIRBHeader):
+			resources, err := parseIRB(seg.data[len(jpegIRBHeader):])
+			if err != nil {
+				return nil, nil, err
+			}
+			for j, r := range resources {
+				if r.id != irbIPTC {
+					continue
+				}
+				datasets, err := parseIIM(r.data)
+				if err != nil {
+					return nil, nil, err
+				}
+				raw := encodeIIM(setIIMValues(datasets, iimValues))
+				resources[j].data = raw
+				resources = setResource(resources, irbIPTCDigest, iimDigest(raw))
+				break
+			}
+			segs[i].data = append(append([]byte(nil), jpegIRBHeader...), encodeIRB(resources)...)
+		}
+	}
+
+	var out []jpegSegment
+	for _, seg := range segs {
+		if seg.marker != 0 {
+			out = append(out, seg)
+		}
+	}
+	if !hasXMP {
+		// Insert the XMP segment after the JFIF and EXIF segments.
+		pos := 0
+		for pos < len(out) && (out[pos].marker == markerAPP0 || out[pos].marker == markerAPP1) {
+			pos++
+		}
+		out = append(out[:pos], append([]jpegSegment{{marker: markerAPP1, data: xmpData}}, out[pos:]...)...)
+	}
+	data, err := joinJPEG(out, rest)
+	return data, packet, err
+}
+
+// extendedXMP returns the GUID of the extended XMP data referenced by the
+// XMP packet originally read from the file.  If the file contains no
+// such data, the empty string is returned.
+func (m *ImageMetadata) extendedXMP(segs []jpegSegment) string {
+	if m.origXMP == nil {
+		return ""
+	}
+	guid, err := PacketGetValue[GUID](m.origXMP, noteNamespace, "HasExtendedXMP")
+	if err != nil || len(guid.V) != 32 {
+		return ""
+	}
+	for _, seg := range segs {
+		if seg.isAPP(markerAPP1, jpegExtXMPHeader) &&
+			bytes.HasPrefix(seg.data[len(jpegExtXMPHeader):], []byte(guid.V)) {
+			return guid.V
+		}
+	}
+	return ""
+}
+
+// getXMP returns the value of the property in the packet, or nil if the
+// property is not set.
+func (f mwgField) getXMP(p *Packet) []string {
+	raw, ok := p.Properties[f.name]
+	if !ok {
+		return nil
+	}
+
+	var res []string
+	switch f.kind {
+	case mwgDate:
+		txt, ok := raw.(Text)
+		if !ok {
+			return nil
+		}
+		d, _, err := parseMWGDate(txt.V)
+		if err != nil {
+			return nil
+		}
+		return []string{mwgClock(d)}
+	case mwgLangAlt:
+		if a, ok := raw.(RawArray); ok {
+			if idx := defaultIndex(a); idx >= 0 {
+				if txt, ok := a.Value[idx].(Text); ok {
+					res = append(res, txt.V)
+				}
+			}
+		} else if txt, ok := raw.(Text); ok {
+			res = append(res, txt.V)
+		}
+	default:
+		if a, ok := raw.(RawArray); ok {
+			for _, elem := range a.Value {
+				if txt, ok := elem.(Text); ok {
+					res = append(res, txt.V)
+				}
+			}
+		} else if txt, ok := raw.(Text); ok {
+			res = append(res, txt.V)
+		}
+	}
+	if len(res) == 0 || len(res) == 1 && res[0] == "" {
+		return nil
+	}
+	return res
+}
+
+// setXMP stores a value from a legacy block in the packet.  For dates,
+// loc is the time zone given in the legacy block, or nil if this is not
+// known.  Dates without a known time zone are stored without one.
+func (f mwgField) setXMP(p *Packet, val []string, loc *time.Location) {
+	old := p.Properties[f.name]
+	switch f.kind {
+	case mwgText:
+		txt, _ := old.(Text)
+		p.Properties[f.name] = Text{V: val[0], Q: txt.Q}
+	case mwgLangAlt:
+		if _, ok := old.(RawArray); ok {
+			p.Properties[f.name] = unflattenRaw(old, val[0], "")
+		} else {
+			p.Properties[f.name] = RawArray{
+				Kind:  Alternative,
+				Value: []Raw{Text{V: val[0], Q: Q{{Name: nameXMLLang, Value: Text{V: "x-default"}}}}},
+			}
+		}
+	case mwgSeq, mwgBag:
+		kind := Ordered
+		if f.kind == mwgBag {
+			kind = Unordered
+		}
+		res := RawArray{Kind: kind}
+		if a, ok := old.(RawArray); ok {
+			res.Kind = a.Kind
+			res.Q = a.Q
+		}
+		for _, s := range val {
+			res.Value = append(res.Value, Text{V: s})
+		}
+		p.Properties[f.name] = res
+	case mwgDate:
+		var oldQ Q
+		if txt, ok := old.(Text); ok {
+			oldQ = txt.Q
+		}
+		t, err := time.Parse(mwgClockFormat, val[0])
+		if err != nil {
+			if _, err := time.Parse(time.DateOnly, val[0]); err == nil {
+				p.Properties[f.name] = Text{V: val[0], Q: oldQ}
+			}
+			return
+		}
+		if loc == nil {
+			// The time zone is unknown, so none is stored.
+			p.Properties[f.name] = Text{V: val[0], Q: oldQ}
+			return
+		}
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+		p.Properties[f.name] = Date{V: t, NumOmitted: 1, Q: oldQ}.EncodeXMP(p)
+	}
+}
+
+// equal compares two values of the property.  Dates without a time are
+// considered equal to all times on the same day.
+func (f mwgField) equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := a[i], b[i]
+		if f.kind == mwgDate {
+			n := min(len(x), len(y))
+			x, y = x[:n], y[:n]
+		}
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+// fromEXIF converts an EXIF value to the representation used by getXMP.
+func (f mwgField) fromEXIF(s string) []string {
+	switch f.kind {
+	case mwgDate:
+		t, err := time.Parse(exifDateFormat, strings.TrimSpace(s))
+		if err != nil {
+			return nil
+		}
+		return []string{t.Format(mwgClockFormat)}
+	case mwgSeq, mwgBag:
+		var res []string
+		for _, part := range strings.Split(s, ";") {
+			if part = strings.TrimSpace(part); part != "" {
+				res = append(res, part)
+			}
+		}
+		return res
+	default:
+		return []string{s}
+	}
+}
+
+// toEXIF converts a value to the representation used in EXIF.
+func (f mwgField) toEXIF(val []string) string {
+	if val == nil {
+		return ""
+	}
+	switch f.kind {
+	case mwgDate:
+		t, err := time.Parse(mwgClockFormat, val[0])
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, val[0])
+		}
+		if err != nil {
+			return ""
+		}
+		return t.Format(exifDateFormat)
+	case mwgSeq, mwgBag:
+		return strings.Join(val, "; ")
+	default:
+		return val[0]
+	}
+}
+
+// fromIIM converts IIM datasets to the representation used by getXMP.
+func (f mwgField) fromIIM(iim map[iimTag][]string) []string {
+	vals := iim[f.iim]
+	if len(vals) == 0 {
+		return nil
+	}
+	switch f.kind {
+	case mwgDate:
+		day, err := time.Parse("20060102", strings.TrimSpace(vals[0]))
+		if err != nil {
+			return nil
+		}
+		if tv := iim[f.iimTime]; len(tv) > 0 && len(tv[0]) >= 6 {
+			t, err := time.Parse("150405", tv[0][:6])
+			if err == nil {
+				day = day.Add(time.Duration(t.Hour())*time.Hour +
+					time.Duration(t.Minute())*time.Minute +
+					time.Duration(t.Second())*time.Second)
+				return []string{day.Format(mwgClockFormat)}
+			}
+		}
+		return []string{day.Format(time.DateOnly)}
+	case mwgSeq, mwgBag:
+		return vals
+	default:
+		return vals[:1]
+	}
+}
+
+// toIIM stores a value in the IIM datasets.
+func (f mwgField) toIIM(iim map[iimTag][]string, val []string, p *Packet) {
+	if f.kind != mwgDate {
+		iim[f.iim] = val
+		return
+	}
+
+	iim[f.iim] = nil
+	iim[f.iimTime] = nil
+	txt, ok := p.Properties[f.name].(Text)
+	if !ok {
+		return
+	}
+	d, hasZone, err := parseMWGDate(txt.V)
+	if err != nil {
+		return
+	}
+	iim[f.iim] = []string{d.V.Format("20060102")}
+	if d.NumOmitted < 3 && hasZone {
+		iim[f.iimTime] = []string{d.V.Format("150405-0700")}
+	} else if d.NumOmitted < 3 {
+		iim[f.iimTime] = []string{d.V.Format("150405")}
+	}
+}
+
+// iimZone returns the time zone of the IIM time dataset corresponding to
+// the field, or nil if no time zone is given.
+func iimZone(f mwgField, iim map[iimTag][]string) *time.Location {
+	if f.kind != mwgDate || f.iimTime == (iimTag{}) {
+		return nil
+	}
+	tv := iim[f.iimTime]
+	if len(tv) == 0 {
+		return nil
+	}
+	t, err := time.Parse("150405-0700", tv[0])
+	if err != nil {
+		return nil
+	}
+	return t.Location()
+}
+
+// parseMWGDate parses the value of a date property.  In addition to the
+// formats accepted by [Date], times without a time zone are accepted, as
+// stored by setXMP for dates from EXIF data.  The second return value
+// reports whether a time zone was given.
+func parseMWGDate(s string) (Date, bool, error) {
+	if d, err := parseDate(s); err == nil {
+		return d, true, nil
+	}
+	for i, format := range mwgLocalFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return Date{V: t, NumOmitted: i}, false, nil
+		}
+	}
+	return Date{}, false, ErrInvalid
+}
+
+// mwgLocalFormats are the XMP date formats without a time zone, indexed by
+// the number of omitted components as in [Date.NumOmitted].
+var mwgLocalFormats = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+// mwgClock returns the local clock time of a date, as used for comparing
+// dates between metadata blocks.
+func mwgClock(d Date) string {
+	if d.NumOmitted >= 3 {
+		return d.V.Format(time.DateOnly)
+	}
+	return d.V.Format(mwgClockFormat)
+}
+
+const (
+	mwgClockFormat = "2006-01-02T15:04:05"
+	exifDateFormat = "2006:01:02 15:04:05"
+)
+
+var errNoFileName = fmt.Errorf("image metadata: %w", os.ErrInvalid)
+
+// ErrFileChanged is returned by [ImageMetadata.Save] if the file was
+// modified by somebody else after it was read.
+var ErrFileChanged = errors.New("file was modified since it was read")
@@ -0,0 +1,497 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// makeJPEG returns a minimal JPEG file with the given metadata blocks.
+// Blocks which are nil are omitted.  If validDigest is false, the IPTC
+// digest is omitted.
+func makeJPEG(t *testing.T, p *Packet, exif map[tiffTag]string, iim map[iimTag][]string, validDigest bool) []byte {
+	t.Helper()
+
+	segs := []jpegSegment{
+		{marker: markerAPP0, data: []byte("JFIF\x00\x01\x02\x00\x00\x01\x00\x01\x00\x00")},
+	}
+	if exif != nil {
+		tiff, err := newTIFF().setASCII(exif)
+		if err != nil {
+			t.Fatal(err)
+		}
+		segs = append(segs, jpegSegment{marker: markerAPP1, data: append(jpegEXIFHeader, tiff.data...)})
+	}
+	if p != nil {
+		packet, err := p.Bytes(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		segs = append(segs, jpegSegment{marker: markerAPP1, data: append(jpegXMPHeader, packet...)})
+	}
+	if iim != nil {
+		var datasets []iimDataset
+		for _, tag := range iimTagOrder {
+			for _, v := range iim[tag] {
+				datasets = append(datasets, iimDataset{tag.record, tag.dataset, []byte(v)})
+			}
+		}
+		raw := encodeIIM(datasets)
+		resources := []irbResource{{id: irbIPTC, data: raw}}
+		if validDigest {
+			resources = append(resources, irbResource{id: irbIPTCDigest, data: iimDigest(raw)})
+		}
+		segs = append(segs, jpegSegment{marker: markerAPP13, data: append(jpegIRBHeader, encodeIRB(resources)...)})
+	}
+	segs = append(segs, jpegSegment{marker: 0xDB, data: make([]byte, 65)})
+
+	data, err := joinJPEG(segs, []byte{0xFF, markerSOS, 0x00, 0x02, 0x12, 0x34, 0xFF, markerEOI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestImageMetadataReconcile(t *testing.T) {
+	p := NewPacket()
+	dc := &DublinCore{
+		Creator: OrderedArray[ProperName]{V: []ProperName{{V: "Alice"}}},
+		Subject: UnorderedArray[Text]{V: []Text{{V: "sea"}, {V: "boat"}}},
+	}
+	dc.Description.Default = Text{V: "XMP caption"}
+	dc.Rights.Default = Text{V: "(c) Alice"}
+	err := p.Set(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exif := map[tiffTag]string{
+		tagImageDescription: "EXIF caption",
+		tagCopyright:        "(c) Alice",
+		tagArtist:           "Alice",
+		tagDateTimeOriginal: "2024:05:18 17:05:33",
+	}
+	iim := map[iimTag][]string{
+		iimKeywords: {"sea"},
+		iimCity:     {"Edinburgh"},
+	}
+
+	data := makeJPEG(t, p, exif, iim, true)
+	m, err := parseImageMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nameDescription := xml.Name{Space: dcNamespace, Local: "description"}
+	nameSubject := xml.Name{Space: dcNamespace, Local: "subject"}
+	expected := []Conflict{
+		{
+			Property:  nameDescription,
+			Block:     BlockEXIF,
+			XMP:       []string{"XMP caption"},
+			Other:     []string{"EXIF caption"},
+			UsedOther: true,
+		},
+		{
+			Property: nameSubject,
+			Block:    BlockIIM,
+			XMP:      []string{"sea", "boat"},
+			Other:    []string{"sea"},
+		},
+	}
+	if d := cmp.Diff(expected, m.Conflicts); d != "" {
+		t.Errorf("conflicts (-want +got):\n%s", d)
+	}
+
+	dc2 := &DublinCore{}
+	m.Packet.Get(dc2)
+	if got := dc2.Description.Default.V; got != "EXIF caption" {
+		t.Errorf("description: got %q, want %q", got, "EXIF caption")
+	}
+	if got := len(dc2.Subject.V); got != 2 {
+		t.Errorf("subject: got %d keywords, want 2", got)
+	}
+
+	ps := &Photoshop{}
+	m.Packet.Get(ps)
+	if ps.City.V != "Edinburgh" {
+		t.Errorf("city: got %q, want %q", ps.City.V, "Edinburgh")
+	}
+	// EXIF dates have no time zone, so none must be added.
+	dateCreated := m.Packet.Properties[xml.Name{Space: photoshopNamespace, Local: "DateCreated"}]
+	if d := cmp.Diff(Raw(Text{V: "2024-05-18T17:05:33"}), dateCreated); d != "" {
+		t.Errorf("date created (-want +got):\n%s", d)
+	}
+}
+
+// TestImageMetadataDigest checks that IIM values are used if the IPTC
+// digest shows that the IIM data was modified without updating the XMP
+// data.
+func TestImageMetadataDigest(t *testing.T) {
+	p := NewPacket()
+	p.SetValue(photoshopNamespace, "City", Text{V: "Paris"})
+	iim := map[iimTag][]string{
+		iimCity: {"London"},
+	}
+
+	for _, validDigest := range []bool{true, false} {
+		data := makeJPEG(t, p, nil, iim, validDigest)
+		m, err := parseImageMetadata(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(m.Conflicts) != 1 || m.Conflicts[0].UsedOther == validDigest {
+			t.Errorf("validDigest=%t: unexpected conflicts %v", validDigest, m.Conflicts)
+		}
+
+		ps := &Photoshop{}
+		m.Packet.Get(ps)
+		want := "London"
+		if validDigest {
+			want = "Paris"
+		}
+		if ps.City.V != want {
+			t.Errorf("validDigest=%t: got %q, want %q", validDigest, ps.City.V, want)
+		}
+	}
+}
+
+// TestImageMetadataSave checks that all metadata blocks are in sync after
+// saving.
+func TestImageMetadataSave(t *testing.T) {
+	exif := map[tiffTag]string{
+		tagImageDescription: "old",
+		tagArtist:           "Alice; Bob",
+	}
+	iim := map[iimTag][]string{
+		iimCaptionAbstract: {"old"},
+		iimByline:          {"Alice", "Bob"},
+	}
+	data := makeJPEG(t, nil, exif, iim, false)
+
+	fname := filepath.Join(t.TempDir(), "test.jpg")
+	err := os.WriteFile(fname, data, 0o666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := OpenImageMetadata(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Conflicts) != 0 {
+		t.Errorf("unexpected conflicts: %v", m.Conflicts)
+	}
+
+	dc := &DublinCore{}
+	m.Packet.Get(dc)
+	if d := cmp.Diff([]ProperName{{V: "Alice"}, {V: "Bob"}}, dc.Creator.V); d != "" {
+		t.Errorf("creator (-want +got):\n%s", d)
+	}
+	dc.Description = Localized{Default: Text{V: "Grüße"}}
+	dc.Creator.V = dc.Creator.V[1:]
+	err = m.Packet.Set(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Packet.SetValue(photoshopNamespace, "DateCreated",
+		NewDate(time.Date(2024, 5, 18, 17, 5, 33, 0, time.FixedZone("", 3600))))
+
+	var changes []*FileChange
+	err = m.Save(&FileOptions{
+		Atomic:   true,
+		OnChange: func(c *FileChange) { changes = append(changes, c) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("got %d change reports, want 1", len(changes))
+	}
+
+	m2, err := OpenImageMetadata(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m2.Conflicts) != 0 {
+		t.Errorf("unexpected conflicts after saving: %v", m2.Conflicts)
+	}
+
+	// Saving again without changes must leave the file untouched.
+	before, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes = nil
+	for range 3 {
+		err = m2.Save(&FileOptions{
+			OnChange: func(c *FileChange) { changes = append(changes, c) },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	after, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 || !bytes.Equal(before, after) {
+		t.Errorf("no-op save changed the file (%d to %d bytes)", len(before), len(after))
+	}
+
+	// check the legacy blocks directly
+	data, err = os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segs, rest, err := splitJPEG(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 8 {
+		t.Errorf("image data changed")
+	}
+	for _, seg := range segs {
+		switch {
+		case seg.isAPP(markerAPP1, jpegEXIFHeader):
+			tiff, err := parseTIFF(seg.data[len(jpegEXIFHeader):])
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tiff.readASCII()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[tiffTag]string{
+				tagImageDescription: "Grüße",
+				tagArtist:           "Bob",
+				tagDateTimeOriginal: "2024:05:18 17:05:33",
+			}
+			if d := cmp.Diff(want, got, cmp.AllowUnexported(tiffTag{})); d != "" {
+				t.Errorf("EXIF (-want +got):\n%s", d)
+			}
+		case seg.isAPP(markerAPP13, jpegIRBHeader):
+			resources, err := parseIRB(seg.data[len(jpegIRBHeader):])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resources) != 2 {
+				t.Fatalf("got %d resources, want 2", len(resources))
+			}
+			datasets, err := parseIIM(resources[0].data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := iimValues(datasets)
+			want := map[iimTag][]string{
+				iimCodedCharset:    {iimUTF8Charset},
+				iimCaptionAbstract: {"Grüße"},
+				iimByline:          {"Bob"},
+				iimDateCreated:     {"20240518"},
+				iimTimeCreated:     {"170533+0100"},
+			}
+			if d := cmp.Diff(want, got, cmp.AllowUnexported(iimTag{})); d != "" {
+				t.Errorf("IIM (-want +got):\n%s", d)
+			}
+		}
+	}
+}
+
+func TestOpenImageMetadataNotJPEG(t *testing.T) {
+	_, err := parseImageMetadata([]byte("GIF89a"))
+	if err != errNotJPEG {
+		t.Errorf("got %v, want %v", err, errNotJPEG)
+	}
+}
+
+// TestImageMetadataBadEXIF checks that malformed EXIF data is ignored,
+// both when reading and when writing the file.
+func TestImageMetadataBadEXIF(t *testing.T) {
+	iim := map[iimTag][]string{
+		iimCity: {"Edinburgh"},
+	}
+	data := makeJPEG(t, nil, nil, iim, false)
+	segs, tail, err := splitJPEG(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badEXIF := append(append([]byte(nil), jpegEXIFHeader...), "MM\x00\x2a\xff\xff\xff\xff"...)
+	segs = append([]jpegSegment{{marker: markerAPP1, data: badEXIF}}, segs...)
+	data, err = joinJPEG(segs, tail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := parseImageMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Warnings) != 1 {
+		t.Errorf("got %d warnings, want 1: %v", len(m.Warnings), m.Warnings)
+	}
+	ps := &Photoshop{}
+	m.Packet.Get(ps)
+	if ps.City.V != "Edinburgh" {
+		t.Errorf("city: got %q, want %q", ps.City.V, "Edinburgh")
+	}
+
+	out, _, err := m.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	segs, _, err = splitJPEG(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, seg := range segs {
+		if bytes.Equal(seg.data, badEXIF) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("malformed EXIF segment was not preserved")
+	}
+}
+
+func TestImageMetadataSaveConcurrent(t *testing.T) {
+	data := makeJPEG(t, nil, nil, map[iimTag][]string{iimCity: {"Paris"}}, true)
+	fname := filepath.Join(t.TempDir(), "test.jpg")
+	err := os.WriteFile(fname, data, 0o666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := OpenImageMetadata(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another writer modifies the file
+	other, err := OpenImageMetadata(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Packet.SetValue(photoshopNamespace, "City", NewText("London"))
+	err = other.Save(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, atomic := range []bool{false, true} {
+		m.Packet.SetValue(photoshopNamespace, "City", NewText("Berlin"))
+		err = m.Save(&FileOptions{Atomic: atomic})
+		if !errors.Is(err, ErrFileChanged) {
+			t.Errorf("atomic=%t: got error %v, want %v", atomic, err, ErrFileChanged)
+		}
+	}
+	got, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("concurrent update was overwritten")
+	}
+}
+
+func TestImageMetadataExtendedXMP(t *testing.T) {
+	ext := NewPacket()
+	ext.SetValue(photoshopNamespace, "History", NewText("long history"))
+	extData, err := ext.Bytes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guid := ExtendedXMPGUID(extData)
+
+	p := NewPacket()
+	err = p.Set(&Note{HasExtendedXMP: guid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := makeJPEG(t, p, nil, nil, false)
+	segs, rest, err := splitJPEG(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extSeg := append([]byte(nil), jpegExtXMPHeader...)
+	extSeg = append(extSeg, guid.V...)
+	extSeg = binary.BigEndian.AppendUint32(extSeg, uint32(len(extData)))
+	extSeg = binary.BigEndian.AppendUint32(extSeg, 0)
+	extSeg = append(extSeg, extData...)
+	segs = append(segs[:2], append([]jpegSegment{{marker: markerAPP1, data: extSeg}}, segs[2:]...)...)
+	data, err = joinJPEG(segs, rest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fname := filepath.Join(t.TempDir(), "test.jpg")
+	err = os.WriteFile(fname, data, 0o666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := OpenImageMetadata(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Packet.Reset()
+	m.Packet.SetValue(photoshopNamespace, "City", NewText("Paris"))
+	err = m.Save(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err = os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segs, _, err = splitJPEG(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foundExt := false
+	for _, seg := range segs {
+		switch {
+		case seg.isAPP(markerAPP1, jpegExtXMPHeader):
+			foundExt = bytes.Equal(seg.data, extSeg)
+		case seg.isAPP(markerAPP1, jpegXMPHeader):
+			main, err := ReadBytes(seg.data[len(jpegXMPHeader):])
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := PacketGetValue[GUID](main, noteNamespace, "HasExtendedXMP")
+			if err != nil || got.V != guid.V {
+				t.Errorf("main packet refers to %q, want %q", got.V, guid.V)
+			}
+		}
+	}
+	if !foundExt {
+		t.Error("extended XMP segment not preserved")
+	}
+}
@@ -0,0 +1,91 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// MWGCollections represents the collections namespace of the Metadata
+// Working Group.  This records the albums or collections an image belongs
+// to.
+//
+// See section 5.10 of the MWG Guidelines for Handling Image Metadata,
+// version 2.0 (2010).
+type MWGCollections struct {
+	_ Namespace `xmp:"http://www.metadataworkinggroup.com/schemas/collections/"`
+	_ Prefix    `xmp:"mwg-coll"`
+
+	// Collections lists the collections the resource belongs to.
+	Collections UnorderedArray[CollectionInfo]
+}
+
+// Add adds the resource to a collection.  If the collection is already
+// listed, with the same name and URI, the model is not changed.
+func (m *MWGCollections) Add(name, uri string) {
+	for _, c := range m.Collections.V {
+		if c.CollectionName.V == name && c.CollectionURI.V == uri {
+			return
+		}
+	}
+	m.Collections.Append(CollectionInfo{
+		CollectionName: NewText(name),
+		CollectionURI:  NewURI(uri),
+	})
+}
+
+// CollectionInfo identifies a collection.
+//
+// This is the CollectionInfo structure from the MWG collections schema.
+type CollectionInfo struct {
+	// CollectionName is the name of the collection, for display to users.
+	CollectionName Text
+
+	// CollectionURI is a URI which uniquely identifies the collection.
+	CollectionURI URI
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (c CollectionInfo) IsZero() bool {
+	return c.CollectionName.IsZero() && c.CollectionURI.IsZero() && len(c.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (c CollectionInfo) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     c.Q,
+	}
+	setField(res, p, mwgCollNamespace, "CollectionName", c.CollectionName)
+	setField(res, p, mwgCollNamespace, "CollectionURI", c.CollectionURI)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (CollectionInfo) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return CollectionInfo{
+		CollectionName: getField[Text](s, mwgCollNamespace, "CollectionName"),
+		CollectionURI:  getField[URI](s, mwgCollNamespace, "CollectionURI"),
+		Q:              s.Q,
+	}, nil
+}
+
+const mwgCollNamespace = "http://www.metadataworkinggroup.com/schemas/collections/"
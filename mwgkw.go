@@ -0,0 +1,180 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// MWGKeywords represents the hierarchical keywords namespace of the
+// Metadata Working Group.
+//
+// See section 5.11 of the MWG Guidelines for Handling Image Metadata,
+// version 2.0 (2010).
+type MWGKeywords struct {
+	_ Namespace `xmp:"http://www.metadataworkinggroup.com/schemas/keywords/"`
+	_ Prefix    `xmp:"mwg-kw"`
+
+	// Keywords holds the keyword hierarchy.
+	Keywords KeywordInfo
+}
+
+// Add adds a keyword, given as the path from the root of the hierarchy to
+// the keyword.  Missing intermediate nodes are created, and the last node
+// is marked as applied.
+func (m *MWGKeywords) Add(path ...string) {
+	if len(path) == 0 {
+		return
+	}
+	m.Keywords.Hierarchy.V = addKeyword(m.Keywords.Hierarchy.V, path)
+}
+
+func addKeyword(nodes []KeywordStruct, path []string) []KeywordStruct {
+	idx := -1
+	for i, n := range nodes {
+		if n.Keyword.V == path[0] {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		idx = len(nodes)
+		nodes = append(nodes, KeywordStruct{Keyword: NewText(path[0])})
+	}
+
+	n := &nodes[idx]
+	if len(path) == 1 {
+		n.Applied = OptionalBool{V: 2}
+	} else {
+		n.Children.V = addKeyword(n.Children.V, path[1:])
+	}
+	return nodes
+}
+
+// Applied returns the paths of all keywords which have been applied to the
+// resource.  Each path starts at the root of the hierarchy.
+func (m *MWGKeywords) Applied() [][]string {
+	var res [][]string
+	var walk func(nodes []KeywordStruct, prefix []string)
+	walk = func(nodes []KeywordStruct, prefix []string) {
+		for _, n := range nodes {
+			path := append(prefix[:len(prefix):len(prefix)], n.Keyword.V)
+			if n.IsApplied() {
+				res = append(res, path)
+			}
+			walk(n.Children.V, path)
+		}
+	}
+	walk(m.Keywords.Hierarchy.V, nil)
+	return res
+}
+
+// KeywordInfo holds a keyword hierarchy.
+//
+// This is the KeywordInfo structure from the MWG keywords schema.
+type KeywordInfo struct {
+	// Hierarchy lists the root nodes of the keyword hierarchy.
+	Hierarchy UnorderedArray[KeywordStruct]
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (k KeywordInfo) IsZero() bool {
+	return k.Hierarchy.IsZero() && len(k.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (k KeywordInfo) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     k.Q,
+	}
+	setField(res, p, mwgKWNamespace, "Hierarchy", k.Hierarchy)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (KeywordInfo) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return KeywordInfo{
+		Hierarchy: getField[UnorderedArray[KeywordStruct]](s, mwgKWNamespace, "Hierarchy"),
+		Q:         s.Q,
+	}, nil
+}
+
+// KeywordStruct is a node in a keyword hierarchy.
+//
+// This is the KeywordStruct structure from the MWG keywords schema.
+type KeywordStruct struct {
+	// Keyword is the name of the node.
+	Keyword Text
+
+	// Applied indicates whether the keyword has been applied to the
+	// resource.  See [KeywordStruct.IsApplied] for the meaning of an
+	// unset value.
+	Applied OptionalBool
+
+	// Children lists the child nodes.
+	Children UnorderedArray[KeywordStruct]
+
+	Q
+}
+
+// IsApplied reports whether the keyword has been applied to the resource.
+// If Applied is not set, leaf nodes are considered to be applied, and
+// all other nodes are not.
+func (k KeywordStruct) IsApplied() bool {
+	if k.Applied.V == 0 {
+		return len(k.Children.V) == 0
+	}
+	return k.Applied.IsTrue()
+}
+
+// IsZero implements the [Value] interface.
+func (k KeywordStruct) IsZero() bool {
+	return k.Keyword.IsZero() && k.Applied.IsZero() && k.Children.IsZero() && len(k.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (k KeywordStruct) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     k.Q,
+	}
+	setField(res, p, mwgKWNamespace, "Keyword", k.Keyword)
+	setField(res, p, mwgKWNamespace, "Applied", k.Applied)
+	setField(res, p, mwgKWNamespace, "Children", k.Children)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (KeywordStruct) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return KeywordStruct{
+		Keyword:  getField[Text](s, mwgKWNamespace, "Keyword"),
+		Applied:  getField[OptionalBool](s, mwgKWNamespace, "Applied"),
+		Children: getField[UnorderedArray[KeywordStruct]](s, mwgKWNamespace, "Children"),
+		Q:        s.Q,
+	}, nil
+}
+
+const mwgKWNamespace = "http://www.metadataworkinggroup.com/schemas/keywords/"
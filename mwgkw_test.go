@@ -0,0 +1,82 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMWGKeywords(t *testing.T) {
+	in := &MWGKeywords{}
+	in.Add("Places", "Europe", "Scotland")
+	in.Add("Places", "Europe", "Wales")
+	in.Add("People", "Ada")
+	in.Add("Places", "Europe", "Scotland")
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `mwg-kw:Keyword="Scotland"`) {
+		t.Errorf("wrong encoding:\n%s", buf.String())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &MWGKeywords{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+
+	expected := [][]string{
+		{"Places", "Europe", "Scotland"},
+		{"Places", "Europe", "Wales"},
+		{"People", "Ada"},
+	}
+	if d := cmp.Diff(expected, out.Applied()); d != "" {
+		t.Errorf("applied keywords differ (-want +got):\n%s", d)
+	}
+}
+
+func TestKeywordApplied(t *testing.T) {
+	leaf := KeywordStruct{Keyword: NewText("leaf")}
+	if !leaf.IsApplied() {
+		t.Error("leaf without Applied flag should be applied")
+	}
+	parent := KeywordStruct{Keyword: NewText("parent")}
+	parent.Children.Append(leaf)
+	if parent.IsApplied() {
+		t.Error("parent without Applied flag should not be applied")
+	}
+	parent.Applied = OptionalBool{V: 2}
+	if !parent.IsApplied() {
+		t.Error("parent with Applied=True should be applied")
+	}
+}
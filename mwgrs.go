@@ -0,0 +1,236 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// MWGRegions represents the region namespace of the Metadata Working Group.
+//
+// Regions identify parts of an image, for example faces found by face
+// detection, the focus area of the camera, or barcodes.
+//
+// See section 5.9 of the MWG Guidelines for Handling Image Metadata,
+// version 2.0 (2010).
+type MWGRegions struct {
+	_ Namespace `xmp:"http://www.metadataworkinggroup.com/schemas/regions/"`
+	_ Prefix    `xmp:"mwg-rs"`
+
+	// Regions describes the regions of the image.
+	Regions RegionInfo
+}
+
+// Region types defined by the MWG guidelines.
+const (
+	RegionTypeFace    = "Face"
+	RegionTypePet     = "Pet"
+	RegionTypeFocus   = "Focus"
+	RegionTypeBarCode = "BarCode"
+)
+
+// RegionInfo lists the regions of an image.
+//
+// This is the RegionInfo structure from the MWG region schema, used by
+// the mwg-rs:Regions property.
+type RegionInfo struct {
+	// AppliedToDimensions gives the size of the image at the time the
+	// regions were determined.  This allows to detect whether the image was
+	// resized or cropped since.
+	AppliedToDimensions Dimensions
+
+	// RegionList is the list of regions.
+	RegionList UnorderedArray[Region]
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (r RegionInfo) IsZero() bool {
+	return r.AppliedToDimensions.IsZero() && r.RegionList.IsZero() && len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r RegionInfo) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     r.Q,
+	}
+	setField(res, p, mwgRSNamespace, "AppliedToDimensions", r.AppliedToDimensions)
+	setField(res, p, mwgRSNamespace, "RegionList", r.RegionList)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (RegionInfo) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return RegionInfo{
+		AppliedToDimensions: getField[Dimensions](s, mwgRSNamespace, "AppliedToDimensions"),
+		RegionList:          getField[UnorderedArray[Region]](s, mwgRSNamespace, "RegionList"),
+		Q:                   s.Q,
+	}, nil
+}
+
+// Region describes a single region of an image.
+//
+// This is the RegionStruct structure from the MWG region schema.
+type Region struct {
+	// Area gives the position and size of the region.
+	Area Area
+
+	// Type is the type of the region, for example [RegionTypeFace].
+	Type Text
+
+	// Name is the name of the region, for example the name of the person
+	// whose face is shown.
+	Name Text
+
+	// Description is a description of the region.
+	Description Text
+
+	// FocusUsage describes how a focus region was used by the camera:
+	// "EvaluatedUsed", "EvaluatedNotUsed", or "NotEvaluatedNotUsed".
+	FocusUsage Text
+
+	// BarCodeValue is the decoded value of a barcode region.
+	BarCodeValue Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (r Region) IsZero() bool {
+	return r.Area.IsZero() && r.Type.IsZero() && r.Name.IsZero() &&
+		r.Description.IsZero() && r.FocusUsage.IsZero() &&
+		r.BarCodeValue.IsZero() && len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r Region) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     r.Q,
+	}
+	setField(res, p, mwgRSNamespace, "Area", r.Area)
+	setField(res, p, mwgRSNamespace, "Type", r.Type)
+	setField(res, p, mwgRSNamespace, "Name", r.Name)
+	setField(res, p, mwgRSNamespace, "Description", r.Description)
+	setField(res, p, mwgRSNamespace, "FocusUsage", r.FocusUsage)
+	setField(res, p, mwgRSNamespace, "BarCodeValue", r.BarCodeValue)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Region) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Region{
+		Area:         getField[Area](s, mwgRSNamespace, "Area"),
+		Type:         getField[Text](s, mwgRSNamespace, "Type"),
+		Name:         getField[Text](s, mwgRSNamespace, "Name"),
+		Description:  getField[Text](s, mwgRSNamespace, "Description"),
+		FocusUsage:   getField[Text](s, mwgRSNamespace, "FocusUsage"),
+		BarCodeValue: getField[Text](s, mwgRSNamespace, "BarCodeValue"),
+		Q:            s.Q,
+	}, nil
+}
+
+// Area describes a rectangular or circular area, or a point, in an image.
+//
+// This is the Area structure from the MWG region schema.  If Unit is
+// "normalized", coordinates are relative to the image size, with (0, 0) at
+// the top-left corner and (1, 1) at the bottom-right corner.  X and Y give
+// the center of the area.
+type Area struct {
+	// X and Y give the center of the area.
+	X, Y Real
+
+	// W and H give the width and height of a rectangular area.
+	W, H Real
+
+	// D gives the diameter of a circular area.
+	D Real
+
+	// Unit is the unit of the coordinates, normally "normalized".
+	Unit Text
+
+	Q
+}
+
+// NewArea returns a normalized rectangular area, given the position of the
+// top-left corner and the size of the rectangle.
+func NewArea(left, top, width, height float64) Area {
+	return Area{
+		X:    Real{V: left + width/2},
+		Y:    Real{V: top + height/2},
+		W:    Real{V: width},
+		H:    Real{V: height},
+		Unit: Text{V: "normalized"},
+	}
+}
+
+// Bounds returns the position of the top-left corner and the size of a
+// rectangular area.
+func (a Area) Bounds() (left, top, width, height float64) {
+	return a.X.V - a.W.V/2, a.Y.V - a.H.V/2, a.W.V, a.H.V
+}
+
+// IsZero implements the [Value] interface.
+func (a Area) IsZero() bool {
+	return a.X.IsZero() && a.Y.IsZero() && a.W.IsZero() && a.H.IsZero() &&
+		a.D.IsZero() && a.Unit.IsZero() && len(a.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (a Area) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     a.Q,
+	}
+	setField(res, p, stAreaNamespace, "x", a.X)
+	setField(res, p, stAreaNamespace, "y", a.Y)
+	setField(res, p, stAreaNamespace, "w", a.W)
+	setField(res, p, stAreaNamespace, "h", a.H)
+	setField(res, p, stAreaNamespace, "d", a.D)
+	setField(res, p, stAreaNamespace, "unit", a.Unit)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Area) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Area{
+		X:    getField[Real](s, stAreaNamespace, "x"),
+		Y:    getField[Real](s, stAreaNamespace, "y"),
+		W:    getField[Real](s, stAreaNamespace, "w"),
+		H:    getField[Real](s, stAreaNamespace, "h"),
+		D:    getField[Real](s, stAreaNamespace, "d"),
+		Unit: getField[Text](s, stAreaNamespace, "unit"),
+		Q:    s.Q,
+	}, nil
+}
+
+const (
+	mwgRSNamespace  = "http://www.metadataworkinggroup.com/schemas/regions/"
+	stAreaNamespace = "http://ns.adobe.com/xmp/sType/Area#"
+)
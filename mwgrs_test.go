@@ -0,0 +1,80 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMWGRegions(t *testing.T) {
+	in := &MWGRegions{
+		Regions: RegionInfo{
+			AppliedToDimensions: Dimensions{
+				W:    Real{V: 4000},
+				H:    Real{V: 3000},
+				Unit: NewText("pixel"),
+			},
+			RegionList: UnorderedArray[Region]{V: []Region{
+				{
+					Area: NewArea(0.25, 0.125, 0.25, 0.5),
+					Type: NewText(RegionTypeFace),
+					Name: NewText("Jane Doe"),
+				},
+				{
+					Area:       Area{X: Real{V: 0.5}, Y: Real{V: 0.5}, D: Real{V: 0.1}, Unit: NewText("normalized")},
+					Type:       NewText(RegionTypeFocus),
+					FocusUsage: NewText("EvaluatedUsed"),
+				},
+			}},
+		},
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`stArea:x=".375"`, `stDim:w="4000"`, `<mwg-rs:Type>Face</mwg-rs:Type>`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("missing %q in encoding:\n%s", s, buf.String())
+		}
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &MWGRegions{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+
+	left, top, width, height := out.Regions.RegionList.V[0].Area.Bounds()
+	if left != 0.25 || top != 0.125 || width != 0.25 || height != 0.5 {
+		t.Errorf("wrong bounds %g, %g, %g, %g", left, top, width, height)
+	}
+}
@@ -0,0 +1,221 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+)
+
+// Best returns the entry of l which best matches the given language
+// preferences, in order of decreasing preference, together with the
+// language of the entry.
+//
+// If none of the languages matches, the global fallback chain set by
+// [SetFallback] is tried.  If this gives no match either, the default
+// value is returned with language "x-default".  If l has no default value,
+// the entry with the alphabetically first language tag is used.
+func (l Localized) Best(prefs ...language.Tag) (Text, language.Tag) {
+	if len(l.V) > 0 && len(prefs) > 0 {
+		langs := l.languages()
+		m := language.NewMatcher(langs)
+		_, idx, conf := m.Match(prefs...)
+		if conf != language.No {
+			return l.V[langs[idx]], langs[idx]
+		}
+	}
+	if txt, lang, ok := l.lookup(getFallback()); ok {
+		return txt, lang
+	}
+	return l.last()
+}
+
+// Lookup returns the first entry of l whose language occurs in the given
+// fallback chain, for example de-AT, de, en, x-default.  Unlike
+// [Localized.Best], only exact matches are considered.  If chain is empty,
+// the global fallback chain set by [SetFallback] is used.
+//
+// If none of the languages in the chain is present, the default value is
+// returned with language "x-default".  If l has no default value, the entry
+// with the alphabetically first language tag is used.
+func (l Localized) Lookup(chain ...language.Tag) (Text, language.Tag) {
+	if len(chain) == 0 {
+		chain = getFallback()
+	}
+	if txt, lang, ok := l.lookup(chain); ok {
+		return txt, lang
+	}
+	return l.last()
+}
+
+// lookup returns the first entry of l whose language occurs in chain.
+func (l Localized) lookup(chain []language.Tag) (Text, language.Tag, bool) {
+	for _, lang := range chain {
+		if lang == defaultLanguage {
+			if l.Default.V != "" {
+				return l.Default, defaultLanguage, true
+			}
+			continue
+		}
+		if txt, ok := l.V[lang]; ok {
+			return txt, lang, true
+		}
+	}
+	return Text{}, language.Und, false
+}
+
+// last returns the value used when no language matches: the default value
+// if present, and the entry with the alphabetically first language tag
+// otherwise.
+func (l Localized) last() (Text, language.Tag) {
+	if l.Default.V != "" {
+		return l.Default, defaultLanguage
+	}
+	if len(l.V) == 0 {
+		return Text{}, language.Und
+	}
+	first := l.languages()[0]
+	return l.V[first], first
+}
+
+// languages returns the languages of l in alphabetical order.
+func (l Localized) languages() []language.Tag {
+	langs := make([]language.Tag, 0, len(l.V))
+	for lang := range l.V {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		return langs[i].String() < langs[j].String()
+	})
+	return langs
+}
+
+// SetFallback sets the global language fallback chain.  The chain is used
+// by [Localized.Best], [Localized.Lookup] and [Packet.Localize] when none
+// of the requested languages is available.  Use "x-default" in the chain
+// to select the default value at a given position.
+//
+// Use [FallbackChain] to construct a chain from a language tag.
+func SetFallback(chain ...language.Tag) {
+	c := slices.Clone(chain)
+	fallback.Store(&c)
+}
+
+var fallback atomic.Pointer[[]language.Tag]
+
+// getFallback returns the global language fallback chain.
+func getFallback() []language.Tag {
+	c := fallback.Load()
+	if c == nil {
+		return nil
+	}
+	return *c
+}
+
+// FallbackChain returns a fallback chain which starts with lang and then
+// proceeds through the shorter tags obtained by removing subtags from the
+// end of lang, followed by the given tail.  For example,
+// FallbackChain(de-AT, en, x-default) gives the chain de-AT, de, en,
+// x-default, and zh-Hant-TW is followed by zh-Hant and zh.
+//
+// Unlike [language.Tag.Parent], no CLDR inheritance rules are applied, so
+// that en-GB is followed by en and not by en-001.
+func FallbackChain(lang language.Tag, tail ...language.Tag) []language.Tag {
+	var res []language.Tag
+	if !lang.IsRoot() {
+		subtags := strings.Split(lang.String(), "-")
+		for n := len(subtags); n > 0; n-- {
+			t, err := language.Parse(strings.Join(subtags[:n], "-"))
+			if err != nil || slices.Contains(res, t) {
+				continue
+			}
+			res = append(res, t)
+		}
+	}
+	for _, t := range tail {
+		if !slices.Contains(res, t) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// LocalizedText is a text value resolved to a single language.
+type LocalizedText struct {
+	Text string
+	Lang language.Tag
+}
+
+// Localize resolves all language alternative properties in the packet to
+// the best match for the language preferences in an HTTP Accept-Language
+// header.  The result maps property names to the selected texts.
+//
+// If the header cannot be parsed or none of the languages match, the global
+// fallback chain set by [SetFallback] is used, followed by the default
+// values of the properties.  Properties which are not language
+// alternatives are omitted.
+func (p *Packet) Localize(acceptLanguage string) map[xml.Name]LocalizedText {
+	prefs, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		prefs = nil
+	}
+
+	res := make(map[xml.Name]LocalizedText)
+	for name, raw := range p.Properties {
+		if !isLangAlt(raw) {
+			continue
+		}
+		v, err := Localized{}.DecodeAnother(raw)
+		if err != nil {
+			continue
+		}
+		txt, lang := v.(Localized).Best(prefs...)
+		res[name] = LocalizedText{Text: txt.V, Lang: lang}
+	}
+	return res
+}
+
+// isLangAlt checks whether val is a language alternative, i.e. an
+// alternative array of text values which all have a language qualifier.
+func isLangAlt(val Raw) bool {
+	a, ok := val.(RawArray)
+	if !ok || a.Kind != Alternative || len(a.Value) == 0 {
+		return false
+	}
+	for _, elem := range a.Value {
+		t, ok := elem.(Text)
+		if !ok {
+			return false
+		}
+		hasLang := false
+		for _, q := range t.Q {
+			if q.Name == nameXMLLang {
+				hasLang = true
+				break
+			}
+		}
+		if !hasLang {
+			return false
+		}
+	}
+	return true
+}
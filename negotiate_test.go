@@ -0,0 +1,167 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
+)
+
+func TestLocalizedBest(t *testing.T) {
+	l := Localized{Default: NewText("Colour")}
+	l.Set(language.BritishEnglish, "Colour")
+	l.Set(language.AmericanEnglish, "Color")
+	l.Set(language.German, "Farbe")
+
+	type testCase struct {
+		prefs    []language.Tag
+		wantText string
+		wantLang language.Tag
+	}
+	cases := []testCase{
+		{nil, "Colour", defaultLanguage},
+		{[]language.Tag{language.German}, "Farbe", language.German},
+		{[]language.Tag{language.MustParse("de-CH")}, "Farbe", language.German},
+		{[]language.Tag{language.AmericanEnglish}, "Color", language.AmericanEnglish},
+		{[]language.Tag{language.Japanese}, "Colour", defaultLanguage},
+		{[]language.Tag{language.Japanese, language.German}, "Farbe", language.German},
+	}
+	for _, c := range cases {
+		txt, lang := l.Best(c.prefs...)
+		if txt.V != c.wantText || lang != c.wantLang {
+			t.Errorf("%v: got %q (%v), want %q (%v)",
+				c.prefs, txt.V, lang, c.wantText, c.wantLang)
+		}
+	}
+
+	noDefault := Localized{}
+	noDefault.Set(language.French, "Couleur")
+	noDefault.Set(language.German, "Farbe")
+	txt, lang := noDefault.Best(language.Japanese)
+	if txt.V != "Farbe" || lang != language.German {
+		t.Errorf("got %q (%v), want first language", txt.V, lang)
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	dc := &DublinCore{}
+	dc.Title.Default = NewText("Harbour")
+	dc.Title.Set(language.German, "Hafen")
+	dc.Description.Set(language.French, "Des bateaux")
+	dc.Subject.Append(NewText("boats"))
+
+	p := NewPacket()
+	err := p.Set(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := p.Localize("de-DE,de;q=0.9,en;q=0.5")
+	want := map[xml.Name]LocalizedText{
+		{Space: "http://purl.org/dc/elements/1.1/", Local: "title"}: {
+			Text: "Hafen",
+			Lang: language.German,
+		},
+		{Space: "http://purl.org/dc/elements/1.1/", Local: "description"}: {
+			Text: "Des bateaux",
+			Lang: language.French,
+		},
+	}
+	if d := cmp.Diff(want, got, cmp.Comparer(func(a, b language.Tag) bool { return a == b })); d != "" {
+		t.Errorf("wrong result (-want +got):\n%s", d)
+	}
+
+	got = p.Localize("invalid;;header")
+	if txt := got[xml.Name{Space: "http://purl.org/dc/elements/1.1/", Local: "title"}]; txt.Text != "Harbour" {
+		t.Errorf("got %q, want default value", txt.Text)
+	}
+}
+
+func TestLocalizedLookup(t *testing.T) {
+	deAT := language.MustParse("de-AT")
+
+	l := Localized{Default: NewText("Colour")}
+	l.Set(language.German, "Farbe")
+	l.Set(language.English, "Color")
+
+	type testCase struct {
+		chain    []language.Tag
+		wantText string
+		wantLang language.Tag
+	}
+	cases := []testCase{
+		{nil, "Colour", defaultLanguage},
+		{FallbackChain(deAT, language.English, defaultLanguage), "Farbe", language.German},
+		{[]language.Tag{language.French, language.English}, "Color", language.English},
+		{[]language.Tag{language.French, defaultLanguage, language.English}, "Colour", defaultLanguage},
+		{[]language.Tag{language.French}, "Colour", defaultLanguage},
+	}
+	for _, c := range cases {
+		txt, lang := l.Lookup(c.chain...)
+		if txt.V != c.wantText || lang != c.wantLang {
+			t.Errorf("%v: got %q (%v), want %q (%v)",
+				c.chain, txt.V, lang, c.wantText, c.wantLang)
+		}
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	cases := []struct {
+		lang string
+		want []string
+	}{
+		{"de-AT", []string{"de-AT", "de", "en", "x-default"}},
+		{"en-GB", []string{"en-GB", "en", "x-default"}},
+		{"zh-Hant-TW", []string{"zh-Hant-TW", "zh-Hant", "zh", "en", "x-default"}},
+		{"en", []string{"en", "x-default"}},
+	}
+	for _, c := range cases {
+		chain := FallbackChain(language.MustParse(c.lang), language.English, defaultLanguage)
+		var got []string
+		for _, t := range chain {
+			got = append(got, t.String())
+		}
+		if d := cmp.Diff(c.want, got); d != "" {
+			t.Errorf("%s: wrong chain (-want +got):\n%s", c.lang, d)
+		}
+	}
+}
+
+func TestSetFallback(t *testing.T) {
+	SetFallback(language.English, defaultLanguage)
+	defer SetFallback()
+
+	l := Localized{Default: NewText("Colour")}
+	l.Set(language.German, "Farbe")
+	l.Set(language.English, "Color")
+
+	txt, lang := l.Best(language.Japanese)
+	if txt.V != "Color" || lang != language.English {
+		t.Errorf("got %q (%v), want English", txt.V, lang)
+	}
+	txt, lang = l.Lookup()
+	if txt.V != "Color" || lang != language.English {
+		t.Errorf("got %q (%v), want English", txt.V, lang)
+	}
+	txt, lang = l.Lookup(language.German)
+	if txt.V != "Farbe" || lang != language.German {
+		t.Errorf("got %q (%v), want German", txt.V, lang)
+	}
+}
@@ -0,0 +1,138 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// SceneCodes lists the IPTC Scene NewsCodes, mapping the six-digit codes to
+// their English names.  These are the permitted values for the
+// Iptc4xmpCore:Scene property.
+//
+// See https://cv.iptc.org/newscodes/scene/ .
+var SceneCodes = map[string]string{
+	"010100": "headshot",
+	"010200": "half-length",
+	"010300": "full-length",
+	"010400": "profile",
+	"010500": "rear view",
+	"010600": "single",
+	"010700": "couple",
+	"010800": "two",
+	"010900": "group",
+	"011000": "general view",
+	"011100": "panoramic view",
+	"011200": "aerial view",
+	"011300": "under-water",
+	"011400": "night scene",
+	"011500": "satellite",
+	"011600": "exterior view",
+	"011700": "interior view",
+	"011800": "close-up",
+	"011900": "action",
+	"012000": "performing",
+	"012100": "posing",
+	"012200": "symbolic",
+	"012300": "off-beat",
+	"012400": "movie scene",
+}
+
+// SubjectCategories lists the top-level IPTC Subject NewsCodes, mapping the
+// eight-digit codes to their English names.
+//
+// See https://cv.iptc.org/newscodes/subjectcode/ .
+var SubjectCategories = map[string]string{
+	"01000000": "arts, culture and entertainment",
+	"02000000": "crime, law and justice",
+	"03000000": "disaster and accident",
+	"04000000": "economy, business and finance",
+	"05000000": "education",
+	"06000000": "environmental issue",
+	"07000000": "health",
+	"08000000": "human interest",
+	"09000000": "labour",
+	"10000000": "lifestyle and leisure",
+	"11000000": "politics",
+	"12000000": "religion and belief",
+	"13000000": "science and technology",
+	"14000000": "social issue",
+	"15000000": "sport",
+	"16000000": "unrest, conflicts and war",
+	"17000000": "weather",
+}
+
+// IsSceneCode reports whether code is one of the IPTC Scene NewsCodes.
+func IsSceneCode(code string) bool {
+	_, ok := SceneCodes[code]
+	return ok
+}
+
+// IsSubjectCode reports whether code is a valid IPTC Subject NewsCode.
+//
+// Subject codes consist of eight digits: two digits for the top-level
+// subject, three digits for the subject matter and three digits for the
+// subject detail.  The top-level subject must be one of the
+// [SubjectCategories], and a subject detail can only be given together with
+// a subject matter.  The lower levels are not checked against the full
+// vocabulary.
+func IsSubjectCode(code string) bool {
+	if len(code) != 8 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	if _, ok := SubjectCategories[code[:2]+"000000"]; !ok {
+		return false
+	}
+	if code[2:5] == "000" && code[5:] != "000" {
+		return false
+	}
+	return true
+}
+
+// CheckNewsCodes checks the values of the Iptc4xmpCore:Scene and
+// Iptc4xmpCore:SubjectCode properties against the IPTC vocabularies.
+// A [Diagnostic] is returned for every invalid entry.
+func (p *Packet) CheckNewsCodes() []Diagnostic {
+	var res []Diagnostic
+	check := func(local string, valid func(string) bool) {
+		name := xml.Name{Space: iptcCoreNamespace, Local: local}
+		val, err := PacketGetValue[UnorderedArray[Text]](p, name.Space, name.Local)
+		if err != nil {
+			if err != ErrNotFound {
+				res = append(res, Diagnostic{Property: name, Message: "malformed value"})
+			}
+			return
+		}
+		for _, code := range val.V {
+			if !valid(code.V) {
+				res = append(res, Diagnostic{
+					Property: name,
+					Message:  fmt.Sprintf("unknown code %q", code.V),
+				})
+			}
+		}
+	}
+	check("Scene", IsSceneCode)
+	check("SubjectCode", IsSubjectCode)
+	return res
+}
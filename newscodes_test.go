@@ -0,0 +1,73 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIsSubjectCode(t *testing.T) {
+	cases := []struct {
+		code string
+		ok   bool
+	}{
+		{"15000000", true},
+		{"15054000", true},
+		{"15054001", true},
+		{"17000000", true},
+		{"18000000", false},
+		{"00000000", false},
+		{"15000001", false},
+		{"1505400", false},
+		{"1505400x", false},
+	}
+	for _, c := range cases {
+		if got := IsSubjectCode(c.code); got != c.ok {
+			t.Errorf("IsSubjectCode(%q) = %t, want %t", c.code, got, c.ok)
+		}
+	}
+}
+
+func TestCheckNewsCodes(t *testing.T) {
+	p := NewPacket()
+	scene := UnorderedArray[Text]{}
+	scene.Append(NewText("011800"))
+	scene.Append(NewText("close-up"))
+	p.SetValue(iptcCoreNamespace, "Scene", scene)
+	subject := UnorderedArray[Text]{}
+	subject.Append(NewText("15054000"))
+	subject.Append(NewText("99000000"))
+	p.SetValue(iptcCoreNamespace, "SubjectCode", subject)
+
+	got := p.CheckNewsCodes()
+	want := []Diagnostic{
+		{
+			Property: xml.Name{Space: iptcCoreNamespace, Local: "Scene"},
+			Message:  `unknown code "close-up"`,
+		},
+		{
+			Property: xml.Name{Space: iptcCoreNamespace, Local: "SubjectCode"},
+			Message:  `unknown code "99000000"`,
+		},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("diagnostics differ (-want +got):\n%s", d)
+	}
+}
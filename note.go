@@ -0,0 +1,48 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+)
+
+// Note represents the XMP Note namespace.
+//
+// When an XMP packet is too large to fit into a single JPEG APP1 segment,
+// the packet is split into a main part and an extended part, see part 3
+// of the XMP specification.  The main part then carries the
+// HasExtendedXMP property, which identifies the extended part.
+type Note struct {
+	_ Namespace `xmp:"http://ns.adobe.com/xmp/note/"`
+	_ Prefix    `xmp:"xmpNote"`
+
+	// HasExtendedXMP is the GUID of the extended XMP data.  This is the
+	// MD5 digest of the serialized extended packet, written as 32
+	// uppercase hexadecimal digits.
+	HasExtendedXMP GUID
+}
+
+// ExtendedXMPGUID returns the identifier used in [Note.HasExtendedXMP]
+// for the given serialized extended XMP packet.
+func ExtendedXMPGUID(extended []byte) GUID {
+	sum := md5.Sum(extended)
+	return GUID{V: strings.ToUpper(hex.EncodeToString(sum[:]))}
+}
+
+const noteNamespace = "http://ns.adobe.com/xmp/note/"
@@ -17,12 +17,55 @@
 package xmp
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 
+	"golang.org/x/exp/maps"
 	"seehuhn.de/go/xmp/jvxml"
 )
 
+// NamespaceInfo describes a namespace used in an XMP packet.
+type NamespaceInfo struct {
+	// URI is the namespace URI.
+	URI string
+
+	// Prefix is the namespace prefix which is used when the packet is
+	// written.
+	Prefix string
+
+	// NumProperties is the number of top-level properties in the namespace.
+	// This is zero for namespaces which are only used for struct fields or
+	// qualifiers.
+	NumProperties int
+}
+
+// Namespaces returns information about all namespaces used in the packet,
+// including namespaces used only in struct fields and qualifiers.
+// The result is sorted by namespace URI.
+func (p *Packet) Namespaces() []NamespaceInfo {
+	nsUsed := p.getNamespaces()
+	nsToPrefix, _ := p.getPrefixes(nsUsed)
+
+	count := make(map[string]int)
+	for key := range p.Properties {
+		count[key.Space]++
+	}
+
+	res := make([]NamespaceInfo, 0, len(nsUsed))
+	for ns := range nsUsed {
+		res = append(res, NamespaceInfo{
+			URI:           ns,
+			Prefix:        nsToPrefix[ns],
+			NumProperties: count[ns],
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].URI < res[j].URI
+	})
+	return res
+}
+
 // getPrefix chooses a new prefix for the given namespace.
 // The new prefix is chosen to be different from the ones already in the
 // prefixToNS map.
@@ -63,9 +106,82 @@ func getPrefix(prefixToNS map[string]string, ns string) string {
 	return prefix
 }
 
+// defaultPrefix lists the conventional prefixes for well-known namespaces.
+// The table is used to format and parse property names (see [formatName]),
+// to recover from undeclared prefixes when reading, and to choose prefixes
+// for the fields of structure types.
 var defaultPrefix = map[string]string{
-	xmlNamespace: "xml",
-	rdfNamespace: "rdf",
+	xmlNamespace:   "xml",
+	rdfNamespace:   "rdf",
+	basicNamespace: "xmp",
+
+	"http://purl.org/dc/elements/1.1/": "dc",
+	rightsNamespace:                    "xmpRights",
+
+	bextNamespace:      "bext",
+	bjNamespace:        "xmpBJ",
+	compNamespace:      "comp",
+	dcTermsNamespace:   "dcterms",
+	digiKamNamespace:   "digiKam",
+	djiNamespace:       "drone-dji",
+	dmNamespace:        "xmpDM",
+	gdepthNamespace:    "GDepth",
+	gimageNamespace:    "GImage",
+	gpanoNamespace:     "GPano",
+	exifNamespace:      "exif",
+	iptcCoreNamespace:  "Iptc4xmpCore",
+	iptcExtNamespace:   "Iptc4xmpExt",
+	mmNamespace:        "xmpMM",
+	mpNamespace:        "MP",
+	mpriNamespace:      "MPRI",
+	mpregNamespace:     "MPReg",
+	msPhotoNamespace:   "MicrosoftPhoto",
+	mwgCollNamespace:   "mwg-coll",
+	mwgKWNamespace:     "mwg-kw",
+	mwgRSNamespace:     "mwg-rs",
+	noteNamespace:      "xmpNote",
+	pdfNamespace:       "pdf",
+	pdfaidNamespace:    "pdfaid",
+	pdfxidNamespace:    "pdfxid",
+	photoshopNamespace: "photoshop",
+	plusNamespace:      "plus",
+	prismNamespace:     "prism",
+	stAreaNamespace:    "stArea",
+	stDimNamespace:     "stDim",
+	stEvtNamespace:     "stEvt",
+	stFntNamespace:     "stFnt",
+	stJobNamespace:     "stJob",
+	stMfsNamespace:     "stMfs",
+	stRefNamespace:     "stRef",
+	stVerNamespace:     "stVer",
+	tpgNamespace:       "xmpTPg",
+	xmpGNamespace:      "xmpG",
+	xmpGImgNamespace:   "xmpGImg",
+	xmpidqNamespace:    "xmpidq",
+}
+
+// displayPrefixes chooses a prefix for each namespace used in the packet,
+// for use in human-readable output.  Unlike in [Packet.Write], the
+// conventional prefixes of well-known namespaces are used if no prefix is
+// registered.
+func (p *Packet) displayPrefixes() map[string]string {
+	q := *p
+	q.nsToPrefix = maps.Clone(defaultPrefix)
+	for ns, pfx := range p.nsToPrefix {
+		q.nsToPrefix[ns] = pfx
+	}
+	nsToPrefix, _ := q.getPrefixes(q.getNamespaces())
+	return nsToPrefix
+}
+
+// prefixNamespace returns the well-known namespace for the given prefix.
+func prefixNamespace(pfx string) (string, bool) {
+	for ns, p := range defaultPrefix {
+		if p == pfx {
+			return ns, true
+		}
+	}
+	return "", false
 }
 
 const (
@@ -74,4 +190,17 @@ const (
 
 	// rdfNamespace is the namespace for RDF.
 	rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+	// basicNamespace is the namespace for the XMP basic properties.
+	basicNamespace = "http://ns.adobe.com/xap/1.0/"
+
+	// mmNamespace is the namespace for the XMP Media Management properties.
+	mmNamespace = "http://ns.adobe.com/xap/1.0/mm/"
+
+	// rightsNamespace is the namespace for the XMP Rights Management
+	// properties.
+	rightsNamespace = "http://ns.adobe.com/xap/1.0/rights/"
+
+	// xmpMetaNamespace is the namespace of the x:xmpmeta element.
+	xmpMetaNamespace = "adobe:ns:meta/"
 )
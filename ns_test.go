@@ -16,7 +16,12 @@
 
 package xmp
 
-import "testing"
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
 
 // TestDefaultPrefix ensures that the prefixes in the defaultPrefix table are
 // unique and non-empty.
@@ -46,3 +51,56 @@ func TestGetPrefix(t *testing.T) {
 		t.Errorf("unexpected prefix %q", p)
 	}
 }
+
+func TestNamespaces(t *testing.T) {
+	p := NewPacket()
+	p.RegisterPrefix("http://ns.seehuhn.de/test/a/#", "x")
+	p.Properties[xml.Name{Space: "http://ns.seehuhn.de/test/a/#", Local: "p"}] = Text{V: "1"}
+	p.Properties[xml.Name{Space: "http://ns.seehuhn.de/test/a/#", Local: "q"}] = Text{V: "2"}
+	p.Properties[xml.Name{Space: "http://ns.seehuhn.de/test/b/#", Local: "s"}] = RawStruct{
+		Value: map[xml.Name]Raw{
+			{Space: "http://ns.seehuhn.de/test/c/#", Local: "f"}: Text{V: "3"},
+		},
+	}
+
+	want := []NamespaceInfo{
+		{URI: "http://ns.seehuhn.de/test/a/#", Prefix: "x", NumProperties: 2},
+		{URI: "http://ns.seehuhn.de/test/b/#", Prefix: "b", NumProperties: 1},
+		{URI: "http://ns.seehuhn.de/test/c/#", Prefix: "c", NumProperties: 0},
+	}
+	got := p.Namespaces()
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("unexpected namespaces (-want +got):\n%s", d)
+	}
+}
+
+// TestPrefixes checks the choice of namespace prefixes: registered prefixes
+// take precedence, the namespaces of structure fields use their
+// conventional prefix, and all other prefixes are derived from the URI.
+func TestPrefixes(t *testing.T) {
+	p := NewPacket()
+	p.SetValue(dcNamespace, "format", NewText("image/jpeg"))
+	p.SetValue(tpgNamespace, "MaxPageSize", Dimensions{W: Real{V: 1}, H: Real{V: 2}, Unit: NewText("inch")})
+	p.SetValue(basicNamespace, "Label", NewText("red"))
+	p.RegisterPrefix(basicNamespace, "basic")
+	p.RegisterPrefix("http://ns.seehuhn.de/test/#", "dc")
+	p.SetValue("http://ns.seehuhn.de/test/#", "prop", NewText("x"))
+
+	nsToPrefix, _ := p.getPrefixes(p.getNamespaces())
+	want := map[string]string{
+		xmlNamespace:                  "xml",
+		rdfNamespace:                  "rdf",
+		basicNamespace:                "basic",
+		"http://ns.seehuhn.de/test/#": "dc",
+		tpgNamespace:                  "pg",
+		stDimNamespace:                "stDim",
+	}
+	for ns, pfx := range want {
+		if got := nsToPrefix[ns]; got != pfx {
+			t.Errorf("%s: got prefix %q, want %q", ns, got, pfx)
+		}
+	}
+	if got := nsToPrefix[dcNamespace]; got == "dc" || got == "" {
+		t.Errorf("%s: got prefix %q", dcNamespace, got)
+	}
+}
@@ -0,0 +1,148 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"errors"
+	"maps"
+)
+
+// PantryItem holds the metadata of an ingredient of a composed document.
+//
+// When a document is assembled from other documents, the XMP metadata of
+// the ingredients can be stored in the xmpMM:Pantry property of the
+// composed document.  Each pantry item contains all top-level properties
+// of an ingredient, including its xmpMM:InstanceID.  Entries of the
+// xmpMM:Ingredients array refer to the pantry items via this instance ID.
+//
+// See section 3.2.1 of part 2 of the XMP specification (2016).
+type PantryItem struct {
+	// Properties are the top-level properties of the ingredient.
+	Properties map[xml.Name]Raw
+
+	Q
+}
+
+// NewPantryItem returns a pantry item which holds all properties of the
+// given packet.
+func NewPantryItem(ingredient *Packet) PantryItem {
+	return PantryItem{Properties: maps.Clone(ingredient.Properties)}
+}
+
+// InstanceID returns the xmpMM:InstanceID of the ingredient.  If the item
+// has no instance ID, the zero value is returned.
+func (item PantryItem) InstanceID() GUID {
+	raw, ok := item.Properties[nameInstanceID]
+	if !ok {
+		return GUID{}
+	}
+	id, err := GUID{}.DecodeAnother(raw)
+	if err != nil {
+		return GUID{}
+	}
+	return id.(GUID)
+}
+
+// Packet returns a new packet which contains the properties of the
+// ingredient.
+func (item PantryItem) Packet() *Packet {
+	p := NewPacket()
+	for name, val := range item.Properties {
+		p.Properties[name] = val
+	}
+	return p
+}
+
+// IsZero implements the [Value] interface.
+func (item PantryItem) IsZero() bool {
+	return len(item.Properties) == 0 && len(item.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (item PantryItem) EncodeXMP(*Packet) Raw {
+	return RawStruct{
+		Value: maps.Clone(item.Properties),
+		Q:     item.Q,
+	}
+}
+
+// DecodeAnother implements the [Value] interface.
+func (PantryItem) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return PantryItem{
+		Properties: maps.Clone(s.Value),
+		Q:          s.Q,
+	}, nil
+}
+
+// AddToPantry stores the metadata of an ingredient in the xmpMM:Pantry
+// property of the packet.  The ingredient must have an xmpMM:InstanceID.
+// If the pantry already contains an item with the same instance ID, the
+// item is replaced.
+func (p *Packet) AddToPantry(ingredient *Packet) error {
+	item := NewPantryItem(ingredient)
+	id := item.InstanceID()
+	if id.IsZero() {
+		return errMissingInstanceID
+	}
+
+	pantry, err := PacketGetValue[UnorderedArray[PantryItem]](p, mmNamespace, "Pantry")
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	replaced := false
+	for i, old := range pantry.V {
+		if old.InstanceID().V == id.V {
+			pantry.V[i] = item
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		pantry.V = append(pantry.V, item)
+	}
+	p.SetValue(mmNamespace, "Pantry", pantry)
+	return nil
+}
+
+// Ingredient looks up the metadata of an ingredient in the xmpMM:Pantry
+// property of the packet.  The ingredient is identified by its instance
+// ID, as found in the stRef:instanceID field of the xmpMM:Ingredients
+// entries.  If no matching pantry item is found, [ErrNotFound] is
+// returned.
+func (p *Packet) Ingredient(instanceID GUID) (*Packet, error) {
+	pantry, err := PacketGetValue[UnorderedArray[PantryItem]](p, mmNamespace, "Pantry")
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range pantry.V {
+		if item.InstanceID().V == instanceID.V {
+			return item.Packet(), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+var (
+	nameInstanceID = xml.Name{Space: mmNamespace, Local: "InstanceID"}
+
+	errMissingInstanceID = errors.New("ingredient has no xmpMM:InstanceID")
+)
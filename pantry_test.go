@@ -0,0 +1,81 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPantry(t *testing.T) {
+	const dc = "http://purl.org/dc/elements/1.1/"
+
+	ingredient := NewPacket()
+	ingredient.SetValue(mmNamespace, "InstanceID", GUID{V: "xmp.iid:1234"})
+	ingredient.SetValue(dc, "source", NewText("photo.jpg"))
+
+	p1 := NewPacket()
+	err := p1.AddToPantry(ingredient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// adding the same ingredient again must not duplicate the item
+	err = p1.AddToPantry(ingredient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mm := &MediaManagement{}
+	p2.Get(mm)
+	if len(mm.Pantry.V) != 1 {
+		t.Fatalf("expected 1 pantry item, got %d", len(mm.Pantry.V))
+	}
+
+	got, err := p2.Ingredient(GUID{V: "xmp.iid:1234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(ingredient.Properties, got.Properties); d != "" {
+		t.Errorf("ingredient differs (-want +got):\n%s", d)
+	}
+
+	_, err = p2.Ingredient(GUID{V: "xmp.iid:5678"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPantryMissingInstanceID(t *testing.T) {
+	p := NewPacket()
+	err := p.AddToPantry(NewPacket())
+	if err == nil {
+		t.Error("expected error")
+	}
+}
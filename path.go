@@ -0,0 +1,174 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Path identifies values inside an XMP packet.
+//
+// In string form, a path consists of steps separated by "/".  Each step is
+// a property or field name, either written as "prefix:local" using one of
+// the well-known prefixes, or as "{namespace}local".  A name can be followed
+// by a 1-based array index in square brackets.  Array elements without an
+// index are all selected.  Examples:
+//
+//	dc:subject
+//	dc:creator[1]
+//	xmpMM:DerivedFrom/stRef:documentID
+type Path []PathStep
+
+// PathStep is one step in a [Path].
+type PathStep struct {
+	Name xml.Name
+
+	// Index is the 1-based index of the selected array element,
+	// or 0 to select all elements.
+	Index int
+}
+
+// ParsePath converts the string form of a path into a [Path].
+func ParsePath(s string) (Path, error) {
+	var res Path
+	for _, part := range splitPath(s) {
+		var step PathStep
+		if strings.HasSuffix(part, "]") {
+			i := strings.LastIndex(part, "[")
+			if i < 0 {
+				return nil, fmt.Errorf("invalid path %q", s)
+			}
+			idx, err := strconv.Atoi(part[i+1 : len(part)-1])
+			if err != nil || idx < 1 {
+				return nil, fmt.Errorf("invalid array index in path %q", s)
+			}
+			step.Index = idx
+			part = part[:i]
+		}
+		name, ok := parseName(part)
+		if !ok {
+			return nil, fmt.Errorf("invalid path %q", s)
+		}
+		step.Name = name
+		res = append(res, step)
+	}
+	return res, nil
+}
+
+// splitPath splits a path into steps.  Slashes inside of namespace URIs
+// are ignored.
+func splitPath(s string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i, c := range s {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '/':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func (path Path) String() string {
+	parts := make([]string, len(path))
+	for i, step := range path {
+		parts[i] = formatName(step.Name)
+		if step.Index > 0 {
+			parts[i] += "[" + strconv.Itoa(step.Index) + "]"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Select returns all values in the packet which are identified by the path.
+// Array elements are included individually.  The result is empty if the
+// path does not match any value.
+func (p *Packet) Select(path Path) []Raw {
+	if len(path) == 0 {
+		return nil
+	}
+	val, ok := p.Properties[path[0].Name]
+	if !ok {
+		return nil
+	}
+	return selectRaw(val, path[0].Index, path[1:], nil)
+}
+
+func selectRaw(val Raw, idx int, rest Path, res []Raw) []Raw {
+	if a, ok := val.(RawArray); ok {
+		if idx > 0 {
+			if idx > len(a.Value) {
+				return res
+			}
+			return selectRaw(a.Value[idx-1], 0, rest, res)
+		}
+		for _, elem := range a.Value {
+			res = selectRaw(elem, 0, rest, res)
+		}
+		return res
+	} else if idx > 1 {
+		return res
+	}
+
+	if len(rest) == 0 {
+		return append(res, val)
+	}
+	s, ok := val.(RawStruct)
+	if !ok {
+		return res
+	}
+	field, ok := s.Value[rest[0].Name]
+	if !ok {
+		return res
+	}
+	return selectRaw(field, rest[0].Index, rest[1:], res)
+}
+
+// parseName converts a name of the form "prefix:local" or
+// "{namespace}local" into an XML name.  Only well-known prefixes are
+// recognized.
+func parseName(s string) (xml.Name, bool) {
+	if strings.HasPrefix(s, "{") {
+		if i := strings.LastIndex(s, "}"); i > 0 && i < len(s)-1 {
+			return xml.Name{Space: s[1:i], Local: s[i+1:]}, true
+		}
+	} else if pfx, local, ok := strings.Cut(s, ":"); ok && local != "" {
+		if ns, ok := prefixNamespace(pfx); ok {
+			return xml.Name{Space: ns, Local: local}, true
+		}
+	}
+	return xml.Name{}, false
+}
+
+// formatName is the inverse of parseName.
+func formatName(name xml.Name) string {
+	if pfx, ok := defaultPrefix[name.Space]; ok {
+		return pfx + ":" + name.Local
+	}
+	return "{" + name.Space + "}" + name.Local
+}
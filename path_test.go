@@ -0,0 +1,88 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, s := range []string{
+		"dc:subject",
+		"dc:creator[2]",
+		"xmpMM:DerivedFrom/stRef:documentID",
+		"{http://ns.seehuhn.de/test/#}s/{http://ns.seehuhn.de/test/#}a[1]",
+	} {
+		path, err := ParsePath(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		if got := path.String(); got != s {
+			t.Errorf("%q: round trip gives %q", s, got)
+		}
+	}
+
+	for _, s := range []string{"", "dc:", "nope:x", "dc:creator[0]", "dc:creator[x]", "dc:creator]"} {
+		if _, err := ParsePath(s); err == nil {
+			t.Errorf("%q: missing error", s)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	p := NewPacket()
+	p.Properties[elemTest] = RawArray{
+		Kind: Ordered,
+		Value: []Raw{
+			RawStruct{Value: map[xml.Name]Raw{elemTestA: Text{V: "1"}}},
+			RawStruct{Value: map[xml.Name]Raw{elemTestA: Text{V: "2"}}},
+			RawStruct{Value: map[xml.Name]Raw{elemTestB: Text{V: "3"}}},
+		},
+	}
+
+	cases := []struct {
+		path Path
+		want []Raw
+	}{
+		{
+			path: Path{{Name: elemTest}, {Name: elemTestA}},
+			want: []Raw{Text{V: "1"}, Text{V: "2"}},
+		},
+		{
+			path: Path{{Name: elemTest, Index: 2}, {Name: elemTestA}},
+			want: []Raw{Text{V: "2"}},
+		},
+		{
+			path: Path{{Name: elemTest, Index: 4}},
+			want: nil,
+		},
+		{
+			path: Path{{Name: elemTest}, {Name: elemTestC}},
+			want: nil,
+		},
+	}
+	for _, c := range cases {
+		got := p.Select(c.path)
+		if d := cmp.Diff(c.want, got); d != "" {
+			t.Errorf("%s: (-want +got):\n%s", c.path, d)
+		}
+	}
+}
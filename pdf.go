@@ -0,0 +1,47 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+// PDF represents the Adobe PDF namespace.
+//
+// See section 3.1 of part 2 of the XMP specification (2016).  The
+// remaining entries of the PDF document information dictionary are
+// represented by properties in the [DublinCore] and [Basic] namespaces.
+type PDF struct {
+	_ Namespace `xmp:"http://ns.adobe.com/pdf/1.3/"`
+	_ Prefix    `xmp:"pdf"`
+
+	// Keywords contains the keywords for the document.
+	Keywords Text
+
+	// PDFVersion is the PDF file version, for example "1.7" or "2.0".
+	PDFVersion Text
+
+	// Producer is the name of the software which converted the document
+	// to PDF.
+	Producer AgentName
+
+	// Trapped indicates whether the document has been modified to include
+	// trapping information.  The value is one of "True", "False" or
+	// "Unknown".
+	//
+	// Since "Unknown" cannot be represented by [OptionalBool], this field
+	// has type Text.
+	Trapped Text
+}
+
+const pdfNamespace = "http://ns.adobe.com/pdf/1.3/"
@@ -0,0 +1,91 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PDFA represents the PDF/A identification namespace.
+//
+// PDF/A files are required to identify the part and conformance level of
+// the PDF/A standard they conform to, see ISO 19005.  Use [PDFA.Validate]
+// to check that the combination of values is allowed.
+type PDFA struct {
+	_ Namespace `xmp:"http://www.aiim.org/pdfa/ns/id/"`
+	_ Prefix    `xmp:"pdfaid"`
+
+	// Part is the part number of the PDF/A standard, e.g. 2 for PDF/A-2.
+	Part Integer `xmp:"part"`
+
+	// Conformance is the conformance level.  For parts 1 to 3 this is
+	// "A", "B" or "U" (not allowed for part 1).  For part 4, this is
+	// empty, "E" or "F".
+	Conformance Text `xmp:"conformance"`
+
+	// Amendment optionally identifies an amendment to the standard.
+	Amendment Text `xmp:"amd"`
+
+	// Revision is the year of the revision of the standard.  This is
+	// required for PDF/A-4.
+	Revision Integer `xmp:"rev"`
+}
+
+// pdfaConformance lists the allowed conformance levels for each part of
+// the PDF/A standard.
+var pdfaConformance = map[int][]string{
+	1: {"A", "B"},
+	2: {"A", "B", "U"},
+	3: {"A", "B", "U"},
+	4: {"", "E", "F"},
+}
+
+// Validate checks that the part and conformance level form a valid
+// combination, and that the revision year is present where required.
+func (a *PDFA) Validate() error {
+	if a.Part.IsZero() {
+		return errors.New("missing PDF/A part")
+	}
+	part := int(a.Part.V)
+	levels, ok := pdfaConformance[part]
+	if !ok {
+		return fmt.Errorf("invalid PDF/A part %d", a.Part.V)
+	}
+
+	valid := false
+	for _, level := range levels {
+		if a.Conformance.V == level {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid conformance level %q for PDF/A-%d",
+			a.Conformance.V, part)
+	}
+
+	if part >= 4 {
+		rev := a.Revision.V
+		if rev < 2020 || rev > 9999 {
+			return fmt.Errorf("invalid revision year %d for PDF/A-%d", rev, part)
+		}
+	}
+	return nil
+}
+
+const pdfaidNamespace = "http://www.aiim.org/pdfa/ns/id/"
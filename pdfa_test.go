@@ -0,0 +1,89 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPDFARoundTrip(t *testing.T) {
+	in := &PDFA{
+		Part:        Integer{V: 2},
+		Conformance: NewText("U"),
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<pdfaid:part>2</pdfaid:part>")) {
+		t.Errorf("wrong encoding:\n%s", buf.Bytes())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &PDFA{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
+
+func TestPDFAValidate(t *testing.T) {
+	type testCase struct {
+		part        int64
+		conformance string
+		rev         int64
+		ok          bool
+	}
+	cases := []testCase{
+		{1, "A", 0, true},
+		{1, "B", 0, true},
+		{1, "U", 0, false},
+		{2, "U", 0, true},
+		{3, "A", 0, true},
+		{3, "", 0, false},
+		{4, "", 2020, true},
+		{4, "F", 2020, true},
+		{4, "E", 0, false},
+		{4, "B", 2020, false},
+		{0, "A", 0, false},
+		{5, "A", 0, false},
+	}
+	for _, c := range cases {
+		a := &PDFA{
+			Part:        Integer{V: c.part},
+			Conformance: NewText(c.conformance),
+			Revision:    Integer{V: c.rev},
+		}
+		err := a.Validate()
+		if (err == nil) != c.ok {
+			t.Errorf("%d%s (rev %d): got error %v", c.part, c.conformance, c.rev, err)
+		}
+	}
+}
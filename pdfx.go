@@ -0,0 +1,37 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+// PDFX represents the PDF/X identification namespace.
+//
+// PDF/X files identify the version of the PDF/X standard they conform to,
+// see ISO 15930.
+type PDFX struct {
+	_ Namespace `xmp:"http://www.npes.org/pdfx/ns/id/"`
+	_ Prefix    `xmp:"pdfxid"`
+
+	// Version is the PDF/X version, for example "PDF/X-4" or
+	// "PDF/X-6p".
+	Version Text `xmp:"GTS_PDFXVersion"`
+
+	// Conformance is the PDF/X conformance level, for example
+	// "PDF/X-1a:2003".  This is only used by the older parts of the
+	// standard.
+	Conformance Text `xmp:"GTS_PDFXConformance"`
+}
+
+const pdfxidNamespace = "http://www.npes.org/pdfx/ns/id/"
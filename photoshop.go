@@ -0,0 +1,62 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// Layer describes a text layer of a Photoshop document, as used in
+// photoshop:TextLayers.
+//
+// See section 3.2 of part 2 of the XMP specification (2016).
+type Layer struct {
+	// LayerName is the name of the layer.
+	LayerName Text
+
+	// LayerText is the text content of the layer.
+	LayerText Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (l Layer) IsZero() bool {
+	return l.LayerName.IsZero() && l.LayerText.IsZero() && len(l.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (l Layer) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     l.Q,
+	}
+	setField(res, p, photoshopNamespace, "LayerName", l.LayerName)
+	setField(res, p, photoshopNamespace, "LayerText", l.LayerText)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Layer) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Layer{
+		LayerName: getField[Text](s, photoshopNamespace, "LayerName"),
+		LayerText: getField[Text](s, photoshopNamespace, "LayerText"),
+		Q:         s.Q,
+	}, nil
+}
@@ -0,0 +1,282 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// PLUS represents the namespace of the Picture Licensing Universal System.
+//
+// PLUS describes the parties involved in licensing an image and the terms
+// of the license.  Many of the properties take URIs from the PLUS
+// vocabulary as values, for example
+// "http://ns.useplus.org/ldf/vocab/MR-NON" for ModelReleaseStatus.
+//
+// See https://www.useplus.com/useplus/standards.asp .
+type PLUS struct {
+	_ Namespace `xmp:"http://ns.useplus.org/ldf/xmp/1.0/"`
+	_ Prefix    `xmp:"plus"`
+
+	// CopyrightOwner lists the owners of the copyright in the image.
+	CopyrightOwner OrderedArray[CopyrightOwner]
+
+	// CopyrightStatus is a PLUS vocabulary URI giving the copyright
+	// status of the image.
+	CopyrightStatus Text
+
+	// CreditLineRequired is a PLUS vocabulary URI stating whether a
+	// credit line is required.
+	CreditLineRequired Text
+
+	// ImageSupplierImageID is the identifier of the image assigned by the
+	// image supplier.
+	ImageSupplierImageID Text
+
+	// LicenseEndDate is the date when the license expires.
+	LicenseEndDate Date
+
+	// LicenseID is the identifier of the license.
+	LicenseID Text
+
+	// LicenseStartDate is the date when the license becomes valid.
+	LicenseStartDate Date
+
+	// LicenseTransactionDate is the date of the license transaction.
+	LicenseTransactionDate Date
+
+	// Licensor lists the parties which license the image.  The PLUS
+	// standard allows up to three licensors.
+	Licensor OrderedArray[Licensor]
+
+	// MinorModelAgeDisclosure is a PLUS vocabulary URI giving the age of
+	// the youngest model shown in the image, if the model is a minor.
+	MinorModelAgeDisclosure Text
+
+	// ModelReleaseID lists identifiers of the model releases.
+	ModelReleaseID UnorderedArray[Text]
+
+	// ModelReleaseStatus is a PLUS vocabulary URI summarising the
+	// availability of model releases.
+	ModelReleaseStatus Text
+
+	// OtherConstraints describes further constraints on the use of the
+	// image.
+	OtherConstraints Localized
+
+	// OtherLicenseRequirements describes further requirements of the
+	// license.
+	OtherLicenseRequirements Localized
+
+	// PropertyReleaseID lists identifiers of the property releases.
+	PropertyReleaseID UnorderedArray[Text]
+
+	// PropertyReleaseStatus is a PLUS vocabulary URI summarising the
+	// availability of property releases.
+	PropertyReleaseStatus Text
+
+	// TermsAndConditionsText gives the terms and conditions of the
+	// license.
+	TermsAndConditionsText Localized
+
+	// TermsAndConditionsURL references the terms and conditions of the
+	// license.
+	TermsAndConditionsURL URI
+
+	// Version is the version of the PLUS standard used, for example
+	// "1.2.0".
+	Version Text
+}
+
+// Licensor describes a party which licenses an image.
+//
+// This is the Licensor structure from the PLUS License Data Format, used
+// by the plus:Licensor property.  The licensor is identified by the
+// embedded [OrganisationDetails]; see there for how the name and the
+// identifiers are stored.
+type Licensor struct {
+	OrganisationDetails
+
+	// StreetAddress is the street address of the licensor.
+	StreetAddress Text
+
+	// ExtendedAddress holds additional address information, for example
+	// an apartment number.
+	ExtendedAddress Text
+
+	// City is the name of the city.
+	City Text
+
+	// Region is the name of the state or province.
+	Region Text
+
+	// PostalCode is the postal code.
+	PostalCode Text
+
+	// Country is the name of the country.
+	Country Text
+
+	// TelephoneType1 is a PLUS vocabulary URI giving the type of
+	// Telephone1, for example work, cell or fax.
+	TelephoneType1 Text
+
+	// Telephone1 is a telephone number.
+	Telephone1 Text
+
+	// TelephoneType2 is a PLUS vocabulary URI giving the type of
+	// Telephone2.
+	TelephoneType2 Text
+
+	// Telephone2 is a second telephone number.
+	Telephone2 Text
+
+	// Email is the email address of the licensor.
+	Email Text
+
+	// URL is the web address of the licensor.
+	URL URI
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (l Licensor) IsZero() bool {
+	return l.OrganisationDetails.IsZero() && l.StreetAddress.IsZero() &&
+		l.ExtendedAddress.IsZero() && l.City.IsZero() && l.Region.IsZero() &&
+		l.PostalCode.IsZero() && l.Country.IsZero() &&
+		l.TelephoneType1.IsZero() && l.Telephone1.IsZero() &&
+		l.TelephoneType2.IsZero() && l.Telephone2.IsZero() &&
+		l.Email.IsZero() && l.URL.IsZero() && len(l.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (l Licensor) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     l.Q,
+	}
+	name, id := l.OrganisationDetails.plusNameID()
+	setField(res, p, plusNamespace, "LicensorName", name)
+	setField(res, p, plusNamespace, "LicensorID", id)
+	setField(res, p, plusNamespace, "LicensorStreetAddress", l.StreetAddress)
+	setField(res, p, plusNamespace, "LicensorExtendedAddress", l.ExtendedAddress)
+	setField(res, p, plusNamespace, "LicensorCity", l.City)
+	setField(res, p, plusNamespace, "LicensorRegion", l.Region)
+	setField(res, p, plusNamespace, "LicensorPostalCode", l.PostalCode)
+	setField(res, p, plusNamespace, "LicensorCountry", l.Country)
+	setField(res, p, plusNamespace, "LicensorTelephoneType1", l.TelephoneType1)
+	setField(res, p, plusNamespace, "LicensorTelephone1", l.Telephone1)
+	setField(res, p, plusNamespace, "LicensorTelephoneType2", l.TelephoneType2)
+	setField(res, p, plusNamespace, "LicensorTelephone2", l.Telephone2)
+	setField(res, p, plusNamespace, "LicensorEmail", l.Email)
+	setField(res, p, plusNamespace, "LicensorURL", l.URL)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Licensor) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Licensor{
+		OrganisationDetails: plusParty(
+			getField[Text](s, plusNamespace, "LicensorName"),
+			getField[Text](s, plusNamespace, "LicensorID")),
+		StreetAddress:   getField[Text](s, plusNamespace, "LicensorStreetAddress"),
+		ExtendedAddress: getField[Text](s, plusNamespace, "LicensorExtendedAddress"),
+		City:            getField[Text](s, plusNamespace, "LicensorCity"),
+		Region:          getField[Text](s, plusNamespace, "LicensorRegion"),
+		PostalCode:      getField[Text](s, plusNamespace, "LicensorPostalCode"),
+		Country:         getField[Text](s, plusNamespace, "LicensorCountry"),
+		TelephoneType1:  getField[Text](s, plusNamespace, "LicensorTelephoneType1"),
+		Telephone1:      getField[Text](s, plusNamespace, "LicensorTelephone1"),
+		TelephoneType2:  getField[Text](s, plusNamespace, "LicensorTelephoneType2"),
+		Telephone2:      getField[Text](s, plusNamespace, "LicensorTelephone2"),
+		Email:           getField[Text](s, plusNamespace, "LicensorEmail"),
+		URL:             getField[URI](s, plusNamespace, "LicensorURL"),
+		Q:               s.Q,
+	}, nil
+}
+
+// CopyrightOwner identifies an owner of the copyright in an image.
+//
+// This is the CopyrightOwner structure from the PLUS License Data Format,
+// used by the plus:CopyrightOwner property.  The owner is identified by
+// the embedded [OrganisationDetails]; see there for how the name and the
+// identifiers are stored.
+type CopyrightOwner struct {
+	OrganisationDetails
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (c CopyrightOwner) IsZero() bool {
+	return c.OrganisationDetails.IsZero() && len(c.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (c CopyrightOwner) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     c.Q,
+	}
+	name, id := c.OrganisationDetails.plusNameID()
+	setField(res, p, plusNamespace, "CopyrightOwnerName", name)
+	setField(res, p, plusNamespace, "CopyrightOwnerID", id)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (CopyrightOwner) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return CopyrightOwner{
+		OrganisationDetails: plusParty(
+			getField[Text](s, plusNamespace, "CopyrightOwnerName"),
+			getField[Text](s, plusNamespace, "CopyrightOwnerID")),
+		Q: s.Q,
+	}, nil
+}
+
+// plusNameID returns the values of the name and ID fields of the PLUS
+// structures.  PLUS allows only a single, untranslated name and a single
+// PLUS-ID: the default name (or the alphabetically first translation) and
+// the first identifier are used.  Roles and qualifiers are not stored.
+func (d OrganisationDetails) plusNameID() (name, id Text) {
+	name, _ = d.Name.last()
+	if len(d.Identifiers.V) > 0 {
+		id = d.Identifiers.V[0]
+	}
+	return name, id
+}
+
+// plusParty converts the name and ID fields of the PLUS structures to an
+// [OrganisationDetails] value.
+func plusParty(name, id Text) OrganisationDetails {
+	var d OrganisationDetails
+	if !name.IsZero() {
+		d.Name = Localized{Default: name}
+	}
+	if !id.IsZero() {
+		d.Identifiers.Append(id)
+	}
+	return d
+}
+
+const plusNamespace = "http://ns.useplus.org/ldf/xmp/1.0/"
@@ -0,0 +1,105 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/text/language"
+)
+
+func TestPLUS(t *testing.T) {
+	in := &PLUS{
+		Version:               NewText("1.2.0"),
+		ImageSupplierImageID:  NewText("IMG-0042"),
+		LicenseID:             NewText("L-2024-001"),
+		LicenseStartDate:      NewDate(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		ModelReleaseStatus:    NewText("http://ns.useplus.org/ldf/vocab/MR-NON"),
+		PropertyReleaseStatus: NewText("http://ns.useplus.org/ldf/vocab/PR-NAP"),
+		TermsAndConditionsURL: NewURI("https://example.com/license/terms"),
+	}
+	in.Licensor.Append(Licensor{
+		OrganisationDetails: OrganisationDetails{
+			Name:        Localized{Default: NewText("Example Images Ltd")},
+			Identifiers: UnorderedArray[Text]{V: []Text{NewText("PLUS-ID-0001")}},
+		},
+		City:           NewText("London"),
+		Country:        NewText("United Kingdom"),
+		TelephoneType1: NewText("http://ns.useplus.org/ldf/vocab/work"),
+		Telephone1:     NewText("+44 20 7946 0000"),
+		Email:          NewText("licensing@example.com"),
+	})
+	in.CopyrightOwner.Append(CopyrightOwner{
+		OrganisationDetails: OrganisationDetails{
+			Name: Localized{Default: NewText("Jane Doe")},
+		},
+	})
+	in.TermsAndConditionsText.Set(language.English, "Editorial use only.")
+	in.ModelReleaseID.Append(NewText("MR-1"))
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`plus:LicensorName="Example Images Ltd"`)) ||
+		!bytes.Contains(buf.Bytes(), []byte(`plus:LicensorID="PLUS-ID-0001"`)) {
+		t.Errorf("wrong encoding:\n%s", buf.Bytes())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &PLUS{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out, cmpopts.EquateEmpty()); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
+
+func TestPLUSParty(t *testing.T) {
+	// PLUS has room for a single name and a single identifier.
+	var name Localized
+	name.Set(language.German, "Beispielbilder GmbH")
+	name.Set(language.English, "Example Images Ltd")
+	c := CopyrightOwner{
+		OrganisationDetails: OrganisationDetails{
+			Name:        name,
+			Identifiers: UnorderedArray[Text]{V: []Text{NewText("a"), NewText("b")}},
+			Role:        UnorderedArray[Text]{V: []Text{NewText("owner")}},
+		},
+	}
+	raw := c.EncodeXMP(NewPacket()).(RawStruct)
+	want := map[xml.Name]Raw{
+		{Space: plusNamespace, Local: "CopyrightOwnerName"}: Text{V: "Beispielbilder GmbH"},
+		{Space: plusNamespace, Local: "CopyrightOwnerID"}:   Text{V: "a"},
+	}
+	if d := cmp.Diff(want, raw.Value); d != "" {
+		t.Errorf("wrong encoding (-want +got):\n%s", d)
+	}
+}
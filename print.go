@@ -0,0 +1,195 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrintOptions controls the output of [Packet.Print].
+type PrintOptions struct {
+	// MaxValueLen (optional) is the maximum number of characters shown for
+	// text values.  Longer values are shortened.  Zero means a default
+	// limit of 72 characters, negative values disable shortening.
+	MaxValueLen int
+
+	// Color, if set, causes ANSI escape sequences to be used to highlight
+	// names, values and qualifiers.
+	Color bool
+
+	// Indent (optional) is used for each level of indentation.
+	// The default is two spaces.
+	Indent string
+}
+
+// Print writes a human-readable tree view of the packet to w.
+//
+// Every property is shown on a separate line, using the same prefixes as
+// [Packet.Write].  Structure fields and array elements are shown indented
+// below their parent.  Qualifiers are shown in square brackets after the
+// value they qualify.  The output is intended for debugging and for
+// command line tools; the format may change between versions.
+func (p *Packet) Print(w io.Writer, opt *PrintOptions) error {
+	if opt == nil {
+		opt = &PrintOptions{}
+	}
+	pr := &printer{
+		w:      w,
+		opt:    opt,
+		indent: opt.Indent,
+	}
+	if pr.indent == "" {
+		pr.indent = "  "
+	}
+	switch {
+	case opt.MaxValueLen == 0:
+		pr.trunc = &Truncation{MaxRunes: 72, Ellipsis: "…"}
+	case opt.MaxValueLen > 0:
+		pr.trunc = &Truncation{MaxRunes: opt.MaxValueLen, Ellipsis: "…"}
+	}
+	pr.nsToPrefix = p.displayPrefixes()
+
+	if p.About != nil {
+		pr.printf("%s %s\n", pr.color(colorName, "about:"), p.About)
+	}
+
+	names := make([]xml.Name, 0, len(p.Properties))
+	for name := range p.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return pr.name(names[i]) < pr.name(names[j])
+	})
+	for _, name := range names {
+		pr.printRaw(0, pr.color(colorName, pr.name(name)), p.Properties[name])
+	}
+	return pr.err
+}
+
+type printer struct {
+	w          io.Writer
+	opt        *PrintOptions
+	indent     string
+	trunc      *Truncation
+	nsToPrefix map[string]string
+	err        error
+}
+
+const (
+	colorName      = "34" // blue
+	colorValue     = "32" // green
+	colorQualifier = "33" // yellow
+	colorKind      = "2"  // faint
+)
+
+func (pr *printer) printf(format string, args ...any) {
+	if pr.err != nil {
+		return
+	}
+	_, pr.err = fmt.Fprintf(pr.w, format, args...)
+}
+
+func (pr *printer) color(code, s string) string {
+	if !pr.opt.Color {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func (pr *printer) name(name xml.Name) string {
+	if pfx, ok := pr.nsToPrefix[name.Space]; ok {
+		return pfx + ":" + name.Local
+	}
+	return "{" + name.Space + "}" + name.Local
+}
+
+func (pr *printer) text(s string) string {
+	if pr.trunc != nil {
+		s, _ = pr.trunc.String(s)
+	}
+	return pr.color(colorValue, strconv.Quote(s))
+}
+
+// printRaw prints a value, labelled with the given string.
+func (pr *printer) printRaw(level int, label string, val Raw) {
+	prefix := strings.Repeat(pr.indent, level)
+	var q Q
+	switch val := val.(type) {
+	case Text:
+		pr.printf("%s%s: %s%s\n", prefix, label, pr.text(val.V), pr.qualifiers(val.Q))
+		q = val.Q
+	case URL:
+		u := "<" + val.V.String() + ">"
+		pr.printf("%s%s: %s%s\n", prefix, label, pr.color(colorValue, u), pr.qualifiers(val.Q))
+		q = val.Q
+	case RawStruct:
+		pr.printf("%s%s: %s%s\n", prefix, label, pr.color(colorKind, "Struct"), pr.qualifiers(val.Q))
+		for _, name := range val.FieldNames() {
+			pr.printRaw(level+1, pr.color(colorName, pr.name(name)), val.Value[name])
+		}
+		q = val.Q
+	case RawArray:
+		kind := fmt.Sprintf("%s, %d items", val.Kind, len(val.Value))
+		if len(val.Value) == 1 {
+			kind = fmt.Sprintf("%s, 1 item", val.Kind)
+		}
+		pr.printf("%s%s: %s%s\n", prefix, label, pr.color(colorKind, kind), pr.qualifiers(val.Q))
+		for i, elem := range val.Value {
+			pr.printRaw(level+1, "["+strconv.Itoa(i+1)+"]", elem)
+		}
+		q = val.Q
+	}
+
+	// Qualifiers which cannot be shown inline are printed below the value.
+	for _, qi := range q {
+		if !isInlineQualifier(qi) {
+			pr.printRaw(level+1, pr.color(colorQualifier, "?"+pr.name(qi.Name)), qi.Value)
+		}
+	}
+}
+
+// qualifiers formats the simple qualifiers of a value for inline display.
+func (pr *printer) qualifiers(q Q) string {
+	var parts []string
+	for _, qi := range q {
+		if !isInlineQualifier(qi) {
+			continue
+		}
+		v := qi.Value.(Text).V
+		if qi.Name == nameXMLLang {
+			parts = append(parts, pr.name(qi.Name)+"="+v)
+		} else {
+			parts = append(parts, pr.name(qi.Name)+"="+strconv.Quote(v))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + pr.color(colorQualifier, "["+strings.Join(parts, ", ")+"]")
+}
+
+// isInlineQualifier reports whether a qualifier can be shown on the same
+// line as the value it qualifies.
+func isInlineQualifier(qi Qualifier) bool {
+	t, ok := qi.Value.(Text)
+	return ok && len(t.Q) == 0
+}
@@ -0,0 +1,73 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestPrint(t *testing.T) {
+	p := NewPacket()
+	p.About = testURL
+	p.RegisterPrefix("http://ns.seehuhn.de/test/#", "test")
+	p.Properties[elemTestA] = Text{
+		V: strings.Repeat("x", 20),
+		Q: Q{{Name: nameXMLLang, Value: Text{V: "en"}}},
+	}
+	p.Properties[elemTestB] = RawArray{
+		Kind: Ordered,
+		Value: []Raw{
+			Text{V: "one"},
+			RawStruct{Value: map[xml.Name]Raw{elemTestC: URL{V: testURL}}},
+		},
+	}
+	p.Properties[elemTestC] = Text{
+		V: "v",
+		Q: Q{{Name: elemTestQ, Value: RawArray{Kind: Unordered, Value: []Raw{Text{V: "q"}}}}},
+	}
+
+	buf := &bytes.Buffer{}
+	err := p.Print(buf, &PrintOptions{MaxValueLen: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `about: http://example.com
+test:a: "xxxxxxxxx…" [xml:lang=en]
+test:b: Seq, 2 items
+  [1]: "one"
+  [2]: Struct
+    test:c: <http://example.com>
+test:c: "v"
+  ?test:q: Bag, 1 item
+    [1]: "q"
+`
+	if got := buf.String(); got != want {
+		t.Errorf("wrong output:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	err = p.Print(buf, &PrintOptions{Color: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\x1b[34mtest:a\x1b[0m") {
+		t.Errorf("missing color codes:\n%q", buf.String())
+	}
+}
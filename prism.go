@@ -0,0 +1,82 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+// PRISM represents the PRISM basic metadata namespace, used for magazine
+// and journal articles.
+//
+// See the PRISM Basic Metadata Specification, version 2.1 (2009).
+type PRISM struct {
+	_ Namespace `xmp:"http://prismstandard.org/namespaces/basic/2.0/"`
+	_ Prefix    `xmp:"prism"`
+
+	// AggregationType is the type of publication, for example "journal",
+	// "magazine" or "book".
+	AggregationType Text `xmp:"aggregationType"`
+
+	// CoverDate is the date shown on the cover of the issue.
+	CoverDate Date `xmp:"coverDate"`
+
+	// CoverDisplayDate is the date shown on the cover, in the form used
+	// by the publication, for example "Spring 2024".
+	CoverDisplayDate Text `xmp:"coverDisplayDate"`
+
+	// DOI is the digital object identifier of the article.
+	DOI Text `xmp:"doi"`
+
+	// EIssn is the ISSN of the electronic version of the publication.
+	EIssn Text `xmp:"eIssn"`
+
+	// Edition identifies the edition of the publication.
+	Edition Text `xmp:"edition"`
+
+	// EndingPage is the last page of the article.
+	EndingPage Text `xmp:"endingPage"`
+
+	// ISBN is the ISBN of the publication.
+	ISBN Text `xmp:"isbn"`
+
+	// ISSN is the ISSN of the print version of the publication.
+	ISSN Text `xmp:"issn"`
+
+	// IssueName is the name of a special issue.
+	IssueName Text `xmp:"issueName"`
+
+	// Number is the issue number.
+	Number Text `xmp:"number"`
+
+	// PageRange lists the pages of the article, for example "1-4, 7".
+	PageRange Text `xmp:"pageRange"`
+
+	// PublicationName is the name of the magazine or journal.
+	PublicationName Text `xmp:"publicationName"`
+
+	// Section is the section of the publication in which the article
+	// appears.
+	Section Text `xmp:"section"`
+
+	// StartingPage is the first page of the article.
+	StartingPage Text `xmp:"startingPage"`
+
+	// URL is the location of the article on the web.
+	URL URI `xmp:"url"`
+
+	// Volume is the volume number.
+	Volume Text `xmp:"volume"`
+}
+
+const prismNamespace = "http://prismstandard.org/namespaces/basic/2.0/"
@@ -0,0 +1,65 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPRISM(t *testing.T) {
+	in := &PRISM{
+		AggregationType: NewText("journal"),
+		CoverDate:       NewDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		DOI:             NewText("10.1000/xyz123"),
+		ISSN:            NewText("1234-5679"),
+		Number:          NewText("7"),
+		PageRange:       NewText("101-115"),
+		PublicationName: NewText("Journal of Examples"),
+		StartingPage:    NewText("101"),
+		EndingPage:      NewText("115"),
+		URL:             NewURI("https://example.com/journal/42/7"),
+		Volume:          NewText("42"),
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<prism:publicationName>Journal of Examples</prism:publicationName>")) {
+		t.Errorf("wrong encoding:\n%s", buf.Bytes())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &PRISM{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
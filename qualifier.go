@@ -0,0 +1,141 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"golang.org/x/text/language"
+)
+
+// CheckQualifiers checks that the qualifiers in the packet are placed
+// where ISO 16684-1:2011 allows them.  A [Diagnostic] is returned for every
+// problem found.  The following rules are checked:
+//
+//   - Qualifier names must be valid and must not be repeated.
+//   - The value of xml:lang must be a valid, unqualified language tag.
+//   - The value of rdf:type must be an unqualified URI.
+//
+// An xml:lang qualifier on a structure or an array is accepted.  This is
+// how the RDF serialization scopes a language to all nested values, and the
+// packet decoder keeps such qualifiers.  The language is inherited by the
+// nested simple values which do not specify a language of their own.
+//
+// The message of each diagnostic identifies the offending value within the
+// property, using "/" for struct fields, "[n]" for array items and "/?" for
+// qualifiers.
+func (p *Packet) CheckQualifiers() []Diagnostic {
+	names := make([]xml.Name, 0, len(p.Properties))
+	for name := range p.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Space != names[j].Space {
+			return names[i].Space < names[j].Space
+		}
+		return names[i].Local < names[j].Local
+	})
+
+	var res []Diagnostic
+	for _, name := range names {
+		checkQualifiers(p.Properties[name], formatName(name), func(loc, msg string) {
+			res = append(res, Diagnostic{Property: name, Message: loc + ": " + msg})
+		})
+	}
+	return res
+}
+
+// SetValueChecked is like [Packet.SetValue], but first checks the placement
+// of qualifiers in the encoded value, see [Packet.CheckQualifiers].  If a
+// problem is found, the packet is not modified and an error wrapping
+// [ErrInvalid] is returned.
+func (p *Packet) SetValueChecked(namespace, propertyName string, value Value) error {
+	name := xml.Name{Space: namespace, Local: propertyName}
+	if !isValidPropertyName(name) {
+		return fmt.Errorf("%w: invalid property name %q", ErrInvalid, formatName(name))
+	}
+	raw := value.EncodeXMP(p)
+	var err error
+	checkQualifiers(raw, formatName(name), func(loc, msg string) {
+		if err == nil {
+			err = fmt.Errorf("%w: %s: %s", ErrInvalid, loc, msg)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	p.record(name, raw)
+	p.Properties[name] = raw
+	return nil
+}
+
+// checkQualifiers checks the qualifiers of val and of all values nested
+// inside val.  Problems are reported via the report function, together
+// with the location of the offending value.
+func checkQualifiers(val Raw, loc string, report func(loc, msg string)) {
+	var qq Q
+	switch val := val.(type) {
+	case Text:
+		qq = val.Q
+	case URL:
+		qq = val.Q
+	case RawStruct:
+		qq = val.Q
+		for _, name := range val.FieldNames() {
+			checkQualifiers(val.Value[name], loc+"/"+formatName(name), report)
+		}
+	case RawArray:
+		qq = val.Q
+		for i, item := range val.Value {
+			checkQualifiers(item, loc+"["+strconv.Itoa(i+1)+"]", report)
+		}
+	}
+
+	seen := make(map[xml.Name]bool, len(qq))
+	for _, q := range qq {
+		qLoc := loc + "/?" + formatName(q.Name)
+		if !isValidQualifierName(q.Name) {
+			report(qLoc, "invalid qualifier name")
+			continue
+		}
+		if seen[q.Name] {
+			report(qLoc, "duplicate qualifier")
+		}
+		seen[q.Name] = true
+
+		switch q.Name {
+		case nameXMLLang:
+			t, ok := q.Value.(Text)
+			if !ok || len(t.Q) > 0 {
+				report(qLoc, "xml:lang must be a simple text value")
+			} else if _, err := language.Parse(t.V); err != nil {
+				report(qLoc, fmt.Sprintf("invalid language tag %q", t.V))
+			}
+			continue
+		case nameRDFType:
+			u, ok := q.Value.(URL)
+			if !ok || len(u.Q) > 0 {
+				report(qLoc, "rdf:type must be a simple URI value")
+			}
+			continue
+		}
+		checkQualifiers(q.Value, qLoc, report)
+	}
+}
@@ -0,0 +1,100 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
+)
+
+func TestCheckQualifiers(t *testing.T) {
+	lang := Qualifier{Name: nameXMLLang, Value: Text{V: "de"}}
+
+	p := NewPacket()
+	p.Properties[elemTestA] = Text{V: "a", Q: Q{lang}}
+	p.Properties[elemTestB] = RawArray{
+		Value: []Raw{Text{V: "1"}, Text{V: "2", Q: Q{lang, lang}}},
+		Kind:  Unordered,
+		Q:     Q{lang},
+	}
+	p.Properties[elemTestC] = RawStruct{
+		Value: map[xml.Name]Raw{
+			elemTestQ: Text{V: "q", Q: Q{{Name: nameXMLLang, Value: Text{V: "not a language"}}}},
+		},
+		Q: Q{{Name: nameRDFType, Value: Text{V: "http://example.com/type"}}},
+	}
+
+	got := p.CheckQualifiers()
+	want := []Diagnostic{
+		{Property: elemTestB, Message: "{http://ns.seehuhn.de/test/#}b[2]/?xml:lang: duplicate qualifier"},
+		{Property: elemTestC, Message: "{http://ns.seehuhn.de/test/#}c/{http://ns.seehuhn.de/test/#}q/?xml:lang: invalid language tag \"not a language\""},
+		{Property: elemTestC, Message: "{http://ns.seehuhn.de/test/#}c/?rdf:type: rdf:type must be a simple URI value"},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("wrong diagnostics (-want +got):\n%s", d)
+	}
+}
+
+func TestSetValueChecked(t *testing.T) {
+	p := NewPacket()
+
+	good := Localized{}
+	good.Set(language.German, "Hallo")
+	err := p.SetValueChecked(elemTest.Space, elemTest.Local, good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.Properties[elemTest]; !ok {
+		t.Error("value not stored")
+	}
+
+	bad := UnorderedArray[Text]{
+		V: []Text{NewText("a")},
+		Q: Q{{Name: nameRDFType, Value: NewText("not a URL")}},
+	}
+	err = p.SetValueChecked(elemTestA.Space, elemTestA.Local, bad)
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected ErrInvalid, got %v", err)
+	}
+	if _, ok := p.Properties[elemTestA]; ok {
+		t.Error("invalid value was stored")
+	}
+}
+
+// The packets written by this library must pass the checks, including
+// packets which use xml:lang on structures and arrays.
+func TestCheckQualifiersRoundTrip(t *testing.T) {
+	for _, tc := range encodeTestCases {
+		buf := &bytes.Buffer{}
+		err := tc.in.Write(buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diags := p.CheckQualifiers(); len(diags) > 0 {
+			t.Errorf("%s: unexpected diagnostics %v", tc.desc, diags)
+		}
+	}
+}
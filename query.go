@@ -0,0 +1,433 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// A Query is a filter condition for XMP packets.
+//
+// Queries are written as conditions on paths (see [Path]), combined with
+// "and", "or", "not" and parentheses.  The following conditions are
+// supported:
+//
+//	path exists              the path selects at least one value
+//	path missing             the path selects no value
+//	path = value             equality
+//	path != value            inequality
+//	path < value             (also <=, >, >=) ordering
+//	path ~ value             case-insensitive substring match
+//
+// Values are numbers or double-quoted strings.  If both sides of a
+// comparison are numbers, they are compared numerically.  If a path
+// selects several values, for example the elements of an array, the
+// condition holds if it holds for at least one of the values.
+//
+// A path can be followed by "@" and a language tag to restrict the
+// comparison to entries of language alternatives in the given language.
+// Language matching is hierarchical, so that "@de" matches entries tagged
+// "de" and "de-CH".  Example:
+//
+//	dc:rights missing and xmp:Rating >= 4
+//	dc:title@de ~ "Sommer" or dc:subject = "summer"
+type Query struct {
+	root queryNode
+	src  string
+}
+
+// ParseQuery parses the string form of a query.
+func ParseQuery(s string) (*Query, error) {
+	qp := &queryParser{src: s}
+	err := qp.tokenize()
+	if err != nil {
+		return nil, err
+	}
+	root, err := qp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if qp.pos < len(qp.tokens) {
+		return nil, qp.errorf("unexpected %q", qp.tokens[qp.pos].text)
+	}
+	return &Query{root: root, src: s}, nil
+}
+
+func (q *Query) String() string {
+	return q.src
+}
+
+// Match reports whether the packet satisfies the query.
+func (q *Query) Match(p *Packet) bool {
+	return q.root.match(p)
+}
+
+// Query returns the keys of all packets in the collection which satisfy
+// the query.
+func (c *Collection) Query(q *Query) ([]string, error) {
+	return c.QueryContext(context.Background(), q)
+}
+
+// QueryContext is like [Collection.Query], but the search is aborted with
+// an error if the context is cancelled or its deadline expires.
+func (c *Collection) QueryContext(ctx context.Context, q *Query) ([]string, error) {
+	keys, err := c.Store.Keys()
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p, err := c.Store.Load(key)
+		if err != nil {
+			return nil, err
+		}
+		if q.Match(p) {
+			res = append(res, key)
+		}
+	}
+	return res, nil
+}
+
+type queryNode interface {
+	match(p *Packet) bool
+}
+
+type queryAnd []queryNode
+
+func (n queryAnd) match(p *Packet) bool {
+	for _, child := range n {
+		if !child.match(p) {
+			return false
+		}
+	}
+	return true
+}
+
+type queryOr []queryNode
+
+func (n queryOr) match(p *Packet) bool {
+	for _, child := range n {
+		if child.match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+type queryNot struct {
+	child queryNode
+}
+
+func (n queryNot) match(p *Packet) bool {
+	return !n.child.match(p)
+}
+
+type queryCond struct {
+	path  Path
+	lang  *language.Tag
+	op    string
+	value string
+}
+
+func (n *queryCond) match(p *Packet) bool {
+	var vals []string
+	for _, raw := range p.Select(n.path) {
+		txt, ok := raw.(Text)
+		if !ok {
+			if u, isURL := raw.(URL); isURL && u.V != nil && n.lang == nil {
+				vals = append(vals, u.V.String())
+			}
+			continue
+		}
+		if n.lang != nil {
+			tag, _ := txt.Q.StripLanguage()
+			if !langMatches(*n.lang, tag) {
+				continue
+			}
+		}
+		vals = append(vals, txt.V)
+	}
+
+	switch n.op {
+	case "exists":
+		return len(vals) > 0
+	case "missing":
+		return len(vals) == 0
+	}
+	for _, v := range vals {
+		if compareQueryValue(v, n.op, n.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// langMatches reports whether tag is equal to or more specific than want.
+func langMatches(want, tag language.Tag) bool {
+	if tag == language.Und {
+		return false
+	}
+	for {
+		if tag == want {
+			return true
+		}
+		parent := tag.Parent()
+		if parent == tag || parent == language.Und {
+			return false
+		}
+		tag = parent
+	}
+}
+
+func compareQueryValue(have, op, want string) bool {
+	if op == "~" {
+		return strings.Contains(strings.ToLower(have), strings.ToLower(want))
+	}
+
+	var cmp int
+	x, errX := strconv.ParseFloat(strings.TrimSpace(have), 64)
+	y, errY := strconv.ParseFloat(want, 64)
+	if errX == nil && errY == nil {
+		switch {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(have, want)
+	}
+
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+type queryToken struct {
+	text   string
+	quoted bool
+	pos    int
+}
+
+type queryParser struct {
+	src    string
+	tokens []queryToken
+	pos    int
+}
+
+func (qp *queryParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("query %q: %s", qp.src, fmt.Sprintf(format, args...))
+}
+
+func (qp *queryParser) tokenize() error {
+	s := qp.src
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case c == '(' || c == ')' || c == '~' || c == '=':
+			qp.tokens = append(qp.tokens, queryToken{text: s[i : i+1], pos: i})
+			i++
+		case c == '<' || c == '>' || c == '!':
+			j := i + 1
+			if j < len(s) && s[j] == '=' {
+				j++
+			}
+			if s[i:j] == "!" {
+				return qp.errorf("unexpected '!' at position %d", i)
+			}
+			qp.tokens = append(qp.tokens, queryToken{text: s[i:j], pos: i})
+			i = j
+		case c == '"':
+			val, err := strconv.QuotedPrefix(s[i:])
+			if err != nil {
+				return qp.errorf("unterminated string at position %d", i)
+			}
+			text, _ := strconv.Unquote(val)
+			qp.tokens = append(qp.tokens, queryToken{text: text, quoted: true, pos: i})
+			i += len(val)
+		default:
+			j := i
+			depth := 0
+			for j < len(s) {
+				c, size := utf8.DecodeRuneInString(s[j:])
+				if c == '{' {
+					depth++
+				} else if c == '}' {
+					depth--
+				} else if depth == 0 && (unicode.IsSpace(c) || strings.ContainsRune("()=<>!~\"", c)) {
+					break
+				}
+				j += size
+			}
+			qp.tokens = append(qp.tokens, queryToken{text: s[i:j], pos: i})
+			i = j
+		}
+	}
+	return nil
+}
+
+func (qp *queryParser) peek() string {
+	if qp.pos >= len(qp.tokens) {
+		return ""
+	}
+	tok := qp.tokens[qp.pos]
+	if tok.quoted {
+		return ""
+	}
+	return tok.text
+}
+
+func (qp *queryParser) parseOr() (queryNode, error) {
+	var res queryOr
+	for {
+		n, err := qp.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+		if qp.peek() != "or" {
+			break
+		}
+		qp.pos++
+	}
+	if len(res) == 1 {
+		return res[0], nil
+	}
+	return res, nil
+}
+
+func (qp *queryParser) parseAnd() (queryNode, error) {
+	var res queryAnd
+	for {
+		n, err := qp.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+		if qp.peek() != "and" {
+			break
+		}
+		qp.pos++
+	}
+	if len(res) == 1 {
+		return res[0], nil
+	}
+	return res, nil
+}
+
+func (qp *queryParser) parseNot() (queryNode, error) {
+	switch qp.peek() {
+	case "not":
+		qp.pos++
+		n, err := qp.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return queryNot{child: n}, nil
+	case "(":
+		qp.pos++
+		n, err := qp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if qp.peek() != ")" {
+			return nil, qp.errorf("missing ')'")
+		}
+		qp.pos++
+		return n, nil
+	}
+	return qp.parseCond()
+}
+
+func (qp *queryParser) parseCond() (queryNode, error) {
+	if qp.pos >= len(qp.tokens) {
+		return nil, qp.errorf("unexpected end of query")
+	}
+	tok := qp.tokens[qp.pos]
+	qp.pos++
+	if tok.quoted {
+		return nil, qp.errorf("expected path at position %d", tok.pos)
+	}
+
+	n := &queryCond{}
+	pathStr := tok.text
+	if i := strings.LastIndex(pathStr, "@"); i > strings.LastIndex(pathStr, "}") {
+		tag, err := language.Parse(pathStr[i+1:])
+		if err != nil {
+			return nil, qp.errorf("invalid language tag %q", pathStr[i+1:])
+		}
+		n.lang = &tag
+		pathStr = pathStr[:i]
+	}
+	path, err := ParsePath(pathStr)
+	if err != nil {
+		return nil, qp.errorf("invalid path %q", pathStr)
+	}
+	n.path = path
+
+	op := qp.peek()
+	switch op {
+	case "exists", "missing":
+		qp.pos++
+		n.op = op
+		return n, nil
+	case "=", "!=", "<", "<=", ">", ">=", "~":
+		qp.pos++
+		n.op = op
+	default:
+		return nil, qp.errorf("expected operator after %q", tok.text)
+	}
+
+	if qp.pos >= len(qp.tokens) {
+		return nil, qp.errorf("missing value after %q", op)
+	}
+	val := qp.tokens[qp.pos]
+	qp.pos++
+	if !val.quoted {
+		if _, err := strconv.ParseFloat(val.text, 64); err != nil {
+			return nil, qp.errorf("invalid value %q", val.text)
+		}
+	}
+	n.value = val.text
+	return n, nil
+}
@@ -0,0 +1,145 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestQuery(t *testing.T) {
+	const dcNS = "http://purl.org/dc/elements/1.1/"
+
+	p := NewPacket()
+	p.SetValue(basicNamespace, "Rating", Real{V: 4})
+	title := Localized{Default: NewText("Summer holiday")}
+	title.Set(language.MustParse("de-CH"), "Sommerferien")
+	p.SetValue(dcNS, "title", title)
+	subject := UnorderedArray[Text]{}
+	subject.Append(NewText("beach"))
+	subject.Append(NewText("sea"))
+	p.SetValue(dcNS, "subject", subject)
+
+	cases := []struct {
+		query string
+		want  bool
+	}{
+		{"dc:rights missing and xmp:Rating >= 4", true},
+		{"dc:rights exists or xmp:Rating > 4", false},
+		{"xmp:Rating = 4.0", true},
+		{"xmp:Rating != 4", false},
+		{"not xmp:Rating < 3", true},
+		{`dc:subject = "sea"`, true},
+		{`dc:subject = "mountain"`, false},
+		{`dc:title ~ "HOLIDAY"`, true},
+		{`dc:title@de ~ "sommer"`, true},
+		{`dc:title@de ~ "holiday"`, false},
+		{`dc:title@fr exists`, false},
+		{`(dc:rights exists or dc:subject = "beach") and xmp:Rating >= 4`, true},
+		{`{http://purl.org/dc/elements/1.1/}subject[2] = "sea"`, true},
+	}
+	for _, c := range cases {
+		q, err := ParseQuery(c.query)
+		if err != nil {
+			t.Errorf("%q: %v", c.query, err)
+			continue
+		}
+		if got := q.Match(p); got != c.want {
+			t.Errorf("%q: got %t, want %t", c.query, got, c.want)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"dc:rights",
+		"dc:rights =",
+		"xmp:Rating >= four",
+		`dc:title@!! exists`,
+		"(dc:rights exists",
+		"dc:rights exists dc:title exists",
+		`"dc:rights" exists`,
+	} {
+		if _, err := ParseQuery(s); err == nil {
+			t.Errorf("%q: missing error", s)
+		}
+	}
+}
+
+// TestQueryNonASCII is a regression test: bytes of multi-byte UTF-8
+// sequences used to be misread as white space, which made the tokenizer
+// loop forever.
+func TestQueryNonASCII(t *testing.T) {
+	const dcNS = "http://purl.org/dc/elements/1.1/"
+
+	p := NewPacket()
+	subject := UnorderedArray[Text]{}
+	subject.Append(NewText("voilà"))
+	p.SetValue(dcNS, "subject", subject)
+
+	for _, c := range []struct {
+		query string
+		want  bool
+	}{
+		{`dc:subject = "voilà"`, true},
+		{"dc:subject\u00a0=\u00a0\"voilà\"", true},
+		{`dc:subject = "été"`, false},
+	} {
+		q, err := ParseQuery(c.query)
+		if err != nil {
+			t.Errorf("%q: %v", c.query, err)
+			continue
+		}
+		if got := q.Match(p); got != c.want {
+			t.Errorf("%q: got %t, want %t", c.query, got, c.want)
+		}
+	}
+
+	// Unquoted non-ASCII words are not valid values, but must not hang.
+	if _, err := ParseQuery("dc:title = voilà"); err == nil {
+		t.Error("missing error for unquoted value")
+	}
+}
+
+// TestQueryNilURL is a regression test: URL values without a URL used to
+// cause a panic.
+func TestQueryNilURL(t *testing.T) {
+	p := NewPacket()
+	p.Properties[xml.Name{Space: basicNamespace, Local: "BaseURL"}] = URL{}
+
+	for _, c := range []struct {
+		query string
+		want  bool
+	}{
+		{"xmp:BaseURL exists", false},
+		{"xmp:BaseURL missing", true},
+		{`xmp:BaseURL = ""`, false},
+		{`xmp:BaseURL != ""`, false},
+	} {
+		q, err := ParseQuery(c.query)
+		if err != nil {
+			t.Errorf("%q: %v", c.query, err)
+			continue
+		}
+		if got := q.Match(p); got != c.want {
+			t.Errorf("%q: got %t, want %t", c.query, got, c.want)
+		}
+	}
+}
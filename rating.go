@@ -0,0 +1,106 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"math"
+)
+
+// RatingRejected is the xmp:Rating value used to mark a rejected resource.
+const RatingRejected = -1
+
+// StarsToPercent converts an xmp:Rating star value into a percent-based
+// MicrosoftPhoto:Rating value, using the mapping from the Metadata Working
+// Group guidelines: 1 star maps to 1, 2 stars to 25, 3 stars to 50,
+// 4 stars to 75 and 5 stars to 99.  Unrated and rejected resources both map
+// to 0, since MicrosoftPhoto:Rating has no representation for rejection.
+func StarsToPercent(stars float64) int {
+	if stars <= 0 {
+		return 0
+	}
+	switch n := math.Round(stars); {
+	case n <= 1:
+		return 1
+	case n >= 5:
+		return 99
+	default:
+		return int(n-1) * 25
+	}
+}
+
+// PercentToStars converts a percent-based MicrosoftPhoto:Rating value into
+// an xmp:Rating star value.  This is the inverse of [StarsToPercent]:
+// values 1-12 map to 1 star, 13-37 to 2 stars, 38-62 to 3 stars, 63-87 to
+// 4 stars, and 88-100 to 5 stars.
+func PercentToStars(percent int) float64 {
+	switch {
+	case percent <= 0:
+		return 0
+	case percent <= 12:
+		return 1
+	case percent <= 37:
+		return 2
+	case percent <= 62:
+		return 3
+	case percent <= 87:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// Rating returns the star rating of the resource.  The result is
+// [RatingRejected] for rejected resources, 0 for unrated resources, or a
+// value in the range (0, 5].
+//
+// Following the Metadata Working Group guidelines, xmp:Rating takes
+// precedence.  If this is not set, the value of MicrosoftPhoto:Rating is
+// converted using [PercentToStars].  The second return value is false if
+// neither property is present.
+func (p *Packet) Rating() (float64, bool) {
+	if r, err := PacketGetValue[Real](p, basicNamespace, "Rating"); err == nil {
+		return r.V, true
+	}
+	if r, err := PacketGetValue[Integer](p, msPhotoNamespace, "Rating"); err == nil {
+		return PercentToStars(int(r.V)), true
+	}
+	return 0, false
+}
+
+// SetRating sets the star rating of the resource.  The value must be
+// [RatingRejected], 0 for "unrated", or a value in the range (0, 5].
+//
+// This sets xmp:Rating.  If the packet also contains a
+// MicrosoftPhoto:Rating property, this is updated to match, so that
+// applications using either property see the same rating.
+func (p *Packet) SetRating(stars float64) {
+	p.SetValue(basicNamespace, "Rating", Real{V: stars})
+
+	_, hasMS := p.Properties[xml.Name{Space: msPhotoNamespace, Local: "Rating"}]
+	if hasMS {
+		p.SetValue(msPhotoNamespace, "Rating", Integer{V: int64(StarsToPercent(stars))})
+	}
+}
+
+// IsRejected reports whether the resource has been marked as rejected.
+func (p *Packet) IsRejected() bool {
+	r, ok := p.Rating()
+	return ok && r == RatingRejected
+}
+
+const msPhotoNamespace = "http://ns.microsoft.com/photo/1.0/"
@@ -0,0 +1,72 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStarsToPercent(t *testing.T) {
+	for stars := 0; stars <= 5; stars++ {
+		pct := StarsToPercent(float64(stars))
+		back := PercentToStars(pct)
+		if back != float64(stars) {
+			t.Errorf("%d stars -> %d%% -> %g stars", stars, pct, back)
+		}
+	}
+	if got := StarsToPercent(RatingRejected); got != 0 {
+		t.Errorf("rejected -> %d%%, want 0", got)
+	}
+}
+
+func TestRating(t *testing.T) {
+	p := NewPacket()
+	if _, ok := p.Rating(); ok {
+		t.Error("empty packet has a rating")
+	}
+
+	// A percent rating is used if xmp:Rating is missing.
+	p.SetValue(msPhotoNamespace, "Rating", Integer{V: 75})
+	if r, ok := p.Rating(); !ok || r != 4 {
+		t.Errorf("got rating %g, %t, want 4", r, ok)
+	}
+
+	// Setting the rating keeps both properties in sync.
+	p.SetRating(2)
+	ms, err := PacketGetValue[Integer](p, msPhotoNamespace, "Rating")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms.V != 25 {
+		t.Errorf("MicrosoftPhoto:Rating = %d, want 25", ms.V)
+	}
+
+	p.SetRating(RatingRejected)
+	if !p.IsRejected() {
+		t.Error("resource not rejected")
+	}
+	want := map[xml.Name]Raw{
+		{Space: basicNamespace, Local: "Rating"}:   Text{V: "-1"},
+		{Space: msPhotoNamespace, Local: "Rating"}: Text{V: "0"},
+	}
+	if d := cmp.Diff(want, p.Properties); d != "" {
+		t.Errorf("properties differ (-want +got):\n%s", d)
+	}
+}
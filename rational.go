@@ -0,0 +1,177 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Rational represents a fraction of two integers, as used for many EXIF
+// properties.  The XMP representation is "numerator/denominator".
+//
+// The fraction is stored exactly as given, without reducing it to lowest
+// terms.  A zero denominator is allowed, since some EXIF writers use the
+// value "0/0" to indicate an unknown value.
+type Rational struct {
+	Num, Den int64
+	Q
+}
+
+// NewRational creates a new Rational value.
+func NewRational(num, den int64, qualifiers ...Qualifier) Rational {
+	return Rational{Num: num, Den: den, Q: qualifiers}
+}
+
+// RationalFromFloat returns the fraction closest to f whose denominator
+// does not exceed maxDen.
+func RationalFromFloat(f float64, maxDen int64) Rational {
+	if maxDen < 1 {
+		maxDen = 1
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) >= math.MaxInt64 {
+		return Rational{}
+	}
+
+	// Compute the convergents of the continued fraction expansion of f,
+	// see https://en.wikipedia.org/wiki/Continued_fraction .
+	neg := f < 0
+	x := math.Abs(f)
+	var p0, q0, p1, q1 int64 = 0, 1, 1, 0
+	for {
+		a := math.Floor(x)
+		if a > math.MaxInt64/2 {
+			break
+		}
+		ai := int64(a)
+		p2, q2 := ai*p1+p0, ai*q1+q0
+		if q2 > maxDen || p2 < 0 {
+			break
+		}
+		p0, q0, p1, q1 = p1, q1, p2, q2
+		frac := x - a
+		if frac < 1e-12 {
+			break
+		}
+		x = 1 / frac
+	}
+	if q1 == 0 {
+		// Even the first convergent has too large a numerator.
+		return Rational{}
+	}
+	if neg {
+		p1 = -p1
+	}
+	return Rational{Num: p1, Den: q1}
+}
+
+// Float64 returns the value of the fraction.  If the denominator is zero,
+// the result is NaN.
+func (r Rational) Float64() float64 {
+	if r.Den == 0 {
+		return math.NaN()
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+// Rat returns the value of the fraction as a [big.Rat].  If the
+// denominator is zero, nil is returned.
+func (r Rational) Rat() *big.Rat {
+	if r.Den == 0 {
+		return nil
+	}
+	return big.NewRat(r.Num, r.Den)
+}
+
+func (r Rational) String() string {
+	return strconv.FormatInt(r.Num, 10) + "/" + strconv.FormatInt(r.Den, 10)
+}
+
+// IsZero implements the [Value] interface.
+func (r Rational) IsZero() bool {
+	return r.Num == 0 && r.Den == 0 && len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r Rational) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: r.String(),
+		Q: r.Q,
+	}
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Rational) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	res, msg, err := parseRational(v)
+	if err != nil || msg != "" {
+		return nil, ErrInvalid
+	}
+	return res, nil
+}
+
+// repair implements the repairer interface.
+// Integers and decimal numbers are converted to the exact fraction.
+func (Rational) repair(val Raw) (Value, string, bool) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, "", false
+	}
+	res, msg, err := parseRational(v)
+	if err != nil {
+		return nil, "", false
+	}
+	return res, msg, true
+}
+
+// parseRational parses a rational number.  If the input is malformed but
+// can be repaired, msg describes the problem.
+func parseRational(v Text) (res Rational, msg string, err error) {
+	s := strings.TrimSpace(v.V)
+	if numStr, denStr, ok := strings.Cut(s, "/"); ok {
+		num, err1 := strconv.ParseInt(numStr, 10, 64)
+		den, err2 := strconv.ParseInt(denStr, 10, 64)
+		if err1 != nil || err2 != nil {
+			return res, "", ErrInvalid
+		}
+		repaired := s != v.V
+		if den < 0 {
+			if num == math.MinInt64 || den == math.MinInt64 {
+				return res, "", ErrInvalid
+			}
+			num, den = -num, -den
+			repaired = true
+		}
+		res = Rational{Num: num, Den: den, Q: v.Q}
+		if repaired {
+			msg = "malformed rational " + strconv.Quote(v.V)
+		}
+		return res, msg, nil
+	}
+
+	x, ok := new(big.Rat).SetString(s)
+	if !ok || !x.Num().IsInt64() || !x.Denom().IsInt64() {
+		return res, "", ErrInvalid
+	}
+	res = Rational{Num: x.Num().Int64(), Den: x.Denom().Int64(), Q: v.Q}
+	return res, "number instead of rational " + strconv.Quote(v.V), nil
+}
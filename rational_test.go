@@ -0,0 +1,107 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRationalDecode(t *testing.T) {
+	testCases := []struct {
+		in       string
+		num, den int64
+		ok       bool
+		repaired bool
+	}{
+		{"1/250", 1, 250, true, false},
+		{"28/10", 28, 10, true, false},
+		{"-1/3", -1, 3, true, false},
+		{"0/0", 0, 0, true, false},
+		{"1/-3", -1, 3, true, true},
+		{" 5/2 ", 5, 2, true, true},
+		{"2.8", 14, 5, true, true},
+		{"7", 7, 1, true, true},
+		{"1/x", 0, 0, false, false},
+		{"", 0, 0, false, false},
+	}
+	for _, tc := range testCases {
+		_, err := Rational{}.DecodeAnother(Text{V: tc.in})
+		if valid := err == nil; valid != (tc.ok && !tc.repaired) {
+			t.Errorf("%q: DecodeAnother gave error %v", tc.in, err)
+		}
+		v, msg, ok := repairValue(Rational{}, Text{V: tc.in})
+		if ok != tc.ok {
+			t.Errorf("%q: got ok=%t, want %t", tc.in, ok, tc.ok)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		if repaired := msg != ""; repaired != tc.repaired {
+			t.Errorf("%q: repaired=%t, want %t", tc.in, repaired, tc.repaired)
+		}
+		r := v.(Rational)
+		if r.Num != tc.num || r.Den != tc.den {
+			t.Errorf("%q: got %d/%d, want %d/%d", tc.in, r.Num, r.Den, tc.num, tc.den)
+		}
+	}
+}
+
+func TestRationalRoundTrip(t *testing.T) {
+	in := NewRational(10, 2500)
+	enc := in.EncodeXMP(nil)
+	if d := cmp.Diff(Text{V: "10/2500"}, enc); d != "" {
+		t.Error(d)
+	}
+	out, err := Rational{}.DecodeAnother(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(in, out); d != "" {
+		t.Error(d)
+	}
+}
+
+func TestRationalFromFloat(t *testing.T) {
+	testCases := []struct {
+		f        float64
+		maxDen   int64
+		num, den int64
+	}{
+		{0.004, 1000, 1, 250},
+		{2.8, 10, 14, 5},
+		{math.Pi, 1000, 355, 113},
+		{-0.5, 100, -1, 2},
+		{3, 100, 3, 1},
+	}
+	for _, tc := range testCases {
+		r := RationalFromFloat(tc.f, tc.maxDen)
+		if r.Num != tc.num || r.Den != tc.den {
+			t.Errorf("%g: got %s, want %d/%d", tc.f, r, tc.num, tc.den)
+		}
+	}
+
+	if f := NewRational(1, 4).Float64(); f != 0.25 {
+		t.Errorf("Float64: got %g", f)
+	}
+	if f := NewRational(1, 0).Float64(); !math.IsNaN(f) {
+		t.Errorf("Float64 with zero denominator: got %g", f)
+	}
+}
@@ -0,0 +1,80 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package samples provides a set of real-world XMP packets for use in tests.
+//
+// The packets were extracted, unmodified, from image files published as part
+// of the FlatBuffers project (https://github.com/google/flatbuffers) and were
+// written by Adobe Photoshop and by other image software.  They are
+// distributed under the Apache License, Version 2.0; see the file
+// data/LICENSE.flatbuffers and Licenses.txt in the top-level directory.  The
+// packets are embedded into the binary, so that they are available without
+// network or file system access.
+package samples
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+//go:embed data/*.xmp
+var data embed.FS
+
+// FS returns a file system containing the sample packets.  All files are
+// located in the root directory of the file system.
+func FS() fs.FS {
+	sub, err := fs.Sub(data, "data")
+	if err != nil {
+		panic(err) // unreachable
+	}
+	return sub
+}
+
+// Names returns the names of all sample packets, in alphabetical order.
+func Names() []string {
+	entries, err := data.ReadDir("data")
+	if err != nil {
+		panic(err) // unreachable
+	}
+	var res []string
+	for _, e := range entries {
+		res = append(res, e.Name())
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Get returns the contents of the sample packet with the given name.
+// The caller may modify the returned slice.
+func Get(name string) ([]byte, error) {
+	return data.ReadFile(path.Join("data", name))
+}
+
+// All returns the contents of all sample packets, in the order given by
+// [Names].
+func All() [][]byte {
+	var res [][]byte
+	for _, name := range Names() {
+		body, err := Get(name)
+		if err != nil {
+			panic(err) // unreachable
+		}
+		res = append(res, body)
+	}
+	return res
+}
@@ -0,0 +1,68 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package samples
+
+import (
+	"bytes"
+	"io/fs"
+	"testing"
+
+	"seehuhn.de/go/xmp"
+)
+
+func TestSamples(t *testing.T) {
+	names := Names()
+	if len(names) == 0 {
+		t.Fatal("no samples found")
+	}
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			body, err := Get(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body2, err := fs.ReadFile(FS(), name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(body, body2) {
+				t.Error("Get and FS disagree")
+			}
+
+			p, err := xmp.Read(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(p.Properties) == 0 {
+				t.Error("no properties found")
+			}
+			for _, d := range p.CheckQualifiers() {
+				t.Error(d)
+			}
+		})
+	}
+	if len(All()) != len(names) {
+		t.Error("All and Names disagree")
+	}
+}
+
+func TestGetMissing(t *testing.T) {
+	_, err := Get("does-not-exist.xmp")
+	if err == nil {
+		t.Error("expected error")
+	}
+}
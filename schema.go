@@ -0,0 +1,284 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// A Schema describes the properties of an XMP namespace.
+// Schemas are derived from XMP namespace structs, see [RegisterSchema].
+//
+// Schemas can be converted to JSON, to provide a machine-readable
+// description of the namespace, for example for generating metadata
+// editing forms.
+type Schema struct {
+	// Namespace is the namespace URI.
+	Namespace string `json:"namespace"`
+
+	// Prefix is the preferred prefix for the namespace.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Properties lists the properties of the namespace, sorted by name.
+	Properties []PropertyInfo `json:"properties"`
+}
+
+// PropertyInfo describes one property of a [Schema].
+type PropertyInfo struct {
+	// Name is the local name of the property.
+	Name string `json:"name"`
+
+	// Field is the name of the corresponding field in the namespace struct.
+	Field string `json:"field"`
+
+	// Type is the Go type used to represent values of the property.
+	Type reflect.Type `json:"-"`
+
+	// ValueType is the name of the XMP value type, e.g. "Text", "Date" or
+	// "ProperName".  For arrays, this is the type of the array elements.
+	// Language alternatives have value type "Lang Alt".
+	ValueType string `json:"valueType"`
+
+	// IsArray is true if the property value is an array.
+	IsArray bool `json:"isArray,omitempty"`
+
+	// ArrayKind is the kind of the array, if IsArray is true.
+	ArrayKind RawArrayType `json:"arrayKind,omitempty"`
+
+	// Constraint (optional) describes restrictions on the format of the
+	// values, e.g. "ISO 8601 date".
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// Property returns information about the property with the given local
+// name.
+func (s *Schema) Property(name string) (PropertyInfo, bool) {
+	i := sort.Search(len(s.Properties), func(i int) bool {
+		return s.Properties[i].Name >= name
+	})
+	if i < len(s.Properties) && s.Properties[i].Name == name {
+		return s.Properties[i], true
+	}
+	return PropertyInfo{}, false
+}
+
+var (
+	schemaMu sync.RWMutex
+	schemas  = map[string]*Schema{}
+)
+
+// RegisterSchema adds the namespace described by an XMP namespace struct to
+// the schema registry.  The argument can be either a struct or a pointer to
+// a struct.  Any previously registered schema for the same namespace is
+// replaced.
+//
+// The schemas for the namespaces defined in this package are registered
+// automatically.
+func RegisterSchema(model any) (*Schema, error) {
+	s, err := schemaFromModel(model)
+	if err != nil {
+		return nil, err
+	}
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	schemas[s.Namespace] = s
+	return s, nil
+}
+
+// LookupSchema returns the registered schema for a namespace,
+// or nil if the namespace is not known.
+func LookupSchema(namespace string) *Schema {
+	schemaMu.RLock()
+	defer schemaMu.RUnlock()
+	return schemas[namespace]
+}
+
+// Schemas returns all registered schemas, sorted by namespace URI.
+func Schemas() []*Schema {
+	schemaMu.RLock()
+	defer schemaMu.RUnlock()
+	res := make([]*Schema, 0, len(schemas))
+	for _, s := range schemas {
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Namespace < res[j].Namespace
+	})
+	return res
+}
+
+// lookupProperty returns information about a property from the schema
+// registry.
+func lookupProperty(name xml.Name) (PropertyInfo, bool) {
+	s := LookupSchema(name.Space)
+	if s == nil {
+		return PropertyInfo{}, false
+	}
+	return s.Property(name.Local)
+}
+
+func schemaFromModel(model any) (*Schema, error) {
+	st := reflect.TypeOf(model)
+	if st != nil && st.Kind() == reflect.Pointer {
+		st = st.Elem()
+	}
+	if st == nil || st.Kind() != reflect.Struct {
+		return nil, errors.New("no struct found")
+	}
+
+	s := &Schema{}
+	for i := 0; i < st.NumField(); i++ {
+		fInfo := st.Field(i)
+		switch fInfo.Type {
+		case nsTagType:
+			s.Namespace = fInfo.Tag.Get("xmp")
+			continue
+		case prefixTagType:
+			s.Prefix = fInfo.Tag.Get("xmp")
+			continue
+		}
+		if !fInfo.IsExported() || !fInfo.Type.Implements(typeType) {
+			continue
+		}
+
+		info := PropertyInfo{
+			Name:  fInfo.Tag.Get("xmp"),
+			Field: fInfo.Name,
+			Type:  fInfo.Type,
+		}
+		if info.Name == "" {
+			info.Name = fInfo.Name
+		}
+		zero := reflect.Zero(fInfo.Type).Interface().(Value)
+		elemType := fInfo.Type
+		if a, ok := zero.EncodeXMP(NewPacket()).(RawArray); ok {
+			info.IsArray = true
+			info.ArrayKind = a.Kind
+			if v, ok := fInfo.Type.FieldByName("V"); ok && v.Type.Kind() == reflect.Slice {
+				elemType = v.Type.Elem()
+			}
+		}
+		info.ValueType, info.Constraint = describeType(elemType)
+		s.Properties = append(s.Properties, info)
+	}
+	if s.Namespace == "" {
+		return nil, errors.New("XMP namespace not specified")
+	}
+	sort.Slice(s.Properties, func(i, j int) bool {
+		return s.Properties[i].Name < s.Properties[j].Name
+	})
+	return s, nil
+}
+
+// describeType returns the XMP value type name and the format constraint
+// for a Go type.
+func describeType(t reflect.Type) (string, string) {
+	switch t {
+	case reflect.TypeFor[Localized]():
+		return "Lang Alt", ""
+	case reflect.TypeFor[Real]():
+		return "Real", "decimal number"
+	case reflect.TypeFor[Decimal]():
+		return "Decimal", "decimal number, preserved as written"
+	case reflect.TypeFor[Integer]():
+		return "Integer", "decimal integer"
+	case reflect.TypeFor[GPSCoordinate]():
+		return "GPSCoordinate", `latitude or longitude, e.g. "52,30.123N"`
+	case reflect.TypeFor[Rational]():
+		return "Rational", `fraction, e.g. "1/250"`
+	case reflect.TypeFor[Date]():
+		return "Date", "ISO 8601 date"
+	case reflect.TypeFor[DateRange]():
+		return "DateRange", "ISO 8601 time interval"
+	case reflect.TypeFor[Locale]():
+		return "Locale", "RFC 3066 language tag"
+	case reflect.TypeFor[MimeType]():
+		return "MIMEType", "RFC 2046 media type"
+	case reflect.TypeFor[OptionalBool]():
+		return "Boolean", `"True" or "False"`
+	case reflect.TypeFor[GUID]():
+		return "GUID", "URI"
+	case reflect.TypeFor[URL]():
+		return "URL", "URI"
+	case reflect.TypeFor[URI]():
+		return "URI", "URI, written as text"
+	}
+	return t.Name(), ""
+}
+
+func init() {
+	for _, model := range []any{
+		&DublinCore{},
+		&Basic{},
+		&RightsManagement{},
+		&MediaManagement{},
+		&BasicJobTicket{},
+		&PagedText{},
+		&Note{},
+		&PDF{},
+		&PDFA{},
+		&PDFX{},
+		&Photoshop{},
+		&DynamicMedia{},
+		&EXIF{},
+		&IPTCCore{},
+		&IPTCExt{},
+		&PLUS{},
+		&GPano{},
+		&GDepth{},
+		&GImage{},
+		&MicrosoftPhoto{},
+		&MicrosoftPhotoRegions{},
+		&MWGRegions{},
+		&MWGCollections{},
+		&MWGKeywords{},
+		&DigiKam{},
+		&PRISM{},
+		&DJI{},
+		&ContentCredentials{},
+	} {
+		_, err := RegisterSchema(model)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// repairArrayKinds corrects the array kinds of properties which are
+// described in the schema registry.  For example, dc:creator is sometimes
+// incorrectly written as a bag instead of a sequence.  All repairs are
+// recorded as diagnostics.
+func (p *Packet) repairArrayKinds() {
+	for name, val := range p.Properties {
+		a, ok := val.(RawArray)
+		if !ok {
+			continue
+		}
+		info, ok := lookupProperty(name)
+		if !ok || !info.IsArray || info.ArrayKind == a.Kind {
+			continue
+		}
+		p.addDiagnostic(name, fmt.Sprintf("array kind %s changed to %s", a.Kind, info.ArrayKind))
+		a.Kind = info.ArrayKind
+		p.Properties[name] = a
+	}
+}
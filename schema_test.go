@@ -0,0 +1,144 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchema(t *testing.T) {
+	s := LookupSchema("http://purl.org/dc/elements/1.1/")
+	if s == nil {
+		t.Fatal("Dublin Core schema not registered")
+	}
+	if s.Prefix != "dc" {
+		t.Errorf("wrong prefix %q", s.Prefix)
+	}
+	info, ok := s.Property("creator")
+	if !ok {
+		t.Fatal("dc:creator not found")
+	}
+	if !info.IsArray || info.ArrayKind != Ordered || info.Field != "Creator" {
+		t.Errorf("wrong property info %v", info)
+	}
+	if _, ok := s.Property("nonexistent"); ok {
+		t.Error("unexpected property found")
+	}
+
+	type testModel struct {
+		_ Namespace `xmp:"http://ns.seehuhn.de/test/schema/#"`
+
+		A Localized
+		B Text `xmp:"bee"`
+	}
+	s, err := RegisterSchema(testModel{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, info := range s.Properties {
+		names = append(names, info.Name)
+	}
+	if d := cmp.Diff([]string{"A", "bee"}, names); d != "" {
+		t.Errorf("wrong properties (-want +got):\n%s", d)
+	}
+
+	_, err = RegisterSchema(42)
+	if err == nil {
+		t.Error("missing error")
+	}
+}
+
+func TestRepairArrayKinds(t *testing.T) {
+	in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:creator><rdf:Bag><rdf:li>A</rdf:li><rdf:li>B</rdf:li></rdf:Bag></dc:creator>
+<dc:subject><rdf:Bag><rdf:li>x</rdf:li></rdf:Bag></dc:subject>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>`
+	p, err := Read(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creator := xml.Name{Space: "http://purl.org/dc/elements/1.1/", Local: "creator"}
+	a, ok := p.Properties[creator].(RawArray)
+	if !ok || a.Kind != Ordered {
+		t.Errorf("dc:creator not repaired: %v", p.Properties[creator])
+	}
+	want := []Diagnostic{{Property: creator, Message: "array kind Bag changed to Seq"}}
+	if d := cmp.Diff(want, p.Diagnostics()); d != "" {
+		t.Errorf("diagnostics differ (-want +got):\n%s", d)
+	}
+
+	buf := &bytes.Buffer{}
+	err = p.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<rdf:Seq>") {
+		t.Error("repaired array not written as rdf:Seq")
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	all := Schemas()
+	if len(all) == 0 {
+		t.Fatal("no schemas registered")
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Namespace >= all[i].Namespace {
+			t.Errorf("schemas not sorted: %q >= %q", all[i-1].Namespace, all[i].Namespace)
+		}
+	}
+
+	data, err := json.Marshal(LookupSchema("http://purl.org/dc/elements/1.1/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Schema
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, ok := decoded.Property("creator")
+	want := PropertyInfo{
+		Name:      "creator",
+		Field:     "Creator",
+		ValueType: "ProperName",
+		IsArray:   true,
+		ArrayKind: Ordered,
+	}
+	if !ok {
+		t.Fatal("dc:creator missing")
+	}
+	if d := cmp.Diff(want, info); d != "" {
+		t.Errorf("dc:creator differs (-want +got):\n%s", d)
+	}
+	date, _ := decoded.Property("date")
+	if date.ValueType != "Date" || date.Constraint != "ISO 8601 date" {
+		t.Errorf("wrong description for dc:date: %v", date)
+	}
+}
@@ -0,0 +1,160 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// BeatSpliceStretch holds the parameters for beat splice time stretching
+// of audio, as used in xmpDM:beatSpliceParams.
+//
+// See section 1.2.6.1 of part 2 of the XMP specification (2016).
+type BeatSpliceStretch struct {
+	// RiseInDecibel is the amplitude rise, in dB, which is needed to
+	// detect a beat.
+	RiseInDecibel Real
+
+	// RiseInTimeDuration is the time over which the amplitude must rise.
+	RiseInTimeDuration Time
+
+	// UseFileBeatsMarker is true if the beat markers of the file should be
+	// used.
+	UseFileBeatsMarker OptionalBool
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (b BeatSpliceStretch) IsZero() bool {
+	return b.RiseInDecibel.IsZero() && b.RiseInTimeDuration.IsZero() &&
+		b.UseFileBeatsMarker.IsZero() && len(b.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (b BeatSpliceStretch) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     b.Q,
+	}
+	setField(res, p, dmNamespace, "riseInDecibel", b.RiseInDecibel)
+	setField(res, p, dmNamespace, "riseInTimeDuration", b.RiseInTimeDuration)
+	setField(res, p, dmNamespace, "useFileBeatsMarker", b.UseFileBeatsMarker)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (BeatSpliceStretch) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return BeatSpliceStretch{
+		RiseInDecibel:      getField[Real](s, dmNamespace, "riseInDecibel"),
+		RiseInTimeDuration: getField[Time](s, dmNamespace, "riseInTimeDuration"),
+		UseFileBeatsMarker: getField[OptionalBool](s, dmNamespace, "useFileBeatsMarker"),
+		Q:                  s.Q,
+	}, nil
+}
+
+// ResampleStretch holds the parameters for resample time stretching of
+// audio, as used in xmpDM:resampleParams.
+//
+// See section 1.2.6.14 of part 2 of the XMP specification (2016).
+type ResampleStretch struct {
+	// Quality is the quality of the resampling: "High", "Medium", or
+	// "Low".
+	Quality Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (r ResampleStretch) IsZero() bool {
+	return r.Quality.IsZero() && len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r ResampleStretch) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     r.Q,
+	}
+	setField(res, p, dmNamespace, "quality", r.Quality)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (ResampleStretch) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return ResampleStretch{
+		Quality: getField[Text](s, dmNamespace, "quality"),
+		Q:       s.Q,
+	}, nil
+}
+
+// TimeScaleStretch holds the parameters for time-scale stretching of
+// audio, as used in xmpDM:timeScaleParams.
+//
+// See section 1.2.6.18 of part 2 of the XMP specification (2016).
+type TimeScaleStretch struct {
+	// FrameOverlappingPercentage is the overlap of consecutive frames, in
+	// percent.
+	FrameOverlappingPercentage Real
+
+	// FrameSize is the frame size, in milliseconds.
+	FrameSize Real
+
+	// Quality is the quality of the stretching: "High", "Medium", or
+	// "Low".
+	Quality Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (t TimeScaleStretch) IsZero() bool {
+	return t.FrameOverlappingPercentage.IsZero() && t.FrameSize.IsZero() &&
+		t.Quality.IsZero() && len(t.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (t TimeScaleStretch) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     t.Q,
+	}
+	setField(res, p, dmNamespace, "frameOverlappingPercentage", t.FrameOverlappingPercentage)
+	setField(res, p, dmNamespace, "frameSize", t.FrameSize)
+	setField(res, p, dmNamespace, "quality", t.Quality)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (TimeScaleStretch) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return TimeScaleStretch{
+		FrameOverlappingPercentage: getField[Real](s, dmNamespace, "frameOverlappingPercentage"),
+		FrameSize:                  getField[Real](s, dmNamespace, "frameSize"),
+		Quality:                    getField[Text](s, dmNamespace, "quality"),
+		Q:                          s.Q,
+	}, nil
+}
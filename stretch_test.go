@@ -0,0 +1,64 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStretchParams(t *testing.T) {
+	in := &DynamicMedia{
+		StretchMode: NewText("Beat Splice"),
+		BeatSpliceParams: BeatSpliceStretch{
+			RiseInDecibel: Real{V: 6.5},
+			RiseInTimeDuration: Time{
+				Scale: NewRational(1, 1000),
+				Value: Integer{V: 15},
+			},
+			UseFileBeatsMarker: OptionalBool{V: 2},
+		},
+		ResampleParams: ResampleStretch{Quality: NewText("High")},
+		TimeScaleParams: TimeScaleStretch{
+			FrameOverlappingPercentage: Real{V: 12.5},
+			FrameSize:                  Real{V: 40},
+			Quality:                    NewText("Medium"),
+		},
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &DynamicMedia{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
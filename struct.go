@@ -96,9 +96,9 @@ type Basic struct {
 	CreatorTool AgentName
 
 	// Identifier is an unambiguous reference to the resource within a given
-	// context.  An array item may be qualified with xmpidq:Scheme to specify
-	// the identification system for that item.
-	Identifier UnorderedArray[Text]
+	// context.  The Scheme field of an item can be used to specify the
+	// identification system for that item.
+	Identifier UnorderedArray[Identifier]
 
 	// Label is a word or short phrase that identifies a resource within a
 	// local context.
@@ -116,6 +116,10 @@ type Basic struct {
 	// The value must be -1 (rejected), 0 (unrated) or a rating in the range
 	// (0, 5].
 	Rating Real
+
+	// Thumbnails lists thumbnail images for the resource.  The entries
+	// are alternatives, for example in different sizes or formats.
+	Thumbnails AlternativeArray[Thumbnail]
 }
 
 // RightsManagement represents the XMP RightsManagement Management namespace.
@@ -127,9 +131,7 @@ type RightsManagement struct {
 
 	// Certificate is a reference to a digital certificate that can be used to
 	// verify the rights management information.
-	//
-	// For historical reasons, this field has type Text instead of URL.
-	Certificate Text
+	Certificate URI
 
 	// Marked is true if the document has been marked as copyrighted.
 	Marked OptionalBool
@@ -143,9 +145,7 @@ type RightsManagement struct {
 
 	// WebStatement is a URL that can be used to access a rights management
 	// information statement.
-	//
-	// For historical reasons, this field has type Text instead of URL.
-	WebStatement Text
+	WebStatement URI
 }
 
 // MediaManagement represents the XMP Media Management namespace.
@@ -163,17 +163,115 @@ type MediaManagement struct {
 	// DocumentID is a unique identifier for the document.
 	DocumentID Text
 
+	// History is a list of high-level actions which resulted in changes
+	// to the document, with the most recent event last.
+	History OrderedArray[ResourceEvent]
+
+	// Ingredients lists the resources which were incorporated into the
+	// document.  For documents with content credentials, the entries
+	// correspond to the ingredients of the C2PA manifest, see
+	// [Packet.AddIngredient].
+	Ingredients UnorderedArray[ResourceRef]
+
 	// InstanceID is a unique identifier for the document instance.
 	InstanceID Text
 
+	// Manifest lists the resources which are used by a composed document.
+	Manifest UnorderedArray[ManifestItem]
+
 	// OriginalDocumentID is a unique identifier for the original document.
 	OriginalDocumentID Text
 
+	// Pantry holds the metadata of the ingredients of a composed
+	// document.
+	Pantry UnorderedArray[PantryItem]
+
 	// RenditionClass is a rendition class name for this resource.
 	RenditionClass Text
 
 	// RenditionParams can be used to provide additional rendition parameters
 	RenditionParams Text
+
+	// Versions is the version history of the document, with the most
+	// recent version last.
+	Versions OrderedArray[Version]
+}
+
+// Photoshop represents the Adobe Photoshop namespace.
+//
+// See section 3.2 of part 2 of the XMP specification (2016).
+type Photoshop struct {
+	_ Namespace `xmp:"http://ns.adobe.com/photoshop/1.0/"`
+	_ Prefix    `xmp:"photoshop"`
+
+	// AuthorsPosition is the job title of the person listed in dc:creator.
+	AuthorsPosition Text
+
+	// CaptionWriter is the name of the person who wrote the description of
+	// the resource.
+	CaptionWriter ProperName
+
+	// Category is a three-letter category code.  This property is
+	// deprecated.
+	Category Text
+
+	// City is the city where the resource was created.
+	City Text
+
+	// ColorMode is the color mode of the image: 0=Bitmap, 1=Gray scale,
+	// 2=Indexed colour, 3=RGB colour, 4=CMYK colour, 7=Multi-channel,
+	// 8=Duotone, 9=LAB colour.
+	ColorMode Integer
+
+	// Country is the country where the resource was created.
+	Country Text
+
+	// Credit is the credit line for the resource.
+	Credit Text
+
+	// DateCreated is the date the intellectual content of the resource was
+	// created.
+	DateCreated Date
+
+	// DocumentAncestors lists the document IDs of documents which have been
+	// placed into this document.
+	DocumentAncestors UnorderedArray[Text]
+
+	// Headline is a short summary of the contents of the resource.
+	Headline Text
+
+	// History is a history of the resource, in free text form.
+	History Text
+
+	// ICCProfile is the description of the color profile of the image.
+	// See [Packet.SetICCProfile].
+	ICCProfile Text
+
+	// Instructions contains special instructions for the use of the
+	// resource.
+	Instructions Text
+
+	// Source identifies the original owner of the copyright of the
+	// resource.
+	Source Text
+
+	// State is the province or state where the resource was created.
+	State Text
+
+	// TextLayers lists the text layers of the document.
+	TextLayers OrderedArray[Layer]
+
+	// SupplementalCategories lists supplemental category codes.
+	// This property is deprecated.
+	SupplementalCategories UnorderedArray[Text]
+
+	// TransmissionReference is an identifier for the purpose of improved
+	// workflow handling.
+	TransmissionReference Text
+
+	// Urgency is the editorial urgency of the resource, from 1 (most
+	// urgent) to 8 (least urgent).  This property is deprecated.
+	Urgency Integer
 }
 
 // Set sets XMP properties from the fields of a namespace struct.
@@ -193,17 +291,7 @@ func (p *Packet) setOne(v any) error {
 	}
 	st := s.Type()
 
-	var namespace, prefix string
-	for i := 0; i < st.NumField(); i++ {
-		fVal := s.Field(i)
-		fInfo := st.Field(i)
-
-		if fVal.Type() == nsTagType {
-			namespace = fInfo.Tag.Get("xmp")
-		} else if fVal.Type() == prefixTagType {
-			prefix = fInfo.Tag.Get("xmp")
-		}
-	}
+	namespace, prefix := modelNamespace(st)
 	if namespace == "" {
 		return errors.New("XMP namespace not specified")
 	}
@@ -223,16 +311,14 @@ func (p *Packet) setOne(v any) error {
 			val = fVal.Interface().(Value)
 		}
 
-		propertyName := fInfo.Tag.Get("xmp")
-		if propertyName == "" {
-			propertyName = fInfo.Name
-		} else if val == nil {
+		if val == nil && fInfo.Tag.Get("xmp") != "" {
 			return fmt.Errorf("field %s does not implement Type", fInfo.Name)
 		}
+		name := propertyName(fInfo)
 		if !val.IsZero() {
-			p.SetValue(namespace, propertyName, val)
+			p.SetValue(namespace, name, val)
 		} else {
-			p.ClearValue(namespace, propertyName)
+			p.ClearValue(namespace, name)
 		}
 	}
 
@@ -247,15 +333,7 @@ func (p *Packet) Get(dst any) {
 	s := reflect.Indirect(reflect.ValueOf(dst))
 	st := s.Type()
 
-	var namespace string
-	for i := 0; i < st.NumField(); i++ {
-		fVal := s.Field(i)
-		fInfo := st.Field(i)
-
-		if fVal.Type() == nsTagType {
-			namespace = fInfo.Tag.Get("xmp")
-		}
-	}
+	namespace, _ := modelNamespace(st)
 	if namespace == "" {
 		panic("not an XMP namespace struct")
 	}
@@ -267,26 +345,127 @@ func (p *Packet) Get(dst any) {
 		if !fVal.CanInterface() || !fVal.Type().Implements(typeType) {
 			continue
 		}
+		p.fillModelField(fVal, fInfo, namespace)
+	}
+}
 
-		propertyName := fInfo.Tag.Get("xmp")
-		if propertyName == "" {
-			propertyName = fInfo.Name
-		}
+// fillModelField fills a single field of a namespace struct.  Missing or invalid
+// properties leave the field unchanged, except that missing properties
+// set the field to the zero value.
+func (p *Packet) fillModelField(fVal reflect.Value, fInfo reflect.StructField, namespace string) {
+	name := xml.Name{Space: namespace, Local: propertyName(fInfo)}
+	xmpData, ok := p.Properties[name]
+	if !ok {
+		fVal.Set(reflect.Zero(fInfo.Type)) // zero missing fields
+		return
+	}
 
-		name := xml.Name{Space: namespace, Local: propertyName}
-		xmpData, ok := p.Properties[name]
-		if !ok {
-			fVal.Set(reflect.Zero(fInfo.Type)) // zero missing fields
-			continue
-		}
+	dec, ok := DecoderFor(fInfo.Type)
+	if !ok {
+		return
+	}
+	u, err := dec.DecodeAnother(xmpData)
+	if err != nil || reflect.TypeOf(u) != fInfo.Type {
+		return
+	}
+	fVal.Set(reflect.ValueOf(u))
+}
 
-		val := fVal.Interface().(Value)
-		u, err := val.DecodeAnother(xmpData)
-		if err != nil {
-			continue
+// SetModelField sets the property corresponding to a single field of a
+// namespace struct.  The argument field is the Go name of the field.  Other
+// fields of the model are ignored, and the corresponding properties are
+// not changed.  If the field has the zero value, the property is removed.
+//
+// For example, the following code sets xmp:ModifyDate without affecting
+// the other XMP basic properties:
+//
+//	p.SetModelField(&xmp.Basic{ModifyDate: xmp.NewDate(t)}, "ModifyDate")
+func (p *Packet) SetModelField(model any, field string) error {
+	s := reflect.Indirect(reflect.ValueOf(model))
+	if s.Kind() != reflect.Struct {
+		return errors.New("no struct found")
+	}
+	namespace, prefix := modelNamespace(s.Type())
+	if namespace == "" {
+		return errors.New("XMP namespace not specified")
+	}
+
+	fVal, fInfo, err := modelField(s, field)
+	if err != nil {
+		return err
+	}
+
+	p.RegisterPrefix(namespace, prefix)
+	val := fVal.Interface().(Value)
+	if !val.IsZero() {
+		p.SetValue(namespace, propertyName(fInfo), val)
+	} else {
+		p.ClearValue(namespace, propertyName(fInfo))
+	}
+	return nil
+}
+
+// GetModelField fills a single field of a namespace struct, using data
+// from the packet.  The argument field is the Go name of the field.  Other
+// fields of the model are not changed.  The argument dst must be a pointer
+// to an XMP namespace struct.
+func (p *Packet) GetModelField(dst any, field string) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.New("no struct pointer found")
+	}
+	s := v.Elem()
+	namespace, _ := modelNamespace(s.Type())
+	if namespace == "" {
+		return errors.New("XMP namespace not specified")
+	}
+
+	fVal, fInfo, err := modelField(s, field)
+	if err != nil {
+		return err
+	}
+
+	p.fillModelField(fVal, fInfo, namespace)
+	return nil
+}
+
+// modelNamespace returns the namespace and the preferred prefix of a
+// namespace struct.  If the struct does not specify a namespace, the empty
+// string is returned.
+func modelNamespace(st reflect.Type) (namespace, prefix string) {
+	for i := 0; i < st.NumField(); i++ {
+		fInfo := st.Field(i)
+		if fInfo.Type == nsTagType {
+			namespace = fInfo.Tag.Get("xmp")
+		} else if fInfo.Type == prefixTagType {
+			prefix = fInfo.Tag.Get("xmp")
 		}
-		fVal.Set(reflect.ValueOf(u))
 	}
+	return namespace, prefix
+}
+
+// modelField returns the field with the given Go name.  An error is
+// returned if the field does not exist or does not hold an XMP value.
+func modelField(s reflect.Value, field string) (reflect.Value, reflect.StructField, error) {
+	fInfo, ok := s.Type().FieldByName(field)
+	if !ok || len(fInfo.Index) != 1 {
+		return reflect.Value{}, fInfo, fmt.Errorf("unknown field %s", field)
+	}
+	fVal := s.Field(fInfo.Index[0])
+	if !fVal.CanInterface() || !fVal.Type().Implements(typeType) {
+		return reflect.Value{}, fInfo, fmt.Errorf("field %s does not implement Value", field)
+	}
+	return fVal, fInfo, nil
+}
+
+// propertyName returns the XMP property name for a field of a namespace
+// struct.  This is given by the "xmp" struct tag, or by the field name if
+// no tag is present.
+func propertyName(fInfo reflect.StructField) string {
+	if name := fInfo.Tag.Get("xmp"); name != "" {
+		return name
+	}
+	return fInfo.Name
 }
 
 var (
@@ -17,6 +17,7 @@
 package xmp
 
 import (
+	"encoding/xml"
 	"testing"
 	"time"
 
@@ -51,3 +52,52 @@ func TestTag(t *testing.T) {
 	// }
 	// fmt.Println(buf.String())
 }
+
+func TestModelField(t *testing.T) {
+	p := NewPacket()
+	err := p.Set(&Basic{Label: NewText("draft")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := NewDate(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC))
+	err = p.SetModelField(&Basic{ModifyDate: date}, "ModifyDate")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other fields of the model must not be touched.
+	out := &Basic{Label: NewText("unchanged")}
+	err = p.GetModelField(out, "ModifyDate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Basic{Label: NewText("unchanged"), ModifyDate: date}
+	if d := cmp.Diff(expected, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+
+	all := &Basic{}
+	p.Get(all)
+	if all.Label.V != "draft" {
+		t.Errorf("Label: got %q, want %q", all.Label.V, "draft")
+	}
+
+	// Zero values remove the property.
+	err = p.SetModelField(&Basic{}, "Label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.Properties[xml.Name{Space: basicNamespace, Local: "Label"}]; ok {
+		t.Error("Label not removed")
+	}
+
+	for _, field := range []string{"NoSuchField", "_"} {
+		if err := p.SetModelField(&Basic{}, field); err == nil {
+			t.Errorf("SetModelField(%q): expected error", field)
+		}
+		if err := p.GetModelField(&Basic{}, field); err == nil {
+			t.Errorf("GetModelField(%q): expected error", field)
+		}
+	}
+}
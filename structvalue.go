@@ -0,0 +1,154 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// Struct is an XMP structure whose fields are not known in advance.
+//
+// The fields are kept in order.  When a Struct is decoded, fields which
+// are simple values are represented as [Text] or [URL], nested structures
+// as Struct, and arrays as [OrderedArray], [UnorderedArray] or
+// [AlternativeArray] with element type [Value].  Use [StructGetValue] to
+// convert a field to a more specific type.
+type Struct struct {
+	Fields []StructField
+	Q
+}
+
+// StructField is a field of a [Struct].
+type StructField struct {
+	Name  xml.Name
+	Value Value
+}
+
+// Set sets the value of a field.  If the field is already present, its
+// value is replaced.  Otherwise, the field is appended to the structure.
+func (s *Struct) Set(namespace, fieldName string, value Value) {
+	name := xml.Name{Space: namespace, Local: fieldName}
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			s.Fields[i].Value = value
+			return
+		}
+	}
+	s.Fields = append(s.Fields, StructField{Name: name, Value: value})
+}
+
+// Get returns the value of a field.
+func (s Struct) Get(namespace, fieldName string) (Value, bool) {
+	name := xml.Name{Space: namespace, Local: fieldName}
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Delete removes a field from the structure.
+func (s *Struct) Delete(namespace, fieldName string) {
+	name := xml.Name{Space: namespace, Local: fieldName}
+	for i, f := range s.Fields {
+		if f.Name == name {
+			s.Fields = append(s.Fields[:i:i], s.Fields[i+1:]...)
+			return
+		}
+	}
+}
+
+// StructGetValue returns the value of a field of s, converted to type E.
+// If the field is not present, [ErrNotFound] is returned.
+func StructGetValue[E Value](s Struct, namespace, fieldName string) (E, error) {
+	var zero E
+	v, ok := s.Get(namespace, fieldName)
+	if !ok {
+		return zero, ErrNotFound
+	}
+	if res, ok := v.(E); ok {
+		return res, nil
+	}
+	return decodeAs[E](v.EncodeXMP(nil))
+}
+
+// IsZero implements the [Value] interface.
+func (s Struct) IsZero() bool {
+	return len(s.Fields) == 0 && len(s.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+// Fields with a zero value are omitted.
+func (s Struct) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     s.Q,
+	}
+	for _, f := range s.Fields {
+		if f.Value == nil || f.Value.IsZero() {
+			continue
+		}
+		res.add(f.Name, f.Value.EncodeXMP(p))
+	}
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Struct) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return decodeStruct(s), nil
+}
+
+func decodeStruct(s RawStruct) Struct {
+	res := Struct{Q: s.Q}
+	for _, name := range s.FieldNames() {
+		res.Fields = append(res.Fields, StructField{
+			Name:  name,
+			Value: rawToValue(s.Value[name]),
+		})
+	}
+	return res
+}
+
+// rawToValue converts a low-level XMP value into a [Value], without
+// knowledge of the expected type.
+func rawToValue(raw Raw) Value {
+	switch raw := raw.(type) {
+	case Text:
+		return raw
+	case URL:
+		return raw
+	case RawStruct:
+		return decodeStruct(raw)
+	case RawArray:
+		elems := make([]Value, len(raw.Value))
+		for i, v := range raw.Value {
+			elems[i] = rawToValue(v)
+		}
+		switch raw.Kind {
+		case Ordered:
+			return OrderedArray[Value]{V: elems, Q: raw.Q}
+		case Alternative:
+			return AlternativeArray[Value]{V: elems, Q: raw.Q}
+		default:
+			return UnorderedArray[Value]{V: elems, Q: raw.Q}
+		}
+	}
+	return nil
+}
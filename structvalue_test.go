@@ -0,0 +1,100 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStruct(t *testing.T) {
+	const ns = "http://example.com/ns/"
+
+	var inner Struct
+	inner.Set(ns, "street", NewText("1 Main St"))
+	inner.Set(ns, "city", NewText("Leeds"))
+
+	var in Struct
+	in.Set(ns, "name", NewText("Example"))
+	in.Set(ns, "count", Integer{V: 7})
+	in.Set(ns, "tags", OrderedArray[Text]{V: []Text{NewText("a"), NewText("b")}})
+	in.Set(ns, "address", inner)
+	in.Set(ns, "empty", Text{})
+
+	p1 := NewPacket()
+	p1.SetValue(ns, "info", in)
+	buf := &bytes.Buffer{}
+	err := p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := PacketGetValue[Struct](p2, ns, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, f := range out.Fields {
+		names = append(names, f.Name.Local)
+	}
+	if d := cmp.Diff([]string{"name", "count", "tags", "address"}, names); d != "" {
+		t.Errorf("wrong fields (-want +got):\n%s", d)
+	}
+
+	count, err := StructGetValue[Integer](out, ns, "count")
+	if err != nil || count.V != 7 {
+		t.Errorf("count: got %v, %v", count, err)
+	}
+	tags, err := StructGetValue[OrderedArray[Text]](out, ns, "tags")
+	if err != nil || len(tags.V) != 2 || tags.V[1].V != "b" {
+		t.Errorf("tags: got %v, %v", tags, err)
+	}
+	addr, err := StructGetValue[Struct](out, ns, "address")
+	if err != nil {
+		t.Fatal(err)
+	}
+	city, _ := addr.Get(ns, "city")
+	if d := cmp.Diff(Value(NewText("Leeds")), city); d != "" {
+		t.Errorf("wrong city (-want +got):\n%s", d)
+	}
+	if _, err := StructGetValue[Text](out, ns, "missing"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	// Encoding the decoded structure gives the original XMP data.
+	if d := cmp.Diff(in.EncodeXMP(nil), out.EncodeXMP(nil)); d != "" {
+		t.Errorf("encodings differ (-want +got):\n%s", d)
+	}
+}
+
+func TestStructDelete(t *testing.T) {
+	const ns = "http://example.com/ns/"
+	var s Struct
+	s.Set(ns, "a", NewText("1"))
+	s.Set(ns, "b", NewText("2"))
+	s.Set(ns, "a", NewText("3"))
+	s.Delete(ns, "a")
+	if len(s.Fields) != 1 || s.Fields[0].Name.Local != "b" {
+		t.Errorf("wrong fields: %v", s.Fields)
+	}
+}
@@ -0,0 +1,109 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/jpeg"
+)
+
+// Thumbnail represents a thumbnail image, as used in xmp:Thumbnails.
+//
+// See section 8.2.2.10 of ISO 16684-1:2011.
+type Thumbnail struct {
+	// Format is the image format.  The only format defined by the XMP
+	// specification is "JPEG".
+	Format Text
+
+	// Width is the width of the image in pixels.
+	Width Integer
+
+	// Height is the height of the image in pixels.
+	Height Integer
+
+	// Image holds the encoded image data.
+	Image Base64Data
+
+	Q
+}
+
+// NewJPEGThumbnail encodes img as a JPEG image and returns a thumbnail
+// value holding the encoded image.  If o is nil, the default JPEG options
+// are used.
+func NewJPEGThumbnail(img image.Image, o *jpeg.Options) (Thumbnail, error) {
+	buf := &bytes.Buffer{}
+	err := jpeg.Encode(buf, img, o)
+	if err != nil {
+		return Thumbnail{}, err
+	}
+	b := img.Bounds()
+	return Thumbnail{
+		Format: NewText("JPEG"),
+		Width:  Integer{V: int64(b.Dx())},
+		Height: Integer{V: int64(b.Dy())},
+		Image:  Base64Data{V: buf.Bytes()},
+	}, nil
+}
+
+// Decode decodes the image data of the thumbnail.
+// Image formats other than JPEG are only supported if the corresponding
+// decoder has been registered with the [image] package.
+func (t Thumbnail) Decode() (image.Image, error) {
+	if len(t.Image.V) == 0 {
+		return nil, ErrInvalid
+	}
+	img, _, err := image.Decode(bytes.NewReader(t.Image.V))
+	return img, err
+}
+
+// IsZero implements the [Value] interface.
+func (t Thumbnail) IsZero() bool {
+	return t.Format.IsZero() && t.Width.IsZero() && t.Height.IsZero() &&
+		t.Image.IsZero() && len(t.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (t Thumbnail) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     t.Q,
+	}
+	setField(res, p, xmpGImgNamespace, "format", t.Format)
+	setField(res, p, xmpGImgNamespace, "width", t.Width)
+	setField(res, p, xmpGImgNamespace, "height", t.Height)
+	setField(res, p, xmpGImgNamespace, "image", t.Image)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Thumbnail) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Thumbnail{
+		Format: getField[Text](s, xmpGImgNamespace, "format"),
+		Width:  getField[Integer](s, xmpGImgNamespace, "width"),
+		Height: getField[Integer](s, xmpGImgNamespace, "height"),
+		Image:  getField[Base64Data](s, xmpGImgNamespace, "image"),
+		Q:      s.Q,
+	}, nil
+}
+
+const xmpGImgNamespace = "http://ns.adobe.com/xap/1.0/g/img/"
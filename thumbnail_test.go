@@ -0,0 +1,94 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestThumbnail(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 8; y++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	thumb, err := NewJPEGThumbnail(img, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := &Basic{
+		Thumbnails: AlternativeArray[Thumbnail]{V: []Thumbnail{thumb}},
+	}
+	p1 := NewPacket()
+	err = p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &Basic{}
+	p2.Get(out)
+
+	if len(out.Thumbnails.V) != 1 {
+		t.Fatalf("expected 1 thumbnail, got %d", len(out.Thumbnails.V))
+	}
+	got := out.Thumbnails.V[0]
+	if got.Format.V != "JPEG" || got.Width.V != 16 || got.Height.V != 8 {
+		t.Errorf("wrong thumbnail metadata: %s %dx%d", got.Format.V, got.Width.V, got.Height.V)
+	}
+	dec, err := got.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := dec.Bounds(); b.Dx() != 16 || b.Dy() != 8 {
+		t.Errorf("wrong decoded size: %v", b)
+	}
+	r, g, _, _ := dec.At(8, 4).RGBA()
+	if r < 0xe000 || g > 0x2000 {
+		t.Errorf("wrong decoded colour: r=%04x g=%04x", r, g)
+	}
+}
+
+func TestThumbnailLineBreaks(t *testing.T) {
+	// Adobe software breaks the base64 data into lines.
+	raw := RawStruct{
+		Value: map[xml.Name]Raw{
+			{Space: xmpGImgNamespace, Local: "format"}: Text{V: "JPEG"},
+			{Space: xmpGImgNamespace, Local: "image"}:  Text{V: "AAEC\nAwQF"},
+		},
+	}
+	v, err := Thumbnail{}.DecodeAnother(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.(Thumbnail).Image.V; !bytes.Equal(got, []byte{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("wrong image data: %v", got)
+	}
+}
@@ -0,0 +1,306 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"golang.org/x/exp/maps"
+)
+
+// tiffBlock is the TIFF structure contained in the EXIF APP1 segment of a
+// JPEG file.  Only the ASCII tags from IFD0 and from the Exif IFD are
+// accessed; all other data is kept unchanged.
+type tiffBlock struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// tiffTag identifies a TIFF tag, together with the IFD it is stored in.
+type tiffTag struct {
+	exifIFD bool
+	tag     uint16
+}
+
+// TIFF tags used for metadata reconciliation.
+var (
+	tagImageDescription  = tiffTag{tag: 0x010E}
+	tagDateTime          = tiffTag{tag: 0x0132}
+	tagArtist            = tiffTag{tag: 0x013B}
+	tagCopyright         = tiffTag{tag: 0x8298}
+	tagDateTimeOriginal  = tiffTag{exifIFD: true, tag: 0x9003}
+	tagDateTimeDigitized = tiffTag{exifIFD: true, tag: 0x9004}
+)
+
+const (
+	tiffTypeASCII = 2
+	tiffTypeLong  = 4
+
+	tiffExifIFDPointer = 0x8769
+)
+
+// ifdEntry is a 12-byte entry of a TIFF image file directory.
+type ifdEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value [4]byte // the value, or the offset of the value
+}
+
+// newTIFF returns an empty big-endian TIFF structure.
+func newTIFF() *tiffBlock {
+	return &tiffBlock{
+		data:  []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 0, 0, 0, 0, 0},
+		order: binary.BigEndian,
+	}
+}
+
+// parseTIFF checks the TIFF header of data.
+func parseTIFF(data []byte) (*tiffBlock, error) {
+	if len(data) < 8 {
+		return nil, errMalformedTIFF
+	}
+	t := &tiffBlock{data: data}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return nil, errMalformedTIFF
+	}
+	if t.order.Uint16(data[2:]) != 42 {
+		return nil, errMalformedTIFF
+	}
+	return t, nil
+}
+
+// readIFD reads the image file directory at the given offset.
+func (t *tiffBlock) readIFD(offs uint32) ([]ifdEntry, uint32, error) {
+	if uint64(offs)+2 > uint64(len(t.data)) {
+		return nil, 0, errMalformedTIFF
+	}
+	n := int(t.order.Uint16(t.data[offs:]))
+	pos := int(offs) + 2
+	if pos+12*n+4 > len(t.data) {
+		return nil, 0, errMalformedTIFF
+	}
+	entries := make([]ifdEntry, n)
+	for i := range entries {
+		e := &entries[i]
+		e.tag = t.order.Uint16(t.data[pos:])
+		e.typ = t.order.Uint16(t.data[pos+2:])
+		e.count = t.order.Uint32(t.data[pos+4:])
+		copy(e.value[:], t.data[pos+8:pos+12])
+		pos += 12
+	}
+	next := t.order.Uint32(t.data[pos:])
+	return entries, next, nil
+}
+
+// ifds reads IFD0 and the Exif IFD.  If there is no Exif IFD, exif is nil
+// and exifPos is -1.  Otherwise, exifPos is the index of the Exif IFD
+// pointer in ifd0.
+func (t *tiffBlock) ifds() (ifd0 []ifdEntry, next0 uint32, exif []ifdEntry, next1 uint32, exifPos int, err error) {
+	ifd0, next0, err = t.readIFD(t.order.Uint32(t.data[4:]))
+	if err != nil {
+		return
+	}
+	exifPos = -1
+	for i, e := range ifd0 {
+		if e.tag == tiffExifIFDPointer && e.typ == tiffTypeLong && e.count == 1 {
+			exifPos = i
+			exif, next1, err = t.readIFD(t.order.Uint32(e.value[:]))
+			break
+		}
+	}
+	return
+}
+
+// readASCII returns the values of all ASCII tags in IFD0 and the Exif IFD.
+// Values are truncated at the first NUL byte.
+func (t *tiffBlock) readASCII() (map[tiffTag]string, error) {
+	ifd0, _, exif, _, _, err := t.ifds()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[tiffTag]string)
+	for _, dir := range []struct {
+		exifIFD bool
+		entries []ifdEntry
+	}{{false, ifd0}, {true, exif}} {
+		for _, e := range dir.entries {
+			if e.typ != tiffTypeASCII {
+				continue
+			}
+			var raw []byte
+			if e.count <= 4 {
+				raw = e.value[:e.count]
+			} else {
+				offs := uint64(t.order.Uint32(e.value[:]))
+				if offs+uint64(e.count) > uint64(len(t.data)) {
+					return nil, errMalformedTIFF
+				}
+				raw = t.data[offs : offs+uint64(e.count)]
+			}
+			for i, c := range raw {
+				if c == 0 {
+					raw = raw[:i]
+					break
+				}
+			}
+			res[tiffTag{exifIFD: dir.exifIFD, tag: e.tag}] = string(raw)
+		}
+	}
+	return res, nil
+}
+
+// setASCII returns a new TIFF structure, where the given ASCII tags are
+// set to new values.  Tags with an empty value are removed.
+//
+// The new values and the modified IFDs are appended to the end of the
+// data, so that all offsets stored in other tags remain valid.  Tags which
+// already have the requested value are ignored; if no tag changes, t is
+// returned unchanged.
+func (t *tiffBlock) setASCII(values map[tiffTag]string) (*tiffBlock, error) {
+	ifd0, next0, exif, next1, exifPos, err := t.ifds()
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := t.readASCII()
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[tiffTag]string)
+	for tag, val := range values {
+		if oldVal, ok := old[tag]; ok && oldVal == val || !ok && val == "" {
+			continue
+		}
+		changed[tag] = val
+	}
+	if len(changed) == 0 {
+		return t, nil
+	}
+	values = changed
+
+	res := &tiffBlock{
+		data:  append([]byte(nil), t.data...),
+		order: t.order,
+	}
+
+	needExif := false
+	for tag, val := range values {
+		if tag.exifIFD && val != "" {
+			needExif = true
+		}
+	}
+	if needExif && exifPos < 0 {
+		ifd0 = setEntry(ifd0, ifdEntry{tag: tiffExifIFDPointer, typ: tiffTypeLong, count: 1})
+		exif = []ifdEntry{}
+	}
+
+	// Iterate in a fixed order, so that the output is deterministic.
+	tags := maps.Keys(values)
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].exifIFD != tags[j].exifIFD {
+			return !tags[i].exifIFD
+		}
+		return tags[i].tag < tags[j].tag
+	})
+	for _, tag := range tags {
+		val := values[tag]
+		if tag.exifIFD && exif == nil {
+			continue
+		}
+		e := ifdEntry{tag: tag.tag, typ: tiffTypeASCII}
+		if val != "" {
+			e.count = uint32(len(val) + 1)
+			if e.count <= 4 {
+				copy(e.value[:], val)
+			} else {
+				offs := res.appendData(append([]byte(val), 0))
+				res.order.PutUint32(e.value[:], offs)
+			}
+		}
+		if tag.exifIFD {
+			exif = setEntry(exif, e)
+		} else {
+			ifd0 = setEntry(ifd0, e)
+		}
+	}
+
+	if exif != nil {
+		offs := res.appendIFD(exif, next1)
+		for i := range ifd0 {
+			if ifd0[i].tag == tiffExifIFDPointer {
+				res.order.PutUint32(ifd0[i].value[:], offs)
+			}
+		}
+	}
+	offs := res.appendIFD(ifd0, next0)
+	res.order.PutUint32(res.data[4:], offs)
+	return res, nil
+}
+
+// appendData appends data at an even offset and returns this offset.
+func (t *tiffBlock) appendData(data []byte) uint32 {
+	if len(t.data)%2 != 0 {
+		t.data = append(t.data, 0)
+	}
+	offs := uint32(len(t.data))
+	t.data = append(t.data, data...)
+	return offs
+}
+
+// appendIFD appends an image file directory and returns its offset.
+func (t *tiffBlock) appendIFD(entries []ifdEntry, next uint32) uint32 {
+	buf := make([]byte, 2+12*len(entries)+4)
+	t.order.PutUint16(buf, uint16(len(entries)))
+	pos := 2
+	for _, e := range entries {
+		t.order.PutUint16(buf[pos:], e.tag)
+		t.order.PutUint16(buf[pos+2:], e.typ)
+		t.order.PutUint32(buf[pos+4:], e.count)
+		copy(buf[pos+8:], e.value[:])
+		pos += 12
+	}
+	t.order.PutUint32(buf[pos:], next)
+	return t.appendData(buf)
+}
+
+// setEntry replaces or inserts an IFD entry, keeping the entries sorted by
+// tag.  Entries with a count of zero are removed.
+func setEntry(entries []ifdEntry, e ifdEntry) []ifdEntry {
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].tag >= e.tag })
+	found := i < len(entries) && entries[i].tag == e.tag
+	switch {
+	case found && e.count == 0:
+		return append(entries[:i], entries[i+1:]...)
+	case found:
+		entries[i] = e
+	case e.count > 0:
+		entries = append(entries, ifdEntry{})
+		copy(entries[i+1:], entries[i:])
+		entries[i] = e
+	}
+	return entries
+}
+
+var errMalformedTIFF = errors.New("malformed EXIF data")
@@ -0,0 +1,159 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTIFFSetASCII(t *testing.T) {
+	// A little-endian TIFF structure with an IFD0 containing a SHORT
+	// orientation tag and a pointer to IFD1.
+	data := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+	data = binary.LittleEndian.AppendUint16(data, 1)
+	data = binary.LittleEndian.AppendUint16(data, 0x0112)
+	data = binary.LittleEndian.AppendUint16(data, 3)
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, 6)
+	data = binary.LittleEndian.AppendUint32(data, 1234)
+
+	t0, err := parseTIFF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[tiffTag]string{
+		tagArtist:           "abc",
+		tagCopyright:        "(c) 2024 Someone",
+		tagDateTimeOriginal: "2024:05:18 17:05:33",
+	}
+	t1, err := t0.setASCII(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		again, err := t0.setASCII(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(again.data, t1.data) {
+			t.Fatal("output is not deterministic")
+		}
+	}
+	t2, err := t1.setASCII(map[tiffTag]string{
+		tagCopyright: "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := t2.readASCII()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[tiffTag]string{
+		tagArtist:           "abc",
+		tagDateTimeOriginal: "2024:05:18 17:05:33",
+	}
+	if d := cmp.Diff(want, got, cmp.AllowUnexported(tiffTag{})); d != "" {
+		t.Errorf("values (-want +got):\n%s", d)
+	}
+
+	ifd0, next, _, _, _, err := t2.ifds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != 1234 {
+		t.Errorf("next IFD: got %d, want 1234", next)
+	}
+	var tags []uint16
+	for _, e := range ifd0 {
+		tags = append(tags, e.tag)
+	}
+	if d := cmp.Diff([]uint16{0x0112, 0x013B, tiffExifIFDPointer}, tags); d != "" {
+		t.Errorf("IFD0 tags (-want +got):\n%s", d)
+	}
+	if ifd0[0].value != [4]byte{6, 0, 0, 0} {
+		t.Errorf("orientation changed: %v", ifd0[0].value)
+	}
+
+	// Setting unchanged values must not grow the data.
+	t3, err := t2.setASCII(map[tiffTag]string{
+		tagArtist:           "abc",
+		tagCopyright:        "",
+		tagDateTimeOriginal: "2024:05:18 17:05:33",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(t3.data, t2.data) {
+		t.Errorf("unchanged values: data grew from %d to %d bytes", len(t2.data), len(t3.data))
+	}
+}
+
+func TestTIFFMalformed(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		[]byte("XX\x00\x2a\x00\x00\x00\x08"),
+		[]byte("MM\x00\x2a\x00\x00\x01\x00"),
+	} {
+		tiff, err := parseTIFF(data)
+		if err == nil {
+			_, err = tiff.readASCII()
+		}
+		if err != errMalformedTIFF {
+			t.Errorf("%q: got %v, want %v", data, err, errMalformedTIFF)
+		}
+	}
+}
+
+func FuzzParseTIFF(f *testing.F) {
+	t0, err := newTIFF().setASCII(map[tiffTag]string{
+		tagImageDescription: "a description",
+		tagArtist:           "Alice",
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(t0.data)
+	f.Add(newTIFF().data)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		t1, err := parseTIFF(data)
+		if err != nil {
+			return
+		}
+		values, err := t1.readASCII()
+		if err != nil {
+			return
+		}
+		t2, err := t1.setASCII(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values2, err := t2.readASCII()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(values, values2, cmp.AllowUnexported(tiffTag{})); d != "" {
+			t.Errorf("round trip (-want +got):\n%s", d)
+		}
+	})
+}
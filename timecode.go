@@ -0,0 +1,163 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Timecode represents an SMPTE timecode, as used in xmpDM:startTimecode
+// and xmpDM:altTimecode.
+//
+// See section 1.2.6.24 of part 2 of the XMP specification (2016).
+type Timecode struct {
+	// TimeFormat is the format of TimeValue, for example "25Timecode" or
+	// "2997DropTimecode".
+	TimeFormat Text
+
+	// TimeValue is the timecode, in the form "hh:mm:ss:ff".  Drop-frame
+	// timecodes use semicolons, as in "hh;mm;ss;ff".
+	TimeValue Text
+
+	Q
+}
+
+// timecodeFormats maps the values of xmpDM:timeFormat to the nominal
+// number of frames per second and the drop-frame flag.
+var timecodeFormats = map[string]struct {
+	fps  int64
+	drop bool
+}{
+	"23976Timecode":       {24, false},
+	"24Timecode":          {24, false},
+	"25Timecode":          {25, false},
+	"2997DropTimecode":    {30, true},
+	"2997NonDropTimecode": {30, false},
+	"30Timecode":          {30, false},
+	"50Timecode":          {50, false},
+	"5994DropTimecode":    {60, true},
+	"5994NonDropTimecode": {60, false},
+	"60Timecode":          {60, false},
+}
+
+// NewTimecode creates a timecode which refers to the given frame number.
+// The format must be one of the time formats defined by the XMP
+// specification, for example "25Timecode" or "2997DropTimecode".
+func NewTimecode(format string, frame int64) (Timecode, error) {
+	f, ok := timecodeFormats[format]
+	if !ok {
+		return Timecode{}, fmt.Errorf("unknown timecode format %q", format)
+	}
+	if frame < 0 {
+		return Timecode{}, fmt.Errorf("negative frame number %d", frame)
+	}
+
+	sep := ":"
+	if f.drop {
+		// In drop-frame timecode, frame numbers 0 and 1 (0 to 3 at 60 fps)
+		// are skipped at the start of every minute, except for minutes
+		// divisible by ten.
+		drop := f.fps / 15
+		perMinute := 60*f.fps - drop
+		perTenMinutes := 10*perMinute + drop
+		d, m := frame/perTenMinutes, frame%perTenMinutes
+		frame += 9 * drop * d
+		if m > drop {
+			frame += drop * ((m - drop) / perMinute)
+		}
+		sep = ";"
+	}
+
+	ff := frame % f.fps
+	s := frame / f.fps
+	value := fmt.Sprintf("%02d%s%02d%s%02d%s%02d", s/3600, sep, s/60%60, sep, s%60, sep, ff)
+	return Timecode{
+		TimeFormat: NewText(format),
+		TimeValue:  NewText(value),
+	}, nil
+}
+
+// Frame returns the frame number the timecode refers to, counting from
+// zero at 00:00:00:00.
+func (t Timecode) Frame() (int64, error) {
+	f, ok := timecodeFormats[t.TimeFormat.V]
+	if !ok {
+		return 0, fmt.Errorf("unknown timecode format %q", t.TimeFormat.V)
+	}
+
+	parts := strings.FieldsFunc(t.TimeValue.V, func(r rune) bool {
+		return r == ':' || r == ';'
+	})
+	if len(parts) != 4 {
+		return 0, fmt.Errorf("malformed timecode %q", t.TimeValue.V)
+	}
+	var x [4]int64
+	for i, part := range parts {
+		v, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("malformed timecode %q", t.TimeValue.V)
+		}
+		x[i] = int64(v)
+	}
+	hh, mm, ss, ff := x[0], x[1], x[2], x[3]
+	if mm >= 60 || ss >= 60 || ff >= f.fps {
+		return 0, fmt.Errorf("invalid timecode %q", t.TimeValue.V)
+	}
+
+	frame := (hh*3600+mm*60+ss)*f.fps + ff
+	if f.drop {
+		drop := f.fps / 15
+		if ss == 0 && ff < drop && mm%10 != 0 {
+			return 0, fmt.Errorf("invalid drop-frame timecode %q", t.TimeValue.V)
+		}
+		minutes := hh*60 + mm
+		frame -= drop * (minutes - minutes/10)
+	}
+	return frame, nil
+}
+
+// IsZero implements the [Value] interface.
+func (t Timecode) IsZero() bool {
+	return t.TimeFormat.IsZero() && t.TimeValue.IsZero() && len(t.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (t Timecode) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     t.Q,
+	}
+	setField(res, p, dmNamespace, "timeFormat", t.TimeFormat)
+	setField(res, p, dmNamespace, "timeValue", t.TimeValue)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Timecode) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Timecode{
+		TimeFormat: getField[Text](s, dmNamespace, "timeFormat"),
+		TimeValue:  getField[Text](s, dmNamespace, "timeValue"),
+		Q:          s.Q,
+	}, nil
+}
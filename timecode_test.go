@@ -0,0 +1,79 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "testing"
+
+func TestTimecodeFrame(t *testing.T) {
+	testCases := []struct {
+		format, value string
+		frame         int64
+	}{
+		{"25Timecode", "00:00:00:00", 0},
+		{"25Timecode", "00:00:01:05", 30},
+		{"25Timecode", "01:00:00:00", 90000},
+		{"2997NonDropTimecode", "00:01:00:00", 1800},
+		{"2997DropTimecode", "00;00;59;29", 1799},
+		{"2997DropTimecode", "00;01;00;02", 1800},
+		{"2997DropTimecode", "00;10;00;00", 17982},
+		{"2997DropTimecode", "01;00;00;00", 107892},
+		{"5994DropTimecode", "00;01;00;04", 3600},
+	}
+	for _, tc := range testCases {
+		in := Timecode{TimeFormat: NewText(tc.format), TimeValue: NewText(tc.value)}
+		frame, err := in.Frame()
+		if err != nil {
+			t.Errorf("%s %s: %v", tc.format, tc.value, err)
+			continue
+		}
+		if frame != tc.frame {
+			t.Errorf("%s %s: got frame %d, want %d", tc.format, tc.value, frame, tc.frame)
+		}
+
+		out, err := NewTimecode(tc.format, tc.frame)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if out.TimeValue.V != tc.value {
+			t.Errorf("%s %d: got %q, want %q", tc.format, tc.frame, out.TimeValue.V, tc.value)
+		}
+	}
+}
+
+func TestTimecodeInvalid(t *testing.T) {
+	testCases := []struct {
+		format, value string
+	}{
+		{"25Timecode", "00:00:00:25"},
+		{"25Timecode", "00:60:00:00"},
+		{"25Timecode", "00:00:00"},
+		{"25Timecode", "aa:00:00:00"},
+		{"2997DropTimecode", "00;01;00;00"},
+		{"unknown", "00:00:00:00"},
+	}
+	for _, tc := range testCases {
+		in := Timecode{TimeFormat: NewText(tc.format), TimeValue: NewText(tc.value)}
+		if _, err := in.Frame(); err == nil {
+			t.Errorf("%s %s: expected error", tc.format, tc.value)
+		}
+	}
+
+	if _, err := NewTimecode("25Timecode", -1); err == nil {
+		t.Error("expected error for negative frame")
+	}
+}
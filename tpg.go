@@ -0,0 +1,135 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// PagedText represents the XMP Paged-Text namespace.
+//
+// See section 8.7 of part 2 of the XMP specification (2016).
+type PagedText struct {
+	_ Namespace `xmp:"http://ns.adobe.com/xap/1.0/t/pg/"`
+	_ Prefix    `xmp:"xmpTPg"`
+
+	// Colorants lists the colours used in the document, including any in
+	// contained documents.
+	Colorants OrderedArray[Colorant] `xmp:"Colorants"`
+
+	// Fonts lists the fonts used in the document, including any in
+	// contained documents.
+	Fonts UnorderedArray[Font] `xmp:"Fonts"`
+
+	// MaxPageSize is the size of the largest page in the document,
+	// including any in contained documents.
+	MaxPageSize Dimensions `xmp:"MaxPageSize"`
+
+	// NPages is the number of pages in the document, including any in
+	// contained documents.
+	NPages Integer `xmp:"NPages"`
+
+	// PlateNames lists the names of the plates needed to print the
+	// document.
+	PlateNames OrderedArray[Text] `xmp:"PlateNames"`
+
+	// SwatchGroups lists the swatch groups defined in the document.
+	SwatchGroups OrderedArray[SwatchGroup] `xmp:"SwatchGroups"`
+}
+
+const tpgNamespace = "http://ns.adobe.com/xap/1.0/t/pg/"
+
+// Font describes a font used in a document.
+//
+// See section 8.2.2.5 of part 1 of the XMP specification (2012).
+type Font struct {
+	// ChildFontFiles lists the file names of the fonts which make up a
+	// composite font.
+	ChildFontFiles OrderedArray[Text]
+
+	// Composite is true if the font is a composite font.
+	Composite OptionalBool
+
+	// FontFace is the style of the font, for example "Bold".
+	FontFace Text
+
+	// FontFamily is the font family name, for example "Minion Pro".
+	FontFamily Text
+
+	// FontFileName is the name of the font file.
+	FontFileName Text
+
+	// FontName is the PostScript name of the font, for example
+	// "MinionPro-Bold".
+	FontName Text
+
+	// FontType is the font type, for example "TrueType", "Type 1", or
+	// "Open Type".
+	FontType Text
+
+	// VersionString is the version of the font.
+	VersionString Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (f Font) IsZero() bool {
+	return f.ChildFontFiles.IsZero() && f.Composite.IsZero() &&
+		f.FontFace.IsZero() && f.FontFamily.IsZero() &&
+		f.FontFileName.IsZero() && f.FontName.IsZero() &&
+		f.FontType.IsZero() && f.VersionString.IsZero() && len(f.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (f Font) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     f.Q,
+	}
+	setField(res, p, stFntNamespace, "childFontFiles", f.ChildFontFiles)
+	setField(res, p, stFntNamespace, "composite", f.Composite)
+	setField(res, p, stFntNamespace, "fontFace", f.FontFace)
+	setField(res, p, stFntNamespace, "fontFamily", f.FontFamily)
+	setField(res, p, stFntNamespace, "fontFileName", f.FontFileName)
+	setField(res, p, stFntNamespace, "fontName", f.FontName)
+	setField(res, p, stFntNamespace, "fontType", f.FontType)
+	setField(res, p, stFntNamespace, "versionString", f.VersionString)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Font) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Font{
+		ChildFontFiles: getField[OrderedArray[Text]](s, stFntNamespace, "childFontFiles"),
+		Composite:      getField[OptionalBool](s, stFntNamespace, "composite"),
+		FontFace:       getField[Text](s, stFntNamespace, "fontFace"),
+		FontFamily:     getField[Text](s, stFntNamespace, "fontFamily"),
+		FontFileName:   getField[Text](s, stFntNamespace, "fontFileName"),
+		FontName:       getField[Text](s, stFntNamespace, "fontName"),
+		FontType:       getField[Text](s, stFntNamespace, "fontType"),
+		VersionString:  getField[Text](s, stFntNamespace, "versionString"),
+		Q:              s.Q,
+	}, nil
+}
+
+// stFntNamespace is the namespace for the Font structure.  The missing
+// slashes after "http:" are part of the namespace URI defined in the XMP
+// specification.
+const stFntNamespace = "http:ns.adobe.com/xap/1.0/sType/Font#"
@@ -0,0 +1,103 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPagedText(t *testing.T) {
+	in := &PagedText{
+		MaxPageSize: Dimensions{
+			W:    Real{V: 8.5},
+			H:    Real{V: 11},
+			Unit: NewText("inch"),
+		},
+		Fonts: UnorderedArray[Font]{V: []Font{
+			{
+				FontName:     NewText("MinionPro-Regular"),
+				FontFamily:   NewText("Minion Pro"),
+				FontFace:     NewText("Regular"),
+				FontType:     NewText("Open Type"),
+				FontFileName: NewText("MinionPro-Regular.otf"),
+				Composite:    OptionalBool{V: 1},
+			},
+		}},
+		NPages: Integer{V: 12},
+		Colorants: OrderedArray[Colorant]{V: []Colorant{
+			{
+				SwatchName: NewText("C=0 M=100 Y=0 K=0"),
+				Mode:       NewText("CMYK"),
+				Type:       NewText("PROCESS"),
+				M:          Real{V: 100},
+			},
+			{
+				SwatchName: NewText("Corporate Blue"),
+				Mode:       NewText("RGB"),
+				Type:       NewText("SPOT"),
+				Red:        Integer{V: 0},
+				Green:      Integer{V: 51},
+				Blue:       Integer{V: 153},
+			},
+		}},
+		SwatchGroups: OrderedArray[SwatchGroup]{V: []SwatchGroup{
+			{
+				GroupName: NewText("Default Swatch Group"),
+				Colorants: OrderedArray[Colorant]{V: []Colorant{
+					{
+						SwatchName: NewText("Sand"),
+						Mode:       NewText("LAB"),
+						Type:       NewText("PROCESS"),
+						L:          Real{V: 80.5},
+						A:          Integer{V: 3},
+						B:          Integer{V: -20},
+					},
+				}},
+			},
+		}},
+		PlateNames: OrderedArray[Text]{V: []Text{
+			NewText("Cyan"), NewText("Magenta"), NewText("Yellow"), NewText("Black"),
+		}},
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`stDim:w="8.5"`)) {
+		t.Errorf("missing stDim:w in output:\n%s", buf.Bytes())
+	}
+
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &PagedText{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,200 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// Truncation describes how text values are shortened to fit into
+// length-limited fields, for example IPTC-IIM datasets.
+type Truncation struct {
+	// MaxBytes (optional) is the maximum length of the result in bytes,
+	// including the ellipsis.  Zero means no limit.
+	MaxBytes int
+
+	// MaxRunes (optional) is the maximum length of the result in runes,
+	// including the ellipsis.  Zero means no limit.
+	MaxRunes int
+
+	// Ellipsis (optional) is appended to values which have been shortened,
+	// for example "…" or "...".  If the ellipsis does not fit into the
+	// length limit by itself, it is omitted.
+	Ellipsis string
+
+	// WordBoundary, if set, causes values to be cut at the last white space
+	// before the length limit, if there is one.
+	WordBoundary bool
+}
+
+// String shortens s to fit into the length limit.
+// Strings are only ever cut at rune boundaries.
+// The second return value indicates whether s was changed.
+func (tr *Truncation) String(s string) (string, bool) {
+	if tr.fits(len(s), utf8.RuneCountInString(s)) {
+		return s, false
+	}
+
+	ellipsis := tr.Ellipsis
+	eBytes, eRunes := len(ellipsis), utf8.RuneCountInString(ellipsis)
+	if !tr.fits(eBytes, eRunes) {
+		ellipsis = ""
+		eBytes, eRunes = 0, 0
+	}
+
+	cut := 0
+	n := 0
+	for i := range s {
+		if !tr.fits(i+eBytes, n+eRunes) {
+			break
+		}
+		cut = i
+		n++
+	}
+
+	if tr.WordBoundary {
+		if i := strings.LastIndexAny(s[:cut], " \t\n"); i > 0 {
+			cut = i
+		}
+	}
+
+	return strings.TrimRight(s[:cut], " \t\n") + ellipsis, true
+}
+
+// Text shortens the text of v to fit into the length limit.
+// Qualifiers are preserved.
+// The second return value indicates whether v was changed.
+func (tr *Truncation) Text(v Text) (Text, bool) {
+	s, changed := tr.String(v.V)
+	return Text{V: s, Q: v.Q}, changed
+}
+
+// Localized shortens all versions of a localized text to fit into the length
+// limit.  The second return value indicates whether v was changed.
+func (tr *Truncation) Localized(v Localized) (Localized, bool) {
+	res := Localized{Q: v.Q}
+
+	var changed, c bool
+	res.Default, changed = tr.Text(v.Default)
+	if v.V != nil {
+		res.V = make(map[language.Tag]Text, len(v.V))
+	}
+	for lang, txt := range v.V {
+		res.V[lang], c = tr.Text(txt)
+		changed = changed || c
+	}
+	return res, changed
+}
+
+// Truncate shortens the value of the given property to fit into the length
+// limit.  This applies to simple text properties and to all text elements of
+// array properties, including localized text.  Other properties are left
+// unchanged.
+//
+// If the value is changed, the original value is recorded in the
+// trunc:Original property (namespace
+// "http://ns.seehuhn.de/xmp/truncated/1.0/"), unless an original value for
+// this property has already been recorded.
+//
+// The return value indicates whether the property was changed.
+func (p *Packet) Truncate(namespace, propertyName string, tr *Truncation) bool {
+	name := xml.Name{Space: namespace, Local: propertyName}
+	orig, ok := p.Properties[name]
+	if !ok {
+		return false
+	}
+
+	var val Raw
+	changed := false
+	switch orig := orig.(type) {
+	case Text:
+		val, changed = tr.Text(orig)
+	case RawArray:
+		a := RawArray{
+			Value: make([]Raw, len(orig.Value)),
+			Kind:  orig.Kind,
+			Q:     orig.Q,
+		}
+		for i, elem := range orig.Value {
+			a.Value[i] = elem
+			if txt, ok := elem.(Text); ok {
+				var c bool
+				a.Value[i], c = tr.Text(txt)
+				changed = changed || c
+			}
+		}
+		val = a
+	}
+	if !changed {
+		return false
+	}
+
+	p.Properties[name] = val
+	p.recordOriginal(name, orig)
+	return true
+}
+
+// recordOriginal stores the original value of a truncated property.
+func (p *Packet) recordOriginal(name xml.Name, orig Raw) {
+	p.RegisterPrefix(truncNamespace, "trunc")
+
+	var list RawArray
+	if l, ok := p.Properties[nameTruncOriginal].(RawArray); ok {
+		list = l
+	} else {
+		list = RawArray{Kind: Unordered}
+	}
+	for _, entry := range list.Value {
+		s, ok := entry.(RawStruct)
+		if !ok {
+			continue
+		}
+		ns, _ := s.Value[nameTruncNamespace].(Text)
+		local, _ := s.Value[nameTruncName].(Text)
+		if ns.V == name.Space && local.V == name.Local {
+			return
+		}
+	}
+
+	list.Value = append(list.Value, RawStruct{
+		Value: map[xml.Name]Raw{
+			nameTruncNamespace: Text{V: name.Space},
+			nameTruncName:      Text{V: name.Local},
+			nameTruncValue:     orig,
+		},
+	})
+	p.Properties[nameTruncOriginal] = list
+}
+
+// fits checks whether a string of the given length fits into the limits.
+func (tr *Truncation) fits(nBytes, nRunes int) bool {
+	return (tr.MaxBytes <= 0 || nBytes <= tr.MaxBytes) &&
+		(tr.MaxRunes <= 0 || nRunes <= tr.MaxRunes)
+}
+
+const truncNamespace = "http://ns.seehuhn.de/xmp/truncated/1.0/"
+
+var (
+	nameTruncOriginal  = xml.Name{Space: truncNamespace, Local: "Original"}
+	nameTruncNamespace = xml.Name{Space: truncNamespace, Local: "namespace"}
+	nameTruncName      = xml.Name{Space: truncNamespace, Local: "name"}
+	nameTruncValue     = xml.Name{Space: truncNamespace, Local: "value"}
+)
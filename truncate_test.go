@@ -0,0 +1,91 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestTruncateString(t *testing.T) {
+	type testCase struct {
+		tr   Truncation
+		in   string
+		want string
+	}
+	cases := []testCase{
+		{Truncation{MaxBytes: 10}, "short", "short"},
+		{Truncation{MaxBytes: 5}, "hello world", "hello"},
+		{Truncation{MaxBytes: 8, Ellipsis: "..."}, "hello world", "hello..."},
+		{Truncation{MaxBytes: 3}, "Grüß", "Gr"},  // ü is two bytes
+		{Truncation{MaxBytes: 4}, "Grüß", "Grü"}, // ß does not fit
+		{Truncation{MaxRunes: 3}, "Grüß", "Grü"},
+		{Truncation{MaxRunes: 3, Ellipsis: "…"}, "Grüß", "Gr…"},
+		{Truncation{MaxBytes: 2, Ellipsis: "..."}, "hello", "he"},
+		{Truncation{MaxBytes: 10, WordBoundary: true}, "hello world", "hello"},
+		{Truncation{MaxBytes: 9, Ellipsis: "…", WordBoundary: true}, "hello world", "hello…"},
+		{Truncation{MaxBytes: 4, WordBoundary: true}, "helloworld", "hell"},
+	}
+	for i, tc := range cases {
+		got, changed := tc.tr.String(tc.in)
+		if got != tc.want {
+			t.Errorf("%d: got %q, want %q", i, got, tc.want)
+		}
+		if changed != (tc.in != tc.want) {
+			t.Errorf("%d: changed = %t", i, changed)
+		}
+	}
+}
+
+func TestPacketTruncate(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+
+	var title Localized
+	title.Set(language.English, "a rather long title")
+	title.Set(language.German, "kurz")
+	p := NewPacket()
+	p.SetValue(ns, "title", title)
+
+	tr := &Truncation{MaxBytes: 10, Ellipsis: "…"}
+	if !p.Truncate(ns, "title", tr) {
+		t.Fatal("property was not truncated")
+	}
+	if p.Truncate(ns, "title", tr) {
+		t.Fatal("property was truncated twice")
+	}
+
+	got, err := PacketGetValue[Localized](p, ns, "title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := got.V[language.English].V; s != "a rathe…" {
+		t.Errorf("unexpected English title %q", s)
+	}
+	if s := got.V[language.German].V; s != "kurz" {
+		t.Errorf("unexpected German title %q", s)
+	}
+
+	orig, ok := p.Properties[nameTruncOriginal].(RawArray)
+	if !ok || len(orig.Value) != 1 {
+		t.Fatalf("original value not recorded: %v", p.Properties[nameTruncOriginal])
+	}
+	entry := orig.Value[0].(RawStruct)
+	if !EqualRaw(entry.Value[nameTruncValue], title.EncodeXMP(p)) {
+		t.Errorf("wrong original value recorded")
+	}
+}
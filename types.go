@@ -17,13 +17,22 @@
 package xmp
 
 import (
+	"encoding/base64"
 	"encoding/xml"
+	"errors"
+	"math"
 	"mime"
+	"net/url"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"golang.org/x/exp/maps"
 	"golang.org/x/text/language"
 )
 
@@ -102,10 +111,90 @@ func NewAgentName(v string, qualifiers ...Qualifier) AgentName {
 	return AgentName{V: v, Q: Q(qualifiers)}
 }
 
+// BuildAgentName creates an AgentName in the recommended format from its
+// components.  Empty components are omitted.
+func BuildAgentName(organization, software, version string, tokens ...string) AgentName {
+	var parts []string
+	for _, s := range []string{organization, software, version} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(tokens) > 0 {
+		parts = append(parts, "("+strings.Join(tokens, ";")+")")
+	}
+	return AgentName{V: strings.Join(parts, " ")}
+}
+
 func (a AgentName) String() string {
 	return a.V
 }
 
+// Organization returns the organization part of the agent name.
+// This is the first word, if the name has at least two words apart from
+// the version.
+func (a AgentName) Organization() string {
+	return a.parse().organization
+}
+
+// Software returns the software name part of the agent name.
+func (a AgentName) Software() string {
+	return a.parse().software
+}
+
+// Version returns the version part of the agent name.  This is the last
+// word before the tokens, if it starts with a digit or with "v" followed by
+// a digit.
+func (a AgentName) Version() string {
+	return a.parse().version
+}
+
+// Tokens returns the semicolon-separated tokens given in parentheses at
+// the end of the agent name.
+func (a AgentName) Tokens() []string {
+	return a.parse().tokens
+}
+
+type agentNameParts struct {
+	organization, software, version string
+	tokens                          []string
+}
+
+// parse splits the agent name into its components.  Since the format is
+// only a recommendation, this uses heuristics.
+func (a AgentName) parse() agentNameParts {
+	var res agentNameParts
+
+	s := strings.TrimSpace(a.V)
+	if strings.HasSuffix(s, ")") {
+		if i := strings.LastIndexByte(s, '('); i >= 0 {
+			for _, tok := range strings.Split(s[i+1:len(s)-1], ";") {
+				if tok = strings.TrimSpace(tok); tok != "" {
+					res.tokens = append(res.tokens, tok)
+				}
+			}
+			s = s[:i]
+		}
+	}
+
+	words := strings.Fields(s)
+	if n := len(words); n >= 2 && isVersionWord(words[n-1]) {
+		res.version = words[n-1]
+		words = words[:n-1]
+	}
+	if len(words) >= 2 {
+		res.organization = words[0]
+		words = words[1:]
+	}
+	res.software = strings.Join(words, " ")
+	return res
+}
+
+func isVersionWord(w string) bool {
+	w = strings.TrimPrefix(strings.TrimPrefix(w, "v"), "V")
+	return w != "" && w[0] >= '0' && w[0] <= '9'
+}
+
 // IsZero implements the [Value] interface.
 func (a AgentName) IsZero() bool {
 	return a.V == "" && len(a.Q) == 0
@@ -196,6 +285,60 @@ func (GUID) DecodeAnother(val Raw) (Value, error) {
 	return GUID{v.V, v.Q}, nil
 }
 
+// URI represents a URI which is stored as a text value.
+//
+// Unlike [URL], which is written using rdf:resource, a URI is written as
+// simple text.  This is the form used by properties such as
+// xmpRights:WebStatement and stRef:filePath.  The text is kept exactly as
+// given, so relative references and non-standard URIs are preserved.
+type URI struct {
+	V string
+	Q
+}
+
+// NewURI creates a new XMP URI value.
+func NewURI(uri string, qualifiers ...Qualifier) URI {
+	return URI{V: uri, Q: Q(qualifiers)}
+}
+
+func (u URI) String() string {
+	return u.V
+}
+
+// URL parses the URI.
+func (u URI) URL() (*url.URL, error) {
+	return url.Parse(u.V)
+}
+
+// IsZero implements the [Value] interface.
+func (u URI) IsZero() bool {
+	return u.V == "" && len(u.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (u URI) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: u.V,
+		Q: u.Q,
+	}
+}
+
+// DecodeAnother implements the [Value] interface.
+// Values written using rdf:resource are accepted, too.
+func (URI) DecodeAnother(val Raw) (Value, error) {
+	switch v := val.(type) {
+	case Text:
+		return URI{v.V, v.Q}, nil
+	case URL:
+		if v.V == nil {
+			return nil, ErrInvalid
+		}
+		return URI{v.V.String(), v.Q}, nil
+	default:
+		return nil, ErrInvalid
+	}
+}
+
 // Real represents a floating-point number.
 type Real struct {
 	V float64
@@ -226,8 +369,210 @@ func (r Real) EncodeXMP(*Packet) Raw {
 	}
 }
 
+// Decimal represents a number which keeps its textual form.
+//
+// Unlike [Real], which re-formats numbers when writing them, a Decimal is
+// written exactly as it was read, so that for example "0.5000" is not
+// changed to "0.5".
+type Decimal struct {
+	V string
+	Q
+}
+
+// NewDecimal creates a new Decimal value from its text representation.
+// An error is returned if s is not a valid number.
+func NewDecimal(s string, qualifiers ...Qualifier) (Decimal, error) {
+	if !decimalRegexp.MatchString(s) {
+		return Decimal{}, ErrInvalid
+	}
+	return Decimal{V: s, Q: Q(qualifiers)}, nil
+}
+
+// DecimalFromFloat creates a Decimal value holding the shortest decimal
+// representation of f.
+func DecimalFromFloat(f float64, qualifiers ...Qualifier) Decimal {
+	return Decimal{V: strconv.FormatFloat(f, 'f', -1, 64), Q: Q(qualifiers)}
+}
+
+func (d Decimal) String() string {
+	return d.V
+}
+
+// Float64 returns the value as a floating-point number.
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(d.V, 64)
+}
+
+// Int64 returns the value as an integer.  An error is returned if the
+// value has a non-zero fractional part or is out of range.
+func (d Decimal) Int64() (int64, error) {
+	i, err := strconv.ParseInt(d.V, 10, 64)
+	if err == nil {
+		return i, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(d.V, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, ErrInvalid
+	}
+	return int64(f), nil
+}
+
+// IsZero implements the [Value] interface.
+func (d Decimal) IsZero() bool {
+	return d.V == "" && len(d.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (d Decimal) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: d.V,
+		Q: d.Q,
+	}
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Decimal) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok || !decimalRegexp.MatchString(v.V) {
+		return nil, ErrInvalid
+	}
+	return Decimal{v.V, v.Q}, nil
+}
+
+// repair implements the repairer interface.
+// Numbers written using locale-specific conventions, for example "0,5",
+// are normalized.
+func (Decimal) repair(val Raw) (Value, string, bool) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, "", false
+	}
+	f, err := parseLenientFloat(v.V)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, "", false
+	}
+	return DecimalFromFloat(f, v.Q...), "malformed number " + strconv.Quote(v.V), true
+}
+
+var decimalRegexp = regexp.MustCompile(`^[+-]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][+-]?[0-9]+)?$`)
+
+// Integer represents a signed integer.
+type Integer struct {
+	V int64
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (i Integer) IsZero() bool {
+	return i.V == 0 && len(i.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (i Integer) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: strconv.FormatInt(i.V, 10),
+		Q: i.Q,
+	}
+}
+
+// DecodeAnother implements the [Value] interface.
+// Values outside the range of int64 are rejected.
+func (Integer) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	i, err := strconv.ParseInt(v.V, 10, 64)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	return Integer{i, v.Q}, nil
+}
+
+// repair implements the repairer interface.
+// Integral values written in floating point notation, for example "3.0",
+// or using thousands separators, for example "1,000", are repaired.
+func (Integer) repair(val Raw) (Value, string, bool) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, "", false
+	}
+	msg := "malformed integer " + strconv.Quote(v.V)
+	if i, ok := parseGroupedInt(v.V); ok {
+		return Integer{i, v.Q}, msg, true
+	} else if groupedRegexp.MatchString(v.V) {
+		// ambiguous, for example "1234,000"
+		return nil, "", false
+	}
+	f, err := strconv.ParseFloat(v.V, 64)
+	if err != nil {
+		f, err = parseLenientFloat(v.V)
+	}
+	if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return nil, "", false
+	}
+	return Integer{int64(f), v.Q}, msg, true
+}
+
+// parseGroupedInt parses integers written with thousands separators, for
+// example "8,000" or "1 234 567".  The first group must have between one
+// and three digits, all other groups must have exactly three digits.
+// Inside integers, a comma followed by three digits is always taken to be a
+// thousands separator and never a decimal comma.  A single dot is left to
+// the floating point parser, so that "3.000" is read as 3.
+func parseGroupedInt(s string) (int64, bool) {
+	s = strings.TrimSpace(s)
+	if strings.Count(s, ".") == 1 {
+		return 0, false
+	}
+	sign := ""
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+
+	var groups []string
+	for {
+		i := strings.IndexFunc(s, isGroupSeparator)
+		if i < 0 {
+			groups = append(groups, s)
+			break
+		}
+		groups = append(groups, s[:i])
+		_, size := utf8.DecodeRuneInString(s[i:])
+		s = s[i+size:]
+	}
+	if len(groups) < 2 || len(groups[0]) < 1 || len(groups[0]) > 3 {
+		return 0, false
+	}
+	for i, g := range groups {
+		if i > 0 && len(g) != 3 {
+			return 0, false
+		}
+		for _, c := range g {
+			if c < '0' || c > '9' {
+				return 0, false
+			}
+		}
+	}
+
+	i, err := strconv.ParseInt(sign+strings.Join(groups, ""), 10, 64)
+	return i, err == nil
+}
+
+// isGroupSeparator reports whether r is used as a thousands separator.
+func isGroupSeparator(r rune) bool {
+	return r == ',' || r == '.' || r == ' ' || r == '\'' || r == '\u00a0' || r == '\u202f'
+}
+
 var (
-	tailRegexp = regexp.MustCompile(`(?:\..*[1-9](0+)|(\.0+))$`)
+	tailRegexp    = regexp.MustCompile(`(?:\..*[1-9](0+)|(\.0+))$`)
+	groupedRegexp = regexp.MustCompile(`,[0-9]{3}(?:[^0-9]|$)`)
 )
 
 // DecodeAnother implements the [Value] interface.
@@ -243,6 +588,67 @@ func (Real) DecodeAnother(val Raw) (Value, error) {
 	return Real{f, v.Q}, nil
 }
 
+// repair implements the repairer interface.
+// Numbers written using locale-specific conventions, for example "0,5",
+// are repaired.
+func (Real) repair(val Raw) (Value, string, bool) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, "", false
+	}
+	f, err := parseLenientFloat(v.V)
+	if err != nil {
+		return nil, "", false
+	}
+	return Real{f, v.Q}, "malformed number " + strconv.Quote(v.V), true
+}
+
+// parseLenientFloat parses numbers written using locale-specific
+// conventions, for example with a decimal comma ("0,5") or with thousands
+// separators ("1,234.5", "1.234,5", "1 234,5").
+//
+// If both '.' and ',' are present, the one which occurs last is taken to
+// be the decimal separator.  If only commas are present, a single comma is
+// taken to be a decimal comma.
+func parseLenientFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+
+	lastDot := strings.LastIndexByte(s, '.')
+	lastComma := strings.LastIndexByte(s, ',')
+
+	var intPart, fracPart string
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		sep := max(lastDot, lastComma)
+		intPart, fracPart = s[:sep], s[sep+1:]
+	case lastComma >= 0 && strings.Count(s, ",") == 1:
+		intPart, fracPart = s[:lastComma], s[lastComma+1:]
+	case lastDot >= 0 && strings.Count(s, ".") == 1:
+		intPart, fracPart = s[:lastDot], s[lastDot+1:]
+	default:
+		intPart, fracPart = s, ""
+	}
+
+	// Remove thousands separators from the integer part.  Separators must
+	// be followed by groups of exactly three digits.
+	sign := ""
+	if len(intPart) > 0 && (intPart[0] == '-' || intPart[0] == '+') {
+		sign, intPart = intPart[:1], intPart[1:]
+	}
+	groups := strings.FieldsFunc(intPart, isGroupSeparator)
+	for i, g := range groups {
+		if i > 0 && len(g) != 3 {
+			return 0, ErrInvalid
+		}
+	}
+
+	clean := sign + strings.Join(groups, "")
+	if fracPart != "" {
+		clean += "." + fracPart
+	}
+	return strconv.ParseFloat(clean, 64)
+}
+
 // Date represents a date and time.
 type Date struct {
 	V time.Time
@@ -267,14 +673,20 @@ func (d Date) IsZero() bool {
 
 // EncodeXMP implements the [Value] interface.
 func (d Date) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: d.format(),
+		Q: d.Q,
+	}
+}
+
+// format returns the XMP text representation of the date, without
+// qualifiers.
+func (d Date) format() string {
 	numOmitted := d.NumOmitted
 	numOmitted = min(numOmitted, len(dateFormats)-1)
 	numOmitted = max(numOmitted, 0)
 	format := dateFormats[numOmitted]
-	return Text{
-		V: d.V.Format(format),
-		Q: d.Q,
-	}
+	return d.V.Format(format)
 }
 
 // DecodeAnother implements the [Value] interface.
@@ -283,20 +695,23 @@ func (Date) DecodeAnother(val Raw) (Value, error) {
 	if !ok {
 		return nil, ErrInvalid
 	}
-	dateString := v.V
+	d, err := parseDate(v.V)
+	if err != nil {
+		return nil, err
+	}
+	d.Q = v.Q
+	return d, nil
+}
 
+// parseDate parses the XMP text representation of a date.
+func parseDate(dateString string) (Date, error) {
 	for i, format := range dateFormats {
 		t, err := time.Parse(format, dateString)
 		if err == nil {
-			val := Date{
-				V:          t,
-				NumOmitted: i,
-				Q:          v.Q,
-			}
-			return val, nil
+			return Date{V: t, NumOmitted: i}, nil
 		}
 	}
-	return nil, ErrInvalid
+	return Date{}, ErrInvalid
 }
 
 var dateFormats = []string{
@@ -308,6 +723,87 @@ var dateFormats = []string{
 	"2006",
 }
 
+// DateRange represents a period of time, for example for the temporal
+// coverage of a resource.
+//
+// In XMP, a DateRange is represented as a text value of the form
+// "start/end", for example "2023-01/2023-03", where start and end use the
+// XMP date format.  Open-ended ranges use ".." in place of the missing
+// date, for example "1990/..".
+type DateRange struct {
+	// Start is the beginning of the period.  If Start is the zero time, the
+	// period has no defined beginning.  Qualifiers of Start are ignored.
+	Start Date
+
+	// End is the end of the period.  If End is the zero time, the period has
+	// no defined end.  Qualifiers of End are ignored.
+	End Date
+
+	Q
+}
+
+func (r DateRange) String() string {
+	return r.format()
+}
+
+// IsZero implements the [Value] interface.
+func (r DateRange) IsZero() bool {
+	return r.Start.V.IsZero() && r.End.V.IsZero() && len(r.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (r DateRange) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: r.format(),
+		Q: r.Q,
+	}
+}
+
+func (r DateRange) format() string {
+	start, end := "..", ".."
+	if !r.Start.V.IsZero() {
+		start = r.Start.format()
+	}
+	if !r.End.V.IsZero() {
+		end = r.End.format()
+	}
+	return start + "/" + end
+}
+
+// DecodeAnother implements the [Value] interface.
+//
+// A single date is interpreted as a range which starts and ends at that date.
+func (DateRange) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+
+	startString, endString, isRange := strings.Cut(v.V, "/")
+	if !isRange {
+		endString = startString
+	}
+
+	res := DateRange{Q: v.Q}
+	var err error
+	if startString != ".." && startString != "" {
+		res.Start, err = parseDate(startString)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if endString != ".." && endString != "" {
+		res.End, err = parseDate(endString)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !isRange && res.Start.V.IsZero() {
+		return nil, ErrInvalid
+	}
+	return res, nil
+}
+
 // Locale represents a language code.
 type Locale struct {
 	V language.Tag
@@ -395,6 +891,50 @@ func (m MimeType) DecodeAnother(val Raw) (Value, error) {
 	}, nil
 }
 
+// Base64Data represents binary data, stored as base64-encoded text.
+// The field V holds the decoded bytes.
+type Base64Data struct {
+	V []byte
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (b Base64Data) IsZero() bool {
+	return len(b.V) == 0 && len(b.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (b Base64Data) EncodeXMP(*Packet) Raw {
+	return Text{
+		V: base64.StdEncoding.EncodeToString(b.V),
+		Q: b.Q,
+	}
+}
+
+// DecodeAnother implements the [Value] interface.
+// White space in the encoded data is ignored, and missing padding is
+// tolerated.
+func (Base64Data) DecodeAnother(val Raw) (Value, error) {
+	v, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, v.V)
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(s)
+	}
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	return Base64Data{V: data, Q: v.Q}, nil
+}
+
 // OptionalBool represents an optional boolean value.
 // The possible values are "True", "False", and unset.
 type OptionalBool struct {
@@ -502,9 +1042,8 @@ func (UnorderedArray[E]) DecodeAnother(val Raw) (Value, error) {
 	if !ok {
 		// Try to fix invalid input files: if the data can be decoded as a
 		// single E, return a single-element array.
-		var tmp E
-		if v, err := tmp.DecodeAnother(val); err == nil {
-			return UnorderedArray[E]{V: []E{v.(E)}}, nil
+		if v, err := decodeAs[E](val); err == nil {
+			return UnorderedArray[E]{V: []E{v}}, nil
 		}
 
 		return nil, ErrInvalid
@@ -514,16 +1053,25 @@ func (UnorderedArray[E]) DecodeAnother(val Raw) (Value, error) {
 	res := UnorderedArray[E]{Q: a.Q}
 	res.V = make([]E, len(a.Value))
 	for i, val := range a.Value {
-		w, err := res.V[i].DecodeAnother(val)
+		w, err := decodeAs[E](val)
 		if err != nil {
 			return nil, err
 		}
-		res.V[i] = w.(E)
+		res.V[i] = w
 	}
 	res.Q = a.Q
 	return res, nil
 }
 
+// repair implements the repairer interface.
+func (UnorderedArray[E]) repair(val Raw) (Value, string, bool) {
+	v, q, msg, ok := repairElements[E](val)
+	if !ok {
+		return nil, "", false
+	}
+	return UnorderedArray[E]{V: v, Q: q}, msg, true
+}
+
 // OrderedArray is an ordered array of values.
 // All elements of the array have the same type, E.
 type OrderedArray[E Value] struct {
@@ -560,9 +1108,8 @@ func (OrderedArray[E]) DecodeAnother(val Raw) (Value, error) {
 	if !ok {
 		// Try to fix invalid input files: if the data can be decoded as a
 		// single E, return a single-element array.
-		var tmp E
-		if v, err := tmp.DecodeAnother(val); err == nil {
-			return OrderedArray[E]{V: []E{v.(E)}}, nil
+		if v, err := decodeAs[E](val); err == nil {
+			return OrderedArray[E]{V: []E{v}}, nil
 		}
 
 		return nil, ErrInvalid
@@ -572,16 +1119,25 @@ func (OrderedArray[E]) DecodeAnother(val Raw) (Value, error) {
 	res := OrderedArray[E]{Q: a.Q}
 	res.V = make([]E, len(a.Value))
 	for i, val := range a.Value {
-		w, err := res.V[i].DecodeAnother(val)
+		w, err := decodeAs[E](val)
 		if err != nil {
 			return nil, err
 		}
-		res.V[i] = w.(E)
+		res.V[i] = w
 	}
 	res.Q = a.Q
 	return res, nil
 }
 
+// repair implements the repairer interface.
+func (OrderedArray[E]) repair(val Raw) (Value, string, bool) {
+	v, q, msg, ok := repairElements[E](val)
+	if !ok {
+		return nil, "", false
+	}
+	return OrderedArray[E]{V: v, Q: q}, msg, true
+}
+
 // AlternativeArray is an ordered array of values.
 // All values in the array have the same type E.
 type AlternativeArray[E Value] struct {
@@ -613,9 +1169,8 @@ func (AlternativeArray[E]) DecodeAnother(val Raw) (Value, error) {
 	if !ok {
 		// Try to fix invalid input files: if the data can be decoded as a
 		// single E, return a single-element array.
-		var tmp E
-		if v, err := tmp.DecodeAnother(val); err == nil {
-			return AlternativeArray[E]{V: []E{v.(E)}}, nil
+		if v, err := decodeAs[E](val); err == nil {
+			return AlternativeArray[E]{V: []E{v}}, nil
 		}
 
 		return nil, ErrInvalid
@@ -625,16 +1180,25 @@ func (AlternativeArray[E]) DecodeAnother(val Raw) (Value, error) {
 	res := AlternativeArray[E]{Q: a.Q}
 	res.V = make([]E, len(a.Value))
 	for i, val := range a.Value {
-		w, err := res.V[i].DecodeAnother(val)
+		w, err := decodeAs[E](val)
 		if err != nil {
 			return nil, err
 		}
-		res.V[i] = w.(E)
+		res.V[i] = w
 	}
 	res.Q = a.Q
 	return res, nil
 }
 
+// repair implements the repairer interface.
+func (AlternativeArray[E]) repair(val Raw) (Value, string, bool) {
+	v, q, msg, ok := repairElements[E](val)
+	if !ok {
+		return nil, "", false
+	}
+	return AlternativeArray[E]{V: v, Q: q}, msg, true
+}
+
 // Localized represents a localized text value.  This is a map from language
 // tags to strings.  This is implemented as an XMP "Language Alternative".
 type Localized struct {
@@ -674,7 +1238,13 @@ func (l Localized) EncodeXMP(*Packet) Raw {
 		}
 		vals = append(vals, t)
 	}
-	for lang, txt := range l.V {
+	// Sort the languages, so that the output is deterministic.
+	langs := maps.Keys(l.V)
+	sort.Slice(langs, func(i, j int) bool {
+		return langs[i].String() < langs[j].String()
+	})
+	for _, lang := range langs {
+		txt := l.V[lang]
 		t := Text{
 			V: txt.V,
 			Q: txt.Q.WithLanguage(lang),
@@ -730,7 +1300,7 @@ type ResourceRef struct {
 	DocumentID GUID
 
 	// FilePath is the file path or URL of the referenced resource.
-	FilePath URL
+	FilePath URI
 
 	// InstanceID is the instance ID of the referenced resource,
 	// as found in the xmpMM:InstanceID field.
@@ -744,30 +1314,143 @@ type ResourceRef struct {
 }
 
 // IsZero implements the [Value] interface.
-func (r *ResourceRef) IsZero() bool {
-	return r == nil
+func (r ResourceRef) IsZero() bool {
+	return r.DocumentID.IsZero() && r.FilePath.IsZero() &&
+		r.InstanceID.IsZero() && r.RenditionClass.IsZero() &&
+		r.RenditionParams.IsZero() && len(r.Q) == 0
 }
 
-// GetXMP implements the [Value] interface.
-func (r *ResourceRef) GetXMP(p *Packet) Raw {
-	ns := "http://ns.adobe.com/xap/1.0/sType/ResourceRef#"
-	p.RegisterPrefix(ns, "stRef")
-	res := &RawStruct{}
-	if !r.DocumentID.IsZero() {
-		res.Value[xml.Name{Space: ns, Local: "documentID"}] = r.DocumentID.EncodeXMP(p)
+// EncodeXMP implements the [Value] interface.
+func (r ResourceRef) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     r.Q,
 	}
-	if !r.FilePath.IsZero() {
-		res.Value[xml.Name{Space: ns, Local: "filePath"}] = r.FilePath.EncodeXMP(p)
+	setField(res, p, stRefNamespace, "documentID", r.DocumentID)
+	setField(res, p, stRefNamespace, "filePath", r.FilePath)
+	setField(res, p, stRefNamespace, "instanceID", r.InstanceID)
+	setField(res, p, stRefNamespace, "renditionClass", r.RenditionClass)
+	setField(res, p, stRefNamespace, "renditionParams", r.RenditionParams)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (ResourceRef) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
 	}
-	if !r.InstanceID.IsZero() {
-		res.Value[xml.Name{Space: ns, Local: "instanceID"}] = r.InstanceID.EncodeXMP(p)
+	return ResourceRef{
+		DocumentID:      getField[GUID](s, stRefNamespace, "documentID"),
+		FilePath:        getField[URI](s, stRefNamespace, "filePath"),
+		InstanceID:      getField[GUID](s, stRefNamespace, "instanceID"),
+		RenditionClass:  getField[RenditionClass](s, stRefNamespace, "renditionClass"),
+		RenditionParams: getField[Text](s, stRefNamespace, "renditionParams"),
+		Q:               s.Q,
+	}, nil
+}
+
+const stRefNamespace = "http://ns.adobe.com/xap/1.0/sType/ResourceRef#"
+
+// Dimensions represents the size of an image or page.
+type Dimensions struct {
+	// W is the width.
+	W Real
+
+	// H is the height.
+	H Real
+
+	// Unit is the unit of W and H, for example "inch", "mm", or "pixel".
+	Unit Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (d Dimensions) IsZero() bool {
+	return d.W.IsZero() && d.H.IsZero() && d.Unit.IsZero() && len(d.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (d Dimensions) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     d.Q,
 	}
-	if !r.RenditionClass.IsZero() {
-		res.Value[xml.Name{Space: ns, Local: "renditionClass"}] = r.RenditionClass.EncodeXMP(p)
+	setField(res, p, stDimNamespace, "w", d.W)
+	setField(res, p, stDimNamespace, "h", d.H)
+	setField(res, p, stDimNamespace, "unit", d.Unit)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Dimensions) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
 	}
-	if !r.RenditionParams.IsZero() {
-		res.Value[xml.Name{Space: ns, Local: "renditionParams"}] = r.RenditionParams.EncodeXMP(p)
+	return Dimensions{
+		W:    getField[Real](s, stDimNamespace, "w"),
+		H:    getField[Real](s, stDimNamespace, "h"),
+		Unit: getField[Text](s, stDimNamespace, "unit"),
+		Q:    s.Q,
+	}, nil
+}
+
+const stDimNamespace = "http://ns.adobe.com/xap/1.0/sType/Dimensions#"
+
+// getField decodes a field of an XMP structure.  If the field is missing or
+// invalid, the zero value is returned.
+func getField[E Value](s RawStruct, ns, local string) E {
+	var zero E
+	raw, ok := s.Value[xml.Name{Space: ns, Local: local}]
+	if !ok {
+		return zero
+	}
+	v, err := decodeAs[E](raw)
+	if err != nil {
+		return zero
 	}
+	return v
+}
 
-	return res
+// decodeAs converts a low-level XMP representation into a value of type E.
+func decodeAs[E Value](val Raw) (E, error) {
+	var zero E
+	dec, ok := DecoderFor(reflect.TypeFor[E]())
+	if !ok {
+		return zero, ErrInvalid
+	}
+	v, err := dec.DecodeAnother(val)
+	if err != nil {
+		return zero, err
+	}
+	res, ok := v.(E)
+	if !ok {
+		return zero, ErrInvalid
+	}
+	return res, nil
+}
+
+// DecoderFor returns a value of Go type t on which DecodeAnother can be
+// called.  For pointer types, a pointer to a new zero value is used
+// instead of the nil pointer.  For interface types, the concrete type is
+// unknown and ok is false.
+func DecoderFor(t reflect.Type) (dec Value, ok bool) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return reflect.New(t.Elem()).Interface().(Value), true
+	case reflect.Interface:
+		return nil, false
+	}
+	return reflect.Zero(t).Interface().(Value), true
+}
+
+// setField stores a field in an XMP structure.  Zero values are omitted.
+func setField(s RawStruct, p *Packet, ns, local string, v Value) {
+	if v.IsZero() {
+		return
+	}
+	p.registerDefaultPrefix(ns)
+	s.Value[xml.Name{Space: ns, Local: local}] = v.EncodeXMP(p)
 }
@@ -17,6 +17,10 @@
 package xmp
 
 import (
+	"bytes"
+	"encoding/xml"
+	"net/url"
+	"strings"
 	"testing"
 
 	"golang.org/x/text/language"
@@ -64,3 +68,520 @@ func TestUnorderedArray(t *testing.T) {
 		t.Errorf("A and B are different (-want +got):\n%s", d)
 	}
 }
+
+// fuzzValueTypes lists zero values of all value types exercised by
+// FuzzDecodeAnother.
+var fuzzValueTypes = []Value{
+	Text{},
+	URL{},
+	ProperName{},
+	AgentName{},
+	RenditionClass{},
+	GUID{},
+	URI{},
+	Identifier{},
+	Real{},
+	Integer{},
+	Decimal{},
+	Rational{},
+	OECF{},
+	CFAPattern{},
+	Flash{},
+	DeviceSettings{},
+	GPSCoordinate{},
+	Date{},
+	DateRange{},
+	Locale{},
+	MimeType{},
+	Base64Data{},
+	OptionalBool{},
+	Localized{},
+	LocationDetails{},
+	PersonDetails{},
+	OrganisationDetails{},
+	ContactInfo{},
+	ArtworkDetails{},
+	RegistryEntry{},
+	Job{},
+	Track{},
+	Marker{},
+	CuePointParam{},
+	ProjectLink{},
+	Timecode{},
+	Layer{},
+	Time{},
+	BeatSpliceStretch{},
+	ResampleStretch{},
+	TimeScaleStretch{},
+	FrameRate{},
+	FrameCount{},
+	PantryItem{},
+	Licensor{},
+	CopyrightOwner{},
+	MPRegionInfo{},
+	MPRegion{},
+	ResourceRef{},
+	ManifestItem{},
+	Dimensions{},
+	Thumbnail{},
+	Font{},
+	Colorant{},
+	SwatchGroup{},
+	Version{},
+	ResourceEvent{},
+	Area{},
+	Region{},
+	RegionInfo{},
+	CollectionInfo{},
+	KeywordInfo{},
+	KeywordStruct{},
+	Struct{},
+	UnorderedArray[Text]{},
+	OrderedArray[Date]{},
+	AlternativeArray[Locale]{},
+}
+
+// FuzzDecodeAnother checks that the DecodeAnother methods of all value types
+// can cope with arbitrary input, and that successfully decoded values
+// survive a round trip through their XMP representation.
+func FuzzDecodeAnother(f *testing.F) {
+	for _, tc := range decodeTestCases {
+		f.Add([]byte(head + tc.in + foot))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		p, err := Read(bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		for name, raw := range p.Properties {
+			for _, zero := range fuzzValueTypes {
+				v, err := zero.DecodeAnother(raw)
+				if err != nil {
+					continue
+				}
+				enc := v.EncodeXMP(p)
+				w, err := zero.DecodeAnother(enc)
+				if err != nil {
+					t.Fatalf("%s as %T: cannot decode re-encoded value: %v", name.Local, zero, err)
+				}
+				if !EqualValue(v, w) {
+					t.Fatalf("%s as %T: round trip changed value: %v != %v", name.Local, zero, v, w)
+				}
+			}
+		}
+	})
+}
+
+func TestParseLenientFloat(t *testing.T) {
+	type testCase struct {
+		in   string
+		want float64
+		ok   bool
+	}
+	cases := []testCase{
+		{"0,5", 0.5, true},
+		{"-1,25", -1.25, true},
+		{"1,234.5", 1234.5, true},
+		{"1.234,5", 1234.5, true},
+		{"1 234,5", 1234.5, true},
+		{"1'234'567", 1234567, true},
+		{"1,234,567", 1234567, true},
+		{"1.234.567", 1234567, true},
+		{"12,34,56", 0, false},
+		{"1,2.3,4", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tc := range cases {
+		got, err := parseLenientFloat(tc.in)
+		if (err == nil) != tc.ok {
+			t.Errorf("%q: unexpected error %v", tc.in, err)
+		} else if tc.ok && got != tc.want {
+			t.Errorf("%q: got %g, want %g", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestIntegerRepair(t *testing.T) {
+	type testCase struct {
+		in   string
+		want int64
+		ok   bool
+	}
+	cases := []testCase{
+		{"8,000", 8000, true},
+		{"-1,234,567", -1234567, true},
+		{"1 234", 1234, true},
+		{"1.234.567", 1234567, true},
+		{"3.0", 3, true},
+		{"3.000", 3, true},
+		{"3,0", 3, true},
+		{"1,5", 0, false},
+		{"1,50", 0, false},
+		{"12,34", 0, false},
+		{"1234,000", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tc := range cases {
+		v, _, ok := Integer{}.repair(Text{V: tc.in})
+		if ok != tc.ok {
+			t.Errorf("%q: got ok=%t, want %t", tc.in, ok, tc.ok)
+		} else if ok && v.(Integer).V != tc.want {
+			t.Errorf("%q: got %d, want %d", tc.in, v.(Integer).V, tc.want)
+		}
+	}
+}
+
+func TestRepairDiagnostics(t *testing.T) {
+	in := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about=""
+	xmlns:GPano="http://ns.google.com/photos/1.0/panorama/"
+	GPano:PoseHeadingDegrees="0,5"
+	GPano:SourcePhotosCount="3.0"
+	GPano:FullPanoWidthPixels="8,000"/>
+</rdf:RDF>
+</x:xmpmeta>`
+
+	// By default, malformed values are kept but rejected.
+	p := NewPacket()
+	err := p.DecodeWithOptions(strings.NewReader(in), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Diagnostics()) != 0 {
+		t.Errorf("unexpected diagnostics %v", p.Diagnostics())
+	}
+	_, err = PacketGetValue[Real](p, gpanoNamespace, "PoseHeadingDegrees")
+	if err != ErrInvalid {
+		t.Errorf("got error %v, want %v", err, ErrInvalid)
+	}
+
+	// In lenient mode, malformed values are repaired while decoding.
+	err = p.DecodeWithOptions(strings.NewReader(in), &DecodeOptions{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Diagnostic{
+		{
+			Property: xml.Name{Space: gpanoNamespace, Local: "FullPanoWidthPixels"},
+			Message:  `malformed integer "8,000"`,
+		},
+		{
+			Property: xml.Name{Space: gpanoNamespace, Local: "PoseHeadingDegrees"},
+			Message:  `malformed number "0,5"`,
+		},
+		{
+			Property: xml.Name{Space: gpanoNamespace, Local: "SourcePhotosCount"},
+			Message:  `malformed integer "3.0"`,
+		},
+	}
+	if d := cmp.Diff(want, p.Diagnostics()); d != "" {
+		t.Errorf("diagnostics (-want +got):\n%s", d)
+	}
+
+	// Reading values does not add further diagnostics.
+	g := &GPano{}
+	p.Get(g)
+	p.Get(g)
+	if g.PoseHeadingDegrees.V != 0.5 || g.SourcePhotosCount.V != 3 || g.FullPanoWidthPixels.V != 8000 {
+		t.Errorf("unexpected values %g, %d, %d", g.PoseHeadingDegrees.V, g.SourcePhotosCount.V, g.FullPanoWidthPixels.V)
+	}
+	if len(p.Diagnostics()) != 3 {
+		t.Errorf("got %d diagnostics, want 3", len(p.Diagnostics()))
+	}
+
+	p.Reset()
+	if len(p.Diagnostics()) != 0 {
+		t.Errorf("diagnostics not cleared")
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	type testCase struct {
+		in  string
+		out string
+	}
+	cases := []testCase{
+		{"2023-01/2023-03", "2023-01/2023-03"},
+		{"2023-01-15/2023-03", "2023-01-15/2023-03"},
+		{"1990/..", "1990/.."},
+		{"../1990", "../1990"},
+		{"/1990", "../1990"},
+		{"2024", "2024/2024"},
+		{"2023-05-01T10:00Z/2023-05-01T12:30Z", "2023-05-01T10:00Z/2023-05-01T12:30Z"},
+	}
+	for _, tc := range cases {
+		v, err := DateRange{}.DecodeAnother(Text{V: tc.in})
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		out := v.EncodeXMP(nil).(Text).V
+		if out != tc.out {
+			t.Errorf("%q: got %q, want %q", tc.in, out, tc.out)
+		}
+	}
+
+	for _, in := range []string{"", "..", "2023/13", "yesterday"} {
+		_, err := DateRange{}.DecodeAnother(Text{V: in})
+		if err != ErrInvalid {
+			t.Errorf("%q: unexpected error %v", in, err)
+		}
+	}
+}
+
+// ptrValue is a Value implementation with pointer receivers.
+type ptrValue struct {
+	V string
+}
+
+func (v *ptrValue) IsZero() bool {
+	return v == nil || v.V == ""
+}
+
+func (v *ptrValue) EncodeXMP(*Packet) Raw {
+	return Text{V: v.V}
+}
+
+func (v *ptrValue) DecodeAnother(val Raw) (Value, error) {
+	t, ok := val.(Text)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return &ptrValue{V: t.V}, nil
+}
+
+func TestPointerValues(t *testing.T) {
+	p := NewPacket()
+	p.SetValue(elemTestA.Space, elemTestA.Local, &ptrValue{V: "a"})
+	p.SetValue(elemTestB.Space, elemTestB.Local,
+		OrderedArray[*ptrValue]{V: []*ptrValue{{V: "b"}, {V: "c"}}})
+
+	a, err := PacketGetValue[*ptrValue](p, elemTestA.Space, elemTestA.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(&ptrValue{V: "a"}, a); d != "" {
+		t.Error(d)
+	}
+
+	b, err := PacketGetValue[OrderedArray[*ptrValue]](p, elemTestB.Space, elemTestB.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]*ptrValue{{V: "b"}, {V: "c"}}, b.V); d != "" {
+		t.Error(d)
+	}
+
+	_, err = PacketGetValue[Value](p, elemTestA.Space, elemTestA.Local)
+	if err != ErrInvalid {
+		t.Errorf("interface type: got %v, want ErrInvalid", err)
+	}
+}
+
+func TestResourceRef(t *testing.T) {
+	in := &MediaManagement{
+		DerivedFrom: ResourceRef{
+			DocumentID:     GUID{V: "xmp.did:1234"},
+			InstanceID:     GUID{V: "xmp.iid:5678"},
+			RenditionClass: RenditionClass{V: "proof:pdf"},
+		},
+		DocumentID: NewText("xmp.did:abcd"),
+	}
+	p := NewPacket()
+	err := p.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &MediaManagement{}
+	p.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
+
+func TestInteger(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected int64
+		ok       bool
+		repaired bool
+	}{
+		{"0", 0, true, false},
+		{"-17", -17, true, false},
+		{"+42", 42, true, false},
+		{"3.0", 3, true, true},
+		{"3.5", 0, false, false},
+		{"9223372036854775807", 9223372036854775807, true, false},
+		{"9223372036854775808", 0, false, false},
+		{"1e30", 0, false, false},
+		{"abc", 0, false, false},
+	}
+	for _, tc := range testCases {
+		_, err := Integer{}.DecodeAnother(Text{V: tc.in})
+		if valid := err == nil; valid != (tc.ok && !tc.repaired) {
+			t.Errorf("%q: DecodeAnother gave error %v", tc.in, err)
+		}
+		v, msg, ok := repairValue(Integer{}, Text{V: tc.in})
+		if ok != tc.ok {
+			t.Errorf("%q: got ok=%t, want %t", tc.in, ok, tc.ok)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		if repaired := msg != ""; repaired != tc.repaired {
+			t.Errorf("%q: repaired=%t, want %t", tc.in, repaired, tc.repaired)
+		}
+		if got := v.(Integer).V; got != tc.expected {
+			t.Errorf("%q: got %d, want %d", tc.in, got, tc.expected)
+		}
+	}
+
+	enc := Integer{V: -123}.EncodeXMP(nil)
+	if d := cmp.Diff(Text{V: "-123"}, enc); d != "" {
+		t.Error(d)
+	}
+}
+
+func TestAgentNameParts(t *testing.T) {
+	testCases := []struct {
+		in                              string
+		organization, software, version string
+		tokens                          []string
+	}{
+		{"Adobe Photoshop CC 2019 (Windows)", "Adobe", "Photoshop CC", "2019", []string{"Windows"}},
+		{"Adobe Acrobat 9.0 (Macintosh; Intel)", "Adobe", "Acrobat", "9.0", []string{"Macintosh", "Intel"}},
+		{"GIMP 2.10", "", "GIMP", "2.10", nil},
+		{"digiKam-8.2.0", "", "digiKam-8.2.0", "", nil},
+		{"Example Tool v3", "Example", "Tool", "v3", nil},
+		{"", "", "", "", nil},
+	}
+	for _, tc := range testCases {
+		a := NewAgentName(tc.in)
+		if got := a.Organization(); got != tc.organization {
+			t.Errorf("%q: organization %q, want %q", tc.in, got, tc.organization)
+		}
+		if got := a.Software(); got != tc.software {
+			t.Errorf("%q: software %q, want %q", tc.in, got, tc.software)
+		}
+		if got := a.Version(); got != tc.version {
+			t.Errorf("%q: version %q, want %q", tc.in, got, tc.version)
+		}
+		if d := cmp.Diff(tc.tokens, a.Tokens()); d != "" {
+			t.Errorf("%q: tokens differ (-want +got):\n%s", tc.in, d)
+		}
+	}
+
+	a := BuildAgentName("Adobe", "Acrobat", "9.0", "Macintosh", "Intel")
+	if a.V != "Adobe Acrobat 9.0 (Macintosh;Intel)" {
+		t.Errorf("wrong agent name %q", a.V)
+	}
+	if a.Software() != "Acrobat" || a.Version() != "9.0" {
+		t.Errorf("round trip failed for %q", a.V)
+	}
+	if a := BuildAgentName("", "seehuhn.de/go/xmp", ""); a.V != "seehuhn.de/go/xmp" {
+		t.Errorf("wrong agent name %q", a.V)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	testCases := []struct {
+		in       string
+		out      string
+		f        float64
+		ok       bool
+		repaired bool
+	}{
+		{"0.5000", "0.5000", 0.5, true, false},
+		{"+3", "+3", 3, true, false},
+		{"-.25", "-.25", -0.25, true, false},
+		{"1.50e3", "1.50e3", 1500, true, false},
+		{"0,5", "0.5", 0.5, true, true},
+		{"NaN", "", 0, false, false},
+		{"Inf", "", 0, false, false},
+		{"0x10", "", 0, false, false},
+		{"", "", 0, false, false},
+	}
+	for _, tc := range testCases {
+		_, err := Decimal{}.DecodeAnother(Text{V: tc.in})
+		if valid := err == nil; valid != (tc.ok && !tc.repaired) {
+			t.Errorf("%q: DecodeAnother gave error %v", tc.in, err)
+		}
+		v, msg, ok := repairValue(Decimal{}, Text{V: tc.in})
+		if ok != tc.ok {
+			t.Errorf("%q: got ok=%t, want %t", tc.in, ok, tc.ok)
+			continue
+		}
+		if !tc.ok {
+			continue
+		}
+		if repaired := msg != ""; repaired != tc.repaired {
+			t.Errorf("%q: repaired=%t, want %t", tc.in, repaired, tc.repaired)
+		}
+		d := v.(Decimal)
+		if enc := d.EncodeXMP(nil).(Text).V; enc != tc.out {
+			t.Errorf("%q: encoded as %q, want %q", tc.in, enc, tc.out)
+		}
+		if f, err := d.Float64(); err != nil || f != tc.f {
+			t.Errorf("%q: Float64 gave %g, %v", tc.in, f, err)
+		}
+	}
+
+	d, err := NewDecimal("42.000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i, err := d.Int64(); err != nil || i != 42 {
+		t.Errorf("Int64 gave %d, %v", i, err)
+	}
+	if _, err := DecimalFromFloat(2.5).Int64(); err == nil {
+		t.Error("expected error for fractional value")
+	}
+	if _, err := NewDecimal("1,5"); err == nil {
+		t.Error("expected error for invalid number")
+	}
+}
+
+func TestURI(t *testing.T) {
+	in := &RightsManagement{
+		WebStatement: NewURI("https://example.com/rights"),
+	}
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<xmpRights:WebStatement>https://example.com/rights</xmpRights:WebStatement>")) {
+		t.Errorf("URI not written as text:\n%s", buf.Bytes())
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &RightsManagement{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+
+	// Values written using rdf:resource are also accepted.
+	v, err := URI{}.DecodeAnother(URL{V: &url.URL{Scheme: "http", Host: "example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(NewURI("http://example.com"), v); d != "" {
+		t.Error(d)
+	}
+
+	u, err := NewURI("../other.psd").URL()
+	if err != nil || u.Path != "../other.psd" {
+		t.Errorf("URL gave %v, %v", u, err)
+	}
+}
@@ -0,0 +1,80 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import "encoding/xml"
+
+// Version describes one version of a document, as used in xmpMM:Versions.
+//
+// See section 8.2.2.11 of ISO 16684-1:2011.
+type Version struct {
+	// Comments describes the changes made in this version.
+	Comments Text
+
+	// Event describes the high-level action which resulted in this
+	// version.
+	Event ResourceEvent
+
+	// Modifier is the person who modified this version.
+	Modifier ProperName
+
+	// ModifyDate is the date and time the version was created.
+	ModifyDate Date
+
+	// Version is the version number, for example "1.0".
+	Version Text
+
+	Q
+}
+
+// IsZero implements the [Value] interface.
+func (v Version) IsZero() bool {
+	return v.Comments.IsZero() && v.Event.IsZero() && v.Modifier.IsZero() &&
+		v.ModifyDate.IsZero() && v.Version.IsZero() && len(v.Q) == 0
+}
+
+// EncodeXMP implements the [Value] interface.
+func (v Version) EncodeXMP(p *Packet) Raw {
+	res := RawStruct{
+		Value: make(map[xml.Name]Raw),
+		Q:     v.Q,
+	}
+	setField(res, p, stVerNamespace, "comments", v.Comments)
+	setField(res, p, stVerNamespace, "event", v.Event)
+	setField(res, p, stVerNamespace, "modifier", v.Modifier)
+	setField(res, p, stVerNamespace, "modifyDate", v.ModifyDate)
+	setField(res, p, stVerNamespace, "version", v.Version)
+	return res
+}
+
+// DecodeAnother implements the [Value] interface.
+func (Version) DecodeAnother(val Raw) (Value, error) {
+	s, ok := val.(RawStruct)
+	if !ok {
+		return nil, ErrInvalid
+	}
+	return Version{
+		Comments:   getField[Text](s, stVerNamespace, "comments"),
+		Event:      getField[ResourceEvent](s, stVerNamespace, "event"),
+		Modifier:   getField[ProperName](s, stVerNamespace, "modifier"),
+		ModifyDate: getField[Date](s, stVerNamespace, "modifyDate"),
+		Version:    getField[Text](s, stVerNamespace, "version"),
+		Q:          s.Q,
+	}, nil
+}
+
+const stVerNamespace = "http://ns.adobe.com/xap/1.0/sType/Version#"
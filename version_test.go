@@ -0,0 +1,75 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVersions(t *testing.T) {
+	when := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	in := &MediaManagement{
+		Versions: OrderedArray[Version]{V: []Version{
+			{
+				Comments: NewText("first draft"),
+				Event: ResourceEvent{
+					Action:        NewText("created"),
+					InstanceID:    GUID{V: "xmp.iid:1234"},
+					SoftwareAgent: NewAgentName("example-tool 1.0"),
+					When:          NewDate(when),
+				},
+				Modifier:   ProperName{V: "Jane Doe"},
+				ModifyDate: NewDate(when),
+				Version:    NewText("1"),
+			},
+			{
+				Comments: NewText("fixed typos"),
+				Event: ResourceEvent{
+					Action:  NewText("edited"),
+					Changed: NewText("/content"),
+					When:    NewDate(when.Add(time.Hour)),
+				},
+				ModifyDate: NewDate(when.Add(time.Hour)),
+				Version:    NewText("2"),
+			},
+		}},
+	}
+
+	p1 := NewPacket()
+	err := p1.Set(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	err = p1.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &MediaManagement{}
+	p2.Get(out)
+	if d := cmp.Diff(in, out); d != "" {
+		t.Errorf("models differ (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,164 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/xml"
+	"hash"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	volatileMu    sync.RWMutex
+	volatileNames = map[xml.Name]bool{
+		{Space: basicNamespace, Local: "MetadataDate"}: true,
+		{Space: mmNamespace, Local: "InstanceID"}:      true,
+	}
+)
+
+// IsVolatile reports whether a property is volatile.  Volatile properties
+// change every time a file is saved, without a change in the content of
+// the metadata.  By default, xmp:MetadataDate and xmpMM:InstanceID are
+// volatile.  Additional properties can be registered using [MarkVolatile].
+//
+// IsVolatile can be passed to [Packet.EqualExcept], [DiffExcept] and
+// [Packet.Digest] to ignore volatile properties.
+func IsVolatile(name xml.Name) bool {
+	volatileMu.RLock()
+	defer volatileMu.RUnlock()
+	return volatileNames[name]
+}
+
+// MarkVolatile registers a property as volatile.
+func MarkVolatile(ns, local string) {
+	volatileMu.Lock()
+	defer volatileMu.Unlock()
+	volatileNames[xml.Name{Space: ns, Local: local}] = true
+}
+
+// Digest returns a SHA-256 hash of the properties of the packet.
+// Properties for which ignore returns true are skipped.
+//
+// The digest depends only on the XMP data model, not on the serialization:
+// packets which compare equal using [Packet.EqualExcept] with the same
+// ignore function have the same digest.
+func (p *Packet) Digest(ignore func(xml.Name) bool) [sha256.Size]byte {
+	names := make([]xml.Name, 0, len(p.Properties))
+	for name := range p.Properties {
+		if ignore == nil || !ignore(name) {
+			names = append(names, name)
+		}
+	}
+	sortNames(names)
+
+	h := sha256.New()
+	if p.About != nil {
+		writeDigestString(h, normalizedURL(p.About))
+	} else {
+		writeDigestString(h, "")
+	}
+	for _, name := range names {
+		writeDigestName(h, name)
+		writeDigestRaw(h, p.Properties[name])
+	}
+	var res [sha256.Size]byte
+	h.Sum(res[:0])
+	return res
+}
+
+func writeDigestRaw(h hash.Hash, val Raw) {
+	switch val := val.(type) {
+	case Text:
+		h.Write([]byte{'T'})
+		writeDigestString(h, val.V)
+		writeDigestQ(h, val.Q)
+	case URL:
+		h.Write([]byte{'U'})
+		writeDigestString(h, normalizedURL(val.V))
+		writeDigestQ(h, val.Q)
+	case RawStruct:
+		h.Write([]byte{'S'})
+		names := val.sortedFieldNames()
+		writeDigestInt(h, len(names))
+		for _, name := range names {
+			writeDigestName(h, name)
+			writeDigestRaw(h, val.Value[name])
+		}
+		writeDigestQ(h, val.Q)
+	case RawArray:
+		h.Write([]byte{'A', byte(val.Kind)})
+		writeDigestInt(h, len(val.Value))
+		for _, elem := range val.Value {
+			writeDigestRaw(h, elem)
+		}
+		writeDigestQ(h, val.Q)
+	default:
+		h.Write([]byte{'0'})
+	}
+}
+
+// writeDigestQ hashes a list of qualifiers.  Since the order of qualifiers
+// is not significant, the qualifiers are hashed individually and the
+// hashes are sorted.
+func writeDigestQ(h hash.Hash, q Q) {
+	sums := make([]string, len(q))
+	for i, qi := range q {
+		hq := sha256.New()
+		writeDigestName(hq, qi.Name)
+		val := qi.Value
+		if txt, ok := val.(Text); ok && qi.Name == nameXMLLang {
+			val = Text{V: strings.ToLower(txt.V), Q: txt.Q}
+		}
+		writeDigestRaw(hq, val)
+		sums[i] = string(hq.Sum(nil))
+	}
+	sort.Strings(sums)
+	writeDigestInt(h, len(sums))
+	for _, s := range sums {
+		h.Write([]byte(s))
+	}
+}
+
+func writeDigestName(h hash.Hash, name xml.Name) {
+	writeDigestString(h, name.Space)
+	writeDigestString(h, name.Local)
+}
+
+func writeDigestString(h hash.Hash, s string) {
+	writeDigestInt(h, len(s))
+	h.Write([]byte(s))
+}
+
+func writeDigestInt(h hash.Hash, n int) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(n))
+	h.Write(buf[:])
+}
+
+// sortNames sorts a list of XML names by namespace and local name.
+func sortNames(names []xml.Name) {
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Space != names[j].Space {
+			return names[i].Space < names[j].Space
+		}
+		return names[i].Local < names[j].Local
+	})
+}
@@ -0,0 +1,79 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmp
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestVolatile(t *testing.T) {
+	makePacket := func(instance string, date time.Time) *Packet {
+		p := NewPacket()
+		p.SetValue(basicNamespace, "MetadataDate", NewDate(date))
+		p.SetValue(mmNamespace, "InstanceID", NewText(instance))
+		p.SetValue(mmNamespace, "DocumentID", NewText("xmp.did:1"))
+		p.Properties[elemTest] = Text{V: "x", Q: Q{
+			{Name: nameXMLLang, Value: Text{V: "en-GB"}},
+			{Name: elemTestQ, Value: Text{V: "q"}},
+		}}
+		return p
+	}
+	a := makePacket("xmp.iid:1", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := makePacket("xmp.iid:2", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	// qualifier order and the case of language tags are not significant
+	b.Properties[elemTest] = Text{V: "x", Q: Q{
+		{Name: elemTestQ, Value: Text{V: "q"}},
+		{Name: nameXMLLang, Value: Text{V: "EN-gb"}},
+	}}
+
+	if a.Equal(b) {
+		t.Error("packets with different volatile properties are equal")
+	}
+	if !a.EqualExcept(b, IsVolatile) {
+		t.Error("packets differ in non-volatile properties")
+	}
+	if changes := DiffExcept(a, b, IsVolatile); len(changes) != 0 {
+		t.Errorf("unexpected changes %v", changes)
+	}
+	if a.Digest(nil) == b.Digest(nil) {
+		t.Error("digests agree")
+	}
+	if a.Digest(IsVolatile) != b.Digest(IsVolatile) {
+		t.Error("digests differ")
+	}
+
+	b.SetValue(mmNamespace, "DocumentID", NewText("xmp.did:2"))
+	if a.Digest(IsVolatile) == b.Digest(IsVolatile) {
+		t.Error("digests agree after change")
+	}
+
+	name := xml.Name{Space: mmNamespace, Local: "DocumentID"}
+	if IsVolatile(name) {
+		t.Fatal("DocumentID is volatile")
+	}
+	MarkVolatile(name.Space, name.Local)
+	defer func() {
+		volatileMu.Lock()
+		delete(volatileNames, name)
+		volatileMu.Unlock()
+	}()
+	if !a.EqualExcept(b, IsVolatile) {
+		t.Error("MarkVolatile had no effect")
+	}
+}
@@ -0,0 +1,16 @@
+module seehuhn.de/go/xmp/watch
+
+go 1.23
+
+replace seehuhn.de/go/xmp => ../
+
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	seehuhn.de/go/xmp v0.8.0
+)
+
+require (
+	golang.org/x/exp v0.0.0-20240409090435-93d18d7e34b8 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+)
@@ -0,0 +1,303 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package watch monitors a directory tree for changes to XMP metadata.
+//
+// Both .xmp sidecar files and image files with embedded XMP packets are
+// monitored.  Whenever such a file changes, it is re-read and an [Event]
+// describing the changed properties is emitted.
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"seehuhn.de/go/xmp"
+)
+
+// Extensions lists the file name extensions of the files which are
+// monitored.  Extensions are compared case-insensitively.
+var Extensions = []string{
+	".xmp", ".jpg", ".jpeg", ".tif", ".tiff", ".png", ".dng", ".psd",
+}
+
+// RetryDelay is the time to wait before a file which could not be read is
+// read again.  Files are often observed while they are still being
+// written, so that an empty or truncated file is seen.  Such reads are
+// ignored and the file is re-read after RetryDelay.  After MaxRetries
+// failed attempts, an [Event] with the error is sent.
+var (
+	RetryDelay = 100 * time.Millisecond
+	MaxRetries = 5
+)
+
+// An Event reports a change to the metadata of a file.
+type Event struct {
+	// Path is the name of the changed file.
+	Path string
+
+	// Packet is the new metadata of the file.  This is nil if the file
+	// was removed or could not be read.
+	Packet *xmp.Packet
+
+	// Changes lists the properties which differ from the previous version
+	// of the file.
+	Changes []xmp.Change
+
+	// Err is set if the file could not be read.
+	Err error
+}
+
+// A Watcher monitors a directory tree.
+type Watcher struct {
+	// Events receives an event for every file where the metadata changed.
+	// The channel is closed when the watcher is closed.
+	Events <-chan Event
+
+	events  chan Event
+	fsw     *fsnotify.Watcher
+	packets map[string]*xmp.Packet
+	retries map[string]retryState
+	retryC  chan string
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// retryState records the failed attempts to read a file.
+type retryState struct {
+	attempts int
+	pending  bool
+}
+
+// New starts watching the directory tree rooted at dir.  All monitored files
+// which exist at this time are read, but no events are reported for them.
+func New(dir string) (*Watcher, error) {
+	return NewContext(context.Background(), dir)
+}
+
+// NewContext is like [New], but the initial scan of the directory tree is
+// aborted with an error if the context is cancelled or its deadline
+// expires.  Once NewContext has returned, the context has no effect.
+func NewContext(ctx context.Context, dir string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan Event, 16)
+	w := &Watcher{
+		Events:  events,
+		events:  events,
+		fsw:     fsw,
+		packets: make(map[string]*xmp.Packet),
+		retries: make(map[string]retryState),
+		retryC:  make(chan string),
+		done:    make(chan struct{}),
+	}
+
+	err = w.addTree(ctx, dir, false)
+	if err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// Close stops the watcher.  Calling Close more than once has no further
+// effect and returns the result of the first call.
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.closeErr = w.fsw.Close()
+		w.wg.Wait()
+		close(w.events)
+	})
+	return w.closeErr
+}
+
+func (w *Watcher) run() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.done:
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handle(ev)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.send(Event{Err: err})
+		case fname := <-w.retryC:
+			r := w.retries[fname]
+			r.pending = false
+			w.retries[fname] = r
+			w.update(fname, w.packets[fname], true)
+		}
+	}
+}
+
+func (w *Watcher) handle(ev fsnotify.Event) {
+	if ev.Has(fsnotify.Create) {
+		info, err := os.Stat(ev.Name)
+		if err == nil && info.IsDir() {
+			err = w.addTree(context.Background(), ev.Name, true)
+			if err != nil {
+				w.send(Event{Path: ev.Name, Err: err})
+			}
+			return
+		}
+	}
+	if !isMonitored(ev.Name) {
+		return
+	}
+
+	old := w.packets[ev.Name]
+	if ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) {
+		delete(w.packets, ev.Name)
+		delete(w.retries, ev.Name)
+		if old != nil {
+			w.send(Event{Path: ev.Name, Changes: xmp.Diff(old, nil)})
+		}
+		return
+	}
+	if !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Write) {
+		return
+	}
+	w.update(ev.Name, old, true)
+}
+
+// update re-reads a file and optionally reports the changes.
+func (w *Watcher) update(fname string, old *xmp.Packet, report bool) {
+	p, err := readPacket(fname)
+	if err != nil {
+		if report {
+			w.retry(fname, err)
+		}
+		return
+	}
+	delete(w.retries, fname)
+	w.packets[fname] = p
+	if !report {
+		return
+	}
+	changes := xmp.Diff(old, p)
+	if len(changes) > 0 {
+		w.send(Event{Path: fname, Packet: p, Changes: changes})
+	}
+}
+
+// retry schedules another attempt to read a file which could not be read.
+// The previous metadata of the file is kept until then.  After MaxRetries
+// failed attempts, the error is reported.
+func (w *Watcher) retry(fname string, err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		// The removal of the file is reported separately.
+		delete(w.retries, fname)
+		return
+	}
+	r := w.retries[fname]
+	if r.pending {
+		return
+	}
+	if r.attempts >= MaxRetries {
+		delete(w.retries, fname)
+		w.send(Event{Path: fname, Err: err})
+		return
+	}
+	w.retries[fname] = retryState{attempts: r.attempts + 1, pending: true}
+	time.AfterFunc(RetryDelay, func() {
+		select {
+		case w.retryC <- fname:
+		case <-w.done:
+		}
+	})
+}
+
+// addTree adds all directories below root to the watch list and reads all
+// monitored files.
+func (w *Watcher) addTree(ctx context.Context, root string, report bool) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return w.fsw.Add(path)
+		}
+		if isMonitored(path) {
+			w.update(path, nil, report)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) send(ev Event) {
+	select {
+	case w.events <- ev:
+	case <-w.done:
+	}
+}
+
+func isMonitored(fname string) bool {
+	ext := filepath.Ext(fname)
+	for _, e := range Extensions {
+		if strings.EqualFold(ext, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// readPacket reads the XMP data from a file.  Sidecar files are parsed
+// directly, other files are scanned for an embedded XMP packet.  For files
+// without an embedded packet, an empty packet is returned.
+func readPacket(fname string) (*xmp.Packet, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errEmpty
+	}
+	if !strings.EqualFold(filepath.Ext(fname), ".xmp") {
+		p, err := xmp.ExtractPacket(data)
+		if errors.Is(err, xmp.ErrNoPacket) {
+			return xmp.NewPacket(), nil
+		}
+		return p, err
+	}
+	return xmp.ReadBytes(data)
+}
+
+var errEmpty = errors.New("file is empty")
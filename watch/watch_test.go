@@ -0,0 +1,191 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package watch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"seehuhn.de/go/xmp"
+)
+
+func writePacket(t *testing.T, fname string, title string) {
+	t.Helper()
+	p := xmp.NewPacket()
+	p.SetValue("http://purl.org/dc/elements/1.1/", "source", xmp.NewText(title))
+	buf := &bytes.Buffer{}
+	err := p.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(fname, buf.Bytes(), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "image.xmp")
+	writePacket(t, fname, "a")
+
+	w, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	writePacket(t, fname, "b")
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-w.Events:
+			if ev.Err != nil {
+				t.Fatalf("unexpected error %v", ev.Err)
+			}
+			if ev.Path != fname {
+				t.Fatalf("unexpected event for %q", ev.Path)
+			}
+			if len(ev.Changes) != 1 {
+				t.Fatalf("expected 1 change, got %d", len(ev.Changes))
+			}
+			if txt, ok := ev.Changes[0].New.(xmp.Text); !ok || txt.V != "b" {
+				t.Fatalf("unexpected change %v", ev.Changes[0])
+			}
+			return
+		case <-timeout:
+			t.Fatal("timeout waiting for event")
+		}
+	}
+}
+
+func TestWatcherIncomplete(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "image.xmp")
+	writePacket(t, fname, "a")
+
+	w, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// An empty file does not report the properties as removed.
+	err = os.WriteFile(fname, nil, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(RetryDelay / 2)
+	writePacket(t, fname, "b")
+
+	timeout := time.After(5 * time.Second)
+	select {
+	case ev := <-w.Events:
+		if ev.Err != nil {
+			t.Fatalf("unexpected error %v", ev.Err)
+		}
+		if len(ev.Changes) != 1 {
+			t.Fatalf("expected 1 change, got %v", ev.Changes)
+		}
+		if txt, ok := ev.Changes[0].New.(xmp.Text); !ok || txt.V != "b" {
+			t.Fatalf("unexpected change %v", ev.Changes[0])
+		}
+	case <-timeout:
+		t.Fatal("timeout waiting for event")
+	}
+
+	// A file which stays unreadable is reported as an error.
+	err = os.WriteFile(fname, []byte("<?xpacket begin"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-w.Events:
+		if ev.Err == nil || len(ev.Changes) > 0 {
+			t.Fatalf("expected an error, got %v", ev)
+		}
+	case <-timeout:
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+// TestWatcherNoPacket checks that image files without an XMP packet are
+// treated as having empty metadata, rather than being reported as errors.
+func TestWatcherNoPacket(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "image.jpg")
+
+	w, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	err = os.WriteFile(fname, []byte("image data without metadata"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(RetryDelay * time.Duration(MaxRetries+2))
+
+	p := xmp.NewPacket()
+	p.SetValue("http://purl.org/dc/elements/1.1/", "source", xmp.NewText("a"))
+	buf := &bytes.Buffer{}
+	buf.WriteString("image data ")
+	err = p.Write(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(fname, buf.Bytes(), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	select {
+	case ev := <-w.Events:
+		if ev.Err != nil {
+			t.Fatalf("unexpected error %v", ev.Err)
+		}
+		if len(ev.Changes) != 1 {
+			t.Fatalf("expected 1 change, got %v", ev.Changes)
+		}
+	case <-timeout:
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+func TestWatcherCloseTwice(t *testing.T) {
+	w, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-w.Events; ok {
+		t.Error("events channel not closed")
+	}
+}
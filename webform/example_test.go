@@ -0,0 +1,50 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webform_test
+
+import (
+	"log"
+	"net/http"
+
+	"seehuhn.de/go/xmp"
+	"seehuhn.de/go/xmp/webform"
+)
+
+// This example serves an editor for the packets in a directory store.
+// The packet to edit is selected using the "key" query parameter.
+func ExampleEditor() {
+	store, err := xmp.NewDirStore("metadata")
+	if err != nil {
+		log.Fatal(err)
+	}
+	c := xmp.NewCollection(store)
+
+	editor := &webform.Editor{
+		Load: func(r *http.Request) (*xmp.Packet, error) {
+			p, err := c.Get(r.URL.Query().Get("key"))
+			if err == xmp.ErrNotFound {
+				return xmp.NewPacket(), nil
+			}
+			return p, err
+		},
+		Save: func(r *http.Request, p *xmp.Packet) error {
+			return c.Put(r.URL.Query().Get("key"), p)
+		},
+	}
+	http.Handle("/edit", editor)
+	log.Fatal(http.ListenAndServe("localhost:8080", nil))
+}
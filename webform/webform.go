@@ -0,0 +1,391 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package webform implements a simple web-based editor for XMP metadata.
+//
+// The [Editor] type is an [http.Handler] which renders an HTML form for all
+// properties described by a set of [xmp.Schema] values, and which applies
+// submitted changes to an XMP packet.  Form fields are named using the
+// string form of an [xmp.Path], and submitted values are validated by
+// decoding them into the Go type used by the corresponding model.
+package webform
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"seehuhn.de/go/xmp"
+)
+
+// Editor is an [http.Handler] for editing XMP packets.
+//
+// A GET request renders the edit form for the packet returned by Load.
+// A POST request applies the submitted changes to this packet and, if all
+// values are valid, stores the packet using Save and redirects back to the
+// form.  If some values are invalid, the form is shown again with error
+// messages and no changes are saved.
+//
+// To protect against cross-site request forgery, the form includes a
+// random token which is also stored in a cookie.  POST requests where the
+// token is missing or does not match the cookie are rejected.
+type Editor struct {
+	// Schemas lists the namespaces which are shown in the form.
+	// If this is nil, all registered schemas are used.
+	Schemas []*xmp.Schema
+
+	// Load returns the packet to edit.
+	Load func(r *http.Request) (*xmp.Packet, error)
+
+	// Save stores the modified packet.
+	Save func(r *http.Request, p *xmp.Packet) error
+}
+
+// ServeHTTP implements the [http.Handler] interface.
+func (e *Editor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p, err := e.Load(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		token, err := csrfToken(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		e.render(w, p, token, nil, nil, http.StatusOK)
+	case http.MethodPost:
+		err := r.ParseForm()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		token := r.PostForm.Get(csrfField)
+		if !checkCSRF(r, token) {
+			http.Error(w, "invalid form token", http.StatusForbidden)
+			return
+		}
+		problems := e.apply(p, r.PostForm)
+		if len(problems) > 0 {
+			e.render(w, p, token, r.PostForm, problems, http.StatusBadRequest)
+			return
+		}
+		err = e.Save(r, p)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, r.URL.String(), http.StatusSeeOther)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+const (
+	csrfCookie = "xmp-csrf"
+	csrfField  = "_csrf"
+)
+
+// csrfToken returns the token for the CSRF cookie of the request.  If the
+// request has no such cookie, a new random token is generated and the
+// cookie is set in the response.
+func csrfToken(w http.ResponseWriter, r *http.Request) (string, error) {
+	if c, err := r.Cookie(csrfCookie); err == nil && c.Value != "" {
+		return c.Value, nil
+	}
+	buf := make([]byte, 16)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return token, nil
+}
+
+// checkCSRF reports whether the submitted token matches the CSRF cookie.
+func checkCSRF(r *http.Request, token string) bool {
+	c, err := r.Cookie(csrfCookie)
+	if err != nil || c.Value == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.Value), []byte(token)) == 1
+}
+
+func (e *Editor) schemas() []*xmp.Schema {
+	if e.Schemas != nil {
+		return e.Schemas
+	}
+	return xmp.Schemas()
+}
+
+// apply updates the packet using the submitted form values.  The returned
+// map lists error messages for invalid fields, indexed by path.
+func (e *Editor) apply(p *xmp.Packet, form map[string][]string) map[string]string {
+	type update struct {
+		path xmp.Path
+		val  xmp.Value
+	}
+	var updates []update
+	problems := make(map[string]string)
+
+	for _, s := range e.schemas() {
+		for _, info := range s.Properties {
+			path := propertyPath(s, info)
+			key := path.String()
+			values, ok := form[key]
+			if !ok || !isEditable(info) {
+				continue
+			}
+			text := strings.TrimSpace(strings.ReplaceAll(values[0], "\r\n", "\n"))
+			if text == currentValue(p, path, info) {
+				continue
+			}
+			if text == "" && info.ValueType != "Lang Alt" {
+				updates = append(updates, update{path: path})
+				continue
+			}
+			val, err := parseValue(p, path, info, text)
+			if err != nil {
+				problems[key] = err.Error()
+				continue
+			}
+			if val.IsZero() {
+				updates = append(updates, update{path: path})
+				continue
+			}
+			updates = append(updates, update{path: path, val: val})
+		}
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	for _, u := range updates {
+		name := u.path[0].Name
+		if u.val == nil {
+			p.ClearValue(name.Space, name.Local)
+		} else {
+			p.SetValue(name.Space, name.Local, u.val)
+		}
+	}
+	return nil
+}
+
+// parseValue converts the submitted text for a property into a value of
+// the Go type used for the property.  For language alternatives, the text
+// replaces the default value and the other translations are kept.  An empty
+// text removes the default value.
+func parseValue(p *xmp.Packet, path xmp.Path, info xmp.PropertyInfo, text string) (xmp.Value, error) {
+	dec, ok := xmp.DecoderFor(info.Type)
+	if !ok {
+		return nil, errors.New("unsupported property type")
+	}
+
+	if info.ValueType == "Lang Alt" {
+		var l xmp.Localized
+		if raw, ok := p.Properties[path[0].Name]; ok {
+			if old, err := dec.DecodeAnother(raw); err == nil {
+				l = old.(xmp.Localized)
+			}
+		}
+		if text == "" {
+			l.Default = xmp.Text{}
+		} else {
+			l.Default = xmp.NewText(text)
+		}
+		return l, nil
+	}
+
+	var raw xmp.Raw
+	if info.IsArray {
+		a := xmp.RawArray{Kind: info.ArrayKind}
+		for _, line := range strings.Split(text, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				a.Value = append(a.Value, xmp.Text{V: line})
+			}
+		}
+		raw = a
+	} else {
+		raw = xmp.Text{V: text}
+	}
+
+	val, err := dec.DecodeAnother(raw)
+	if err != nil {
+		if info.Constraint != "" {
+			return nil, fmt.Errorf("invalid value, expected %s", info.Constraint)
+		}
+		return nil, errors.New("invalid value")
+	}
+	return val, nil
+}
+
+// isEditable reports whether a property can be edited using a single text
+// field.  Structures and arrays of structures are not supported.
+func isEditable(info xmp.PropertyInfo) bool {
+	elem := info.Type
+	if info.IsArray && info.ValueType != "Lang Alt" {
+		f, ok := info.Type.FieldByName("V")
+		if !ok {
+			return false
+		}
+		elem = f.Type.Elem()
+	}
+	if info.ValueType == "Lang Alt" {
+		return true
+	}
+	dec, ok := xmp.DecoderFor(elem)
+	if !ok {
+		return false
+	}
+	_, isText := dec.EncodeXMP(xmp.NewPacket()).(xmp.Text)
+	return isText
+}
+
+// currentValue returns the text shown in the form for a property.
+func currentValue(p *xmp.Packet, path xmp.Path, info xmp.PropertyInfo) string {
+	vals := p.Select(path)
+	if len(vals) == 0 {
+		return ""
+	}
+	if info.ValueType == "Lang Alt" {
+		// Select returns the individual entries of the language
+		// alternative.  Show the default entry, or the first entry if
+		// there is no default.
+		for _, v := range vals {
+			if t, ok := v.(xmp.Text); ok {
+				if tag, _ := t.Q.StripLanguage(); tag.String() == "x-default" {
+					return t.V
+				}
+			}
+		}
+		t, _ := vals[0].(xmp.Text)
+		return t.V
+	}
+
+	var lines []string
+	for _, v := range vals {
+		switch v := v.(type) {
+		case xmp.Text:
+			lines = append(lines, v.V)
+		case xmp.URL:
+			lines = append(lines, v.V.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func propertyPath(s *xmp.Schema, info xmp.PropertyInfo) xmp.Path {
+	return xmp.Path{{Name: xml.Name{Space: s.Namespace, Local: info.Name}}}
+}
+
+type formField struct {
+	Path       string
+	Label      string
+	Value      string
+	Multiline  bool
+	Constraint string
+	Error      string
+}
+
+type formSection struct {
+	Namespace string
+	Prefix    string
+	Fields    []formField
+}
+
+type formData struct {
+	Token    string
+	Sections []formSection
+}
+
+// render shows the edit form.  If form is non-nil, the submitted values
+// are shown instead of the values from the packet, so that rejected input
+// can be corrected.
+func (e *Editor) render(w http.ResponseWriter, p *xmp.Packet, token string, form map[string][]string, problems map[string]string, status int) {
+	var sections []formSection
+	for _, s := range e.schemas() {
+		sec := formSection{Namespace: s.Namespace, Prefix: s.Prefix}
+		for _, info := range s.Properties {
+			if !isEditable(info) {
+				continue
+			}
+			path := propertyPath(s, info)
+			key := path.String()
+			f := formField{
+				Path:       key,
+				Label:      key,
+				Value:      currentValue(p, path, info),
+				Multiline:  info.IsArray && info.ValueType != "Lang Alt",
+				Constraint: info.Constraint,
+				Error:      problems[key],
+			}
+			if vals, ok := form[key]; ok {
+				f.Value = vals[0]
+			}
+			sec.Fields = append(sec.Fields, f)
+		}
+		sort.Slice(sec.Fields, func(i, j int) bool {
+			return sec.Fields[i].Label < sec.Fields[j].Label
+		})
+		if len(sec.Fields) > 0 {
+			sections = append(sections, sec)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	formTemplate.Execute(w, formData{Token: token, Sections: sections})
+}
+
+var formTemplate = template.Must(template.New("form").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>XMP Metadata</title></head>
+<body>
+<form method="post">
+<input type="hidden" name="_csrf" value="{{.Token}}">
+{{range .Sections}}<fieldset>
+<legend>{{.Prefix}} ({{.Namespace}})</legend>
+{{range .Fields}}<p>
+<label for="{{.Path}}">{{.Label}}</label><br>
+{{if .Multiline}}<textarea id="{{.Path}}" name="{{.Path}}" rows="3">{{.Value}}</textarea>
+{{else}}<input id="{{.Path}}" name="{{.Path}}" value="{{.Value}}"{{if .Constraint}} title="{{.Constraint}}"{{end}}>
+{{end}}{{if .Error}}<br><strong class="error">{{.Error}}</strong>
+{{end}}</p>
+{{end}}</fieldset>
+{{end}}<p><button type="submit">Save</button></p>
+</form>
+</body>
+</html>
+`))
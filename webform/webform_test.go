@@ -0,0 +1,154 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package webform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	"seehuhn.de/go/xmp"
+)
+
+func TestEditor(t *testing.T) {
+	const dcNS = "http://purl.org/dc/elements/1.1/"
+
+	p := xmp.NewPacket()
+	title := xmp.Localized{Default: xmp.NewText("Old title")}
+	title.Set(language.German, "Alter Titel")
+	p.SetValue(dcNS, "title", title)
+
+	var saved *xmp.Packet
+	e := &Editor{
+		Schemas: []*xmp.Schema{xmp.LookupSchema(dcNS), xmp.LookupSchema("http://ns.adobe.com/xap/1.0/")},
+		Load:    func(*http.Request) (*xmp.Packet, error) { return p, nil },
+		Save:    func(_ *http.Request, q *xmp.Packet) error { saved = q; return nil },
+	}
+
+	// The form shows the current values.
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/edit", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET: status %d", rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected one cookie, got %d", len(cookies))
+	}
+	token := cookies[0].Value
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/edit", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(cookies[0])
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `name="_csrf" value="`+token+`"`) {
+		t.Errorf("form does not contain the CSRF token:\n%s", body)
+	}
+	for _, want := range []string{`name="dc:title" value="Old title"`, `name="dc:subject"`, `name="xmp:Rating"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("form does not contain %q:\n%s", want, body)
+		}
+	}
+
+	// Requests without a valid token are rejected.
+	form := url.Values{"dc:title": {"New title"}}
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/edit", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("POST without token: status %d", rec.Code)
+	}
+	form.Set("_csrf", "wrong")
+	if rec := post(form); rec.Code != http.StatusForbidden {
+		t.Fatalf("POST with wrong token: status %d", rec.Code)
+	}
+
+	// Invalid values are rejected, and the submitted input is shown again.
+	form = url.Values{
+		"_csrf":      {token},
+		"dc:title":   {"New title"},
+		"xmp:Rating": {"five"},
+	}
+	rec = post(form)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("POST invalid: status %d", rec.Code)
+	}
+	body = rec.Body.String()
+	if !strings.Contains(body, "invalid value, expected decimal number") {
+		t.Error("missing error message")
+	}
+	for _, want := range []string{`name="dc:title" value="New title"`, `name="xmp:Rating" value="five"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("form does not contain %q:\n%s", want, body)
+		}
+	}
+	if saved != nil {
+		t.Fatal("invalid data was saved")
+	}
+
+	// Valid values are applied.
+	form = url.Values{
+		"_csrf":      {token},
+		"dc:title":   {"New title"},
+		"dc:subject": {"boats\r\nharbour\r\n"},
+		"xmp:Rating": {"4"},
+	}
+	rec = post(form)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("POST: status %d", rec.Code)
+	}
+	if saved == nil {
+		t.Fatal("packet not saved")
+	}
+
+	gotTitle, err := xmp.PacketGetValue[xmp.Localized](saved, dcNS, "title")
+	if err != nil || gotTitle.Default.V != "New title" {
+		t.Errorf("wrong title %q, %v", gotTitle.Default.V, err)
+	}
+	if gotTitle.V[language.German].V != "Alter Titel" {
+		t.Errorf("translation lost: %v", gotTitle)
+	}
+	subject, err := xmp.PacketGetValue[xmp.UnorderedArray[xmp.Text]](saved, dcNS, "subject")
+	if err != nil || len(subject.V) != 2 || subject.V[1].V != "harbour" {
+		t.Errorf("wrong subject %v, %v", subject, err)
+	}
+	rating, ok := saved.Rating()
+	if !ok || rating != 4 {
+		t.Errorf("wrong rating %g", rating)
+	}
+
+	// An empty default keeps the translations.
+	p = saved
+	saved = nil
+	rec = post(url.Values{"_csrf": {token}, "dc:title": {""}})
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("POST empty title: status %d", rec.Code)
+	}
+	gotTitle, err = xmp.PacketGetValue[xmp.Localized](saved, dcNS, "title")
+	if err != nil || gotTitle.Default.V != "" || gotTitle.V[language.German].V != "Alter Titel" {
+		t.Errorf("wrong title %v, %v", gotTitle, err)
+	}
+}
@@ -19,8 +19,10 @@ package xmp
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"net/url"
-	"sort"
+	"reflect"
+	"time"
 
 	"golang.org/x/exp/maps"
 	"golang.org/x/text/language"
@@ -38,7 +40,39 @@ type Packet struct {
 	// About (optional) is the URL of the resource described by the XMP packet.
 	About *url.URL
 
+	// Now (optional) returns the current time.  This is used whenever a time
+	// stamp is stored in the packet, for example by
+	// [Packet.UpdateMetadataDate].  If Now is nil, [time.Now] is used.
+	Now func() time.Time
+
+	// NewID (optional) returns a new, unique identifier.  This is used
+	// whenever a new ID is generated, for example by [Packet.NewGUID].
+	// If NewID is nil, random UUIDs are used.
+	NewID func() string
+
+	// Journal (optional) records changes made through [Packet.SetValue] and
+	// [Packet.ClearValue].  If Journal is nil, changes are not recorded.
+	Journal *Journal
+
+	// LeadingComments and TrailingComments (optional) are XML comments
+	// which are written before and after the RDF data of the packet.
+	LeadingComments, TrailingComments []string
+
+	// PropertyComments (optional) maps property names to XML comments
+	// which are written immediately before the corresponding property.
+	// When a packet is decoded, comments inside a property value are
+	// included here, too.
+	PropertyComments map[xml.Name][]string
+
+	// ProcInsts (optional) are XML processing instructions which are
+	// written at the start of the packet, after the xpacket header.
+	// When a packet is decoded, all processing instructions other than
+	// the xpacket header and trailer are collected here.
+	ProcInsts []xml.ProcInst
+
 	nsToPrefix map[string]string
+
+	diagnostics []Diagnostic
 }
 
 // NewPacket allocates a new, empty XMP packet.
@@ -48,6 +82,29 @@ func NewPacket() *Packet {
 	}
 }
 
+// Reset removes all properties, the About URL, the registered prefixes,
+// the diagnostics, the journal, and the clock and ID sources from the packet.
+// After Reset, the packet is equivalent to a packet returned by [NewPacket],
+// but memory allocated for the property map is retained.  This allows to
+// re-use packets, e.g. via a [sync.Pool].
+func (p *Packet) Reset() {
+	if p.Properties == nil {
+		p.Properties = make(map[xml.Name]Raw)
+	} else {
+		clear(p.Properties)
+	}
+	p.About = nil
+	p.Now = nil
+	p.NewID = nil
+	p.Journal = nil
+	p.LeadingComments = nil
+	p.TrailingComments = nil
+	p.PropertyComments = nil
+	p.ProcInsts = nil
+	clear(p.nsToPrefix)
+	p.diagnostics = p.diagnostics[:0]
+}
+
 // RegisterPrefix registers a namespace prefix.
 func (p *Packet) RegisterPrefix(ns, prefix string) {
 	if p.nsToPrefix == nil {
@@ -56,6 +113,22 @@ func (p *Packet) RegisterPrefix(ns, prefix string) {
 	p.nsToPrefix[ns] = prefix
 }
 
+// registerDefaultPrefix registers the conventional prefix of a well-known
+// namespace, unless a prefix is already registered for ns.  This is used
+// for the namespaces of structure fields, which are not covered by the
+// Prefix tag of a model.
+func (p *Packet) registerDefaultPrefix(ns string) {
+	if p == nil {
+		return
+	}
+	if _, ok := p.nsToPrefix[ns]; ok {
+		return
+	}
+	if pfx, ok := defaultPrefix[ns]; ok {
+		p.RegisterPrefix(ns, pfx)
+	}
+}
+
 // SetValue stores the given value in the packet.
 func (p *Packet) SetValue(namespace, propertyName string, value Value) {
 	if !isValidPropertyName(xml.Name{Space: namespace, Local: propertyName}) {
@@ -65,12 +138,15 @@ func (p *Packet) SetValue(namespace, propertyName string, value Value) {
 	if !isValidPropertyName(name) {
 		panic("invalid property name")
 	}
-	p.Properties[name] = value.EncodeXMP(p)
+	raw := value.EncodeXMP(p)
+	p.record(name, raw)
+	p.Properties[name] = raw
 }
 
 // ClearValue removes the given property from the packet.
 func (p *Packet) ClearValue(namespace, propertyName string) {
 	name := xml.Name{Space: namespace, Local: propertyName}
+	p.record(name, nil)
 	delete(p.Properties, name)
 }
 
@@ -79,6 +155,10 @@ func (p *Packet) ClearValue(namespace, propertyName string) {
 // In case the value is not found, [ErrNotFound] is returned. If the value
 // exists but has the wrong format, [ErrInvalid] is returned.
 //
+// E can be a pointer type, in which case DecodeAnother is called on a
+// pointer to a new zero value.  If E is an interface type, the concrete
+// type of the value is not known and [ErrInvalid] is returned.
+//
 // Once Go supports methods with type parameters, this function can be turned
 // into a method on [Packet].
 func PacketGetValue[E Value](p *Packet, namespace, propertyName string) (E, error) {
@@ -88,11 +168,19 @@ func PacketGetValue[E Value](p *Packet, namespace, propertyName string) (E, erro
 	if !ok {
 		return zero, ErrNotFound
 	}
-	u, err := zero.DecodeAnother(xmpData)
+	dec, ok := DecoderFor(reflect.TypeFor[E]())
+	if !ok {
+		return zero, ErrInvalid
+	}
+	u, err := dec.DecodeAnother(xmpData)
 	if err != nil {
 		return zero, err
 	}
-	return u.(E), nil
+	res, ok := u.(E)
+	if !ok {
+		return zero, ErrInvalid
+	}
+	return res, nil
 }
 
 // Raw is one of [Text], [URL], [RawStruct], or [RawArray].  These are the
@@ -167,6 +255,19 @@ func (q Q) getLangAttr(attr []xml.Attr) []xml.Attr {
 	return attr
 }
 
+// lang returns the value of the xml:lang qualifier, or the empty string if
+// there is no language qualifier.
+func (q Q) lang() string {
+	for _, q := range q {
+		if q.Name == nameXMLLang {
+			if v, ok := q.Value.(Text); ok {
+				return v.V
+			}
+		}
+	}
+	return ""
+}
+
 // hasQualifiers returns true if there are any qualifiers other than xml:lang.
 func (q Q) hasQualifiers() bool {
 	for _, q := range q {
@@ -407,6 +508,14 @@ func (u URL) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 // RawStruct is an XMP structure.
 type RawStruct struct {
 	Value map[xml.Name]Raw
+
+	// Order (optional) gives the order of the fields.  When the structure
+	// is written, the fields listed in Order come first, in the given
+	// order, followed by the remaining fields sorted by name.  When a
+	// structure is read, Order is set to the order of the fields in the
+	// source.  Order is ignored when structures are compared.
+	Order []xml.Name
+
 	Q
 }
 
@@ -478,8 +587,9 @@ func (s RawStruct) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 	// </test:prop>
 
 	attr := s.Q.getLangAttr(nil)
+	lang := s.Q.lang()
 
-	fieldNames := s.fieldNames()
+	fieldNames := s.FieldNames()
 	if s.Q.hasQualifiers() { // use option 4
 		attr = append(attr, attrParseTypeResource)
 		tokens = append(tokens,
@@ -487,7 +597,7 @@ func (s RawStruct) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 			xml.StartElement{Name: nameRDFValue, Attr: []xml.Attr{attrParseTypeResource}},
 		)
 		for _, fieldName := range fieldNames {
-			tokens = s.Value[fieldName].appendXML(tokens, fieldName)
+			tokens = appendChildXML(tokens, s.Value[fieldName], fieldName, lang)
 		}
 		tokens = append(tokens, xml.EndElement{Name: nameRDFValue})
 		for _, q := range s.Q {
@@ -497,7 +607,7 @@ func (s RawStruct) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 			tokens = q.Value.appendXML(tokens, q.Name)
 		}
 		tokens = append(tokens, xml.EndElement{Name: name})
-	} else if s.allSimple() && len(s.Value) > 0 { // use option 1c
+	} else if s.allSimple() && len(s.Value) > 0 && lang == "" { // use option 1c
 		for _, fieldName := range fieldNames {
 			attr = append(attr, xml.Attr{
 				Name:  fieldName,
@@ -509,25 +619,55 @@ func (s RawStruct) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 		attr = append(attr, attrParseTypeResource)
 		tokens = append(tokens, xml.StartElement{Name: name, Attr: attr})
 		for _, fieldName := range fieldNames {
-			tokens = s.Value[fieldName].appendXML(tokens, fieldName)
+			tokens = appendChildXML(tokens, s.Value[fieldName], fieldName, lang)
 		}
 		tokens = append(tokens, xml.EndElement{Name: name})
 	}
 	return tokens
 }
 
-// fieldNames returns the field names sorted by namespace and local name.
-func (s *RawStruct) fieldNames() []xml.Name {
-	fieldNames := maps.Keys(s.Value)
-	sort.Slice(fieldNames, func(i, j int) bool {
-		if fieldNames[i].Space != fieldNames[j].Space {
-			return fieldNames[i].Space < fieldNames[j].Space
+// FieldNames returns the names of the fields of the structure, in the
+// order in which they are written.  See the Order field for details.
+func (s RawStruct) FieldNames() []xml.Name {
+	res := make([]xml.Name, 0, len(s.Value))
+	seen := make(map[xml.Name]bool, len(s.Value))
+	for _, name := range s.Order {
+		if _, ok := s.Value[name]; ok && !seen[name] {
+			res = append(res, name)
+			seen[name] = true
+		}
+	}
+	if len(res) == len(s.Value) {
+		return res
+	}
+
+	var rest []xml.Name
+	for name := range s.Value {
+		if !seen[name] {
+			rest = append(rest, name)
 		}
-		return fieldNames[i].Local < fieldNames[j].Local
-	})
+	}
+	sortNames(rest)
+	return append(res, rest...)
+}
+
+// sortedFieldNames returns the field names sorted by namespace and local
+// name, ignoring the Order field.
+func (s RawStruct) sortedFieldNames() []xml.Name {
+	fieldNames := maps.Keys(s.Value)
+	sortNames(fieldNames)
 	return fieldNames
 }
 
+// add sets the value of a field, appending the field name to Order if the
+// field is new.
+func (s *RawStruct) add(name xml.Name, val Raw) {
+	if _, exists := s.Value[name]; !exists {
+		s.Order = append(s.Order, name)
+	}
+	s.Value[name] = val
+}
+
 // allSimple returns true if all values are simple non-URI values, with no
 // qualifiers.
 func (s *RawStruct) allSimple() bool {
@@ -608,6 +748,7 @@ func (a RawArray) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 	// </test:prop>
 
 	attr := a.Q.getLangAttr(nil)
+	lang := a.Q.lang()
 
 	var envName xml.Name
 	switch a.Kind {
@@ -628,7 +769,7 @@ func (a RawArray) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 			xml.StartElement{Name: nameRDFValue},
 			xml.StartElement{Name: envName})
 		for _, v := range a.Value {
-			tokens = v.appendXML(tokens, nameRDFLi)
+			tokens = appendChildXML(tokens, v, nameRDFLi, lang)
 		}
 		tokens = append(tokens, xml.EndElement{Name: envName})
 		tokens = append(tokens, xml.EndElement{Name: nameRDFValue})
@@ -644,7 +785,7 @@ func (a RawArray) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 			xml.StartElement{Name: name, Attr: attr},
 			xml.StartElement{Name: envName})
 		for _, v := range a.Value {
-			tokens = v.appendXML(tokens, nameRDFLi)
+			tokens = appendChildXML(tokens, v, nameRDFLi, lang)
 		}
 		tokens = append(tokens,
 			xml.EndElement{Name: envName},
@@ -654,6 +795,42 @@ func (a RawArray) appendXML(tokens []xml.Token, name xml.Name) []xml.Token {
 	return tokens
 }
 
+// appendChildXML appends the XML representation of a struct field or array
+// item.  If the enclosing element has the language parentLang and the
+// child has no language of its own, an empty xml:lang attribute is added to
+// the child, so that the language is not inherited when the packet is read
+// back.
+func appendChildXML(tokens []xml.Token, v Raw, name xml.Name, parentLang string) []xml.Token {
+	start := len(tokens)
+	tokens = v.appendXML(tokens, name)
+	if parentLang == "" || start >= len(tokens) {
+		return tokens
+	}
+	switch t := tokens[start].(type) {
+	case xml.StartElement:
+		if !hasLangAttr(t.Attr) {
+			t.Attr = append([]xml.Attr{{Name: nameXMLLang}}, t.Attr...)
+			tokens[start] = t
+		}
+	case jvxml.EmptyElement:
+		if !hasLangAttr(t.Attr) {
+			t.Attr = append([]xml.Attr{{Name: nameXMLLang}}, t.Attr...)
+			tokens[start] = t
+		}
+	}
+	return tokens
+}
+
+// hasLangAttr checks whether the attributes include xml:lang.
+func hasLangAttr(attr []xml.Attr) bool {
+	for _, a := range attr {
+		if a.Name == nameXMLLang {
+			return true
+		}
+	}
+	return false
+}
+
 // RawArrayType represents the type of an XMP array (unordered, ordered, or
 // alternative).
 type RawArrayType int
@@ -665,6 +842,39 @@ const (
 	Alternative
 )
 
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (k RawArrayType) MarshalText() ([]byte, error) {
+	switch k {
+	case Unordered, Ordered, Alternative:
+		return []byte(k.String()), nil
+	}
+	return nil, fmt.Errorf("invalid array type %d", int(k))
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (k *RawArrayType) UnmarshalText(text []byte) error {
+	for _, kind := range []RawArrayType{Unordered, Ordered, Alternative} {
+		if string(text) == kind.String() {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid array type %q", text)
+}
+
+func (k RawArrayType) String() string {
+	switch k {
+	case Unordered:
+		return "Bag"
+	case Ordered:
+		return "Seq"
+	case Alternative:
+		return "Alt"
+	default:
+		return fmt.Sprintf("RawArrayType(%d)", int(k))
+	}
+}
+
 // ErrInvalid is returned by [PacketGetValue] when XMP data is present in the XML
 // file, but the data does not have the expected structure.
 var ErrInvalid = errors.New("invalid XMP data")
@@ -0,0 +1,228 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package xmparrow exports XMP metadata from many packets into columnar
+// tables in the Apache Arrow format.
+//
+// Each column of a table is described by a property path (see
+// [xmp.ParsePath]), and each packet contributes one row.  The resulting
+// record batches can be written as an Arrow IPC stream, which is read
+// directly by analytics tools like pandas, Polars or DuckDB.
+package xmparrow
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"seehuhn.de/go/xmp"
+)
+
+// DefaultBatchSize is the number of rows per record batch used by [Writer]
+// when no other size is given.
+const DefaultBatchSize = 1024
+
+// Column describes one column of an exported table.
+type Column struct {
+	// Name is the column name in the Arrow schema.
+	Name string
+
+	// Path selects the values for this column from each packet.
+	Path xmp.Path
+
+	// List indicates that the column holds all values selected by Path,
+	// as a list of strings.  If List is false, only the first selected
+	// value is used.
+	List bool
+}
+
+// ParseColumns returns one column for each of the given property paths.
+// The column names are the path strings.  A path ending in "[]" gives a
+// list column.
+func ParseColumns(paths ...string) ([]Column, error) {
+	cols := make([]Column, 0, len(paths))
+	for _, s := range paths {
+		col := Column{Name: s}
+		if len(s) > 2 && s[len(s)-2:] == "[]" {
+			s = s[:len(s)-2]
+			col.List = true
+		}
+		path, err := xmp.ParsePath(s)
+		if err != nil {
+			return nil, err
+		}
+		col.Path = path
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// Schema returns the Arrow schema for a table with the given columns.
+// All columns are nullable; missing values are represented as nulls.
+func Schema(cols []Column) *arrow.Schema {
+	fields := make([]arrow.Field, len(cols))
+	for i, col := range cols {
+		var tp arrow.DataType = arrow.BinaryTypes.String
+		if col.List {
+			tp = arrow.ListOf(tp)
+		}
+		fields[i] = arrow.Field{Name: col.Name, Type: tp, Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// Record builds a record batch with one row for each packet.
+// The caller must call Release on the result.
+func Record(mem memory.Allocator, cols []Column, packets []*xmp.Packet) arrow.RecordBatch {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+	b := array.NewRecordBuilder(mem, Schema(cols))
+	defer b.Release()
+	for _, p := range packets {
+		appendRow(b, cols, p)
+	}
+	return b.NewRecordBatch()
+}
+
+func appendRow(b *array.RecordBuilder, cols []Column, p *xmp.Packet) {
+	for i, col := range cols {
+		vals := p.Select(col.Path)
+		if !col.List {
+			sb := b.Field(i).(*array.StringBuilder)
+			if len(vals) == 0 {
+				sb.AppendNull()
+			} else {
+				sb.Append(valueString(vals[0]))
+			}
+			continue
+		}
+
+		lb := b.Field(i).(*array.ListBuilder)
+		if len(vals) == 0 {
+			lb.AppendNull()
+			continue
+		}
+		lb.Append(true)
+		sb := lb.ValueBuilder().(*array.StringBuilder)
+		for _, v := range vals {
+			sb.Append(valueString(v))
+		}
+	}
+}
+
+// valueString converts an XMP value to the string stored in a table cell.
+// Simple values are stored verbatim, structures and arrays use the compact
+// notation of the %+v format.
+func valueString(val xmp.Raw) string {
+	switch val := val.(type) {
+	case xmp.Text:
+		return val.V
+	case xmp.URL:
+		if val.V == nil {
+			return ""
+		}
+		return val.V.String()
+	default:
+		return fmt.Sprintf("%+v", val)
+	}
+}
+
+// Writer writes packets as rows of an Arrow IPC stream.
+// Rows are buffered and written in record batches.
+type Writer struct {
+	// BatchSize is the maximal number of rows per record batch.
+	// If this is zero, [DefaultBatchSize] is used.
+	BatchSize int
+
+	cols []Column
+	w    *ipc.Writer
+	b    *array.RecordBuilder
+	rows int
+}
+
+// NewWriter returns a new Writer which writes a table with the given
+// columns to w.  The caller must call Close to flush buffered rows and to
+// terminate the stream.
+func NewWriter(w io.Writer, cols []Column) *Writer {
+	schema := Schema(cols)
+	mem := memory.DefaultAllocator
+	return &Writer{
+		cols: cols,
+		w:    ipc.NewWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(mem)),
+		b:    array.NewRecordBuilder(mem, schema),
+	}
+}
+
+// Add appends one row to the table.
+func (w *Writer) Add(p *xmp.Packet) error {
+	appendRow(w.b, w.cols, p)
+	w.rows++
+
+	batchSize := w.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if w.rows >= batchSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// AddCollection appends one row for every packet in the collection.
+// Rows are added in the order of the collection keys.
+func (w *Writer) AddCollection(c *xmp.Collection) error {
+	keys, err := c.Keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		p, err := c.Get(key)
+		if err != nil {
+			return err
+		}
+		err = w.Add(p)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Writer) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	rec := w.b.NewRecordBatch()
+	defer rec.Release()
+	w.rows = 0
+	return w.w.Write(rec)
+}
+
+// Close writes all buffered rows and terminates the stream.
+// Close does not close the underlying writer.
+func (w *Writer) Close() error {
+	err := w.flush()
+	w.b.Release()
+	err2 := w.w.Close()
+	if err == nil {
+		err = err2
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmparrow
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/language"
+	"seehuhn.de/go/xmp"
+)
+
+func TestWriter(t *testing.T) {
+	var packets []*xmp.Packet
+	for i, title := range []string{"first", "", "third"} {
+		p := xmp.NewPacket()
+		dc := &xmp.DublinCore{}
+		if title != "" {
+			dc.Title.Set(language.Und, title)
+		}
+		for j := 0; j < i; j++ {
+			dc.Subject.Append(xmp.NewText(string(rune('a' + j))))
+		}
+		err := p.Set(dc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packets = append(packets, p)
+	}
+
+	cols, err := ParseColumns("dc:title", "dc:subject[]")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf, cols)
+	w.BatchSize = 2
+	for _, p := range packets {
+		err := w.Add(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ipc.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+
+	var titles []string
+	var subjects [][]string
+	batches := 0
+	for r.Next() {
+		batches++
+		rec := r.RecordBatch()
+		tc := rec.Column(0).(*array.String)
+		sc := rec.Column(1).(*array.List)
+		sv := sc.ListValues().(*array.String)
+		offsets := sc.Offsets()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			if tc.IsNull(i) {
+				titles = append(titles, "<null>")
+			} else {
+				titles = append(titles, tc.Value(i))
+			}
+			var row []string
+			if sc.IsValid(i) {
+				for k := offsets[i]; k < offsets[i+1]; k++ {
+					row = append(row, sv.Value(int(k)))
+				}
+			}
+			subjects = append(subjects, row)
+		}
+	}
+	if batches != 2 {
+		t.Errorf("got %d batches, want 2", batches)
+	}
+	if d := cmp.Diff([]string{"first", "<null>", "third"}, titles); d != "" {
+		t.Errorf("titles (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([][]string{nil, {"a"}, {"a", "b"}}, subjects); d != "" {
+		t.Errorf("subjects (-want +got):\n%s", d)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	p := xmp.NewPacket()
+	err := p.Set(&xmp.DublinCore{Format: xmp.MimeType{V: "image/jpeg"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cols, err := ParseColumns("dc:format", "dc:title")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := Record(nil, cols, []*xmp.Packet{p, xmp.NewPacket()})
+	defer rec.Release()
+
+	if rec.NumRows() != 2 || rec.NumCols() != 2 {
+		t.Fatalf("got %dx%d table, want 2x2", rec.NumRows(), rec.NumCols())
+	}
+	format := rec.Column(0).(*array.String)
+	if format.Value(0) != "image/jpeg" || !format.IsNull(1) {
+		t.Errorf("wrong format column %v", format)
+	}
+	if rec.Column(1).NullN() != 2 {
+		t.Errorf("expected only nulls in title column")
+	}
+}
+
+func TestValueStringNilURL(t *testing.T) {
+	if s := valueString(xmp.URL{}); s != "" {
+		t.Errorf("got %q, want empty string", s)
+	}
+}
@@ -0,0 +1,16 @@
+module seehuhn.de/go/xmp/xmppb
+
+go 1.22.2
+
+replace seehuhn.de/go/xmp => ../
+
+require (
+	github.com/google/go-cmp v0.6.0
+	google.golang.org/protobuf v1.34.2
+	seehuhn.de/go/xmp v0.8.0
+)
+
+require (
+	golang.org/x/exp v0.0.0-20240409090435-93d18d7e34b8 // indirect
+	golang.org/x/text v0.16.0 // indirect
+)
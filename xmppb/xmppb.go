@@ -0,0 +1,378 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package xmppb converts XMP packets to and from a protocol buffer
+// representation.
+//
+// This allows services to exchange XMP metadata without serializing to and
+// parsing RDF/XML at every step.  The message definitions are given in the
+// file xmp.proto in this directory; they can be used to generate code for
+// other languages.  The encoding produced by [Marshal] is deterministic.
+package xmppb
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"seehuhn.de/go/xmp"
+)
+
+// Field numbers, as defined in xmp.proto.
+const (
+	packetAbout      protowire.Number = 1
+	packetProperties protowire.Number = 2
+	packetPrefixes   protowire.Number = 3
+
+	mapKey   protowire.Number = 1
+	mapValue protowire.Number = 2
+
+	nameNamespace protowire.Number = 1
+	nameLocal     protowire.Number = 2
+
+	propertyName  protowire.Number = 1
+	propertyValue protowire.Number = 2
+
+	valueText       protowire.Number = 1
+	valueURI        protowire.Number = 2
+	valueStruct     protowire.Number = 3
+	valueArray      protowire.Number = 4
+	valueQualifiers protowire.Number = 5
+
+	structFields protowire.Number = 1
+
+	arrayKind  protowire.Number = 1
+	arrayItems protowire.Number = 2
+)
+
+// Marshal converts an XMP packet into the protocol buffer wire format.
+func Marshal(p *xmp.Packet) ([]byte, error) {
+	var b []byte
+	if p.About != nil {
+		b = appendString(b, packetAbout, p.About.String())
+	}
+	for _, name := range sortedNames(p.Properties) {
+		msg, err := appendProperty(nil, name, p.Properties[name])
+		if err != nil {
+			return nil, err
+		}
+		b = appendMessage(b, packetProperties, msg)
+	}
+	for _, ns := range p.Namespaces() {
+		if ns.Prefix == "" {
+			continue
+		}
+		var entry []byte
+		entry = appendString(entry, mapKey, ns.URI)
+		entry = appendString(entry, mapValue, ns.Prefix)
+		b = appendMessage(b, packetPrefixes, entry)
+	}
+	return b, nil
+}
+
+// Unmarshal converts data in the protocol buffer wire format into an XMP
+// packet.  Unknown fields are ignored.
+func Unmarshal(data []byte) (*xmp.Packet, error) {
+	p := xmp.NewPacket()
+	err := walkFields(data, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		switch num {
+		case packetAbout:
+			u, err := url.Parse(string(v))
+			if err != nil {
+				return err
+			}
+			p.About = u
+		case packetProperties:
+			name, val, err := parseProperty(v)
+			if err != nil {
+				return err
+			}
+			p.Properties[name] = val
+		case packetPrefixes:
+			var ns, prefix string
+			err := walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte) error {
+				switch num {
+				case mapKey:
+					ns = string(v)
+				case mapValue:
+					prefix = string(v)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			p.RegisterPrefix(ns, prefix)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func appendProperty(b []byte, name xml.Name, val xmp.Raw) ([]byte, error) {
+	var nameMsg []byte
+	nameMsg = appendString(nameMsg, nameNamespace, name.Space)
+	nameMsg = appendString(nameMsg, nameLocal, name.Local)
+	b = appendMessage(b, propertyName, nameMsg)
+
+	valMsg, err := appendValue(nil, val)
+	if err != nil {
+		return nil, err
+	}
+	return appendMessage(b, propertyValue, valMsg), nil
+}
+
+func appendValue(b []byte, val xmp.Raw) ([]byte, error) {
+	var q xmp.Q
+	switch val := val.(type) {
+	case xmp.Text:
+		b = appendString(b, valueText, val.V)
+		q = val.Q
+	case xmp.URL:
+		s := ""
+		if val.V != nil {
+			s = val.V.String()
+		}
+		b = appendString(b, valueURI, s)
+		q = val.Q
+	case xmp.RawStruct:
+		var msg []byte
+		for _, name := range val.FieldNames() {
+			field, err := appendProperty(nil, name, val.Value[name])
+			if err != nil {
+				return nil, err
+			}
+			msg = appendMessage(msg, structFields, field)
+		}
+		b = appendMessage(b, valueStruct, msg)
+		q = val.Q
+	case xmp.RawArray:
+		var msg []byte
+		if val.Kind != 0 {
+			msg = protowire.AppendTag(msg, arrayKind, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, uint64(val.Kind))
+		}
+		for _, item := range val.Value {
+			itemMsg, err := appendValue(nil, item)
+			if err != nil {
+				return nil, err
+			}
+			msg = appendMessage(msg, arrayItems, itemMsg)
+		}
+		b = appendMessage(b, valueArray, msg)
+		q = val.Q
+	default:
+		return nil, errInvalidValue
+	}
+
+	for _, qi := range q {
+		msg, err := appendProperty(nil, qi.Name, qi.Value)
+		if err != nil {
+			return nil, err
+		}
+		b = appendMessage(b, valueQualifiers, msg)
+	}
+	return b, nil
+}
+
+func parseProperty(data []byte) (xml.Name, xmp.Raw, error) {
+	var name xml.Name
+	var val xmp.Raw
+	err := walkFields(data, func(num protowire.Number, _ protowire.Type, v []byte) error {
+		switch num {
+		case propertyName:
+			return walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte) error {
+				switch num {
+				case nameNamespace:
+					name.Space = string(v)
+				case nameLocal:
+					name.Local = string(v)
+				}
+				return nil
+			})
+		case propertyValue:
+			var err error
+			val, err = parseValue(v)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return name, nil, err
+	}
+	if name.Local == "" || val == nil {
+		return name, nil, errInvalidValue
+	}
+	return name, val, nil
+}
+
+func parseValue(data []byte) (xmp.Raw, error) {
+	var val xmp.Raw
+	var q xmp.Q
+	err := walkFields(data, func(num protowire.Number, _ protowire.Type, v []byte) error {
+		switch num {
+		case valueText:
+			val = xmp.Text{V: string(v)}
+		case valueURI:
+			u, err := url.Parse(string(v))
+			if err != nil {
+				return err
+			}
+			val = xmp.URL{V: u}
+		case valueStruct:
+			s := xmp.RawStruct{Value: make(map[xml.Name]xmp.Raw)}
+			err := walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte) error {
+				if num != structFields {
+					return nil
+				}
+				name, field, err := parseProperty(v)
+				if err != nil {
+					return err
+				}
+				if _, exists := s.Value[name]; !exists {
+					s.Order = append(s.Order, name)
+				}
+				s.Value[name] = field
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			val = s
+		case valueArray:
+			var a xmp.RawArray
+			err := walkFields(v, func(num protowire.Number, typ protowire.Type, v []byte) error {
+				switch num {
+				case arrayKind:
+					k, n := protowire.ConsumeVarint(v)
+					if n < 0 {
+						return protowire.ParseError(n)
+					}
+					a.Kind = xmp.RawArrayType(k)
+				case arrayItems:
+					item, err := parseValue(v)
+					if err != nil {
+						return err
+					}
+					a.Value = append(a.Value, item)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if a.Kind < xmp.Unordered || a.Kind > xmp.Alternative {
+				return errInvalidValue
+			}
+			val = a
+		case valueQualifiers:
+			name, qv, err := parseProperty(v)
+			if err != nil {
+				return err
+			}
+			q = append(q, xmp.Qualifier{Name: name, Value: qv})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := val.(type) {
+	case xmp.Text:
+		v.Q = q
+		return v, nil
+	case xmp.URL:
+		v.Q = q
+		return v, nil
+	case xmp.RawStruct:
+		v.Q = q
+		return v, nil
+	case xmp.RawArray:
+		v.Q = q
+		return v, nil
+	}
+	return nil, errInvalidValue
+}
+
+// walkFields calls fn for every field in a protocol buffer message.  For
+// length-delimited fields, the argument v is the field content; for varint
+// fields, v is the encoded varint.
+func walkFields(data []byte, fn func(num protowire.Number, typ protowire.Type, v []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var v []byte
+		switch typ {
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			_, n = protowire.ConsumeVarint(data)
+			if n >= 0 {
+				v = data[:n]
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if v != nil || typ == protowire.BytesType {
+			err := fn(num, typ, v)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func sortedNames(m map[xml.Name]xmp.Raw) []xml.Name {
+	names := make([]xml.Name, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Space != names[j].Space {
+			return names[i].Space < names[j].Space
+		}
+		return names[i].Local < names[j].Local
+	})
+	return names
+}
+
+var errInvalidValue = errors.New("invalid XMP value")
@@ -0,0 +1,92 @@
+// seehuhn.de/go/xmp - Extensible Metadata Platform in Go
+// Copyright (C) 2024  Jochen Voss <voss@seehuhn.de>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package xmppb
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"seehuhn.de/go/xmp"
+)
+
+func TestRoundTrip(t *testing.T) {
+	const ns = "http://ns.seehuhn.de/test/#"
+	name := func(local string) xml.Name { return xml.Name{Space: ns, Local: local} }
+	lang := xml.Name{Space: "http://www.w3.org/XML/1998/namespace", Local: "lang"}
+	u := &url.URL{Scheme: "https", Host: "example.com", Path: "/a"}
+
+	p := xmp.NewPacket()
+	p.About = u
+	p.RegisterPrefix(ns, "test")
+	p.Properties[name("text")] = xmp.Text{V: "hello"}
+	p.Properties[name("empty")] = xmp.Text{V: ""}
+	p.Properties[name("url")] = xmp.URL{V: u, Q: xmp.Q{{Name: name("q"), Value: xmp.Text{V: "q"}}}}
+	p.Properties[name("struct")] = xmp.RawStruct{
+		Value: map[xml.Name]xmp.Raw{
+			name("a"): xmp.Text{V: "1"},
+			name("b"): xmp.RawArray{Kind: xmp.Ordered, Value: []xmp.Raw{xmp.Text{V: "x"}}},
+		},
+		Order: []xml.Name{name("b"), name("a")},
+	}
+	p.Properties[name("alt")] = xmp.RawArray{
+		Kind: xmp.Alternative,
+		Value: []xmp.Raw{
+			xmp.Text{V: "Hello", Q: xmp.Q{{Name: lang, Value: xmp.Text{V: "x-default"}}}},
+			xmp.Text{V: "Hallo", Q: xmp.Q{{Name: lang, Value: xmp.Text{V: "de"}}}},
+		},
+	}
+
+	data, err := Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(p.Properties, q.Properties); d != "" {
+		t.Errorf("properties differ (-want +got):\n%s", d)
+	}
+	if q.About.String() != u.String() {
+		t.Errorf("wrong about URL %q", q.About)
+	}
+
+	// The encoding is deterministic and preserves the prefixes.
+	data2, err := Marshal(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, data2) {
+		t.Error("encoding is not deterministic")
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, data := range [][]byte{
+		{0x12},                   // truncated property
+		{0x12, 0x02, 0x0a, 0x00}, // property without value
+		{0x12, 0x06, 0x0a, 0x02, 0x12, 0x00, 0x12, 0x00}, // value without kind
+	} {
+		_, err := Unmarshal(data)
+		if err == nil {
+			t.Errorf("%x: missing error", data)
+		}
+	}
+}